@@ -1,24 +1,99 @@
 package discoveryv1
 
 // SyncRequest is the payload agents send periodically to report their state.
+//
+// Enrolled agents authenticate with an "Authorization: Bearer <credential>" header.
+// On its first sync an agent sends EnrollmentToken instead and receives its
+// credential in SyncResponse.Credential.
+//
+// With mutual TLS enabled an agent may send a PEM CSR on any authenticated sync;
+// the control-plane signs it for the agent ID and returns the certificate.
+//
+// Agents that cannot be dialed set Pull: the response then carries their desired
+// specs, and the next request acknowledges the applied versions in Applied.
+//
+// Metrics is an optional resource snapshot kept in the agent's metrics history.
 type SyncRequest struct {
 	UptimeSeconds      int64 `json:"uptime_seconds"`
 	Ts                 int64 `json:"ts,omitempty"`
 	EndpointType       int   `json:"endpoint_type"`
 	APIVersion         int   `json:"api_version,omitempty"`
 	HeartbeatIntervalS int   `json:"heartbeat_interval_s,omitempty"`
+	Pull               bool  `json:"pull,omitempty"`
 
-	Metadata map[string]string `json:"metadata,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Capabilities *Capabilities     `json:"capabilities,omitempty"`
+	Metrics      *Metrics          `json:"metrics,omitempty"`
+	Applied      []SpecAck         `json:"applied,omitempty"`
 
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Endpoint string `json:"endpoint"`
-	OS       string `json:"os"`
-	Arch     string `json:"arch"`
-	Platform string `json:"platform"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Endpoint        string `json:"endpoint"`
+	OS              string `json:"os"`
+	Arch            string `json:"arch"`
+	Platform        string `json:"platform"`
+	EnrollmentToken string `json:"enrollment_token,omitempty"`
+	CSR             string `json:"csr,omitempty"`
+}
+
+// Capabilities describes the task kinds and runtimes an agent supports.
+// Agents that omit it are assumed to support every task kind.
+type Capabilities struct {
+	Limits ResourceLimits `json:"limits"`
+
+	TaskKinds []string          `json:"task_kinds,omitempty"`
+	Runtimes  map[string]string `json:"runtimes,omitempty"`
+}
+
+// ResourceLimits are the limits an agent enforces for its tasks; zero means not reported.
+type ResourceLimits struct {
+	CPUMillicores int64 `json:"cpu_millicores,omitempty"`
+	MemoryBytes   int64 `json:"memory_bytes,omitempty"`
+	MaxTasks      int   `json:"max_tasks,omitempty"`
+}
+
+// Metrics is a resource snapshot; zero values mean not reported.
+// CPUPercent is the usage across all cores, 0–100.
+type Metrics struct {
+	CPUPercent float64 `json:"cpu_percent,omitempty"`
+	Load1      float64 `json:"load1,omitempty"`
+	Load5      float64 `json:"load5,omitempty"`
+	Load15     float64 `json:"load15,omitempty"`
+
+	MemoryUsedBytes  int64 `json:"memory_used_bytes,omitempty"`
+	MemoryTotalBytes int64 `json:"memory_total_bytes,omitempty"`
+	DiskUsedBytes    int64 `json:"disk_used_bytes,omitempty"`
+	DiskTotalBytes   int64 `json:"disk_total_bytes,omitempty"`
+
+	TasksRunning int `json:"tasks_running,omitempty"`
+	TasksTotal   int `json:"tasks_total,omitempty"`
+}
+
+// SpecAck acknowledges a desired spec version applied by a pull-mode agent.
+// Error is empty on success.
+type SpecAck struct {
+	SpecID  string `json:"spec_id"`
+	Version int    `json:"version"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DesiredSpec is a spec rendered for a pull-mode agent; Spec is the agent CreateSpec JSON.
+type DesiredSpec struct {
+	SpecID  string         `json:"spec_id"`
+	Version int            `json:"version"`
+	Spec    map[string]any `json:"spec"`
 }
 
 // SyncResponse is returned to the agent after a successful sync.
+//
+// Credential is only set on the sync that enrolled the agent; Certificate and
+// CACertificate (PEM) only when the request carried a CSR. Specs is the full
+// desired spec set of a pull-mode agent, dependencies first, then by priority;
+// it is null for agents that are dialed by the control-plane.
 type SyncResponse struct {
-	Success bool `json:"success"`
+	Success       bool          `json:"success"`
+	Credential    string        `json:"credential,omitempty"`
+	Certificate   string        `json:"certificate,omitempty"`
+	CACertificate string        `json:"ca_certificate,omitempty"`
+	Specs         []DesiredSpec `json:"specs"`
 }
@@ -0,0 +1,9 @@
+package proxyv1
+
+// LogLine is a single line of task output streamed from an agent.
+type LogLine struct {
+	TS int64 `json:"ts"` // Unix timestamp in milliseconds.
+
+	Stream string `json:"stream"` // "stdout" or "stderr".
+	Line   string `json:"line"`
+}
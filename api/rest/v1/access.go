@@ -13,6 +13,12 @@ type LoginResponse struct {
 	SessionID    string `json:"session_id"`
 }
 
+// RefreshRequest is the request body for exchanging a refresh token.
+type RefreshRequest struct {
+	SessionID    string `json:"session_id"`
+	RefreshToken string `json:"refresh_token"`
+}
+
 // LogoutRequest identifies the session to terminate.
 type LogoutRequest struct {
 	SessionID string `json:"session_id"`
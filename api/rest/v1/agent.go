@@ -5,8 +5,9 @@ type Agent struct {
 	UptimeSeconds     int64 `json:"uptime_seconds"`
 	HeartbeatInterval int   `json:"heartbeat_interval_s,omitempty"`
 
-	Metadata map[string]string `json:"metadata,omitempty"`
-	Labels   map[string]string `json:"labels,omitempty"`
+	Metadata     map[string]string  `json:"metadata,omitempty"`
+	Labels       map[string]string  `json:"labels,omitempty"`
+	Capabilities *AgentCapabilities `json:"capabilities,omitempty"`
 
 	ID           string `json:"id"`
 	Name         string `json:"name"`
@@ -17,7 +18,30 @@ type Agent struct {
 	Arch         string `json:"arch"`
 	Platform     string `json:"platform"`
 	Status       string `json:"status"`
+	Approval     string `json:"approval"`
 	LastSeenAt   string `json:"last_seen_at,omitempty"`
+
+	// CertExpiresAt is the expiry of the client certificate issued to the agent (mutual TLS).
+	CertExpiresAt string `json:"cert_expires_at,omitempty"`
+	// PullMode agents receive their specs in the discovery sync response and are never dialed.
+	PullMode bool `json:"pull_mode,omitempty"`
+	// Cordoned agents keep their rollouts but are left out of new deployments and runs.
+	Cordoned bool `json:"cordoned,omitempty"`
+
+	// Reachability is the result of the last active probe: unknown, reachable or unreachable.
+	Reachability string `json:"reachability"`
+	ProbedAt     string `json:"probed_at,omitempty"`
+	ProbeError   string `json:"probe_error,omitempty"`
+}
+
+// AgentCapabilities is the REST representation of agent-reported capabilities.
+type AgentCapabilities struct {
+	CPUMillicores int64 `json:"cpu_millicores,omitempty"`
+	MemoryBytes   int64 `json:"memory_bytes,omitempty"`
+	MaxTasks      int   `json:"max_tasks,omitempty"`
+
+	TaskKinds []string          `json:"task_kinds,omitempty"`
+	Runtimes  map[string]string `json:"runtimes,omitempty"`
 }
 
 // AgentListResponse is the paginated list of agents.
@@ -26,7 +50,67 @@ type AgentListResponse struct {
 	NextCursor string  `json:"next_cursor,omitempty"`
 }
 
+// AgentMetricSample is one resource snapshot reported by an agent; zero values mean not reported.
+type AgentMetricSample struct {
+	CPUPercent float64 `json:"cpu_percent"`
+	Load1      float64 `json:"load1"`
+	Load5      float64 `json:"load5"`
+	Load15     float64 `json:"load15"`
+
+	MemoryUsedBytes  int64 `json:"memory_used_bytes"`
+	MemoryTotalBytes int64 `json:"memory_total_bytes"`
+	DiskUsedBytes    int64 `json:"disk_used_bytes"`
+	DiskTotalBytes   int64 `json:"disk_total_bytes"`
+
+	TasksRunning int `json:"tasks_running"`
+	TasksTotal   int `json:"tasks_total"`
+
+	Time string `json:"time"`
+}
+
+// AgentMetricsResponse is the metrics history of an agent within a time range, oldest first.
+type AgentMetricsResponse struct {
+	Items []AgentMetricSample `json:"items"`
+}
+
+// AgentFieldChange is one inventory field that changed between two syncs.
+// Old is empty when the field appeared, New is empty when it disappeared.
+type AgentFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// AgentChange is one entry of an agent's inventory change history.
+type AgentChange struct {
+	ID     string             `json:"id"`
+	Time   string             `json:"time"`
+	Fields []AgentFieldChange `json:"fields"`
+}
+
+// AgentHistoryResponse is a page of an agent's inventory change history, newest first.
+type AgentHistoryResponse struct {
+	Items      []AgentChange `json:"items"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// AgentDrainResponse is the outcome of draining an agent.
+type AgentDrainResponse struct {
+	// Undeployed lists the specs whose rollout on the agent was removed.
+	Undeployed []string `json:"undeployed"`
+	// Retargeted lists the label-selected specs that gained a rollout on another agent.
+	Retargeted []string `json:"retargeted,omitempty"`
+	// Failed maps the specs that could not be retargeted to the reason.
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
 // AgentPatchLabelsRequest is the request body for patching agent labels.
 type AgentPatchLabelsRequest struct {
 	Labels map[string]string `json:"labels"`
 }
+
+// TaskLogEnd is the payload of the final "end" event of a task log stream.
+// Error is set when the agent stream broke off before the agent ended it.
+type TaskLogEnd struct {
+	Error string `json:"error,omitempty"`
+}
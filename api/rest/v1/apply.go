@@ -0,0 +1,33 @@
+package restv1
+
+// ManifestDocument is one document of a multi-document apply manifest.
+//
+// Kind selects which of the other fields is used:
+//   - "Spec":        Spec (name identifies the spec; depends_on lists spec names, not IDs)
+//   - "AgentLabels": Agent and Labels (labels replace the agent's labels)
+type ManifestDocument struct {
+	Spec   *SpecCreateRequest `json:"spec,omitempty"`
+	Labels map[string]string  `json:"labels,omitempty"`
+
+	Kind  string `json:"kind"`
+	Agent string `json:"agent,omitempty"`
+}
+
+// ApplyResult is the outcome of applying one manifest object.
+type ApplyResult struct {
+	Fields map[string]string `json:"fields,omitempty"`
+
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	ID     string `json:"id,omitempty"`
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ApplyResponse lists per-object apply results in manifest order, followed by pruned objects.
+type ApplyResponse struct {
+	Items []ApplyResult `json:"items"`
+
+	DryRun bool `json:"dry_run"`
+	Failed int  `json:"failed"`
+}
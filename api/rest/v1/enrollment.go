@@ -0,0 +1,40 @@
+package restv1
+
+// EnrollmentToken is the REST representation of an agent enrollment token.
+//
+// The token value is only returned once, in EnrollmentTokenCreateResponse.
+type EnrollmentToken struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	ExpiresAt  string            `json:"expires_at,omitempty"`
+	LastUsedAt string            `json:"last_used_at,omitempty"`
+	MaxUses    int               `json:"max_uses,omitempty"`
+	Uses       int               `json:"uses"`
+	Usable     bool              `json:"usable"`
+	CreatedAt  string            `json:"created_at"`
+	UpdatedAt  string            `json:"updated_at"`
+}
+
+// EnrollmentTokenListResponse is the paginated list of enrollment tokens.
+type EnrollmentTokenListResponse struct {
+	Items      []EnrollmentToken `json:"items"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// EnrollmentTokenCreateRequest is the request body for creating an enrollment token.
+//
+// Labels are applied to every agent enrolled with the token.
+// A zero TTLSeconds never expires; a zero MaxUses allows unlimited enrollments.
+type EnrollmentTokenCreateRequest struct {
+	Name       string            `json:"name"`
+	Labels     map[string]string `json:"labels,omitempty"`
+	TTLSeconds int64             `json:"ttl_s,omitempty"`
+	MaxUses    int               `json:"max_uses,omitempty"`
+}
+
+// EnrollmentTokenCreateResponse is returned once on creation and carries the token value.
+type EnrollmentTokenCreateResponse struct {
+	EnrollmentToken
+	Token string `json:"token"`
+}
@@ -0,0 +1,59 @@
+package restv1
+
+// Run is the REST representation of an ad-hoc run.
+//
+// Status is "running" until every target has a final result, then "succeeded"
+// if all of them succeeded and "failed" otherwise. Summary counts results per status.
+type Run struct {
+	KindConfig   map[string]any    `json:"kind_config,omitempty"`
+	TargetLabels map[string]string `json:"target_labels,omitempty"`
+	RunnerLabels map[string]string `json:"runner_labels,omitempty"`
+	Summary      map[string]int    `json:"summary"`
+	Results      []RunResult       `json:"results"`
+
+	TimeoutMs int64 `json:"timeout_ms"`
+
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Slot       string `json:"slot"`
+	Status     string `json:"status"`
+	KindType   string `json:"kind_type"`
+	CreatedBy  string `json:"created_by,omitempty"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+	FinishedAt string `json:"finished_at,omitempty"`
+}
+
+// RunResult is the outcome of a run on a single agent.
+type RunResult struct {
+	Attempt int `json:"attempt,omitempty"`
+
+	SubmittedAt string `json:"submitted_at,omitempty"`
+	FinishedAt  string `json:"finished_at,omitempty"`
+	AgentID     string `json:"agent_id"`
+	TaskID      string `json:"task_id,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// RunListResponse is the paginated list of runs.
+type RunListResponse struct {
+	Items      []Run  `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// RunCreateRequest is the request body for starting a run.
+//
+// Targets and TargetLabels are combined; at least one agent must match.
+// A zero TimeoutMs keeps the default task timeout.
+type RunCreateRequest struct {
+	KindConfig   map[string]any    `json:"kind_config"`
+	TargetLabels map[string]string `json:"target_labels,omitempty"`
+	RunnerLabels map[string]string `json:"runner_labels,omitempty"`
+	Targets      []string          `json:"targets,omitempty"`
+
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+
+	KindType string `json:"kind_type"`
+	Name     string `json:"name"`
+}
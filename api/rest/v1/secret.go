@@ -0,0 +1,28 @@
+package restv1
+
+// Secret is the REST representation of a secret.
+//
+// The value is write-only and never returned.
+type Secret struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Reference   string `json:"reference"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// SecretListResponse is the paginated list of secrets.
+type SecretListResponse struct {
+	Items      []Secret `json:"items"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// SecretUpsertRequest is the request body for creating/updating a secret.
+//
+// On update, an empty Value keeps the current value and Name is ignored.
+type SecretUpsertRequest struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
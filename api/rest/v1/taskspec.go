@@ -7,6 +7,9 @@ type Spec struct {
 	RunnerLabels map[string]string `json:"runner_labels,omitempty"`
 	CreateSpec   map[string]any    `json:"create_spec,omitempty"`
 	Targets      []string          `json:"targets,omitempty"`
+	DependsOn    []string          `json:"depends_on,omitempty"`
+	Placement    []string          `json:"placement,omitempty"`
+	Secrets      []string          `json:"secrets,omitempty"`
 
 	BackoffFactor float64 `json:"backoff_factor"`
 
@@ -15,7 +18,11 @@ type Spec struct {
 	BackoffFirstMs int64 `json:"backoff_first_ms"`
 	BackoffMaxMs   int64 `json:"backoff_max_ms"`
 
-	Version int `json:"version"`
+	Version  int `json:"version"`
+	Priority int `json:"priority"`
+
+	// ManagedBy names the declarative source that owns the spec; such specs are read-only.
+	ManagedBy string `json:"managed_by,omitempty"`
 
 	ID          string `json:"id"`
 	Name        string `json:"name"`
@@ -40,6 +47,9 @@ type SpecCreateRequest struct {
 	RunnerLabels map[string]string `json:"runner_labels,omitempty"`
 	KindConfig   map[string]any    `json:"kind_config,omitempty"`
 	Targets      []string          `json:"targets,omitempty"`
+	DependsOn    []string          `json:"depends_on,omitempty"`
+	// Placement lists constraint expressions (e.g. "os in (ubuntu,debian)", "metadata.kernel >= 6.0").
+	Placement []string `json:"placement,omitempty"`
 
 	BackoffFactor float64 `json:"backoff_factor"`
 
@@ -48,6 +58,9 @@ type SpecCreateRequest struct {
 	BackoffMaxMs   int64 `json:"backoff_max_ms"`
 	TimeoutMs      int64 `json:"timeout_ms"`
 
+	// Priority orders pushes to an agent shared with other specs; nil keeps the current value.
+	Priority *int `json:"priority,omitempty"`
+
 	RestartType string `json:"restart_type"`
 	KindType    string `json:"kind_type"`
 	Admission   string `json:"admission"`
@@ -55,3 +68,29 @@ type SpecCreateRequest struct {
 	Name        string `json:"name"`
 	Slot        string `json:"slot"`
 }
+
+// SpecPreview is the CreateSpec payload of a spec rendered for one agent.
+type SpecPreview struct {
+	CreateSpec map[string]any `json:"create_spec"`
+
+	SpecID  string `json:"spec_id"`
+	AgentID string `json:"agent_id"`
+}
+
+// SpecPlan lists the deploy decision for every agent a spec resolves to.
+type SpecPlan struct {
+	SpecID string          `json:"spec_id"`
+	Items  []SpecPlanEntry `json:"items"`
+}
+
+// SpecPlanEntry is the deploy decision for one agent.
+//
+// Source is "target" (listed explicitly) or "selector" (matched by target_labels).
+// Action is "deploy", "exclude" (rollout recorded as excluded) or "skip" (no rollout).
+type SpecPlanEntry struct {
+	AgentID   string `json:"agent_id"`
+	AgentName string `json:"agent_name,omitempty"`
+	Source    string `json:"source"`
+	Action    string `json:"action"`
+	Reason    string `json:"reason,omitempty"`
+}
@@ -9,24 +9,33 @@ import (
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
 	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/internal/auth/pki"
 	"github.com/soltiHQ/control-plane/internal/auth/wire"
 	"github.com/soltiHQ/control-plane/internal/bootstrap"
 	"github.com/soltiHQ/control-plane/internal/config"
 	"github.com/soltiHQ/control-plane/internal/event"
 	"github.com/soltiHQ/control-plane/internal/handler"
+	"github.com/soltiHQ/control-plane/internal/metrics"
 	"github.com/soltiHQ/control-plane/internal/proxy"
 	"github.com/soltiHQ/control-plane/internal/server"
+	"github.com/soltiHQ/control-plane/internal/server/runner/gitops"
 	"github.com/soltiHQ/control-plane/internal/server/runner/grpcserver"
 	"github.com/soltiHQ/control-plane/internal/server/runner/httpserver"
 	"github.com/soltiHQ/control-plane/internal/server/runner/lifecycle"
+	"github.com/soltiHQ/control-plane/internal/server/runner/oneshot"
 	syncrunner "github.com/soltiHQ/control-plane/internal/server/runner/sync"
 	"github.com/soltiHQ/control-plane/internal/service/access"
 	"github.com/soltiHQ/control-plane/internal/service/agent"
 	"github.com/soltiHQ/control-plane/internal/service/credential"
+	"github.com/soltiHQ/control-plane/internal/service/enrollment"
+	"github.com/soltiHQ/control-plane/internal/service/manifest"
 	"github.com/soltiHQ/control-plane/internal/service/role"
+	"github.com/soltiHQ/control-plane/internal/service/run"
+	"github.com/soltiHQ/control-plane/internal/service/secret"
 	"github.com/soltiHQ/control-plane/internal/service/session"
 	"github.com/soltiHQ/control-plane/internal/service/spec"
 	"github.com/soltiHQ/control-plane/internal/service/user"
@@ -44,7 +53,11 @@ type services struct {
 	session    *session.Service
 	access     *access.Service
 	agent      *agent.Service
+	enrollment *enrollment.Service
 	spec       *spec.Service
+	secret     *secret.Service
+	run        *run.Service
+	manifest   *manifest.Service
 	user       *user.Service
 	role       *role.Service
 }
@@ -61,21 +74,35 @@ func main() {
 	var (
 		store     = inmemory.New()
 		authModel = wire.NewAuth(store, cfg.Auth)
-		svc       = initServices(store, authModel, logger)
+		svc       = initServices(cfg, store, authModel, logger)
 	)
 	if err = bootstrap.Run(context.Background(), logger, svc.role, svc.user, svc.credential); err != nil {
 		logger.Fatal().Err(err).Msg("failed to bootstrap")
 	}
 
-	proxyPool := proxy.NewPool()
+	var (
+		ca       *pki.CA
+		agentTLS proxy.AgentTLS
+	)
+	if cfg.TLS.Enabled {
+		if ca, err = pki.New(cfg.TLS); err != nil {
+			logger.Fatal().Err(err).Msg("failed to load certificate authority")
+		}
+		agentTLS = ca.AgentClientTLS
+		cfg.HTTPDiscovery.TLSConfig = ca.ServerTLS()
+	}
+
+	proxyPool := proxy.NewPool(agentTLS)
 	defer proxyPool.Close()
 
 	eventHub := event.NewHub(logger)
 	defer eventHub.Close()
 
+	metricStore := metrics.NewStore(cfg.Metrics)
+
 	htmx.Configure(cfg.Triggers)
 
-	lifecycleRunner, err := lifecycle.New(cfg.Lifecycle, logger, store, eventHub)
+	lifecycleRunner, err := lifecycle.New(cfg.Lifecycle, logger, store, svc.agent, metricStore, proxyPool, eventHub)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create lifecycle runner")
 	}
@@ -85,25 +112,44 @@ func main() {
 		logger.Fatal().Err(err).Msg("failed to create sync runner")
 	}
 
-	mainHandler := buildMainHandler(cfg, logger, svc, authModel, proxyPool, eventHub)
+	oneshotRunner, err := oneshot.New(cfg.Oneshot, logger, store, proxyPool, eventHub)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create oneshot runner")
+	}
+
+	reporters := []server.Reporter{lifecycleRunner, syncRunner}
+	var gitopsRunner *gitops.Runner
+	if cfg.GitOps.Enabled() {
+		if gitopsRunner, err = gitops.New(cfg.GitOps, logger, svc.manifest, eventHub); err != nil {
+			logger.Fatal().Err(err).Msg("failed to create gitops runner")
+		}
+		reporters = append(reporters, gitopsRunner)
+	}
+
+	mainHandler := buildMainHandler(cfg, logger, svc, authModel, proxyPool, metricStore, eventHub, reporters...)
 	httpRunner, err := httpserver.New(cfg.HTTP, logger, mainHandler)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create http server")
 	}
 
-	discoveryHandler := buildDiscoveryHandler(logger, svc.agent, eventHub)
+	discoveryHandler := buildDiscoveryHandler(logger, svc.agent, svc.enrollment, svc.spec, metricStore, ca, eventHub)
 	httpDiscoveryRunner, err := httpserver.New(cfg.HTTPDiscovery, logger, discoveryHandler)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create http discovery server")
 	}
 
-	grpcSrv := buildGRPCServer(logger, svc.agent, eventHub)
+	grpcSrv := buildGRPCServer(logger, svc.agent, svc.enrollment, svc.spec, proxyPool, metricStore, ca, eventHub)
 	grpcRunner, err := grpcserver.New(cfg.GRPC, logger, grpcSrv)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create grpc server")
 	}
 
-	srv, err := server.New(cfg.Server, logger, httpRunner, httpDiscoveryRunner, grpcRunner, lifecycleRunner, syncRunner)
+	runners := []server.Runner{httpRunner, httpDiscoveryRunner, grpcRunner, lifecycleRunner, syncRunner, oneshotRunner}
+	if gitopsRunner != nil {
+		runners = append(runners, gitopsRunner)
+	}
+
+	srv, err := server.New(cfg.Server, logger, runners...)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("failed to create server")
 	}
@@ -117,21 +163,26 @@ func main() {
 	logger.Info().Msg("server stopped")
 }
 
-func initServices(store *inmemory.Store, authModel *wire.Auth, logger zerolog.Logger) services {
+func initServices(cfg config.Config, store *inmemory.Store, authModel *wire.Auth, logger zerolog.Logger) services {
+	agentSVC := agent.New(store, logger)
 	return services{
 		access:     access.New(authModel, store, logger),
 		credential: credential.New(store, logger),
 		session:    session.New(store, logger),
-		agent:      agent.New(store, logger),
+		agent:      agentSVC,
+		enrollment: enrollment.New(cfg.Enrollment, store, logger),
 		spec:       spec.New(store, logger),
+		secret:     secret.New(store, logger),
+		run:        run.New(store, logger),
+		manifest:   manifest.New(store, agentSVC, logger),
 		role:       role.New(store, logger),
 		user:       user.New(store, logger),
 	}
 }
 
-func buildMainHandler(cfg config.Config, logger zerolog.Logger, svc services, authModel *wire.Auth, proxyPool *proxy.Pool, eventHub *event.Hub) http.Handler {
+func buildMainHandler(cfg config.Config, logger zerolog.Logger, svc services, authModel *wire.Auth, proxyPool *proxy.Pool, metricStore *metrics.Store, eventHub *event.Hub, runners ...server.Reporter) http.Handler {
 	var (
-		apiHandler    = handler.NewAPI(logger, svc.user, svc.access, svc.session, svc.credential, svc.agent, svc.spec, proxyPool, eventHub)
+		apiHandler    = handler.NewAPI(logger, svc.user, svc.access, svc.session, svc.credential, svc.agent, svc.enrollment, svc.spec, svc.secret, svc.run, svc.manifest, proxyPool, metricStore, eventHub, runners...)
 		authMW        = middleware.Auth(authModel.Verifier, authModel.Session)
 		uiHandler     = handler.NewUI(logger, svc.access, eventHub)
 		staticHandler = handler.NewStatic(logger)
@@ -156,9 +207,9 @@ func buildMainHandler(cfg config.Config, logger zerolog.Logger, svc services, au
 	return h
 }
 
-func buildDiscoveryHandler(logger zerolog.Logger, agentSVC *agent.Service, eventHub *event.Hub) http.Handler {
+func buildDiscoveryHandler(logger zerolog.Logger, agentSVC *agent.Service, enrollSVC *enrollment.Service, specSVC *spec.Service, metricStore *metrics.Store, ca *pki.CA, eventHub *event.Hub) http.Handler {
 	var (
-		httpDiscovery = handler.NewHTTPDiscovery(logger, agentSVC, eventHub)
+		httpDiscovery = handler.NewHTTPDiscovery(logger, agentSVC, enrollSVC, specSVC, metricStore, ca, eventHub)
 		mux           = http.NewServeMux()
 	)
 	mux.HandleFunc("/api/v1/discovery/sync", httpDiscovery.Sync)
@@ -170,16 +221,25 @@ func buildDiscoveryHandler(logger zerolog.Logger, agentSVC *agent.Service, event
 	return h
 }
 
-func buildGRPCServer(logger zerolog.Logger, agentSVC *agent.Service, eventHub *event.Hub) *grpc.Server {
+func buildGRPCServer(logger zerolog.Logger, agentSVC *agent.Service, enrollSVC *enrollment.Service, specSVC *spec.Service, proxyPool *proxy.Pool, metricStore *metrics.Store, ca *pki.CA, eventHub *event.Hub) *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			interceptor.UnaryRecovery(logger),
+			interceptor.UnaryRequestID(),
+			interceptor.UnaryLogger(logger),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.StreamRecovery(logger),
+			interceptor.StreamRequestID(),
+			interceptor.StreamLogger(logger),
+		),
+	}
+	if ca != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(ca.ServerTLS())))
+	}
 	var (
-		srv = grpc.NewServer(
-			grpc.ChainUnaryInterceptor(
-				interceptor.UnaryRecovery(logger),
-				interceptor.UnaryRequestID(),
-				interceptor.UnaryLogger(logger),
-			),
-		)
-		grpcDiscovery = handler.NewGRPCDiscovery(logger, agentSVC, eventHub)
+		srv           = grpc.NewServer(opts...)
+		grpcDiscovery = handler.NewGRPCDiscovery(logger, agentSVC, enrollSVC, specSVC, proxyPool, metricStore, ca, eventHub)
 	)
 	genv1.RegisterDiscoverServiceServer(srv, grpcDiscovery)
 	return srv
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// runLabel edits agent labels kubectl-style: KEY=VALUE sets a label, KEY- removes it.
+// The server replaces the whole label set, so the current labels are read first.
+func runLabel(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("label")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) < 2 {
+		return errors.New("usage: podiumctl label AGENT_ID KEY=VALUE... KEY-...")
+	}
+	id, edits := pos[0], pos[1:]
+
+	set := make(map[string]string)
+	var remove []string
+	for _, ed := range edits {
+		switch {
+		case strings.HasSuffix(ed, "-") && !strings.Contains(ed, "="):
+			remove = append(remove, strings.TrimSuffix(ed, "-"))
+		case strings.Contains(ed, "="):
+			k, v, _ := strings.Cut(ed, "=")
+			if k == "" || v == "" {
+				return fmt.Errorf("invalid label %q: key and value must be non-empty", ed)
+			}
+			set[k] = v
+		default:
+			return fmt.Errorf("invalid label edit %q: want KEY=VALUE or KEY-", ed)
+		}
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	ag, err := c.GetAgent(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	labels := make(map[string]string, len(ag.Labels)+len(set))
+	maps.Copy(labels, ag.Labels)
+	maps.Copy(labels, set)
+	for _, k := range remove {
+		delete(labels, k)
+	}
+
+	if err = c.SetAgentLabels(ctx, id, labels); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "agent %s labeled: %s\n", id, joinLabels(labels))
+	return nil
+}
+
+// runApprove admits agents pending approval.
+func runApprove(ctx context.Context, e *env, args []string) error {
+	return setApproval(ctx, e, "approve", args)
+}
+
+// runReject rejects agents; their syncs are refused until approved again.
+func runReject(ctx context.Context, e *env, args []string) error {
+	return setApproval(ctx, e, "reject", args)
+}
+
+func setApproval(ctx context.Context, e *env, action string, args []string) error {
+	fs := e.flags(action)
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) == 0 {
+		return fmt.Errorf("usage: podiumctl %s AGENT_ID...", action)
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	set, done := c.ApproveAgent, "approved"
+	if action == "reject" {
+		set, done = c.RejectAgent, "rejected"
+	}
+	for _, id := range pos {
+		if err = set(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "agent %s %s\n", id, done)
+	}
+	return nil
+}
+
+// runCordon cordons agents; they keep their rollouts but get no new work.
+func runCordon(ctx context.Context, e *env, args []string) error {
+	return setCordon(ctx, e, "cordon", args)
+}
+
+// runUncordon lets cordoned agents receive new work again.
+func runUncordon(ctx context.Context, e *env, args []string) error {
+	return setCordon(ctx, e, "uncordon", args)
+}
+
+func setCordon(ctx context.Context, e *env, action string, args []string) error {
+	fs := e.flags(action)
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) == 0 {
+		return fmt.Errorf("usage: podiumctl %s AGENT_ID...", action)
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	set, done := c.CordonAgent, "cordoned"
+	if action == "uncordon" {
+		set, done = c.UncordonAgent, "uncordoned"
+	}
+	for _, id := range pos {
+		if err = set(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "agent %s %s\n", id, done)
+	}
+	return nil
+}
+
+// runDrain cordons an agent and removes its rollouts, optionally re-targeting label-selected specs.
+func runDrain(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("drain")
+	retarget := fs.Bool("retarget", false, "roll label-selected specs out to the other matching agents")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := oneArg(pos, "agent ID")
+	if err != nil {
+		return err
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	p, err := e.printer()
+	if err != nil {
+		return err
+	}
+	res, err := c.DrainAgent(ctx, id, *retarget)
+	if err != nil {
+		return err
+	}
+	return p.print(res, func() *table {
+		retargeted := make(map[string]bool, len(res.Retargeted))
+		for _, s := range res.Retargeted {
+			retargeted[s] = true
+		}
+		t := &table{header: []string{"SPEC", "RESULT"}}
+		for _, s := range res.Undeployed {
+			switch {
+			case retargeted[s]:
+				t.add(s, "undeployed, retargeted")
+			case res.Failed[s] != "":
+				t.add(s, "undeployed, retarget failed: "+res.Failed[s])
+			default:
+				t.add(s, "undeployed")
+			}
+		}
+		return t
+	})
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/soltiHQ/control-plane/pkg/client"
+)
+
+func runLogin(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("login")
+	subject := fs.String("u", "", "subject (user name) to log in as")
+	fromStdin := fs.Bool("password-stdin", false, "read the password from stdin")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	c, store, err := e.client()
+	if err != nil {
+		return err
+	}
+	in := bufio.NewReader(e.stdin)
+	if *subject == "" {
+		if *fromStdin {
+			return errors.New("-u is required with --password-stdin")
+		}
+		fmt.Fprint(e.stderr, "Subject: ")
+		if *subject, err = readLine(in); err != nil {
+			return err
+		}
+	}
+
+	password := os.Getenv("PODIUM_PASSWORD")
+	switch {
+	case *fromStdin:
+		if password, err = readLine(in); err != nil {
+			return err
+		}
+	case password == "":
+		if password, err = readPassword(e, in); err != nil {
+			return err
+		}
+	}
+
+	if err = c.Login(ctx, *subject, password); err != nil {
+		if errors.Is(err, client.ErrUnauthorized) {
+			return errors.New("invalid subject or password")
+		}
+		return err
+	}
+	if err = store.update(c.BaseURL(), *subject, c.Tokens()); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "Logged in to %s as %s\n", c.BaseURL(), *subject)
+	return nil
+}
+
+func runLogout(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("logout")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	// The cached tokens are dropped even if the session is already gone.
+	if err = c.Logout(ctx); err != nil && !errors.Is(err, client.ErrUnauthorized) {
+		return err
+	}
+	fmt.Fprintf(e.stderr, "Logged out of %s\n", c.BaseURL())
+	return nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPassword prompts for a password, turning terminal echo off when
+// stdin is a terminal and stty is available.
+func readPassword(e *env, r *bufio.Reader) (string, error) {
+	fmt.Fprint(e.stderr, "Password: ")
+	if f, ok := e.stdin.(*os.File); ok {
+		if fi, err := f.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 {
+			if stty(f, "-echo") == nil {
+				defer func() {
+					_ = stty(f, "echo")
+					fmt.Fprintln(e.stderr)
+				}()
+			}
+		}
+	}
+	return readLine(r)
+}
+
+func stty(tty *os.File, arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = tty
+	return cmd.Run()
+}
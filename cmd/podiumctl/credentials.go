@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/soltiHQ/control-plane/pkg/client"
+)
+
+// credentials is a cached token pair for one server.
+type credentials struct {
+	client.Tokens
+	Subject string `json:"subject"`
+}
+
+// credentialStore is the on-disk token cache, keyed by server URL.
+// Current is the server used when --server is not given.
+type credentialStore struct {
+	Servers map[string]*credentials `json:"servers"`
+	Current string                  `json:"current,omitempty"`
+
+	path string
+}
+
+// credentialsPath returns the token cache location:
+// $PODIUM_CREDENTIALS, or <user config dir>/podiumctl/credentials.json.
+func credentialsPath() (string, error) {
+	if p := os.Getenv("PODIUM_CREDENTIALS"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "podiumctl", "credentials.json"), nil
+}
+
+// loadCredentials reads the token cache; a missing file yields an empty store.
+func loadCredentials() (*credentialStore, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	cs := &credentialStore{Servers: make(map[string]*credentials), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, cs); err != nil {
+		return nil, errors.New("corrupt credentials file " + path + ": " + err.Error())
+	}
+	if cs.Servers == nil {
+		cs.Servers = make(map[string]*credentials)
+	}
+	return cs, nil
+}
+
+// save writes the token cache, readable by the current user only.
+func (cs *credentialStore) save() error {
+	if err := os.MkdirAll(filepath.Dir(cs.path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := cs.path + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cs.path)
+}
+
+// update stores the tokens of server, or forgets the server when t is empty.
+func (cs *credentialStore) update(server, subject string, t client.Tokens) error {
+	if t.AccessToken == "" {
+		delete(cs.Servers, server)
+		if cs.Current == server {
+			cs.Current = ""
+		}
+		return cs.save()
+	}
+	cr := cs.Servers[server]
+	if cr == nil {
+		cr = &credentials{}
+		cs.Servers[server] = cr
+	}
+	if subject != "" {
+		cr.Subject = subject
+	}
+	cr.Tokens = t
+	cs.Current = server
+	return cs.save()
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/soltiHQ/control-plane/pkg/client"
+)
+
+// eventLine is one activity record as printed by "events".
+type eventLine struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	ID     string    `json:"id,omitempty"`
+	Name   string    `json:"name,omitempty"`
+	By     string    `json:"by,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// runEvents prints recent activity, then follows the feed.
+//
+// The event stream only carries update notifications, so each notification
+// (and every --interval, for events recorded without one) triggers a re-read
+// of the dashboard activity feed; records newer than the last printed one are shown.
+func runEvents(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("events")
+	count := fs.Int("n", 10, "number of recent events to print first")
+	interval := fs.Duration("interval", 5*time.Second, "poll interval between notifications")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+	if _, err := e.printer(); err != nil {
+		return err
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	recent, err := fetchEvents(ctx, c)
+	if err != nil {
+		return err
+	}
+	var last time.Time
+	if n := len(recent); n > 0 {
+		last = recent[n-1].Time
+	}
+	if *count < len(recent) {
+		recent = recent[len(recent)-*count:]
+	}
+	if err = printEvents(e, recent); err != nil {
+		return err
+	}
+
+	notify := make(chan struct{}, 1)
+	go followStream(ctx, e, c, notify)
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notify:
+		case <-ticker.C:
+		}
+
+		recs, err := fetchEvents(ctx, c)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		var fresh []client.Event
+		for _, r := range recs {
+			if r.Time.After(last) {
+				fresh = append(fresh, r)
+			}
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+		last = fresh[len(fresh)-1].Time
+		if err = printEvents(e, fresh); err != nil {
+			return err
+		}
+	}
+}
+
+// fetchEvents returns the dashboard activity feed, oldest first.
+func fetchEvents(ctx context.Context, c *client.Client) ([]client.Event, error) {
+	dash, err := c.Dashboard(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(dash.Events)
+	return dash.Events, nil
+}
+
+// followStream signals notify for every message on the event stream,
+// reconnecting with a short delay until ctx is done.
+func followStream(ctx context.Context, e *env, c *client.Client, notify chan<- struct{}) {
+	for ctx.Err() == nil {
+		for _, err := range c.Notifications(ctx) {
+			if err != nil {
+				if ctx.Err() == nil {
+					fmt.Fprintln(e.stderr, "event stream:", err)
+				}
+				break
+			}
+			select {
+			case notify <- struct{}{}:
+			default:
+			}
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+// printEvents writes records one per line (table), as JSON lines, or as YAML documents.
+func printEvents(e *env, recs []client.Event) error {
+	for _, r := range recs {
+		line := eventLine{
+			Time:   r.Time,
+			Kind:   r.Kind,
+			ID:     r.Payload.ID,
+			Name:   r.Payload.Name,
+			By:     r.Payload.By,
+			Detail: r.Payload.Detail,
+		}
+		switch e.output {
+		case outputJSON:
+			if err := json.NewEncoder(e.stdout).Encode(line); err != nil {
+				return err
+			}
+		case outputYAML:
+			fmt.Fprintln(e.stdout, "---")
+			p := &printer{w: e.stdout, format: outputYAML}
+			if err := p.print(line, nil); err != nil {
+				return err
+			}
+		default:
+			by := line.By
+			if by == "" {
+				by = "-"
+			}
+			fmt.Fprintf(e.stdout, "%s  %-22s %-28s by %s", line.Time.Local().Format(time.DateTime), line.Kind, r.Payload.DisplayName(), by)
+			if line.Detail != "" {
+				fmt.Fprintf(e.stdout, "  %s", line.Detail)
+			}
+			fmt.Fprintln(e.stdout)
+		}
+	}
+	return nil
+}
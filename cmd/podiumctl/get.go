@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/pkg/client"
+)
+
+// rolloutRow is a rollout entry tagged with its spec, as printed by "get rollouts".
+type rolloutRow struct {
+	SpecID   string `json:"spec_id"`
+	SpecName string `json:"spec_name"`
+	restv1.RolloutEntry
+}
+
+func runGet(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("get")
+	query := fs.String("q", "", "filter lists by a search query")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) == 0 {
+		return errors.New("usage: podiumctl get agents|agent|specs|spec|rollouts|runs|run|users|user [ID]")
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	p, err := e.printer()
+	if err != nil {
+		return err
+	}
+
+	var (
+		opts           = client.ListOptions{Query: *query}
+		resource, rest = pos[0], pos[1:]
+	)
+	switch resource {
+	case "agents":
+		items, err := client.Collect(c.Agents(ctx, opts))
+		if err != nil {
+			return err
+		}
+		return p.print(items, func() *table { return agentTable(items...) })
+	case "agent":
+		id, err := oneArg(rest, "agent ID")
+		if err != nil {
+			return err
+		}
+		ag, err := c.GetAgent(ctx, id)
+		if err != nil {
+			return err
+		}
+		return p.print(ag, func() *table { return agentTable(*ag) })
+	case "specs":
+		items, err := client.Collect(c.Specs(ctx, opts))
+		if err != nil {
+			return err
+		}
+		return p.print(items, func() *table { return specTable(items...) })
+	case "spec":
+		id, err := oneArg(rest, "spec ID")
+		if err != nil {
+			return err
+		}
+		ts, err := c.GetSpec(ctx, id)
+		if err != nil {
+			return err
+		}
+		return p.print(ts, func() *table { return specTable(*ts) })
+	case "rollouts":
+		rows, err := rollouts(ctx, c, rest)
+		if err != nil {
+			return err
+		}
+		return p.print(rows, func() *table { return rolloutTable(rows) })
+	case "runs":
+		items, err := client.Collect(c.Runs(ctx, opts))
+		if err != nil {
+			return err
+		}
+		return p.print(items, func() *table { return runTable(items...) })
+	case "run":
+		id, err := oneArg(rest, "run ID")
+		if err != nil {
+			return err
+		}
+		x, err := c.GetRun(ctx, id)
+		if err != nil {
+			return err
+		}
+		return p.print(x, func() *table { return runResultTable(*x) })
+	case "users":
+		items, err := client.Collect(c.Users(ctx, opts))
+		if err != nil {
+			return err
+		}
+		return p.print(items, func() *table { return userTable(items...) })
+	case "user":
+		id, err := oneArg(rest, "user ID")
+		if err != nil {
+			return err
+		}
+		u, err := c.GetUser(ctx, id)
+		if err != nil {
+			return err
+		}
+		return p.print(u, func() *table { return userTable(*u) })
+	default:
+		return fmt.Errorf("unknown resource %q", resource)
+	}
+}
+
+// rollouts returns the rollout entries of one spec, or of every spec if none is given.
+func rollouts(ctx context.Context, c *client.Client, args []string) ([]rolloutRow, error) {
+	var specs []restv1.Spec
+	switch len(args) {
+	case 0:
+		var err error
+		if specs, err = client.Collect(c.Specs(ctx, client.ListOptions{})); err != nil {
+			return nil, err
+		}
+	case 1:
+		ts, err := c.GetSpec(ctx, args[0])
+		if err != nil {
+			return nil, err
+		}
+		specs = []restv1.Spec{*ts}
+	default:
+		return nil, errors.New("usage: podiumctl get rollouts [SPEC_ID]")
+	}
+
+	rows := []rolloutRow{}
+	for _, ts := range specs {
+		entries, err := c.SpecRollouts(ctx, ts.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, en := range entries {
+			rows = append(rows, rolloutRow{SpecID: ts.ID, SpecName: ts.Name, RolloutEntry: en})
+		}
+	}
+	return rows, nil
+}
+
+func oneArg(args []string, what string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("expected exactly one %s", what)
+	}
+	return args[0], nil
+}
+
+func agentTable(items ...restv1.Agent) *table {
+	t := &table{header: []string{"ID", "NAME", "STATUS", "APPROVAL", "PLATFORM", "ENDPOINT", "LAST SEEN", "LABELS"}}
+	for _, ag := range items {
+		status := ag.Status
+		if ag.Cordoned {
+			status += ",cordoned"
+		}
+		if ag.Reachability == "unreachable" {
+			status += ",unreachable"
+		}
+		t.add(ag.ID, ag.Name, status, ag.Approval, ag.OS+"/"+ag.Arch, ag.Endpoint, ag.LastSeenAt, joinLabels(ag.Labels))
+	}
+	return t
+}
+
+func specTable(items ...restv1.Spec) *table {
+	t := &table{header: []string{"ID", "NAME", "KIND", "VERSION", "TARGETS", "MANAGED BY", "UPDATED"}}
+	for _, ts := range items {
+		t.add(ts.ID, ts.Name, ts.KindType, strconv.Itoa(ts.Version), strconv.Itoa(len(ts.Targets)), ts.ManagedBy, ts.UpdatedAt)
+	}
+	return t
+}
+
+func rolloutTable(rows []rolloutRow) *table {
+	t := &table{header: []string{"SPEC", "AGENT", "STATUS", "DESIRED", "ACTUAL", "ATTEMPTS", "LAST SYNCED", "ERROR"}}
+	for _, r := range rows {
+		t.add(r.SpecName, r.AgentID, r.Status, strconv.Itoa(r.DesiredVersion), strconv.Itoa(r.ActualVersion),
+			strconv.Itoa(r.Attempts), r.LastSyncedAt, r.Error)
+	}
+	return t
+}
+
+func userTable(items ...restv1.User) *table {
+	t := &table{header: []string{"ID", "SUBJECT", "NAME", "EMAIL", "ROLES", "DISABLED"}}
+	for _, u := range items {
+		t.add(u.ID, u.Subject, u.Name, u.Email, strings.Join(u.RoleNames, ","), strconv.FormatBool(u.Disabled))
+	}
+	return t
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/soltiHQ/control-plane/pkg/client"
+)
+
+// runLogs prints the output of a task on an agent. Table output writes raw
+// lines (stderr lines to stderr, with --timestamps prefixed by their time);
+// JSON writes one record per line and YAML one document per record.
+func runLogs(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("logs")
+	follow := fs.Bool("f", false, "keep streaming new lines")
+	tail := fs.Int("tail", 0, "number of recent lines to print first (0 = agent default)")
+	stamps := fs.Bool("timestamps", false, "prefix each line with its time")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 {
+		return errors.New("usage: podiumctl logs AGENT_ID TASK_ID [-f] [--tail N] [--timestamps]")
+	}
+	if _, err = e.printer(); err != nil {
+		return err
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	for ll, err := range c.TaskLogs(ctx, pos[0], pos[1], client.LogOptions{Tail: *tail, Follow: *follow}) {
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		switch e.output {
+		case outputJSON:
+			if err = json.NewEncoder(e.stdout).Encode(ll); err != nil {
+				return err
+			}
+		case outputYAML:
+			fmt.Fprintln(e.stdout, "---")
+			p := &printer{w: e.stdout, format: outputYAML}
+			if err = p.print(ll, nil); err != nil {
+				return err
+			}
+		default:
+			w := e.stdout
+			if ll.Stream == "stderr" {
+				w = e.stderr
+			}
+			if *stamps {
+				fmt.Fprint(w, time.UnixMilli(ll.TS).Local().Format(time.RFC3339Nano), " ")
+			}
+			fmt.Fprintln(w, ll.Line)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Command podiumctl is a command-line client for the control-plane REST API.
+//
+// Usage:
+//
+//	podiumctl [--server URL] [-o table|json|yaml] <command> [args]
+//
+// Commands:
+//
+//	login       authenticate and cache a token pair
+//	logout      revoke the cached session
+//	get         list or show agents, specs, rollouts, runs and users
+//	create      create a spec from a YAML or JSON file
+//	edit        replace a spec from a YAML or JSON file
+//	apply       apply a multi-document manifest
+//	deploy      roll a spec out to its target agents
+//	undeploy    stop rolling a spec out
+//	label       add or remove agent labels
+//	approve     admit agents pending approval
+//	reject      reject agents
+//	cordon      stop new work landing on agents
+//	uncordon    let cordoned agents receive work again
+//	drain       cordon an agent and move its specs away
+//	run         run a one-shot command across agents
+//	logs        print or follow the output of an agent task
+//	events      tail the activity feed
+//	token       create, list and delete agent enrollment tokens
+//
+// Tokens are cached per server in $PODIUM_CREDENTIALS, or
+// <user config dir>/podiumctl/credentials.json, and refreshed automatically.
+// The server defaults to $PODIUM_SERVER, then to the last server logged in to.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/soltiHQ/control-plane/pkg/client"
+)
+
+const usage = `podiumctl is a command-line client for the control plane.
+
+Usage:
+  podiumctl [--server URL] [-o table|json|yaml] <command> [args]
+
+Commands:
+  login [-u SUBJECT] [--password-stdin]     authenticate and cache a token pair
+  logout                                    revoke the cached session
+  get agents|specs|runs|users [-q QUERY]    list resources
+  get agent|spec|run|user ID                show one resource
+  get rollouts [SPEC_ID]                    show rollout state (all specs if omitted)
+  create spec -f FILE                       create a spec from YAML/JSON ("-" for stdin)
+  edit spec ID -f FILE                      replace a spec from YAML/JSON
+  apply -f FILE [--prune] [--dry-run]       apply a multi-document manifest
+  deploy SPEC_ID [--dry-run]                roll a spec out to its targets, or show the plan
+  undeploy SPEC_ID                          stop rolling a spec out
+  label AGENT_ID KEY=VALUE... KEY-...       add, change or remove agent labels
+  approve AGENT_ID...                       admit agents pending approval
+  reject AGENT_ID...                        reject agents; their syncs are refused
+  cordon AGENT_ID...                        keep rollouts but exclude agents from new work
+  uncordon AGENT_ID...                      let cordoned agents receive new work again
+  drain AGENT_ID [--retarget]               cordon an agent and remove its rollouts;
+                                            --retarget rolls its label-selected specs out elsewhere
+  run [-l SELECTOR] [--agent ID,...] [--timeout DURATION] [--wait] -- COMMAND [ARGS...]
+                                            run a one-shot command on matching agents
+  run -f FILE [--wait]                      start a run from YAML/JSON
+  logs AGENT_ID TASK_ID [-f] [--tail N]     print or follow a task's output
+  events [-n COUNT] [--interval DURATION]   tail the activity feed
+  token create NAME [-l SELECTOR] [--ttl DURATION] [--max-uses N]
+                                            create an agent enrollment token (printed once)
+  token list                                list enrollment tokens
+  token delete ID                           delete an enrollment token
+
+Environment:
+  PODIUM_SERVER       default server URL
+  PODIUM_PASSWORD     password for login
+  PODIUM_CREDENTIALS  token cache path
+`
+
+// command runs one podiumctl subcommand with its remaining arguments.
+type command func(ctx context.Context, e *env, args []string) error
+
+var commands = map[string]command{
+	"login":    runLogin,
+	"logout":   runLogout,
+	"get":      runGet,
+	"create":   runCreate,
+	"edit":     runEdit,
+	"apply":    runApply,
+	"deploy":   runDeploy,
+	"undeploy": runUndeploy,
+	"label":    runLabel,
+	"approve":  runApprove,
+	"reject":   runReject,
+	"cordon":   runCordon,
+	"uncordon": runUncordon,
+	"drain":    runDrain,
+	"run":      runRun,
+	"logs":     runLogs,
+	"events":   runEvents,
+	"token":    runToken,
+}
+
+// env holds global options shared by all commands.
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	server string
+	output string
+}
+
+// flags returns a flag set for a command with the global options registered,
+// so they may appear before or after the command name.
+func (e *env) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.server, "server", e.server, "control-plane URL")
+	fs.StringVar(&e.output, "o", e.output, "output format: table, json or yaml")
+	fs.Usage = func() { fmt.Fprint(e.stderr, usage) }
+	return fs
+}
+
+// parse parses flags interleaved with positional arguments and returns the positionals.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return pos, nil
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// client resolves the server and returns an API client for it, resuming the
+// cached session. Refreshed tokens are written back to the cache.
+func (e *env) client() (*client.Client, *credentialStore, error) {
+	store, err := loadCredentials()
+	if err != nil {
+		return nil, nil, err
+	}
+	server := e.server
+	if server == "" {
+		server = os.Getenv("PODIUM_SERVER")
+	}
+	if server == "" {
+		server = store.Current
+	}
+	if server == "" {
+		return nil, nil, errors.New("no server; pass --server or set PODIUM_SERVER")
+	}
+	if !strings.Contains(server, "://") {
+		server = "http://" + server
+	}
+	server = strings.TrimRight(server, "/")
+
+	cfg := client.Config{
+		BaseURL: server,
+		OnTokens: func(t client.Tokens) {
+			if err := store.update(server, "", t); err != nil {
+				fmt.Fprintln(e.stderr, "warning: cannot save credentials:", err)
+			}
+		},
+	}
+	if cr := store.Servers[server]; cr != nil {
+		cfg.Tokens = cr.Tokens
+	}
+	c, err := client.New(cfg)
+	return c, store, err
+}
+
+func (e *env) printer() (*printer, error) {
+	return newPrinter(e.stdout, e.output)
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	e := &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, output: outputTable}
+	if err := run(ctx, e, os.Args[1:]); err != nil {
+		switch {
+		case errors.Is(err, flag.ErrHelp):
+		case errors.Is(err, client.ErrNoTokens):
+			fmt.Fprintln(os.Stderr, "error: not logged in; run: podiumctl login")
+		case errors.Is(err, client.ErrUnauthorized):
+			fmt.Fprintln(os.Stderr, "error: session expired or revoked; run: podiumctl login")
+		default:
+			fmt.Fprintln(os.Stderr, "error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("podiumctl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprint(e.stderr, usage)
+		return flag.ErrHelp
+	}
+
+	name := fs.Arg(0)
+	cmd, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q; run podiumctl -h", name)
+	}
+	return cmd(ctx, e, fs.Args()[1:])
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// runStatusRunning is the run status reported until every target has a final result.
+const runStatusRunning = "running"
+
+// runRun starts an ad-hoc run, either from a run file or from a command after "--".
+// With --wait the run is polled until every target finished; a failed run is an error.
+func runRun(ctx context.Context, e *env, args []string) error {
+	var command []string
+	if i := slices.Index(args, "--"); i >= 0 {
+		args, command = args[:i], args[i+1:]
+	}
+
+	fs := e.flags("run")
+	file := fs.String("f", "", "run file (YAML or JSON), - for stdin")
+	name := fs.String("name", "", "run name (default: the command name)")
+	selector := fs.String("l", "", "target agents by label selector KEY=VALUE,...")
+	agents := fs.String("agent", "", "target agent IDs, comma-separated")
+	timeout := fs.Duration("timeout", 0, "task timeout (default: the server default)")
+	wait := fs.Bool("wait", false, "wait until every target finished and print the results")
+	interval := fs.Duration("interval", 2*time.Second, "poll interval with --wait")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 || (*file == "") == (len(command) == 0) {
+		return errors.New("usage: podiumctl run [-l SELECTOR] [--agent ID,...] [--timeout DURATION] [--wait] -- COMMAND [ARGS...]\n" +
+			"       podiumctl run -f FILE [--wait]")
+	}
+
+	var in restv1.RunCreateRequest
+	if *file != "" {
+		if in, err = readObjectFile[restv1.RunCreateRequest](e, *file, nil); err != nil {
+			return err
+		}
+	} else {
+		in.KindType = "subprocess"
+		in.KindConfig = map[string]any{"command": command[0], "args": command[1:]}
+		in.Name = filepath.Base(command[0])
+	}
+	if *name != "" {
+		in.Name = *name
+	}
+	if *selector != "" {
+		if in.TargetLabels, err = parseSelector(*selector); err != nil {
+			return err
+		}
+	}
+	if *agents != "" {
+		in.Targets = append(in.Targets, strings.Split(*agents, ",")...)
+	}
+	if *timeout > 0 {
+		in.TimeoutMs = timeout.Milliseconds()
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	p, err := e.printer()
+	if err != nil {
+		return err
+	}
+
+	x, err := c.CreateRun(ctx, in)
+	if err != nil {
+		return err
+	}
+	if !*wait {
+		fmt.Fprintf(e.stdout, "run %s started on %d agent(s)\n", x.ID, len(x.Results))
+		return nil
+	}
+
+	for x.Status == runStatusRunning {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(*interval):
+		}
+		if x, err = c.GetRun(ctx, x.ID); err != nil {
+			return err
+		}
+	}
+	if err = p.print(x, func() *table { return runResultTable(*x) }); err != nil {
+		return err
+	}
+	if x.Status != "succeeded" {
+		return fmt.Errorf("run %s %s: %s", x.ID, x.Status, runSummary(x.Summary))
+	}
+	return nil
+}
+
+// parseSelector parses a KEY=VALUE,... label selector.
+func parseSelector(s string) (map[string]string, error) {
+	out := make(map[string]string)
+	for _, kv := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" || v == "" {
+			return nil, fmt.Errorf("invalid selector %q: want KEY=VALUE", kv)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// runSummary renders result counts per status as sorted "status=n" pairs.
+func runSummary(m map[string]int) string {
+	labels := make(map[string]string, len(m))
+	for k, n := range m {
+		labels[k] = strconv.Itoa(n)
+	}
+	return joinLabels(labels)
+}
+
+func runTable(items ...restv1.Run) *table {
+	t := &table{header: []string{"ID", "NAME", "STATUS", "KIND", "TARGETS", "SUMMARY", "CREATED"}}
+	for _, x := range items {
+		t.add(x.ID, x.Name, x.Status, x.KindType, strconv.Itoa(len(x.Results)), runSummary(x.Summary), x.CreatedAt)
+	}
+	return t
+}
+
+func runResultTable(x restv1.Run) *table {
+	t := &table{header: []string{"AGENT", "STATUS", "ATTEMPT", "TASK", "FINISHED", "ERROR"}}
+	for _, res := range x.Results {
+		t.add(res.AgentID, res.Status, strconv.Itoa(res.Attempt), res.TaskID, res.FinishedAt, res.Error)
+	}
+	return t
+}
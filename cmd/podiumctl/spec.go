@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/pkg/client"
+)
+
+// readOnlySpecFields are keys of restv1.Spec that a spec file may carry
+// (e.g. the output of "get spec -o yaml") but that cannot be set.
+var readOnlySpecFields = []string{"id", "version", "created_at", "updated_at", "managed_by", "secrets", "create_spec"}
+
+func runCreate(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("create")
+	file := fs.String("f", "", "spec file (YAML or JSON), - for stdin")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 || pos[0] != "spec" || *file == "" {
+		return errors.New("usage: podiumctl create spec -f FILE")
+	}
+
+	in, err := readSpecFile(e, *file)
+	if err != nil {
+		return err
+	}
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	if err = c.CreateSpec(ctx, in); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "spec %s created\n", in.Name)
+	return nil
+}
+
+func runEdit(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("edit")
+	file := fs.String("f", "", "spec file (YAML or JSON), - for stdin")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 || pos[0] != "spec" || *file == "" {
+		return errors.New("usage: podiumctl edit spec ID -f FILE")
+	}
+
+	in, err := readSpecFile(e, *file)
+	if err != nil {
+		return err
+	}
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	if err = c.UpdateSpec(ctx, pos[1], in); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "spec %s updated\n", pos[1])
+	return nil
+}
+
+func runApply(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("apply")
+	file := fs.String("f", "", "manifest file (multi-document YAML or JSON), - for stdin")
+	prune := fs.Bool("prune", false, "delete specs missing from the manifest")
+	dryRun := fs.Bool("dry-run", false, "report changes without applying them")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("usage: podiumctl apply -f FILE [--prune] [--dry-run]")
+	}
+
+	data, err := readFile(e, *file)
+	if err != nil {
+		return err
+	}
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	p, err := e.printer()
+	if err != nil {
+		return err
+	}
+
+	res, err := c.Apply(ctx, data, client.ApplyOptions{Prune: *prune, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	if err = p.print(res, func() *table {
+		t := &table{header: []string{"KIND", "NAME", "ID", "ACTION", "ERROR"}}
+		for _, it := range res.Items {
+			t.add(it.Kind, it.Name, it.ID, it.Action, it.Error)
+		}
+		return t
+	}); err != nil {
+		return err
+	}
+	if res.Failed > 0 {
+		return fmt.Errorf("%d object(s) failed", res.Failed)
+	}
+	return nil
+}
+
+func runDeploy(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("deploy")
+	dryRun := fs.Bool("dry-run", false, "show the deploy plan without deploying")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if !*dryRun {
+		return specAction(ctx, e, "deploy", pos, (*client.Client).DeploySpec)
+	}
+
+	id, err := oneArg(pos, "spec ID")
+	if err != nil {
+		return err
+	}
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	p, err := e.printer()
+	if err != nil {
+		return err
+	}
+	plan, err := c.SpecPlan(ctx, id)
+	if err != nil {
+		return err
+	}
+	return p.print(plan, func() *table {
+		t := &table{header: []string{"AGENT", "NAME", "SOURCE", "ACTION", "REASON"}}
+		for _, it := range plan.Items {
+			t.add(it.AgentID, it.AgentName, it.Source, it.Action, it.Reason)
+		}
+		return t
+	})
+}
+
+func runUndeploy(ctx context.Context, e *env, args []string) error {
+	return specAction(ctx, e, "undeploy", args, (*client.Client).UndeploySpec)
+}
+
+func specAction(ctx context.Context, e *env, name string, args []string, fn func(*client.Client, context.Context, string) error) error {
+	fs := e.flags(name)
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := oneArg(pos, "spec ID")
+	if err != nil {
+		return err
+	}
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+	if err = fn(c, ctx, id); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.stdout, "spec %s %sed\n", id, name)
+	return nil
+}
+
+// readSpecFile decodes a YAML or JSON spec file into a create request.
+// Read-only fields are dropped; any other unknown field is an error.
+func readSpecFile(e *env, path string) (restv1.SpecCreateRequest, error) {
+	return readObjectFile[restv1.SpecCreateRequest](e, path, readOnlySpecFields)
+}
+
+// readObjectFile decodes a YAML or JSON file into T using its JSON field names.
+// Keys listed in drop are ignored; any other unknown field is an error.
+func readObjectFile[T any](e *env, path string, drop []string) (T, error) {
+	var in T
+
+	data, err := readFile(e, path)
+	if err != nil {
+		return in, err
+	}
+	var raw map[string]any
+	if err = yaml.Unmarshal(data, &raw); err != nil {
+		return in, fmt.Errorf("%s: %w", path, err)
+	}
+	if raw == nil {
+		return in, fmt.Errorf("%s: empty file", path)
+	}
+	for _, k := range drop {
+		delete(raw, k)
+	}
+
+	buf, err := json.Marshal(raw)
+	if err != nil {
+		return in, fmt.Errorf("%s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.DisallowUnknownFields()
+	if err = dec.Decode(&in); err != nil {
+		return in, fmt.Errorf("%s: %w", path, err)
+	}
+	return in, nil
+}
+
+func readFile(e *env, path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(e.stdin)
+	}
+	return os.ReadFile(path)
+}
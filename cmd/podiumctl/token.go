@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/pkg/client"
+)
+
+const tokenUsage = "usage: podiumctl token create NAME [-l SELECTOR] [--ttl DURATION] [--max-uses N] | token list | token delete ID"
+
+// runToken manages agent enrollment tokens.
+func runToken(ctx context.Context, e *env, args []string) error {
+	fs := e.flags("token")
+	labels := fs.String("l", "", "labels applied to enrolled agents, KEY=VALUE,...")
+	ttl := fs.Duration("ttl", 0, "token lifetime (0 never expires)")
+	maxUses := fs.Int("max-uses", 0, "maximum number of enrollments (0 unlimited)")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) == 0 {
+		return errors.New(tokenUsage)
+	}
+
+	c, _, err := e.client()
+	if err != nil {
+		return err
+	}
+
+	switch action, rest := pos[0], pos[1:]; action {
+	case "create":
+		name, err := oneArg(rest, "token name")
+		if err != nil {
+			return err
+		}
+		in := restv1.EnrollmentTokenCreateRequest{
+			Name:       name,
+			TTLSeconds: int64(*ttl / time.Second),
+			MaxUses:    *maxUses,
+		}
+		if *labels != "" {
+			if in.Labels, err = parseSelector(*labels); err != nil {
+				return err
+			}
+		}
+		out, err := c.CreateEnrollmentToken(ctx, in)
+		if err != nil {
+			return err
+		}
+		if e.output != outputTable {
+			p, err := e.printer()
+			if err != nil {
+				return err
+			}
+			return p.print(out, nil)
+		}
+		fmt.Fprintf(e.stdout, "enrollment token %s created; it is shown only once:\n%s\n", out.ID, out.Token)
+		return nil
+	case "list":
+		p, err := e.printer()
+		if err != nil {
+			return err
+		}
+		items, err := client.Collect(c.EnrollmentTokens(ctx, client.ListOptions{}))
+		if err != nil {
+			return err
+		}
+		return p.print(items, func() *table { return tokenTable(items...) })
+	case "delete":
+		id, err := oneArg(rest, "token ID")
+		if err != nil {
+			return err
+		}
+		if err = c.DeleteEnrollmentToken(ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "enrollment token %s deleted\n", id)
+		return nil
+	default:
+		return errors.New(tokenUsage)
+	}
+}
+
+func tokenTable(items ...restv1.EnrollmentToken) *table {
+	t := &table{header: []string{"ID", "NAME", "USES", "EXPIRES", "USABLE", "LABELS"}}
+	for _, x := range items {
+		uses := strconv.Itoa(x.Uses)
+		if x.MaxUses > 0 {
+			uses += "/" + strconv.Itoa(x.MaxUses)
+		}
+		expires := x.ExpiresAt
+		if expires == "" {
+			expires = "never"
+		}
+		t.add(x.ID, x.Name, uses, expires, strconv.FormatBool(x.Usable), joinLabels(x.Labels))
+	}
+	return t
+}
@@ -17,4 +17,30 @@ var (
 	ErrEmptyID = errors.New("id cannot be empty")
 	// ErrUnknownEndpointType indicates an unrecognized endpoint type value.
 	ErrUnknownEndpointType = errors.New("unknown endpoint type")
+	// ErrDependencyCycle indicates that spec dependencies form a cycle.
+	ErrDependencyCycle = errors.New("dependency cycle")
+	// ErrDependencyMissing indicates that a spec depends on a spec that does not exist.
+	ErrDependencyMissing = errors.New("dependency not found")
+	// ErrDependencyInUse indicates that a spec cannot be removed while other specs depend on it.
+	ErrDependencyInUse = errors.New("spec is a dependency of other specs")
+	// ErrInvalidTemplate indicates that a spec template cannot be parsed or rendered.
+	ErrInvalidTemplate = errors.New("invalid template")
+	// ErrInvalidSpec indicates that a spec does not satisfy the agent CreateSpec contract.
+	ErrInvalidSpec = errors.New("invalid spec")
+	// ErrNoTargets indicates that a run resolves to no agents.
+	ErrNoTargets = errors.New("no target agents")
+	// ErrUnknownTarget indicates that a run names an agent that does not exist.
+	ErrUnknownTarget = errors.New("unknown target agent")
+	// ErrIncompatibleAgent indicates that an agent cannot run a spec (e.g. unsupported task kind).
+	ErrIncompatibleAgent = errors.New("incompatible agent")
+	// ErrInvalidManifest indicates that an apply manifest cannot be decoded.
+	ErrInvalidManifest = errors.New("invalid manifest")
+	// ErrEnrollmentExpired indicates that an enrollment token is past its expiry.
+	ErrEnrollmentExpired = errors.New("enrollment token expired")
+	// ErrAgentNotApproved indicates that an agent is pending approval or was rejected.
+	ErrAgentNotApproved = errors.New("agent not approved")
+	// ErrEnrollmentExhausted indicates that an enrollment token reached its use limit.
+	ErrEnrollmentExhausted = errors.New("enrollment token exhausted")
+	// ErrAgentCordoned indicates that the agent was cordoned and takes no new work.
+	ErrAgentCordoned = errors.New("agent cordoned")
 )
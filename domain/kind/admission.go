@@ -10,3 +10,13 @@ const (
 	AdmissionReplace       AdmissionStrategy = "replace"
 	AdmissionQueue         AdmissionStrategy = "queue"
 )
+
+// Valid reports whether a is a known admission strategy.
+func (a AdmissionStrategy) Valid() bool {
+	switch a {
+	case AdmissionDropIfRunning, AdmissionReplace, AdmissionQueue:
+		return true
+	default:
+		return false
+	}
+}
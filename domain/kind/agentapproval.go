@@ -0,0 +1,41 @@
+package kind
+
+// AgentApproval describes whether an operator admitted an agent to receive work.
+//
+// The zero value is approved, so agents enrolled while approval is not required
+// are targetable immediately.
+type AgentApproval uint8
+
+const (
+	AgentApprovalApproved AgentApproval = iota
+	AgentApprovalPending
+	AgentApprovalRejected
+)
+
+// String returns the human-readable approval label.
+func (s AgentApproval) String() string {
+	switch s {
+	case AgentApprovalApproved:
+		return "approved"
+	case AgentApprovalPending:
+		return "pending"
+	case AgentApprovalRejected:
+		return "rejected"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseAgentApproval parses an approval label; ok is false for unknown labels.
+func ParseAgentApproval(s string) (AgentApproval, bool) {
+	switch s {
+	case "approved":
+		return AgentApprovalApproved, true
+	case "pending":
+		return AgentApprovalPending, true
+	case "rejected":
+		return AgentApprovalRejected, true
+	default:
+		return 0, false
+	}
+}
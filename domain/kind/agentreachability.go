@@ -0,0 +1,27 @@
+package kind
+
+// AgentReachability describes whether the control-plane could reach an agent's endpoint.
+//
+// It is set by the active probe of the lifecycle runner, independently of heartbeats.
+// The zero value is unknown: the agent was never probed, or probing is disabled.
+type AgentReachability uint8
+
+const (
+	AgentReachabilityUnknown AgentReachability = iota
+	AgentReachabilityReachable
+	AgentReachabilityUnreachable
+)
+
+// String returns the human-readable reachability label.
+func (r AgentReachability) String() string {
+	switch r {
+	case AgentReachabilityUnknown:
+		return "unknown"
+	case AgentReachabilityReachable:
+		return "reachable"
+	case AgentReachabilityUnreachable:
+		return "unreachable"
+	default:
+		return "unknown"
+	}
+}
@@ -9,3 +9,13 @@ const (
 	JitterEqual        JitterStrategy = "equal"
 	JitterDecorrelated JitterStrategy = "decorrelated"
 )
+
+// Valid reports whether j is a known jitter strategy.
+func (j JitterStrategy) Valid() bool {
+	switch j {
+	case JitterNone, JitterFull, JitterEqual, JitterDecorrelated:
+		return true
+	default:
+		return false
+	}
+}
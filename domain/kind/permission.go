@@ -4,8 +4,16 @@ package kind
 type Permission string
 
 const (
-	AgentsGet  Permission = "agents:get"
-	AgentsEdit Permission = "agents:edit"
+	AgentsGet   Permission = "agents:get"
+	AgentsEdit  Permission = "agents:edit"
+	AgentsTasks Permission = "agents:tasks"
+
+	AgentsApprove Permission = "agents:approve"
+	AgentsDrain   Permission = "agents:drain"
+
+	EnrollmentGet    Permission = "enrollment:get"
+	EnrollmentAdd    Permission = "enrollment:add"
+	EnrollmentDelete Permission = "enrollment:delete"
 
 	UsersGet    Permission = "users:get"
 	UsersAdd    Permission = "users:add"
@@ -17,12 +25,28 @@ const (
 	SpecsEdit   Permission = "taskspecs:edit"
 	SpecsDeploy Permission = "taskspecs:deploy"
 	SpecsDelete Permission = "taskspecs:delete"
+
+	SecretsGet    Permission = "secrets:get"
+	SecretsAdd    Permission = "secrets:add"
+	SecretsEdit   Permission = "secrets:edit"
+	SecretsDelete Permission = "secrets:delete"
+
+	RunsGet    Permission = "runs:get"
+	RunsAdd    Permission = "runs:add"
+	RunsDelete Permission = "runs:delete"
 )
 
 // All contains all declared permissions.
 var All = []Permission{
 	AgentsGet,
 	AgentsEdit,
+	AgentsTasks,
+	AgentsApprove,
+	AgentsDrain,
+
+	EnrollmentGet,
+	EnrollmentAdd,
+	EnrollmentDelete,
 
 	UsersGet,
 	UsersAdd,
@@ -34,4 +58,13 @@ var All = []Permission{
 	SpecsEdit,
 	SpecsDeploy,
 	SpecsDelete,
+
+	SecretsGet,
+	SecretsAdd,
+	SecretsEdit,
+	SecretsDelete,
+
+	RunsGet,
+	RunsAdd,
+	RunsDelete,
 }
@@ -8,3 +8,13 @@ const (
 	RestartAlways    RestartType = "always"
 	RestartOnFailure RestartType = "onFailure"
 )
+
+// Valid reports whether r is a known restart type.
+func (r RestartType) Valid() bool {
+	switch r {
+	case RestartNever, RestartAlways, RestartOnFailure:
+		return true
+	default:
+		return false
+	}
+}
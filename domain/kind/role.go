@@ -11,7 +11,7 @@ type BuiltinRole struct {
 //
 // Numbering scheme: XYZ
 //   - X: reserved (0)
-//   - Y: group (0 = global, 1 = users, 2 = specs, 3 = agents)
+//   - Y: group (0 = global, 1 = users, 2 = specs, 3 = agents, 4 = secrets, 5 = runs)
 //   - Z: level (1 = admin, 2 = editor, 3 = reader)
 const (
 	RoleAdminID  = "001"
@@ -28,6 +28,14 @@ const (
 
 	RoleAgentEditorID = "031"
 	RoleAgentReaderID = "032"
+
+	RoleSecretAdminID  = "041"
+	RoleSecretEditorID = "042"
+	RoleSecretReaderID = "043"
+
+	RoleRunAdminID  = "051"
+	RoleRunEditorID = "052"
+	RoleRunReaderID = "053"
 )
 
 // BuiltinRoles contains all roles the control-plane seeds on first startup.
@@ -35,11 +43,13 @@ var BuiltinRoles = []BuiltinRole{
 	// Global
 	{RoleAdminID, "Admin", All},
 	{RoleEditorID, "Editor", []Permission{
-		AgentsGet, AgentsEdit,
+		AgentsGet, AgentsEdit, AgentsTasks,
 		UsersGet, UsersAdd, UsersEdit,
 		SpecsGet, SpecsAdd, SpecsEdit, SpecsDeploy,
+		SecretsGet,
+		RunsGet, RunsAdd,
 	}},
-	{RoleReaderID, "Reader", []Permission{AgentsGet, UsersGet, SpecsGet}},
+	{RoleReaderID, "Reader", []Permission{AgentsGet, UsersGet, SpecsGet, RunsGet}},
 
 	// Users
 	{RoleUserAdminID, "userAdmin", []Permission{UsersGet, UsersAdd, UsersEdit, UsersDelete}},
@@ -52,6 +62,16 @@ var BuiltinRoles = []BuiltinRole{
 	{RoleSpecReaderID, "specReader", []Permission{SpecsGet}},
 
 	// Agents
-	{RoleAgentEditorID, "agentEditor", []Permission{AgentsGet, AgentsEdit}},
+	{RoleAgentEditorID, "agentEditor", []Permission{AgentsGet, AgentsEdit, AgentsTasks}},
 	{RoleAgentReaderID, "agentReader", []Permission{AgentsGet}},
+
+	// Secrets
+	{RoleSecretAdminID, "secretAdmin", []Permission{SecretsGet, SecretsAdd, SecretsEdit, SecretsDelete}},
+	{RoleSecretEditorID, "secretEditor", []Permission{SecretsGet, SecretsAdd, SecretsEdit}},
+	{RoleSecretReaderID, "secretReader", []Permission{SecretsGet}},
+
+	// Runs
+	{RoleRunAdminID, "runAdmin", []Permission{RunsGet, RunsAdd, RunsDelete}},
+	{RoleRunEditorID, "runEditor", []Permission{RunsGet, RunsAdd}},
+	{RoleRunReaderID, "runReader", []Permission{RunsGet}},
 }
@@ -9,6 +9,7 @@ const (
 	SyncStatusDrift
 	SyncStatusFailed
 	SyncStatusUnknown
+	SyncStatusExcluded
 )
 
 // String returns the human-readable sync status label.
@@ -24,6 +25,8 @@ func (s SyncStatus) String() string {
 		return "failed"
 	case SyncStatusUnknown:
 		return "unknown"
+	case SyncStatusExcluded:
+		return "excluded"
 	default:
 		return "unknown"
 	}
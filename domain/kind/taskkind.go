@@ -9,3 +9,13 @@ const (
 	TaskKindContainer  TaskKindType = "container"
 	TaskKindWasm       TaskKindType = "wasm"
 )
+
+// Valid reports whether k is a known task kind.
+func (k TaskKindType) Valid() bool {
+	switch k {
+	case TaskKindSubprocess, TaskKindContainer, TaskKindWasm:
+		return true
+	default:
+		return false
+	}
+}
@@ -18,6 +18,16 @@ const (
 // Priority returns the sort weight (lower = more important).
 func (s TaskStatus) Priority() int { return int(s) }
 
+// Terminal reports whether the task has finished and will not run again on its own.
+func (s TaskStatus) Terminal() bool {
+	switch s {
+	case TaskStatusFailed, TaskStatusTimeout, TaskStatusExhausted, TaskStatusCanceled, TaskStatusSucceeded:
+		return true
+	default:
+		return false
+	}
+}
+
 // String returns the lowercase status label.
 func (s TaskStatus) String() string {
 	switch s {
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/soltiHQ/control-plane/domain"
@@ -36,9 +37,28 @@ type Agent struct {
 	endpointType kind.EndpointType
 	apiVersion   kind.APIVersion
 	status       kind.AgentStatus
+	approval     kind.AgentApproval
+
+	// cordoned agents keep their rollouts but are excluded from new ones.
+	cordoned bool
+
+	// reachability is the result of the last active probe of the agent endpoint.
+	reachability kind.AgentReachability
+	probedAt     time.Time
+	probeError   string
 
 	metadata map[string]string
 	labels   map[string]string
+
+	capabilities Capabilities
+
+	// pullMode agents are never dialed; they receive desired specs in the sync response.
+	pullMode bool
+
+	// credentialHash is the hash of the per-agent discovery credential issued on enrollment.
+	credentialHash string
+	// certExpiresAt is the expiry of the last client certificate issued to the agent; zero if none.
+	certExpiresAt time.Time
 }
 
 // NewAgent creates a new agent domain entity.
@@ -80,12 +100,15 @@ type AgentParams struct {
 	UptimeSeconds      int64
 	HeartbeatIntervalS int
 
-	Metadata map[string]string
+	Metadata     map[string]string
+	Capabilities Capabilities
+
+	PullMode bool
 }
 
 // NewAgentFrom constructs an Agent from transport-agnostic AgentParams.
 //
-// Performs a defensive copy of Metadata.
+// Performs a defensive copy of Metadata and Capabilities.
 func NewAgentFrom(p AgentParams) (*Agent, error) {
 	if p.ID == "" {
 		return nil, domain.ErrEmptyID
@@ -120,6 +143,9 @@ func NewAgentFrom(p AgentParams) (*Agent, error) {
 
 		uptimeSeconds: p.UptimeSeconds,
 
+		capabilities: p.Capabilities.clone(),
+		pullMode:     p.PullMode,
+
 		status:            kind.AgentStatusActive,
 		lastSeenAt:        now,
 		heartbeatInterval: time.Duration(p.HeartbeatIntervalS) * time.Second,
@@ -153,6 +179,80 @@ func (a *Agent) Arch() string { return a.arch }
 // Platform returns the agent's platform.
 func (a *Agent) Platform() string { return a.platform }
 
+// Capabilities returns a copy of the agent-reported capabilities.
+func (a *Agent) Capabilities() Capabilities { return a.capabilities.clone() }
+
+// PullMode reports whether the agent pulls its specs in the sync response
+// instead of being dialed by the control-plane.
+func (a *Agent) PullMode() bool { return a.pullMode }
+
+// CredentialHash returns the hash of the agent's discovery credential, empty if not enrolled.
+func (a *Agent) CredentialHash() string { return a.credentialHash }
+
+// SetCredentialHash sets the hash of the agent's discovery credential.
+func (a *Agent) SetCredentialHash(h string) { a.credentialHash = h }
+
+// CertExpiresAt returns the expiry of the agent's last issued certificate, zero if none.
+func (a *Agent) CertExpiresAt() time.Time { return a.certExpiresAt }
+
+// SetCertExpiresAt records the expiry of a certificate issued to the agent.
+func (a *Agent) SetCertExpiresAt(t time.Time) { a.certExpiresAt = t }
+
+// Approval returns whether an operator admitted the agent.
+func (a *Agent) Approval() kind.AgentApproval { return a.approval }
+
+// SetApproval sets the agent's approval state.
+func (a *Agent) SetApproval(s kind.AgentApproval) {
+	a.approval = s
+	a.updatedAt = time.Now()
+}
+
+// CheckApproval returns nil if the agent may receive work, or an error matching
+// [domain.ErrAgentNotApproved] if it is pending approval or was rejected.
+func (a *Agent) CheckApproval() error {
+	if a.approval == kind.AgentApprovalApproved {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", domain.ErrAgentNotApproved, a.approval)
+}
+
+// Cordoned reports whether an operator cordoned the agent.
+func (a *Agent) Cordoned() bool { return a.cordoned }
+
+// SetCordoned cordons or uncordons the agent.
+func (a *Agent) SetCordoned(v bool) {
+	a.cordoned = v
+	a.updatedAt = time.Now()
+}
+
+// CheckCordon returns nil if the agent accepts new work, or an error matching
+// [domain.ErrAgentCordoned] if it was cordoned.
+func (a *Agent) CheckCordon() error {
+	if a.cordoned {
+		return domain.ErrAgentCordoned
+	}
+	return nil
+}
+
+// Reachability returns the result of the last active probe of the agent endpoint.
+func (a *Agent) Reachability() kind.AgentReachability { return a.reachability }
+
+// ProbedAt returns when the agent was last probed, zero if never.
+func (a *Agent) ProbedAt() time.Time { return a.probedAt }
+
+// ProbeError returns the error of the last failed probe, empty if it succeeded.
+func (a *Agent) ProbeError() string { return a.probeError }
+
+// SetReachability records the outcome of a probe made at t; errMsg is kept only when unreachable.
+func (a *Agent) SetReachability(r kind.AgentReachability, errMsg string, t time.Time) {
+	if r != kind.AgentReachabilityUnreachable {
+		errMsg = ""
+	}
+	a.reachability = r
+	a.probeError = errMsg
+	a.probedAt = t
+}
+
 // Status returns the agent's lifecycle status.
 func (a *Agent) Status() kind.AgentStatus { return a.status }
 
@@ -217,6 +317,20 @@ func (a *Agent) LabelsAll() map[string]string {
 	return out
 }
 
+// MatchesLabels reports whether the agent has every key=value pair of selector.
+// An empty selector matches no agent.
+func (a *Agent) MatchesLabels(selector map[string]string) bool {
+	if len(selector) == 0 {
+		return false
+	}
+	for k, v := range selector {
+		if got, ok := a.labels[k]; !ok || got != v {
+			return false
+		}
+	}
+	return true
+}
+
 // LabelAdd sets a control-plane owned label on the agent.
 func (a *Agent) LabelAdd(key, value string) {
 	a.labels[key] = value
@@ -259,8 +373,18 @@ func (a *Agent) Clone() *Agent {
 		platform:     a.platform,
 
 		uptimeSeconds: a.uptimeSeconds,
+		capabilities:  a.capabilities.clone(),
+		pullMode:      a.pullMode,
+
+		credentialHash: a.credentialHash,
+		certExpiresAt:  a.certExpiresAt,
 
 		status:            a.status,
+		approval:          a.approval,
+		cordoned:          a.cordoned,
+		reachability:      a.reachability,
+		probedAt:          a.probedAt,
+		probeError:        a.probeError,
 		lastSeenAt:        a.lastSeenAt,
 		heartbeatInterval: a.heartbeatInterval,
 		staleAt:           a.staleAt,
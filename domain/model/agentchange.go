@@ -0,0 +1,161 @@
+package model
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/soltiHQ/control-plane/domain"
+)
+
+var _ domain.Entity[*AgentChange] = (*AgentChange)(nil)
+
+// FieldChange is a single inventory field that differs between two syncs.
+//
+// Old is empty when the field appeared, New is empty when it disappeared.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// AgentChange is an immutable record of the inventory fields an agent changed in one sync.
+//
+// Changes are append-only: once created they are never updated,
+// so UpdatedAt always equals CreatedAt.
+type AgentChange struct {
+	createdAt time.Time
+
+	id      string
+	agentID string
+	fields  []FieldChange
+}
+
+// NewAgentChange creates a change record for agentID with the given field diffs.
+func NewAgentChange(id, agentID string, fields []FieldChange) (*AgentChange, error) {
+	if id == "" || agentID == "" {
+		return nil, domain.ErrEmptyID
+	}
+	if len(fields) == 0 {
+		return nil, domain.ErrFieldEmpty
+	}
+	cp := make([]FieldChange, len(fields))
+	copy(cp, fields)
+
+	return &AgentChange{
+		createdAt: time.Now(),
+		id:        id,
+		agentID:   agentID,
+		fields:    cp,
+	}, nil
+}
+
+func (c *AgentChange) ID() string           { return c.id }
+func (c *AgentChange) AgentID() string      { return c.agentID }
+func (c *AgentChange) CreatedAt() time.Time { return c.createdAt }
+func (c *AgentChange) UpdatedAt() time.Time { return c.createdAt }
+
+// Fields returns a copy of the changed fields, sorted by field name.
+func (c *AgentChange) Fields() []FieldChange {
+	out := make([]FieldChange, len(c.fields))
+	copy(out, c.fields)
+	return out
+}
+
+// FieldNames returns the names of the changed fields.
+func (c *AgentChange) FieldNames() []string {
+	out := make([]string, 0, len(c.fields))
+	for _, f := range c.fields {
+		out = append(out, f.Field)
+	}
+	return out
+}
+
+// Clone creates a deep copy of the AgentChange.
+func (c *AgentChange) Clone() *AgentChange {
+	fields := make([]FieldChange, len(c.fields))
+	copy(fields, c.fields)
+
+	return &AgentChange{
+		createdAt: c.createdAt,
+		id:        c.id,
+		agentID:   c.agentID,
+		fields:    fields,
+	}
+}
+
+// DiffInventory returns the inventory fields that differ between old and cur, sorted by field name.
+//
+// Only what the agent reports about itself is compared: identity, endpoint, platform,
+// metadata and capabilities. Runtime state (status, uptime, last seen) and fields owned
+// by the control plane (labels, approval, credentials) are not part of the inventory.
+func DiffInventory(old, cur *Agent) []FieldChange {
+	if old == nil || cur == nil {
+		return nil
+	}
+	a, b := inventoryOf(old), inventoryOf(cur)
+
+	var out []FieldChange
+	for field, ov := range a {
+		if nv := b[field]; nv != ov {
+			out = append(out, FieldChange{Field: field, Old: ov, New: nv})
+		}
+	}
+	for field, nv := range b {
+		if _, ok := a[field]; !ok {
+			out = append(out, FieldChange{Field: field, New: nv})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out
+}
+
+// inventoryOf flattens the inventory of a into field → value.
+// Empty values are omitted so a field that appears or disappears diffs against "".
+func inventoryOf(a *Agent) map[string]string {
+	out := make(map[string]string)
+	set := func(field, value string) {
+		if value != "" {
+			out[field] = value
+		}
+	}
+
+	set("name", a.Name())
+	set("endpoint", a.Endpoint())
+	set("endpoint_type", string(a.EndpointType()))
+	set("api_version", a.APIVersion().String())
+	set("os", a.OS())
+	set("arch", a.Arch())
+	set("platform", a.Platform())
+	set("pull_mode", strconv.FormatBool(a.PullMode()))
+	if d := a.HeartbeatInterval(); d > 0 {
+		set("heartbeat_interval", d.String())
+	}
+	for k, v := range a.MetadataAll() {
+		set("metadata."+k, v)
+	}
+
+	caps := a.Capabilities()
+	if len(caps.TaskKinds) > 0 {
+		kinds := make([]string, 0, len(caps.TaskKinds))
+		for _, k := range caps.TaskKinds {
+			kinds = append(kinds, string(k))
+		}
+		sort.Strings(kinds)
+		set("capabilities.task_kinds", strings.Join(kinds, ","))
+	}
+	for name, version := range caps.Runtimes {
+		set("capabilities.runtimes."+name, version)
+	}
+	if l := caps.Limits; l.CPUMillicores > 0 {
+		set("capabilities.limits.cpu_millicores", strconv.FormatInt(l.CPUMillicores, 10))
+	}
+	if l := caps.Limits; l.MemoryBytes > 0 {
+		set("capabilities.limits.memory_bytes", strconv.FormatInt(l.MemoryBytes, 10))
+	}
+	if l := caps.Limits; l.MaxTasks > 0 {
+		set("capabilities.limits.max_tasks", strconv.Itoa(l.MaxTasks))
+	}
+	return out
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+)
+
+func TestDiffInventory(t *testing.T) {
+	t.Parallel()
+
+	base := func() AgentParams {
+		return AgentParams{
+			ID:                 "a1",
+			Name:               "web-1",
+			Endpoint:           "http://web-1:8080",
+			EndpointType:       1,
+			APIVersion:         1,
+			OS:                 "ubuntu",
+			Arch:               "amd64",
+			UptimeSeconds:      10,
+			HeartbeatIntervalS: 30,
+			Metadata:           map[string]string{"kernel": "6.1", "hostname": "web-1"},
+			Capabilities: Capabilities{
+				TaskKinds: []kind.TaskKindType{kind.TaskKindSubprocess, kind.TaskKindContainer},
+				Runtimes:  map[string]string{"docker": "24.0.7"},
+				Limits:    ResourceLimits{CPUMillicores: 2000, MemoryBytes: 1 << 30},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(p *AgentParams)
+		want   []FieldChange
+	}{
+		{name: "nothing changed", change: func(p *AgentParams) {}},
+		{
+			name:   "runtime state is ignored",
+			change: func(p *AgentParams) { p.UptimeSeconds = 999 },
+		},
+		{
+			name: "task kind order is ignored",
+			change: func(p *AgentParams) {
+				p.Capabilities.TaskKinds = []kind.TaskKindType{kind.TaskKindContainer, kind.TaskKindSubprocess}
+			},
+		},
+		{
+			name:   "identity fields",
+			change: func(p *AgentParams) { p.Name, p.OS, p.Platform = "web-2", "debian", "linux" },
+			want: []FieldChange{
+				{Field: "name", Old: "web-1", New: "web-2"},
+				{Field: "os", Old: "ubuntu", New: "debian"},
+				{Field: "platform", New: "linux"},
+			},
+		},
+		{
+			name:   "heartbeat and pull mode",
+			change: func(p *AgentParams) { p.HeartbeatIntervalS, p.PullMode = 0, true },
+			want: []FieldChange{
+				{Field: "heartbeat_interval", Old: "30s"},
+				{Field: "pull_mode", Old: "false", New: "true"},
+			},
+		},
+		{
+			name: "metadata appears, disappears and changes",
+			change: func(p *AgentParams) {
+				p.Metadata = map[string]string{"kernel": "6.2", "rack": "r1"}
+			},
+			want: []FieldChange{
+				{Field: "metadata.hostname", Old: "web-1"},
+				{Field: "metadata.kernel", Old: "6.1", New: "6.2"},
+				{Field: "metadata.rack", New: "r1"},
+			},
+		},
+		{
+			name:   "empty metadata value reads as removed",
+			change: func(p *AgentParams) { p.Metadata["hostname"] = "" },
+			want:   []FieldChange{{Field: "metadata.hostname", Old: "web-1"}},
+		},
+		{
+			name: "capabilities",
+			change: func(p *AgentParams) {
+				p.Capabilities.TaskKinds = []kind.TaskKindType{kind.TaskKindWasm}
+				p.Capabilities.Runtimes = map[string]string{"docker": "25.0.0", "wasmtime": "20"}
+			},
+			want: []FieldChange{
+				{Field: "capabilities.runtimes.docker", Old: "24.0.7", New: "25.0.0"},
+				{Field: "capabilities.runtimes.wasmtime", New: "20"},
+				{Field: "capabilities.task_kinds", Old: "container,subprocess", New: "wasm"},
+			},
+		},
+		{
+			name:   "capabilities disappear",
+			change: func(p *AgentParams) { p.Capabilities = Capabilities{} },
+			want: []FieldChange{
+				{Field: "capabilities.limits.cpu_millicores", Old: "2000"},
+				{Field: "capabilities.limits.memory_bytes", Old: "1073741824"},
+				{Field: "capabilities.runtimes.docker", Old: "24.0.7"},
+				{Field: "capabilities.task_kinds", Old: "container,subprocess"},
+			},
+		},
+		{
+			name: "limits appear and change",
+			change: func(p *AgentParams) {
+				p.Capabilities.Limits = ResourceLimits{CPUMillicores: 4000, MemoryBytes: 1 << 30, MaxTasks: 8}
+			},
+			want: []FieldChange{
+				{Field: "capabilities.limits.cpu_millicores", Old: "2000", New: "4000"},
+				{Field: "capabilities.limits.max_tasks", New: "8"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldP, curP := base(), base()
+			tt.change(&curP)
+
+			old, err := NewAgentFrom(oldP)
+			if err != nil {
+				t.Fatalf("new agent: %v", err)
+			}
+			cur, err := NewAgentFrom(curP)
+			if err != nil {
+				t.Fatalf("new agent: %v", err)
+			}
+
+			got := DiffInventory(old, cur)
+			if len(got) != 0 || len(tt.want) != 0 {
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("DiffInventory =\n  %+v\nwant\n  %+v", got, tt.want)
+				}
+			}
+		})
+	}
+
+	a, err := NewAgentFrom(base())
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	if got := DiffInventory(nil, a); got != nil {
+		t.Fatalf("DiffInventory without a previous state = %v, want nil", got)
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import "github.com/soltiHQ/control-plane/domain/kind"
+
+// ResourceLimits are the limits an agent enforces for its tasks.
+// Zero values mean unlimited or not reported.
+type ResourceLimits struct {
+	CPUMillicores int64
+	MemoryBytes   int64
+	MaxTasks      int
+}
+
+// Capabilities describes what an agent reports it can run.
+//
+// An agent that reports no task kinds is assumed to support every kind,
+// so agents predating capability reporting stay targetable.
+type Capabilities struct {
+	Limits ResourceLimits
+
+	TaskKinds []kind.TaskKindType
+	Runtimes  map[string]string // runtime name → version (e.g. "docker": "24.0.7")
+}
+
+// Reported reports whether the agent sent any capability data.
+func (c Capabilities) Reported() bool {
+	return len(c.TaskKinds) > 0 || len(c.Runtimes) > 0 || c.Limits != (ResourceLimits{})
+}
+
+// SupportsKind reports whether the agent can run tasks of kind k.
+func (c Capabilities) SupportsKind(k kind.TaskKindType) bool {
+	if len(c.TaskKinds) == 0 {
+		return true
+	}
+	for _, tk := range c.TaskKinds {
+		if tk == k {
+			return true
+		}
+	}
+	return false
+}
+
+// clone returns a deep copy of the capabilities.
+func (c Capabilities) clone() Capabilities {
+	out := Capabilities{Limits: c.Limits}
+	if len(c.TaskKinds) > 0 {
+		out.TaskKinds = make([]kind.TaskKindType, len(c.TaskKinds))
+		copy(out.TaskKinds, c.TaskKinds)
+	}
+	if len(c.Runtimes) > 0 {
+		out.Runtimes = make(map[string]string, len(c.Runtimes))
+		for k, v := range c.Runtimes {
+			out.Runtimes[k] = v
+		}
+	}
+	return out
+}
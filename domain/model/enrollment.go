@@ -0,0 +1,163 @@
+package model
+
+import (
+	"time"
+
+	"github.com/soltiHQ/control-plane/domain"
+)
+
+var _ domain.Entity[*EnrollmentToken] = (*EnrollmentToken)(nil)
+
+// EnrollmentToken authorizes agents to register with the control-plane.
+//
+// An agent presents the token on its first discovery sync and receives a
+// per-agent credential in exchange; every later sync must carry that credential.
+// Labels of the token are applied to each agent enrolled with it.
+//
+// Notes:
+//   - Only a hash of the token value is stored; the raw value is shown once on creation.
+//   - A zero expiresAt never expires; a zero maxUses allows unlimited enrollments.
+type EnrollmentToken struct {
+	createdAt  time.Time
+	updatedAt  time.Time
+	expiresAt  time.Time
+	lastUsedAt time.Time
+
+	id   string
+	name string
+	hash string
+
+	labels map[string]string
+
+	maxUses int
+	uses    int
+}
+
+// NewEnrollmentToken creates a new enrollment token entity from the hash of its value.
+func NewEnrollmentToken(id, name, hash string) (*EnrollmentToken, error) {
+	if id == "" {
+		return nil, domain.ErrEmptyID
+	}
+	if name == "" {
+		return nil, domain.ErrEmptyName
+	}
+	if hash == "" {
+		return nil, domain.ErrFieldEmpty
+	}
+
+	now := time.Now()
+	return &EnrollmentToken{
+		createdAt: now,
+		updatedAt: now,
+		id:        id,
+		name:      name,
+		hash:      hash,
+		labels:    make(map[string]string),
+	}, nil
+}
+
+// ID returns the unique identifier of the token.
+func (t *EnrollmentToken) ID() string { return t.id }
+
+// Name returns the human-readable token name.
+func (t *EnrollmentToken) Name() string { return t.name }
+
+// Hash returns the stored hash of the token value.
+func (t *EnrollmentToken) Hash() string { return t.hash }
+
+// ExpiresAt returns the expiry time; zero means the token never expires.
+func (t *EnrollmentToken) ExpiresAt() time.Time { return t.expiresAt }
+
+// MaxUses returns the enrollment limit; zero means unlimited.
+func (t *EnrollmentToken) MaxUses() int { return t.maxUses }
+
+// Uses returns how many agents enrolled with the token.
+func (t *EnrollmentToken) Uses() int { return t.uses }
+
+// LastUsedAt returns the time of the last enrollment, zero if never used.
+func (t *EnrollmentToken) LastUsedAt() time.Time { return t.lastUsedAt }
+
+// CreatedAt returns the timestamp when the token was created.
+func (t *EnrollmentToken) CreatedAt() time.Time { return t.createdAt }
+
+// UpdatedAt returns the timestamp of the last modification.
+func (t *EnrollmentToken) UpdatedAt() time.Time { return t.updatedAt }
+
+// LabelsAll returns a copy of the labels applied to enrolled agents.
+func (t *EnrollmentToken) LabelsAll() map[string]string {
+	out := make(map[string]string, len(t.labels))
+	for k, v := range t.labels {
+		out[k] = v
+	}
+	return out
+}
+
+// SetLabels replaces the labels applied to enrolled agents.
+func (t *EnrollmentToken) SetLabels(labels map[string]string) {
+	t.labels = make(map[string]string, len(labels))
+	for k, v := range labels {
+		if k == "" || v == "" {
+			continue
+		}
+		t.labels[k] = v
+	}
+	t.updatedAt = time.Now()
+}
+
+// SetExpiresAt sets the expiry time; zero disables expiry.
+func (t *EnrollmentToken) SetExpiresAt(at time.Time) {
+	t.expiresAt = at
+	t.updatedAt = time.Now()
+}
+
+// SetMaxUses sets the enrollment limit; zero or less means unlimited.
+func (t *EnrollmentToken) SetMaxUses(n int) {
+	t.maxUses = max(n, 0)
+	t.updatedAt = time.Now()
+}
+
+// Expired reports whether the token has expired at now.
+func (t *EnrollmentToken) Expired(now time.Time) bool {
+	return !t.expiresAt.IsZero() && !now.Before(t.expiresAt)
+}
+
+// Exhausted reports whether the token reached its enrollment limit.
+func (t *EnrollmentToken) Exhausted() bool {
+	return t.maxUses > 0 && t.uses >= t.maxUses
+}
+
+// Use checks that the token can still enroll an agent at now and records the use.
+//
+// Returns domain.ErrEnrollmentExpired or domain.ErrEnrollmentExhausted otherwise.
+func (t *EnrollmentToken) Use(now time.Time) error {
+	if t.Expired(now) {
+		return domain.ErrEnrollmentExpired
+	}
+	if t.Exhausted() {
+		return domain.ErrEnrollmentExhausted
+	}
+	t.uses++
+	t.lastUsedAt = now
+	t.updatedAt = now
+	return nil
+}
+
+// Clone creates a deep copy of the token.
+func (t *EnrollmentToken) Clone() *EnrollmentToken {
+	labels := make(map[string]string, len(t.labels))
+	for k, v := range t.labels {
+		labels[k] = v
+	}
+	return &EnrollmentToken{
+		createdAt:  t.createdAt,
+		updatedAt:  t.updatedAt,
+		expiresAt:  t.expiresAt,
+		lastUsedAt: t.lastUsedAt,
+		id:         t.id,
+		name:       t.name,
+		hash:       t.hash,
+		labels:     labels,
+		maxUses:    t.maxUses,
+		uses:       t.uses,
+	}
+}
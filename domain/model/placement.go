@@ -0,0 +1,295 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/soltiHQ/control-plane/domain"
+)
+
+// ConstraintOp is the comparison a placement constraint applies to an agent field.
+type ConstraintOp string
+
+const (
+	ConstraintEq        ConstraintOp = "="
+	ConstraintNotEq     ConstraintOp = "!="
+	ConstraintIn        ConstraintOp = "in"
+	ConstraintNotIn     ConstraintOp = "notin"
+	ConstraintExists    ConstraintOp = "exists"
+	ConstraintNotExists ConstraintOp = "!exists"
+	ConstraintGt        ConstraintOp = ">"
+	ConstraintGte       ConstraintOp = ">="
+	ConstraintLt        ConstraintOp = "<"
+	ConstraintLte       ConstraintOp = "<="
+)
+
+// Constraint is a parsed placement predicate evaluated against an agent.
+//
+// Key is one of os, arch, platform, name, endpoint_type, or a
+// "metadata.<key>" / "labels.<key>" reference.
+// Ordered operators (>, >=, <, <=) compare dotted version numbers ("6.1.12-generic" > "6.0").
+type Constraint struct {
+	Key    string
+	Op     ConstraintOp
+	Values []string
+}
+
+var (
+	constraintSetRe = regexp.MustCompile(`^(\S+)\s+(in|notin)\s*\((.*)\)$`)
+	constraintCmpRe = regexp.MustCompile(`^([^\s=!<>]+)\s*(==|=|!=|>=|<=|>|<)\s*(.+)$`)
+	constraintKeyRe = regexp.MustCompile(`^[^\s=!<>(),]+$`)
+	versionRe       = regexp.MustCompile(`^\d+(\.\d+)*`)
+)
+
+// ParseConstraint parses a placement expression:
+//
+//	os in (ubuntu,debian)     os notin (alpine)
+//	arch=arm64                arch != amd64
+//	metadata.kernel >= 6.0
+//	labels.gpu                !labels.gpu       (exists / not exists)
+func ParseConstraint(expr string) (Constraint, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return Constraint{}, errors.New("is empty")
+	}
+
+	var c Constraint
+	switch m := constraintSetRe.FindStringSubmatch(expr); {
+	case m != nil:
+		c = Constraint{Key: m[1], Op: ConstraintOp(m[2])}
+		for _, v := range strings.Split(m[3], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				c.Values = append(c.Values, v)
+			}
+		}
+		if len(c.Values) == 0 {
+			return Constraint{}, fmt.Errorf("%s needs at least one value", c.Op)
+		}
+	case strings.HasPrefix(expr, "!"):
+		c = Constraint{Key: strings.TrimSpace(expr[1:]), Op: ConstraintNotExists}
+	default:
+		if m = constraintCmpRe.FindStringSubmatch(expr); m != nil {
+			op := ConstraintOp(m[2])
+			if op == "==" {
+				op = ConstraintEq
+			}
+			c = Constraint{Key: m[1], Op: op, Values: []string{strings.TrimSpace(m[3])}}
+		} else {
+			c = Constraint{Key: expr, Op: ConstraintExists}
+		}
+	}
+
+	if err := checkConstraintKey(c.Key); err != nil {
+		return Constraint{}, err
+	}
+	if c.ordered() {
+		if _, ok := parseVersion(c.Values[0]); !ok {
+			return Constraint{}, fmt.Errorf("%s needs a numeric version, got %q", c.Op, c.Values[0])
+		}
+	}
+	return c, nil
+}
+
+// String returns the canonical expression of c.
+func (c Constraint) String() string {
+	switch c.Op {
+	case ConstraintExists:
+		return c.Key
+	case ConstraintNotExists:
+		return "!" + c.Key
+	case ConstraintIn, ConstraintNotIn:
+		return fmt.Sprintf("%s %s (%s)", c.Key, c.Op, strings.Join(c.Values, ","))
+	default:
+		return c.Key + " " + string(c.Op) + " " + c.Values[0]
+	}
+}
+
+// Match reports whether agent a satisfies c.
+func (c Constraint) Match(a *Agent) bool {
+	v, ok := agentField(a, c.Key)
+	switch c.Op {
+	case ConstraintExists:
+		return ok
+	case ConstraintNotExists:
+		return !ok
+	case ConstraintEq:
+		return ok && v == c.Values[0]
+	case ConstraintNotEq:
+		return !ok || v != c.Values[0]
+	case ConstraintIn:
+		return ok && contains(c.Values, v)
+	case ConstraintNotIn:
+		return !ok || !contains(c.Values, v)
+	}
+
+	if !ok {
+		return false
+	}
+	got, ok := parseVersion(v)
+	if !ok {
+		return false
+	}
+	want, _ := parseVersion(c.Values[0])
+	cmp := compareVersions(got, want)
+	switch c.Op {
+	case ConstraintGt:
+		return cmp > 0
+	case ConstraintGte:
+		return cmp >= 0
+	case ConstraintLt:
+		return cmp < 0
+	case ConstraintLte:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+// Constraints parses the placement expressions of ts.
+func (ts *Spec) Constraints() ([]Constraint, error) {
+	out := make([]Constraint, 0, len(ts.placement))
+	for _, expr := range ts.placement {
+		c, err := ParseConstraint(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", expr, err)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+// CheckPlacement reports why ts cannot run on a, or nil when it can:
+// the agent must support the spec's task kind and satisfy every placement constraint.
+// The returned error matches [domain.ErrIncompatibleAgent] and lists every violation.
+func (ts *Spec) CheckPlacement(a *Agent) error {
+	var reasons []string
+
+	if !a.capabilities.SupportsKind(ts.kindType) {
+		kinds := make([]string, len(a.capabilities.TaskKinds))
+		for i, k := range a.capabilities.TaskKinds {
+			kinds[i] = string(k)
+		}
+		sort.Strings(kinds)
+		reasons = append(reasons, fmt.Sprintf("task kind %s not supported (agent supports %s)",
+			ts.kindType, strings.Join(kinds, ", ")))
+	}
+
+	constraints, err := ts.Constraints()
+	if err != nil {
+		reasons = append(reasons, "invalid placement "+err.Error())
+	}
+	for _, c := range constraints {
+		if c.Match(a) {
+			continue
+		}
+		got := c.Key + " not set"
+		if v, ok := agentField(a, c.Key); ok {
+			got = c.Key + "=" + v
+		}
+		reasons = append(reasons, fmt.Sprintf("constraint %s not met (%s)", c, got))
+	}
+
+	if len(reasons) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", domain.ErrIncompatibleAgent, strings.Join(reasons, "; "))
+}
+
+func (c Constraint) ordered() bool {
+	switch c.Op {
+	case ConstraintGt, ConstraintGte, ConstraintLt, ConstraintLte:
+		return true
+	default:
+		return false
+	}
+}
+
+func checkConstraintKey(key string) error {
+	if !constraintKeyRe.MatchString(key) {
+		return fmt.Errorf("invalid key %q", key)
+	}
+	switch key {
+	case "os", "arch", "platform", "name", "endpoint_type":
+		return nil
+	}
+	for _, prefix := range []string{"metadata.", "labels."} {
+		if k, ok := strings.CutPrefix(key, prefix); ok && k != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown key %q (use os, arch, platform, name, endpoint_type, metadata.<key> or labels.<key>)", key)
+}
+
+// agentField returns the agent value a constraint key refers to; ok is false when it is unset.
+func agentField(a *Agent, key string) (string, bool) {
+	switch key {
+	case "os":
+		return a.os, a.os != ""
+	case "arch":
+		return a.arch, a.arch != ""
+	case "platform":
+		return a.platform, a.platform != ""
+	case "name":
+		return a.name, a.name != ""
+	case "endpoint_type":
+		return string(a.endpointType), a.endpointType != ""
+	}
+	if k, ok := strings.CutPrefix(key, "metadata."); ok {
+		return a.Metadata(k)
+	}
+	if k, ok := strings.CutPrefix(key, "labels."); ok {
+		return a.Label(k)
+	}
+	return "", false
+}
+
+// parseVersion extracts the leading dotted number of s ("6.1.12-generic" → [6 1 12]).
+func parseVersion(s string) ([]int, bool) {
+	m := versionRe.FindString(strings.TrimPrefix(strings.TrimSpace(s), "v"))
+	if m == "" {
+		return nil, false
+	}
+	parts := strings.Split(m, ".")
+	out := make([]int, len(parts))
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		out[i] = n
+	}
+	return out, true
+}
+
+// compareVersions compares dotted versions component-wise; missing components count as 0.
+func compareVersions(a, b []int) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func contains(xs []string, v string) bool {
+	for _, x := range xs {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+)
+
+func TestParseConstraint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr   string
+		key    string
+		op     ConstraintOp
+		values []string
+		err    string // substring; empty means the expression is valid
+	}{
+		{expr: "os in (ubuntu,debian)", key: "os", op: ConstraintIn, values: []string{"ubuntu", "debian"}},
+		{expr: "os in(ubuntu)", key: "os", op: ConstraintIn, values: []string{"ubuntu"}},
+		{expr: "os notin ( alpine , , arch )", key: "os", op: ConstraintNotIn, values: []string{"alpine", "arch"}},
+		{expr: "os in ()", err: "needs at least one value"},
+		{expr: "os in ( , )", err: "needs at least one value"},
+		{expr: "arch=arm64", key: "arch", op: ConstraintEq, values: []string{"arm64"}},
+		{expr: "arch == arm64", key: "arch", op: ConstraintEq, values: []string{"arm64"}},
+		{expr: "arch != amd64", key: "arch", op: ConstraintNotEq, values: []string{"amd64"}},
+		{expr: "name = web 1", key: "name", op: ConstraintEq, values: []string{"web 1"}},
+		{expr: "metadata.kernel >= 6.0", key: "metadata.kernel", op: ConstraintGte, values: []string{"6.0"}},
+		{expr: "metadata.kernel>6", key: "metadata.kernel", op: ConstraintGt, values: []string{"6"}},
+		{expr: "metadata.kernel < v6", key: "metadata.kernel", op: ConstraintLt, values: []string{"v6"}},
+		{expr: "metadata.kernel <= 6.1.12-generic", key: "metadata.kernel", op: ConstraintLte, values: []string{"6.1.12-generic"}},
+		{expr: "metadata.kernel >= new", err: "needs a numeric version"},
+		{expr: "labels.gpu", key: "labels.gpu", op: ConstraintExists},
+		{expr: "  labels.gpu  ", key: "labels.gpu", op: ConstraintExists},
+		{expr: "!labels.gpu", key: "labels.gpu", op: ConstraintNotExists},
+		{expr: "! labels.gpu", key: "labels.gpu", op: ConstraintNotExists},
+		{expr: "platform", key: "platform", op: ConstraintExists},
+		{expr: "endpoint_type = grpc", key: "endpoint_type", op: ConstraintEq, values: []string{"grpc"}},
+		{expr: "", err: "is empty"},
+		{expr: "   ", err: "is empty"},
+		{expr: "!", err: "invalid key"},
+		{expr: "!!labels.gpu", err: "invalid key"},
+		{expr: "colour = red", err: "unknown key"},
+		{expr: "kernel", err: "unknown key"},
+		{expr: "metadata. = x", err: "unknown key"},
+		{expr: "labels.", err: "unknown key"},
+		{expr: "os in (ubuntu", err: "invalid key"},
+		{expr: "=ubuntu", err: "invalid key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseConstraint(tt.expr)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseConstraint(%q) error = %v, want containing %q", tt.expr, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q): %v", tt.expr, err)
+			}
+			if c.Key != tt.key || c.Op != tt.op || !slices.Equal(c.Values, tt.values) {
+				t.Fatalf("ParseConstraint(%q) = %+v, want {%s %s %v}", tt.expr, c, tt.key, tt.op, tt.values)
+			}
+
+			// The canonical form parses back to the same constraint.
+			again, err := ParseConstraint(c.String())
+			if err != nil || again.Key != c.Key || again.Op != c.Op || !slices.Equal(again.Values, c.Values) {
+				t.Fatalf("ParseConstraint(%q) = %+v, %v; want %+v", c.String(), again, err, c)
+			}
+		})
+	}
+}
+
+func TestConstraint_Match(t *testing.T) {
+	t.Parallel()
+
+	a, err := NewAgentFrom(AgentParams{
+		ID:           "a1",
+		Name:         "web-1",
+		EndpointType: 1,
+		APIVersion:   1,
+		OS:           "ubuntu",
+		Arch:         "arm64",
+		Metadata:     map[string]string{"kernel": "6.1.12-generic", "release": "v6", "flavor": "generic"},
+	})
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	a.LabelAdd("gpu", "a100")
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "os = ubuntu", want: true},
+		{expr: "os = debian"},
+		{expr: "os != debian", want: true},
+		{expr: "os != ubuntu"},
+		{expr: "platform != linux", want: true}, // unset fields differ from every value
+		{expr: "platform = linux"},
+		{expr: "os in (debian,ubuntu)", want: true},
+		{expr: "os in (debian,alpine)"},
+		{expr: "os notin (alpine)", want: true},
+		{expr: "os notin (alpine,ubuntu)"},
+		{expr: "platform notin (linux)", want: true},
+		{expr: "platform in (linux)"},
+		{expr: "labels.gpu", want: true},
+		{expr: "labels.gpu = a100", want: true},
+		{expr: "!labels.gpu"},
+		{expr: "labels.ssd"},
+		{expr: "!labels.ssd", want: true},
+		{expr: "name = web-1", want: true},
+		{expr: "endpoint_type = http", want: true},
+		{expr: "metadata.kernel > 6", want: true},
+		{expr: "metadata.kernel >= 6.1.12", want: true},
+		{expr: "metadata.kernel > 6.1.12"},
+		{expr: "metadata.kernel <= 6.1.12", want: true},
+		{expr: "metadata.kernel < 6.2", want: true},
+		{expr: "metadata.kernel < 6.1.2"},
+		{expr: "metadata.kernel >= 6.1.12-rc1", want: true},
+		{expr: "metadata.release >= 6.0.0", want: true}, // "v6" reads as 6
+		{expr: "metadata.release < 6.0.1", want: true},
+		{expr: "metadata.release > 5.99", want: true},
+		{expr: "metadata.flavor > 1"}, // not a version
+		{expr: "metadata.missing < 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			c, err := ParseConstraint(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q): %v", tt.expr, err)
+			}
+			if got := c.Match(a); got != tt.want {
+				t.Fatalf("%s matched = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want []int
+		ok   bool
+	}{
+		{in: "6.1.12-generic", want: []int{6, 1, 12}, ok: true},
+		{in: "v6", want: []int{6}, ok: true},
+		{in: " 6.0 ", want: []int{6, 0}, ok: true},
+		{in: "6.", want: []int{6}, ok: true},
+		{in: "6.x", want: []int{6}, ok: true},
+		{in: "10.04.1", want: []int{10, 4, 1}, ok: true},
+		{in: ""},
+		{in: "v"},
+		{in: "generic"},
+		{in: "-6"},
+		{in: "99999999999999999999"},
+	}
+	for _, tt := range tests {
+		got, ok := parseVersion(tt.in)
+		if ok != tt.ok || !slices.Equal(got, tt.want) {
+			t.Errorf("parseVersion(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b []int
+		want int
+	}{
+		{a: []int{6, 1, 12}, b: []int{6}, want: 1},
+		{a: []int{6}, b: []int{6, 0, 0}, want: 0},
+		{a: []int{6, 0, 1}, b: []int{6}, want: 1},
+		{a: []int{5, 10}, b: []int{6}, want: -1},
+		{a: []int{6, 10}, b: []int{6, 9}, want: 1},
+		{a: []int{6, 9}, b: []int{6, 10}, want: -1},
+		{a: nil, b: []int{0}, want: 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSpec_CheckPlacement(t *testing.T) {
+	t.Parallel()
+
+	a, err := NewAgentFrom(AgentParams{
+		ID:           "a1",
+		EndpointType: 1,
+		APIVersion:   1,
+		OS:           "ubuntu",
+		Capabilities: Capabilities{TaskKinds: []kind.TaskKindType{kind.TaskKindSubprocess}},
+	})
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	ts, err := NewSpec("s1", "s1", "s1")
+	if err != nil {
+		t.Fatalf("new spec: %v", err)
+	}
+
+	ts.SetPlacement([]string{"os = ubuntu"})
+	if err = ts.CheckPlacement(a); err != nil {
+		t.Fatalf("expected placement to match: %v", err)
+	}
+
+	// Every violated constraint is listed.
+	ts.SetPlacement([]string{"os = debian", "arch = arm64"})
+	err = ts.CheckPlacement(a)
+	if !errors.Is(err, domain.ErrIncompatibleAgent) {
+		t.Fatalf("expected ErrIncompatibleAgent, got %v", err)
+	}
+	for _, want := range []string{"os = debian not met (os=ubuntu)", "arch = arm64 not met (arch not set)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %q", err, want)
+		}
+	}
+
+	// So are an unsupported task kind and an invalid expression.
+	ts.SetKindType(kind.TaskKindWasm)
+	ts.SetPlacement([]string{"colour = red"})
+	err = ts.CheckPlacement(a)
+	if !errors.Is(err, domain.ErrIncompatibleAgent) {
+		t.Fatalf("expected ErrIncompatibleAgent, got %v", err)
+	}
+	for _, want := range []string{"not supported (agent supports subprocess)", `invalid placement "colour = red": unknown key`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not mention %q", err, want)
+		}
+	}
+}
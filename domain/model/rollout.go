@@ -124,6 +124,14 @@ func (ss *Rollout) MarkFailed(errMsg string) {
 	ss.updatedAt = time.Now()
 }
 
+// MarkExcluded records that the agent cannot run the spec; the sync runner skips the rollout
+// until it is deployed again.
+func (ss *Rollout) MarkExcluded(reason string) {
+	ss.status = kind.SyncStatusExcluded
+	ss.errMsg = reason
+	ss.updatedAt = time.Now()
+}
+
 // MarkUnknown sets the state when the agent is unreachable.
 func (ss *Rollout) MarkUnknown() {
 	ss.status = kind.SyncStatusUnknown
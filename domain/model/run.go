@@ -0,0 +1,226 @@
+package model
+
+import (
+	"time"
+
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+)
+
+var _ domain.Entity[*Run] = (*Run)(nil)
+
+// RunResult is the outcome of a run on a single agent.
+//
+// A result is pending until the task is submitted; after that Status mirrors
+// the task status reported by the agent until it becomes terminal.
+type RunResult struct {
+	SubmittedAt time.Time
+	FinishedAt  time.Time
+
+	AgentID string
+	TaskID  string
+	Error   string
+	Attempt int
+
+	Status kind.TaskStatus
+}
+
+// Submitted reports whether the task was handed to the agent.
+func (r RunResult) Submitted() bool { return !r.SubmittedAt.IsZero() }
+
+// Done reports whether the result is final.
+func (r RunResult) Done() bool { return !r.FinishedAt.IsZero() }
+
+// Run is an ad-hoc, one-shot execution of a task on a set of agents.
+//
+// Unlike a Spec, a run is not reconciled: its task is pushed once to every
+// target with restart "never" and the run only collects how each execution ended.
+// The task occupies its own slot (see RunSlot), so it never replaces a spec task.
+//
+// Targets are resolved once on creation, from explicit agent IDs and/or a label selector.
+type Run struct {
+	createdAt  time.Time
+	updatedAt  time.Time
+	finishedAt time.Time
+
+	id        string
+	name      string
+	createdBy string
+
+	task         *Spec
+	targetLabels map[string]string
+	results      []RunResult
+}
+
+// RunSlot returns the agent slot used by the task of run id.
+func RunSlot(id string) string {
+	return "run-" + id
+}
+
+// NewRun creates a new run with a subprocess task and no targets.
+func NewRun(id, name string) (*Run, error) {
+	if id == "" {
+		return nil, domain.ErrEmptyID
+	}
+	task, err := NewSpec(id, name, RunSlot(id))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Run{
+		createdAt: now,
+		updatedAt: now,
+
+		id:   id,
+		name: name,
+		task: task,
+
+		targetLabels: make(map[string]string),
+	}, nil
+}
+
+// --- Getters ---
+
+func (r *Run) ID() string                      { return r.id }
+func (r *Run) Name() string                    { return r.name }
+func (r *Run) CreatedBy() string               { return r.createdBy }
+func (r *Run) Slot() string                    { return r.task.Slot() }
+func (r *Run) KindType() kind.TaskKindType     { return r.task.KindType() }
+func (r *Run) KindConfig() map[string]any      { return r.task.KindConfig() }
+func (r *Run) RunnerLabels() map[string]string { return r.task.RunnerLabels() }
+func (r *Run) TimeoutMs() int64                { return r.task.TimeoutMs() }
+func (r *Run) CreatedAt() time.Time            { return r.createdAt }
+func (r *Run) UpdatedAt() time.Time            { return r.updatedAt }
+func (r *Run) FinishedAt() time.Time           { return r.finishedAt }
+
+// Done reports whether every target has a final result.
+func (r *Run) Done() bool { return !r.finishedAt.IsZero() }
+
+// TargetLabels returns a defensive copy of the label selector the targets were resolved from.
+func (r *Run) TargetLabels() map[string]string {
+	out := make(map[string]string, len(r.targetLabels))
+	for k, v := range r.targetLabels {
+		out[k] = v
+	}
+	return out
+}
+
+// Targets returns the resolved target agent IDs.
+func (r *Run) Targets() []string {
+	out := make([]string, 0, len(r.results))
+	for _, res := range r.results {
+		out = append(out, res.AgentID)
+	}
+	return out
+}
+
+// Results returns a copy of the per-agent results, in target order.
+func (r *Run) Results() []RunResult {
+	out := make([]RunResult, len(r.results))
+	copy(out, r.results)
+	return out
+}
+
+// --- Setters ---
+
+func (r *Run) SetCreatedBy(by string) {
+	r.createdBy = by
+	r.updatedAt = time.Now()
+}
+
+func (r *Run) SetKindType(kt kind.TaskKindType) {
+	r.task.SetKindType(kt)
+	r.updatedAt = time.Now()
+}
+
+func (r *Run) SetKindConfig(cfg map[string]any) {
+	r.task.SetKindConfig(cfg)
+	r.updatedAt = time.Now()
+}
+
+func (r *Run) SetTimeoutMs(ms int64) {
+	r.task.SetTimeoutMs(ms)
+	r.updatedAt = time.Now()
+}
+
+func (r *Run) SetRunnerLabels(labels map[string]string) {
+	r.task.SetRunnerLabels(labels)
+	r.updatedAt = time.Now()
+}
+
+func (r *Run) SetTargetLabels(labels map[string]string) {
+	cp := make(map[string]string, len(labels))
+	for k, v := range labels {
+		cp[k] = v
+	}
+	r.targetLabels = cp
+	r.updatedAt = time.Now()
+}
+
+// SetTargets replaces the targets with a pending result per agent ID.
+func (r *Run) SetTargets(agentIDs []string) {
+	results := make([]RunResult, 0, len(agentIDs))
+	for _, id := range agentIDs {
+		results = append(results, RunResult{AgentID: id, Status: kind.TaskStatusPending})
+	}
+	r.results = results
+	r.finishedAt = time.Time{}
+	r.updatedAt = time.Now()
+}
+
+// SetResult replaces the result for res.AgentID; results for unknown agents are ignored.
+// The run is marked finished once every result is done.
+func (r *Run) SetResult(res RunResult) {
+	done := true
+	for i := range r.results {
+		if r.results[i].AgentID == res.AgentID {
+			r.results[i] = res
+		}
+		done = done && r.results[i].Done()
+	}
+	now := time.Now()
+	if done && r.finishedAt.IsZero() {
+		r.finishedAt = now
+	}
+	r.updatedAt = now
+}
+
+// --- Task ---
+
+// Validate checks the run task against the agent CreateSpec contract.
+// See Spec.Validate.
+func (r *Run) Validate() error { return r.task.Validate() }
+
+// SecretRefs returns the names of the secrets referenced by the run task.
+func (r *Run) SecretRefs() ([]string, error) { return r.task.SecretRefs() }
+
+// RenderCreateSpec builds the CreateSpec payload of the run task for a specific agent.
+// See Spec.RenderCreateSpec.
+func (r *Run) RenderCreateSpec(a *Agent, secrets SecretResolver) (map[string]any, error) {
+	return r.task.RenderCreateSpec(a, secrets)
+}
+
+// Clone creates a deep copy of the Run.
+func (r *Run) Clone() *Run {
+	targetLabels := make(map[string]string, len(r.targetLabels))
+	for k, v := range r.targetLabels {
+		targetLabels[k] = v
+	}
+	results := make([]RunResult, len(r.results))
+	copy(results, r.results)
+
+	return &Run{
+		createdAt:  r.createdAt,
+		updatedAt:  r.updatedAt,
+		finishedAt: r.finishedAt,
+
+		id:        r.id,
+		name:      r.name,
+		createdBy: r.createdBy,
+
+		task:         r.task.Clone(),
+		targetLabels: targetLabels,
+		results:      results,
+	}
+}
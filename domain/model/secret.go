@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/soltiHQ/control-plane/domain"
+)
+
+var _ domain.Entity[*Secret] = (*Secret)(nil)
+
+// Secret is a named sensitive value that specs reference instead of embedding it.
+//
+// Specs refer to a secret by name using the template function
+//
+//	{{ secret "db-password" }}
+//
+// The value is only resolved by the sync runner when the spec is pushed to an agent;
+// everywhere else the reference is shown masked.
+//
+// Notes:
+//   - Name is unique across secrets.
+//   - The value is never exposed through API responses.
+type Secret struct {
+	createdAt time.Time
+	updatedAt time.Time
+
+	id          string
+	name        string
+	value       string
+	description string
+}
+
+// NewSecret creates a new secret entity.
+func NewSecret(id, name, value string) (*Secret, error) {
+	if id == "" {
+		return nil, domain.ErrEmptyID
+	}
+	if name == "" {
+		return nil, domain.ErrEmptyName
+	}
+
+	now := time.Now()
+	return &Secret{
+		createdAt: now,
+		updatedAt: now,
+		id:        id,
+		name:      name,
+		value:     value,
+	}, nil
+}
+
+// ID returns the unique identifier of the secret.
+func (s *Secret) ID() string { return s.id }
+
+// Name returns the name specs use to reference the secret.
+func (s *Secret) Name() string { return s.name }
+
+// Value returns the secret value.
+func (s *Secret) Value() string { return s.value }
+
+// Description returns the optional human-readable description.
+func (s *Secret) Description() string { return s.description }
+
+// CreatedAt returns the timestamp when the secret was created.
+func (s *Secret) CreatedAt() time.Time { return s.createdAt }
+
+// UpdatedAt returns the timestamp of the last modification.
+func (s *Secret) UpdatedAt() time.Time { return s.updatedAt }
+
+// SetValue replaces the secret value.
+func (s *Secret) SetValue(value string) {
+	s.value = value
+	s.updatedAt = time.Now()
+}
+
+// SetDescription replaces the description.
+func (s *Secret) SetDescription(description string) {
+	s.description = description
+	s.updatedAt = time.Now()
+}
+
+// Clone creates a deep copy of the secret.
+func (s *Secret) Clone() *Secret {
+	return &Secret{
+		createdAt:   s.createdAt,
+		updatedAt:   s.updatedAt,
+		id:          s.id,
+		name:        s.name,
+		value:       s.value,
+		description: s.description,
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"reflect"
 	"time"
 
 	"github.com/soltiHQ/control-plane/domain"
@@ -29,8 +30,12 @@ type Spec struct {
 	id           string
 	name         string
 	version      int
+	priority     int               // higher values are pushed first to a shared agent
 	targets      []string          // concrete agent IDs
 	targetLabels map[string]string // label selector for dynamic targeting
+	dependsOn    []string          // spec IDs that must be synced on an agent first
+	placement    []string          // constraint expressions every target agent must satisfy
+	managedBy    string            // owner of a declaratively managed spec (e.g. "gitops:main"); empty = editable
 	createdAt    time.Time
 	updatedAt    time.Time
 
@@ -88,6 +93,8 @@ func (ts *Spec) ID() string            { return ts.id }
 func (ts *Spec) Name() string           { return ts.name }
 func (ts *Spec) Slot() string           { return ts.slot }
 func (ts *Spec) Version() int           { return ts.version }
+func (ts *Spec) Priority() int          { return ts.priority }
+func (ts *Spec) ManagedBy() string      { return ts.managedBy }
 func (ts *Spec) CreatedAt() time.Time   { return ts.createdAt }
 func (ts *Spec) UpdatedAt() time.Time   { return ts.updatedAt }
 func (ts *Spec) KindType() kind.TaskKindType       { return ts.kindType }
@@ -113,6 +120,20 @@ func (ts *Spec) Targets() []string {
 	return out
 }
 
+// Placement returns a copy of the placement constraint expressions.
+func (ts *Spec) Placement() []string {
+	out := make([]string, len(ts.placement))
+	copy(out, ts.placement)
+	return out
+}
+
+// DependsOn returns a copy of the spec IDs this spec depends on.
+func (ts *Spec) DependsOn() []string {
+	out := make([]string, len(ts.dependsOn))
+	copy(out, ts.dependsOn)
+	return out
+}
+
 // TargetLabels returns a defensive copy of the target label selector.
 func (ts *Spec) TargetLabels() map[string]string {
 	out := make(map[string]string, len(ts.targetLabels))
@@ -138,6 +159,16 @@ func (ts *Spec) SetName(name string) {
 	ts.updatedAt = time.Now()
 }
 
+func (ts *Spec) SetPriority(priority int) {
+	ts.priority = priority
+	ts.updatedAt = time.Now()
+}
+
+func (ts *Spec) SetManagedBy(owner string) {
+	ts.managedBy = owner
+	ts.updatedAt = time.Now()
+}
+
 func (ts *Spec) SetSlot(slot string) {
 	ts.slot = slot
 	ts.updatedAt = time.Now()
@@ -189,6 +220,20 @@ func (ts *Spec) SetTargets(targets []string) {
 	ts.updatedAt = time.Now()
 }
 
+func (ts *Spec) SetDependsOn(specIDs []string) {
+	cp := make([]string, len(specIDs))
+	copy(cp, specIDs)
+	ts.dependsOn = cp
+	ts.updatedAt = time.Now()
+}
+
+func (ts *Spec) SetPlacement(exprs []string) {
+	cp := make([]string, len(exprs))
+	copy(cp, exprs)
+	ts.placement = cp
+	ts.updatedAt = time.Now()
+}
+
 func (ts *Spec) SetTargetLabels(labels map[string]string) {
 	cp := make(map[string]string, len(labels))
 	for k, v := range labels {
@@ -213,6 +258,24 @@ func (ts *Spec) IncrementVersion() {
 	ts.updatedAt = time.Now()
 }
 
+// Assign replaces the desired state of ts with a copy of src,
+// keeping the id, version and creation time of ts.
+func (ts *Spec) Assign(src *Spec) {
+	c := src.Clone()
+	c.id, c.version, c.createdAt = ts.id, ts.version, ts.createdAt
+	c.updatedAt = time.Now()
+	*ts = *c
+}
+
+// SameDesiredState reports whether ts and o describe the same desired state,
+// ignoring id, version and timestamps.
+func (ts *Spec) SameDesiredState(o *Spec) bool {
+	a, b := ts.Clone(), o.Clone()
+	a.id, a.version, a.createdAt, a.updatedAt = "", 0, time.Time{}, time.Time{}
+	b.id, b.version, b.createdAt, b.updatedAt = "", 0, time.Time{}, time.Time{}
+	return reflect.DeepEqual(a, b)
+}
+
 // ToCreateSpec builds a map[string]any in the agent's CreateSpec JSON format.
 //
 // Example output:
@@ -264,6 +327,10 @@ func (ts *Spec) Clone() *Spec {
 	}
 	targets := make([]string, len(ts.targets))
 	copy(targets, ts.targets)
+	dependsOn := make([]string, len(ts.dependsOn))
+	copy(dependsOn, ts.dependsOn)
+	placement := make([]string, len(ts.placement))
+	copy(placement, ts.placement)
 	targetLabels := make(map[string]string, len(ts.targetLabels))
 	for k, v := range ts.targetLabels {
 		targetLabels[k] = v
@@ -277,8 +344,12 @@ func (ts *Spec) Clone() *Spec {
 		id:           ts.id,
 		name:         ts.name,
 		version:      ts.version,
+		priority:     ts.priority,
 		targets:      targets,
 		targetLabels: targetLabels,
+		dependsOn:    dependsOn,
+		placement:    placement,
+		managedBy:    ts.managedBy,
 		createdAt:    ts.createdAt,
 		updatedAt:    ts.updatedAt,
 
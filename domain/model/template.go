@@ -0,0 +1,233 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/soltiHQ/control-plane/domain"
+)
+
+// TemplateData is the data a spec template is rendered against.
+//
+// Templates use Go text/template syntax inside string values of kindConfig
+// and runnerLabels, for example:
+//
+//	{{ .Agent.Labels.region }}
+//	{{ .Agent.Metadata.hostname }}
+//	{{ .Agent.Name }}
+//	{{ secret "db-password" }}
+//
+// The secret function is only allowed in kindConfig: runner labels are reported
+// back by the agent in task listings and logs, so a secret there would leak.
+type TemplateData struct {
+	Agent TemplateAgent
+}
+
+// TemplateAgent exposes agent attributes to spec templates.
+type TemplateAgent struct {
+	Labels   map[string]string
+	Metadata map[string]string
+
+	ID       string
+	Name     string
+	OS       string
+	Arch     string
+	Platform string
+}
+
+// SecretResolver returns the value of the named secret for the "secret" template function.
+type SecretResolver func(name string) (string, error)
+
+// noSecret is the SecretResolver of runnerLabels templates, where secrets are not allowed.
+func noSecret(name string) (string, error) {
+	return "", fmt.Errorf("%w: secret %q is only allowed in kind_config", domain.ErrInvalidTemplate, name)
+}
+
+// MaskedSecret is a SecretResolver that renders a masked reference instead of the value.
+// It is used wherever a rendered spec leaves the sync runner (API responses, previews).
+func MaskedSecret(name string) (string, error) {
+	return "<secret:" + name + ">", nil
+}
+
+// NewTemplateData builds template data for the given agent.
+func NewTemplateData(a *Agent) TemplateData {
+	return TemplateData{Agent: TemplateAgent{
+		Labels:   a.LabelsAll(),
+		Metadata: a.MetadataAll(),
+
+		ID:       a.ID(),
+		Name:     a.Name(),
+		OS:       a.OS(),
+		Arch:     a.Arch(),
+		Platform: a.Platform(),
+	}}
+}
+
+// ValidateTemplates checks that every templated value in kindConfig and runnerLabels
+// parses and only references known fields.
+//
+// Missing label or metadata keys are not an error here; they depend on the agent
+// and are reported when the spec is rendered for it.
+func (ts *Spec) ValidateTemplates() error {
+	_, err := ts.SecretRefs()
+	return err
+}
+
+// SecretRefs returns the sorted, de-duplicated names of secrets referenced by
+// kindConfig templates.
+//
+// Like ValidateTemplates, it fails if a template is invalid or runnerLabels reference a secret.
+func (ts *Spec) SecretRefs() ([]string, error) {
+	seen := make(map[string]struct{})
+	r := renderer{
+		data: TemplateData{Agent: TemplateAgent{
+			Labels:   map[string]string{},
+			Metadata: map[string]string{},
+		}},
+		missing: "missingkey=zero",
+		secret: func(name string) (string, error) {
+			seen[name] = struct{}{}
+			return MaskedSecret(name)
+		},
+	}
+	if _, err := r.value("kind_config", ts.kindConfig); err != nil {
+		return nil, err
+	}
+	lr := r.withoutSecrets()
+	for k, v := range ts.runnerLabels {
+		if _, err := lr.string("runner_labels."+k, v); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]string, 0, len(seen))
+	for name := range seen {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// RenderCreateSpec builds the CreateSpec payload for a specific agent,
+// rendering templated kindConfig and runnerLabels values against it.
+//
+// Secret references in kindConfig are resolved through secrets; pass MaskedSecret
+// to keep values out of the result. A secret referenced from runnerLabels, or a
+// label or metadata key the agent does not have, is an error.
+func (ts *Spec) RenderCreateSpec(a *Agent, secrets SecretResolver) (map[string]any, error) {
+	r := renderer{
+		data:    NewTemplateData(a),
+		missing: "missingkey=error",
+		secret:  secrets,
+	}
+	spec := ts.ToCreateSpec()
+
+	kindCfg, err := r.value("kind_config", ts.kindConfig)
+	if err != nil {
+		return nil, err
+	}
+	spec["kind"] = map[string]any{string(ts.kindType): kindCfg}
+
+	if len(ts.runnerLabels) > 0 {
+		lr := r.withoutSecrets()
+		labels := make(map[string]string, len(ts.runnerLabels))
+		for k, v := range ts.runnerLabels {
+			if labels[k], err = lr.string("runner_labels."+k, v); err != nil {
+				return nil, err
+			}
+		}
+		spec["labels"] = labels
+	}
+	return spec, nil
+}
+
+// renderer renders spec template values against one data set.
+type renderer struct {
+	data    TemplateData
+	missing string
+	secret  SecretResolver
+}
+
+// withoutSecrets returns a copy of r whose secret function always fails.
+func (r renderer) withoutSecrets() renderer {
+	r.secret = noSecret
+	return r
+}
+
+// value renders every string inside v, descending into maps and slices.
+// Other values are returned unchanged.
+func (r renderer) value(path string, v any) (any, error) {
+	switch x := v.(type) {
+	case string:
+		return r.string(path, x)
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, item := range x {
+			rv, err := r.value(path+"."+k, item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rv
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(x))
+		for i, item := range x {
+			rv, err := r.value(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rv
+		}
+		return out, nil
+	case map[string]string:
+		out := make(map[string]string, len(x))
+		for k, item := range x {
+			rv, err := r.string(path+"."+k, item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rv
+		}
+		return out, nil
+	case []string:
+		out := make([]string, len(x))
+		for i, item := range x {
+			rv, err := r.string(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rv
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// string renders s as a template; strings without actions are returned as is.
+func (r renderer) string(path, s string) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+
+	secret := r.secret
+	if secret == nil {
+		secret = MaskedSecret
+	}
+	tpl, err := template.New(path).
+		Option(r.missing).
+		Funcs(template.FuncMap{"secret": secret}).
+		Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrInvalidTemplate, err)
+	}
+
+	var b strings.Builder
+	if err = tpl.Execute(&b, r.data); err != nil {
+		return "", fmt.Errorf("%w: %v", domain.ErrInvalidTemplate, err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,292 @@
+package model
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+)
+
+// templateSpec builds a subprocess spec with the given kind config and runner labels.
+func templateSpec(t *testing.T, kindCfg map[string]any, runnerLabels map[string]string) *Spec {
+	t.Helper()
+
+	ts, err := NewSpec("s1", "s1", "s1")
+	if err != nil {
+		t.Fatalf("new spec: %v", err)
+	}
+	ts.SetKindType(kind.TaskKindSubprocess)
+	ts.SetKindConfig(kindCfg)
+	ts.SetRunnerLabels(runnerLabels)
+	return ts
+}
+
+// fakeSecrets resolves secrets from a fixed map.
+func fakeSecrets(values map[string]string) SecretResolver {
+	return func(name string) (string, error) {
+		v, ok := values[name]
+		if !ok {
+			return "", errors.New("unknown secret: " + name)
+		}
+		return v, nil
+	}
+}
+
+func TestSpec_RenderCreateSpec(t *testing.T) {
+	t.Parallel()
+
+	a, err := NewAgentFrom(AgentParams{
+		ID:           "a1",
+		Name:         "web-1",
+		EndpointType: 1,
+		APIVersion:   1,
+		OS:           "ubuntu",
+		Arch:         "arm64",
+		Metadata:     map[string]string{"hostname": "web-1.local"},
+	})
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	a.LabelAdd("region", "eu")
+
+	secrets := fakeSecrets(map[string]string{"db": "s3cret"})
+
+	tests := []struct {
+		name     string
+		kindCfg  map[string]any
+		labels   map[string]string
+		resolver SecretResolver
+		wantCfg  map[string]any
+		wantLbls map[string]string
+		err      string // substring; empty means rendering succeeds
+	}{
+		{
+			name:    "plain values are kept",
+			kindCfg: map[string]any{"command": "./web", "args": []any{"-v"}, "port": 8080},
+			wantCfg: map[string]any{"command": "./web", "args": []any{"-v"}, "port": 8080},
+		},
+		{
+			name:    "agent fields",
+			kindCfg: map[string]any{"command": "./{{ .Agent.Name }}-{{ .Agent.OS }}-{{ .Agent.Arch }}"},
+			wantCfg: map[string]any{"command": "./web-1-ubuntu-arm64"},
+		},
+		{
+			name: "nested maps and slices",
+			kindCfg: map[string]any{
+				"command": "./web",
+				"args":    []any{"--region", "{{ .Agent.Labels.region }}", 3},
+				"env": map[string]any{
+					"HOST":  "{{ .Agent.Metadata.hostname }}",
+					"PATHS": []string{"/srv/{{ .Agent.ID }}"},
+					"TAGS":  map[string]string{"id": "{{ .Agent.ID }}"},
+				},
+			},
+			wantCfg: map[string]any{
+				"command": "./web",
+				"args":    []any{"--region", "eu", 3},
+				"env": map[string]any{
+					"HOST":  "web-1.local",
+					"PATHS": []string{"/srv/a1"},
+					"TAGS":  map[string]string{"id": "a1"},
+				},
+			},
+		},
+		{
+			name:     "secret in kind_config is resolved",
+			kindCfg:  map[string]any{"command": "./web", "env": map[string]any{"PASS": `{{ secret "db" }}`}},
+			resolver: secrets,
+			wantCfg:  map[string]any{"command": "./web", "env": map[string]any{"PASS": "s3cret"}},
+		},
+		{
+			name:     "masked secret",
+			kindCfg:  map[string]any{"command": `./web --pass={{ secret "db" }}`},
+			resolver: MaskedSecret,
+			wantCfg:  map[string]any{"command": "./web --pass=<secret:db>"},
+		},
+		{
+			name:    "nil resolver masks secrets",
+			kindCfg: map[string]any{"command": `./web --pass={{ secret "db" }}`},
+			wantCfg: map[string]any{"command": "./web --pass=<secret:db>"},
+		},
+		{
+			name:     "unknown secret",
+			kindCfg:  map[string]any{"command": `{{ secret "other" }}`},
+			resolver: secrets,
+			err:      "unknown secret: other",
+		},
+		{
+			name:     "runner labels are rendered",
+			kindCfg:  map[string]any{"command": "./web"},
+			labels:   map[string]string{"region": "{{ .Agent.Labels.region }}", "team": "core"},
+			wantCfg:  map[string]any{"command": "./web"},
+			wantLbls: map[string]string{"region": "eu", "team": "core"},
+		},
+		{
+			name:     "secret in runner labels is refused",
+			kindCfg:  map[string]any{"command": "./web"},
+			labels:   map[string]string{"pass": `{{ secret "db" }}`},
+			resolver: secrets,
+			err:      "only allowed in kind_config",
+		},
+		{
+			name:    "missing label key",
+			kindCfg: map[string]any{"command": "{{ .Agent.Labels.zone }}"},
+			err:     "map has no entry for key",
+		},
+		{
+			name:    "missing metadata key in runner labels",
+			kindCfg: map[string]any{"command": "./web"},
+			labels:  map[string]string{"rack": "{{ .Agent.Metadata.rack }}"},
+			err:     "map has no entry for key",
+		},
+		{
+			name:    "unknown field",
+			kindCfg: map[string]any{"command": "{{ .Agent.Zone }}"},
+			err:     "can't evaluate field Zone",
+		},
+		{
+			name:    "parse error",
+			kindCfg: map[string]any{"command": "{{ .Agent.Name "},
+			err:     "unclosed action",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := templateSpec(t, tt.kindCfg, tt.labels)
+			spec, err := ts.RenderCreateSpec(a, tt.resolver)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("RenderCreateSpec error = %v, want containing %q", err, tt.err)
+				}
+				if !errors.Is(err, domain.ErrInvalidTemplate) {
+					t.Fatalf("RenderCreateSpec error = %v, want ErrInvalidTemplate", err)
+				}
+				if strings.Contains(err.Error(), "s3cret") {
+					t.Fatalf("RenderCreateSpec error leaks the secret value: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderCreateSpec: %v", err)
+			}
+
+			gotCfg := spec["kind"].(map[string]any)[string(kind.TaskKindSubprocess)]
+			if !reflect.DeepEqual(gotCfg, tt.wantCfg) {
+				t.Fatalf("kind config = %#v, want %#v", gotCfg, tt.wantCfg)
+			}
+			gotLbls, _ := spec["labels"].(map[string]string)
+			if len(gotLbls) != 0 || len(tt.wantLbls) != 0 {
+				if !reflect.DeepEqual(gotLbls, tt.wantLbls) {
+					t.Fatalf("labels = %v, want %v", gotLbls, tt.wantLbls)
+				}
+			}
+		})
+	}
+
+	// Rendering never changes the stored template.
+	ts := templateSpec(t, map[string]any{"command": "{{ .Agent.Name }}"}, nil)
+	if _, err = ts.RenderCreateSpec(a, nil); err != nil {
+		t.Fatalf("RenderCreateSpec: %v", err)
+	}
+	if got := ts.KindConfig()["command"]; got != "{{ .Agent.Name }}" {
+		t.Fatalf("stored kind config changed to %v", got)
+	}
+}
+
+func TestSpec_SecretRefs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		kindCfg map[string]any
+		labels  map[string]string
+		want    []string
+		err     string // substring; empty means the templates are valid
+	}{
+		{
+			name:    "no templates",
+			kindCfg: map[string]any{"command": "./web"},
+			want:    []string{},
+		},
+		{
+			name: "sorted and de-duplicated",
+			kindCfg: map[string]any{
+				"command": `./web --token={{ secret "token" }}`,
+				"env":     map[string]any{"A": `{{ secret "db" }}`, "B": []any{`{{ secret "token" }}`}},
+			},
+			want: []string{"db", "token"},
+		},
+		{
+			name:    "agent references are not secrets",
+			kindCfg: map[string]any{"command": "{{ .Agent.Labels.zone }}"},
+			labels:  map[string]string{"zone": "{{ .Agent.Labels.zone }}"},
+			want:    []string{},
+		},
+		{
+			name:    "secret in runner labels",
+			kindCfg: map[string]any{"command": "./web"},
+			labels:  map[string]string{"pass": `{{ secret "db" }}`},
+			err:     "only allowed in kind_config",
+		},
+		{
+			name:    "invalid template",
+			kindCfg: map[string]any{"command": `{{ secret }}`},
+			err:     "wrong number of args",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := templateSpec(t, tt.kindCfg, tt.labels).SecretRefs()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) || !errors.Is(err, domain.ErrInvalidTemplate) {
+					t.Fatalf("SecretRefs error = %v, want ErrInvalidTemplate containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SecretRefs: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("SecretRefs = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpec_ValidateTemplates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		kindCfg map[string]any
+		labels  map[string]string
+		err     string // substring; empty means the templates are valid
+	}{
+		// Label and metadata keys depend on the agent: missing ones render as zero values.
+		{name: "unknown label key", kindCfg: map[string]any{"command": "{{ .Agent.Labels.zone }}"}},
+		{name: "unknown metadata key", labels: map[string]string{"rack": "{{ .Agent.Metadata.rack }}"}},
+		{name: "secret in kind_config", kindCfg: map[string]any{"command": `{{ secret "db" }}`}},
+		{name: "unknown field", kindCfg: map[string]any{"command": "{{ .Agent.Zone }}"}, err: "can't evaluate field Zone"},
+		{name: "unknown function", kindCfg: map[string]any{"command": "{{ env \"HOME\" }}"}, err: `function "env" not defined`},
+		{name: "parse error in runner labels", labels: map[string]string{"x": "{{ .Agent.Name "}, err: "unclosed action"},
+		{name: "secret in runner labels", labels: map[string]string{"pass": `{{ secret "db" }}`}, err: "only allowed in kind_config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := templateSpec(t, tt.kindCfg, tt.labels).ValidateTemplates()
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) || !errors.Is(err, domain.ErrInvalidTemplate) {
+					t.Fatalf("ValidateTemplates error = %v, want ErrInvalidTemplate containing %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateTemplates: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+)
+
+// FieldError describes a single invalid field.
+//
+// Field uses the REST field name; kind config keys are prefixed with "kind_config."
+// (e.g. "kind_config.command").
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// ValidationError lists every invalid field of a spec.
+// It matches domain.ErrInvalidSpec via errors.Is.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return domain.ErrInvalidSpec.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error { return domain.ErrInvalidSpec }
+
+// FieldMap returns the field errors keyed by field name.
+func (e *ValidationError) FieldMap() map[string]string {
+	out := make(map[string]string, len(e.Fields))
+	for _, f := range e.Fields {
+		out[f.Field] = f.Message
+	}
+	return out
+}
+
+// kindValidators check kindConfig for each task kind.
+// https://github.com/soltiHQ/sdk/blob/main/crates/solti-model/src/kind/task.rs
+var kindValidators = map[kind.TaskKindType]func(v *validator, cfg map[string]any){
+	kind.TaskKindSubprocess: func(v *validator, cfg map[string]any) {
+		v.requiredString(cfg, "command")
+		v.optionalStrings(cfg, "args")
+		v.optionalStringMap(cfg, "env")
+		v.optionalString(cfg, "cwd")
+		v.optionalBool(cfg, "failOnNonZero")
+	},
+	kind.TaskKindContainer: func(v *validator, cfg map[string]any) {
+		v.requiredString(cfg, "image")
+		v.optionalStringOrStrings(cfg, "command")
+		v.optionalStrings(cfg, "args")
+		v.optionalStringMap(cfg, "env")
+	},
+	kind.TaskKindWasm: func(v *validator, cfg map[string]any) {
+		v.requiredString(cfg, "module")
+		v.optionalStrings(cfg, "args")
+		v.optionalStringMap(cfg, "env")
+	},
+}
+
+// Validate checks the spec fields against the agent CreateSpec contract:
+// known kind with a well-formed kindConfig, and known restart, jitter and
+// admission values with consistent timings. Placement expressions must parse.
+//
+// Returns *ValidationError listing every invalid field, or nil.
+func (ts *Spec) Validate() error {
+	v := &validator{}
+
+	if ts.slot == "" {
+		v.add("slot", "is required")
+	}
+
+	if check, ok := kindValidators[ts.kindType]; ok {
+		check(v, ts.kindConfig)
+	} else {
+		v.add("kind_type", oneOf(kind.TaskKindSubprocess, kind.TaskKindContainer, kind.TaskKindWasm))
+	}
+
+	if ts.timeoutMs <= 0 {
+		v.add("timeout_ms", "must be greater than 0")
+	}
+	if !ts.restartType.Valid() {
+		v.add("restart_type", oneOf(kind.RestartNever, kind.RestartOnFailure, kind.RestartAlways))
+	}
+	if ts.intervalMs < 0 {
+		v.add("interval_ms", "must not be negative")
+	}
+
+	if !ts.backoff.Jitter.Valid() {
+		v.add("jitter", oneOf(kind.JitterNone, kind.JitterFull, kind.JitterEqual, kind.JitterDecorrelated))
+	}
+	if ts.backoff.FirstMs <= 0 {
+		v.add("backoff_first_ms", "must be greater than 0")
+	}
+	if ts.backoff.MaxMs < ts.backoff.FirstMs {
+		v.add("backoff_max_ms", "must not be less than backoff_first_ms")
+	}
+	if ts.backoff.Factor < 1 {
+		v.add("backoff_factor", "must be at least 1")
+	}
+
+	if !ts.admission.Valid() {
+		v.add("admission", oneOf(kind.AdmissionDropIfRunning, kind.AdmissionReplace, kind.AdmissionQueue))
+	}
+
+	for i, expr := range ts.placement {
+		if _, err := ParseConstraint(expr); err != nil {
+			v.add(fmt.Sprintf("placement.%d", i), fmt.Sprintf("%q: %v", expr, err))
+		}
+	}
+
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(field, msg string) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: msg})
+}
+
+func (v *validator) requiredString(cfg map[string]any, key string) {
+	s, ok := cfg[key].(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		v.add("kind_config."+key, "is required")
+	}
+}
+
+func (v *validator) optionalString(cfg map[string]any, key string) {
+	if x, ok := cfg[key]; ok {
+		if _, ok = x.(string); !ok {
+			v.add("kind_config."+key, "must be a string")
+		}
+	}
+}
+
+func (v *validator) optionalBool(cfg map[string]any, key string) {
+	if x, ok := cfg[key]; ok {
+		if _, ok = x.(bool); !ok {
+			v.add("kind_config."+key, "must be a boolean")
+		}
+	}
+}
+
+func (v *validator) optionalStrings(cfg map[string]any, key string) {
+	if x, ok := cfg[key]; ok && !isStrings(x) {
+		v.add("kind_config."+key, "must be a list of strings")
+	}
+}
+
+func (v *validator) optionalStringOrStrings(cfg map[string]any, key string) {
+	x, ok := cfg[key]
+	if !ok {
+		return
+	}
+	if _, ok = x.(string); ok || isStrings(x) {
+		return
+	}
+	v.add("kind_config."+key, "must be a string or a list of strings")
+}
+
+func (v *validator) optionalStringMap(cfg map[string]any, key string) {
+	x, ok := cfg[key]
+	if !ok {
+		return
+	}
+	switch m := x.(type) {
+	case map[string]string:
+		return
+	case map[string]any:
+		var bad []string
+		for k, val := range m {
+			if _, ok = val.(string); !ok {
+				bad = append(bad, k)
+			}
+		}
+		if len(bad) == 0 {
+			return
+		}
+		sort.Strings(bad)
+		v.add("kind_config."+key, "values must be strings: "+strings.Join(bad, ", "))
+	default:
+		v.add("kind_config."+key, "must be a map of strings")
+	}
+}
+
+func isStrings(x any) bool {
+	switch xs := x.(type) {
+	case []string:
+		return true
+	case []any:
+		for _, s := range xs {
+			if _, ok := s.(string); !ok {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func oneOf[T ~string](values ...T) string {
+	parts := make([]string, len(values))
+	for i, s := range values {
+		parts[i] = string(s)
+	}
+	return fmt.Sprintf("must be one of %s", strings.Join(parts, ", "))
+}
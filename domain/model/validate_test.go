@@ -0,0 +1,201 @@
+package model
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+)
+
+func TestSpec_Validate(t *testing.T) {
+	t.Parallel()
+
+	subprocess := func(ts *Spec) {
+		ts.SetKindType(kind.TaskKindSubprocess)
+		ts.SetKindConfig(map[string]any{"command": "./web"})
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(ts *Spec)
+		fields []string // sorted field keys; empty means the spec is valid
+	}{
+		{name: "valid subprocess", setup: subprocess},
+		{
+			name: "valid subprocess with all fields",
+			setup: func(ts *Spec) {
+				ts.SetKindConfig(map[string]any{
+					"command":       "./web",
+					"args":          []any{"-v"},
+					"env":           map[string]any{"A": "1"},
+					"cwd":           "/srv",
+					"failOnNonZero": true,
+				})
+			},
+		},
+		{
+			name: "valid container",
+			setup: func(ts *Spec) {
+				ts.SetKindType(kind.TaskKindContainer)
+				ts.SetKindConfig(map[string]any{"image": "nginx:1", "command": []string{"nginx", "-g"}, "env": map[string]string{"A": "1"}})
+			},
+		},
+		{
+			name: "valid container with a string command",
+			setup: func(ts *Spec) {
+				ts.SetKindType(kind.TaskKindContainer)
+				ts.SetKindConfig(map[string]any{"image": "nginx:1", "command": "nginx"})
+			},
+		},
+		{
+			name: "valid wasm",
+			setup: func(ts *Spec) {
+				ts.SetKindType(kind.TaskKindWasm)
+				ts.SetKindConfig(map[string]any{"module": "app.wasm", "args": []string{"-v"}})
+			},
+		},
+		{name: "subprocess without command", setup: func(ts *Spec) {}, fields: []string{"kind_config.command"}},
+		{
+			name:   "subprocess with blank command",
+			setup:  func(ts *Spec) { ts.SetKindConfig(map[string]any{"command": "  "}) },
+			fields: []string{"kind_config.command"},
+		},
+		{
+			name: "container without image",
+			setup: func(ts *Spec) {
+				ts.SetKindType(kind.TaskKindContainer)
+				ts.SetKindConfig(map[string]any{"command": "nginx"})
+			},
+			fields: []string{"kind_config.image"},
+		},
+		{
+			name: "wasm without module",
+			setup: func(ts *Spec) {
+				ts.SetKindType(kind.TaskKindWasm)
+				ts.SetKindConfig(map[string]any{"command": "./web"})
+			},
+			fields: []string{"kind_config.module"},
+		},
+		{
+			name: "subprocess with wrong types",
+			setup: func(ts *Spec) {
+				ts.SetKindConfig(map[string]any{
+					"command":       "./web",
+					"args":          []any{"-v", 1},
+					"env":           map[string]any{"A": 1},
+					"cwd":           1,
+					"failOnNonZero": "yes",
+				})
+			},
+			fields: []string{"kind_config.args", "kind_config.cwd", "kind_config.env", "kind_config.failOnNonZero"},
+		},
+		{
+			name: "container with wrong types",
+			setup: func(ts *Spec) {
+				ts.SetKindType(kind.TaskKindContainer)
+				ts.SetKindConfig(map[string]any{"image": 1, "command": 1, "env": []string{"A=1"}})
+			},
+			fields: []string{"kind_config.command", "kind_config.env", "kind_config.image"},
+		},
+		{
+			name:   "unknown kind",
+			setup:  func(ts *Spec) { ts.SetKindType("docker") },
+			fields: []string{"kind_type"},
+		},
+		{
+			name: "enums",
+			setup: func(ts *Spec) {
+				subprocess(ts)
+				ts.SetRestartType("sometimes")
+				ts.SetAdmission("maybe")
+				ts.SetBackoff(BackoffConfig{Jitter: "some", FirstMs: 1000, MaxMs: 5000, Factor: 2})
+			},
+			fields: []string{"admission", "jitter", "restart_type"},
+		},
+		{
+			name: "timings",
+			setup: func(ts *Spec) {
+				subprocess(ts)
+				ts.SetTimeoutMs(0)
+				ts.SetIntervalMs(-1)
+				ts.SetBackoff(BackoffConfig{Jitter: kind.JitterNone, FirstMs: 0, MaxMs: -1, Factor: 0.5})
+			},
+			fields: []string{"backoff_factor", "backoff_first_ms", "backoff_max_ms", "interval_ms", "timeout_ms"},
+		},
+		{
+			name: "backoff max below first",
+			setup: func(ts *Spec) {
+				subprocess(ts)
+				ts.SetBackoff(BackoffConfig{Jitter: kind.JitterFull, FirstMs: 2000, MaxMs: 1000, Factor: 2})
+			},
+			fields: []string{"backoff_max_ms"},
+		},
+		{
+			name:   "empty slot",
+			setup:  func(ts *Spec) { subprocess(ts); ts.SetSlot("") },
+			fields: []string{"slot"},
+		},
+		{
+			name: "placement by index",
+			setup: func(ts *Spec) {
+				subprocess(ts)
+				ts.SetPlacement([]string{"os = ubuntu", "colour = red", "metadata.kernel >= new"})
+			},
+			fields: []string{"placement.1", "placement.2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, err := NewSpec("s1", "s1", "s1")
+			if err != nil {
+				t.Fatalf("new spec: %v", err)
+			}
+			tt.setup(ts)
+
+			err = ts.Validate()
+			if len(tt.fields) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+
+			var ve *ValidationError
+			if !errors.As(err, &ve) || !errors.Is(err, domain.ErrInvalidSpec) {
+				t.Fatalf("Validate error = %v, want *ValidationError matching ErrInvalidSpec", err)
+			}
+			fm := ve.FieldMap()
+			if got := slices.Sorted(maps.Keys(fm)); !slices.Equal(got, tt.fields) {
+				t.Fatalf("field keys = %v, want %v", got, tt.fields)
+			}
+			for field, msg := range fm {
+				if msg == "" {
+					t.Fatalf("field %s has an empty message", field)
+				}
+				if !strings.Contains(err.Error(), field+": "+msg) {
+					t.Fatalf("error %q does not mention %s", err, field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidationError_FieldMap(t *testing.T) {
+	t.Parallel()
+
+	ve := &ValidationError{Fields: []FieldError{
+		{Field: "slot", Message: "is required"},
+		{Field: "kind_config.command", Message: "is required"},
+	}}
+	want := map[string]string{"slot": "is required", "kind_config.command": "is required"}
+	if got := ve.FieldMap(); !maps.Equal(got, want) {
+		t.Fatalf("FieldMap = %v, want %v", got, want)
+	}
+	if got, want := ve.Error(), "invalid spec: slot: is required; kind_config.command: is required"; got != want {
+		t.Fatalf("Error = %q, want %q", got, want)
+	}
+}
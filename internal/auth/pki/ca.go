@@ -0,0 +1,344 @@
+// Package pki implements the control-plane certificate authority used for mutual TLS with agents:
+//   - Loading the CA from PEM files, or generating (and persisting) one on first start
+//   - Signing agent CSRs at enrollment with the agent ID bound into the certificate
+//   - Issuing the control-plane certificate, marked with its own identity URI, used by
+//     discovery listeners and outbound agent calls
+//   - Verifying peer certificates against an expected agent or control-plane identity.
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	caTTL       = 10 * 365 * 24 * time.Hour
+	caName      = "solti control-plane CA"
+	serverName  = "solti control-plane"
+	clockSkew   = 5 * time.Minute
+	pemCert     = "CERTIFICATE"
+	pemKey      = "PRIVATE KEY"
+	pemCSR      = "CERTIFICATE REQUEST"
+	serialBytes = 16
+)
+
+// CA is the built-in certificate authority of the control-plane.
+type CA struct {
+	cfg Config
+
+	cert    *x509.Certificate
+	certPEM []byte
+	key     crypto.Signer
+	pool    *x509.CertPool
+
+	server tls.Certificate
+}
+
+// New loads the CA from cfg.CACert / cfg.CAKey or generates one, and issues the
+// control-plane certificate for cfg.ServerNames.
+func New(cfg Config) (*CA, error) {
+	cfg = cfg.withDefaults()
+
+	cert, key, err := loadOrGenerate(cfg.CACert, cfg.CAKey)
+	if err != nil {
+		return nil, err
+	}
+	ca := &CA{
+		cfg:     cfg,
+		cert:    cert,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: pemCert, Bytes: cert.Raw}),
+		key:     key,
+		pool:    x509.NewCertPool(),
+	}
+	ca.pool.AddCert(cert)
+
+	if ca.server, err = ca.issueServer(); err != nil {
+		return nil, err
+	}
+	return ca, nil
+}
+
+// CertPEM returns the PEM-encoded CA certificate agents use as trust root.
+func (ca *CA) CertPEM() []byte { return ca.certPEM }
+
+// Pool returns a pool containing the CA certificate.
+func (ca *CA) Pool() *x509.CertPool { return ca.pool }
+
+// ServerTLS returns the TLS config for discovery listeners.
+//
+// Client certificates are verified when presented but not required, so agents
+// can enroll before they hold one; handlers bind the verified identity to the agent.
+func (ca *CA) ServerTLS() *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{ca.server},
+		ClientCAs:    ca.pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}
+}
+
+// ClientTLS returns the TLS config for outbound agent calls, presenting the
+// control-plane certificate. Use [ForAgent] to pin the expected agent identity.
+func (ca *CA) ClientTLS() *tls.Config {
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{ca.server},
+		RootCAs:      ca.pool,
+	}
+}
+
+// AgentClientTLS returns [CA.ClientTLS] pinned to the identity of agentID.
+func (ca *CA) AgentClientTLS(agentID string) *tls.Config {
+	return ForAgent(ca.ClientTLS(), agentID)
+}
+
+// SignAgentCSR signs a PEM-encoded CSR for agentID and returns the PEM certificate.
+//
+// The requested subject and names are not trusted: the certificate is issued to agentID,
+// carries its identity URI and, as its only DNS/IP SAN, the host of the agent's registered
+// endpoint so the agent can serve its API with it. A CSR asking for any other name, or for
+// a control-plane server name, is rejected. The certificate is valid for both client and
+// server authentication; agents tell the control-plane apart by [ForControlPlane].
+func (ca *CA) SignAgentCSR(csrPEM []byte, agentID, endpoint string) ([]byte, time.Time, error) {
+	if agentID == "" {
+		return nil, time.Time{}, fmt.Errorf("%w: empty agent id", ErrInvalidCSR)
+	}
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != pemCSR {
+		return nil, time.Time{}, fmt.Errorf("%w: expected a PEM %q block", ErrInvalidCSR, pemCSR)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %v", ErrInvalidCSR, err)
+	}
+
+	host := endpointHost(endpoint)
+	if ca.isServerName(host) {
+		host = ""
+	}
+	requested := make([]string, 0, len(csr.DNSNames)+len(csr.IPAddresses))
+	requested = append(requested, csr.DNSNames...)
+	for _, ip := range csr.IPAddresses {
+		requested = append(requested, ip.String())
+	}
+	for _, name := range requested {
+		switch {
+		case ca.isServerName(name):
+			return nil, time.Time{}, fmt.Errorf("%w: %q is a control-plane name", ErrInvalidCSR, name)
+		case !strings.EqualFold(name, host):
+			return nil, time.Time{}, fmt.Errorf("%w: %q is not the agent endpoint host", ErrInvalidCSR, name)
+		}
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: agentID},
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    now.Add(ca.cfg.CertTTL),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		URIs:        []*url.URL{agentURI(agentID)},
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		tmpl.IPAddresses = []net.IP{ip}
+	} else if host != "" {
+		tmpl.DNSNames = []string{host}
+	}
+	der, err := ca.sign(tmpl, csr.PublicKey)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemCert, Bytes: der}), tmpl.NotAfter, nil
+}
+
+// isServerName reports whether name is one of the control-plane certificate names.
+func (ca *CA) isServerName(name string) bool {
+	if name == "" {
+		return false
+	}
+	ip := net.ParseIP(name)
+	for _, sn := range ca.cfg.ServerNames {
+		if ip != nil {
+			if sip := net.ParseIP(sn); sip != nil && sip.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(sn, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointHost returns the host of an agent endpoint: a URL ("https://host:port/path")
+// or a gRPC address ("host:port"). It returns "" if there is none.
+func endpointHost(endpoint string) string {
+	if endpoint == "" {
+		return ""
+	}
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			return ""
+		}
+		return u.Hostname()
+	}
+	if host, _, err := net.SplitHostPort(endpoint); err == nil {
+		return host
+	}
+	return strings.Trim(endpoint, "[]")
+}
+
+// issueServer issues the control-plane certificate with a fresh key.
+func (ca *CA) issueServer() (tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: serverName},
+		NotBefore:   now.Add(-clockSkew),
+		NotAfter:    now.Add(defaultServerTTL),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		URIs:        []*url.URL{controlPlaneURI()},
+	}
+	for _, name := range ca.cfg.ServerNames {
+		if ip := net.ParseIP(name); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, name)
+		}
+	}
+	der, err := ca.sign(tmpl, key.Public())
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der, ca.cert.Raw}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+func (ca *CA) sign(tmpl *x509.Certificate, pub any) ([]byte, error) {
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.SerialNumber = serial
+	return x509.CreateCertificate(rand.Reader, tmpl, ca.cert, pub, ca.key)
+}
+
+// loadOrGenerate loads the CA from PEM files, or generates a new one and writes
+// it when both paths are set and neither file exists.
+func loadOrGenerate(certPath, keyPath string) (*x509.Certificate, crypto.Signer, error) {
+	if certPath == "" || keyPath == "" {
+		if certPath != keyPath {
+			return nil, nil, fmt.Errorf("%w: ca_cert and ca_key must be set together", ErrInvalidCA)
+		}
+		return generate()
+	}
+
+	certPEM, certErr := os.ReadFile(certPath)
+	keyPEM, keyErr := os.ReadFile(keyPath)
+	switch {
+	case certErr == nil && keyErr == nil:
+		return parse(certPEM, keyPEM)
+	case errors.Is(certErr, fs.ErrNotExist) && errors.Is(keyErr, fs.ErrNotExist):
+	default:
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCA, errors.Join(certErr, keyErr))
+	}
+
+	cert, key, err := generate()
+	if err != nil {
+		return nil, nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: pemKey, Bytes: der}), 0o600); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCA, err)
+	}
+	if err = os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: pemCert, Bytes: cert.Raw}), 0o644); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCA, err)
+	}
+	return cert, key, nil
+}
+
+func generate() (*x509.Certificate, crypto.Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	serial, err := newSerial()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: caName},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(caTTL),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func parse(certPEM, keyPEM []byte) (*x509.Certificate, crypto.Signer, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCA, err)
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCA, err)
+	}
+	if !cert.IsCA {
+		return nil, nil, fmt.Errorf("%w: certificate is not a CA", ErrInvalidCA)
+	}
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: unsupported key type", ErrInvalidCA)
+	}
+	return cert, key, nil
+}
+
+func newSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBytes*8))
+}
@@ -0,0 +1,363 @@
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// mkCSR creates a CSR for cn requesting names as DNS or IP SANs.
+func mkCSR(t *testing.T, cn string, names ...string) ([]byte, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey err=%v", err)
+	}
+	req := &x509.CertificateRequest{Subject: pkix.Name{CommonName: cn}}
+	for _, name := range names {
+		if ip := net.ParseIP(name); ip != nil {
+			req.IPAddresses = append(req.IPAddresses, ip)
+		} else {
+			req.DNSNames = append(req.DNSNames, name)
+		}
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, req, key)
+	if err != nil {
+		t.Fatalf("CreateCertificateRequest err=%v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemCSR, Bytes: der}), key
+}
+
+func parseCert(t *testing.T, certPEM []byte) *x509.Certificate {
+	t.Helper()
+
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		t.Fatalf("expected PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificate err=%v", err)
+	}
+	return cert
+}
+
+func TestNew_GeneratesAndReloadsCA(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfg := Config{CACert: filepath.Join(dir, "ca.crt"), CAKey: filepath.Join(dir, "ca.key")}
+
+	ca, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New err=%v", err)
+	}
+	fi, err := os.Stat(cfg.CAKey)
+	if err != nil {
+		t.Fatalf("expected key file, err=%v", err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Fatalf("expected key mode 0600, got=%v", fi.Mode().Perm())
+	}
+
+	again, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New (reload) err=%v", err)
+	}
+	if string(again.CertPEM()) != string(ca.CertPEM()) {
+		t.Fatalf("expected reloaded CA to match the generated one")
+	}
+}
+
+func TestNew_RejectsPartialCA(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfg := Config{CACert: filepath.Join(dir, "ca.crt"), CAKey: filepath.Join(dir, "ca.key")}
+	if err := os.WriteFile(cfg.CACert, []byte("junk"), 0o644); err != nil {
+		t.Fatalf("WriteFile err=%v", err)
+	}
+
+	if _, err := New(cfg); !errors.Is(err, ErrInvalidCA) {
+		t.Fatalf("expected ErrInvalidCA, err=%v", err)
+	}
+	if _, err := New(Config{CACert: cfg.CACert}); !errors.Is(err, ErrInvalidCA) {
+		t.Fatalf("expected ErrInvalidCA for cert without key, err=%v", err)
+	}
+}
+
+func TestSignAgentCSR_BindsAgentIdentity(t *testing.T) {
+	t.Parallel()
+
+	ca, err := New(Config{CertTTL: time.Hour})
+	if err != nil {
+		t.Fatalf("New err=%v", err)
+	}
+	csr, _ := mkCSR(t, "someone-else", "agent-1.local")
+
+	certPEM, notAfter, err := ca.SignAgentCSR(csr, "agent-1", "https://agent-1.local:8443")
+	if err != nil {
+		t.Fatalf("SignAgentCSR err=%v", err)
+	}
+	cert := parseCert(t, certPEM)
+
+	if cert.Subject.CommonName != "agent-1" {
+		t.Fatalf("expected CN agent-1, got=%q", cert.Subject.CommonName)
+	}
+	if got := AgentID(cert); got != "agent-1" {
+		t.Fatalf("expected AgentID agent-1, got=%q", got)
+	}
+	if len(cert.DNSNames) != 1 || cert.DNSNames[0] != "agent-1.local" || len(cert.IPAddresses) != 0 {
+		t.Fatalf("expected only the endpoint host as SAN, dns=%v ip=%v", cert.DNSNames, cert.IPAddresses)
+	}
+	if IsControlPlane(cert) {
+		t.Fatalf("expected agent certificate without the control-plane identity")
+	}
+	if cert.NotAfter.Sub(notAfter).Abs() > time.Second {
+		t.Fatalf("expected NotAfter=%v, got=%v", notAfter, cert.NotAfter)
+	}
+	if _, err = cert.Verify(x509.VerifyOptions{
+		Roots:     ca.Pool(),
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Fatalf("expected certificate to verify as client, err=%v", err)
+	}
+}
+
+func TestSignAgentCSR_Invalid(t *testing.T) {
+	t.Parallel()
+
+	ca, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New err=%v", err)
+	}
+	csr, _ := mkCSR(t, "x")
+
+	cases := map[string]struct {
+		csr []byte
+		id  string
+	}{
+		"not pem":  {csr: []byte("nope"), id: "agent-1"},
+		"empty id": {csr: csr, id: ""},
+		"cert pem": {csr: ca.CertPEM(), id: "agent-1"},
+	}
+	for name, tc := range cases {
+		if _, _, err := ca.SignAgentCSR(tc.csr, tc.id, ""); !errors.Is(err, ErrInvalidCSR) {
+			t.Fatalf("%s: expected ErrInvalidCSR, err=%v", name, err)
+		}
+	}
+}
+
+func TestSignAgentCSR_RejectsForeignNames(t *testing.T) {
+	t.Parallel()
+
+	ca, err := New(Config{ServerNames: []string{"cp.example.com", "10.0.0.5"}})
+	if err != nil {
+		t.Fatalf("New err=%v", err)
+	}
+
+	cases := map[string]struct {
+		names    []string
+		endpoint string
+	}{
+		"server dns name":          {names: []string{"cp.example.com"}, endpoint: "https://agent-1.local"},
+		"server ip":                {names: []string{"10.0.0.5"}, endpoint: "https://agent-1.local"},
+		"server name as endpoint":  {names: []string{"cp.example.com"}, endpoint: "https://cp.example.com:9443"},
+		"other host":               {names: []string{"agent-2.local"}, endpoint: "https://agent-1.local"},
+		"endpoint plus extra name": {names: []string{"agent-1.local", "other.local"}, endpoint: "agent-1.local:50051"},
+		"name without endpoint":    {names: []string{"agent-1.local"}},
+	}
+	for name, tc := range cases {
+		csr, _ := mkCSR(t, "agent-1", tc.names...)
+		if _, _, err := ca.SignAgentCSR(csr, "agent-1", tc.endpoint); !errors.Is(err, ErrInvalidCSR) {
+			t.Fatalf("%s: expected ErrInvalidCSR, err=%v", name, err)
+		}
+	}
+}
+
+func TestSignAgentCSR_EndpointHost(t *testing.T) {
+	t.Parallel()
+
+	ca, err := New(Config{ServerNames: []string{"cp.example.com"}})
+	if err != nil {
+		t.Fatalf("New err=%v", err)
+	}
+
+	cases := map[string]struct {
+		endpoint string
+		dns      string
+		ip       string
+	}{
+		"http url":     {endpoint: "https://agent-1.local:8443/api", dns: "agent-1.local"},
+		"grpc address": {endpoint: "10.1.2.3:50051", ip: "10.1.2.3"},
+		"ipv6 address": {endpoint: "[fd00::1]:50051", ip: "fd00::1"},
+		"server name":  {endpoint: "https://CP.example.com"},
+		"no endpoint":  {},
+	}
+	for name, tc := range cases {
+		csr, _ := mkCSR(t, "agent-1")
+		certPEM, _, err := ca.SignAgentCSR(csr, "agent-1", tc.endpoint)
+		if err != nil {
+			t.Fatalf("%s: SignAgentCSR err=%v", name, err)
+		}
+		cert := parseCert(t, certPEM)
+
+		var dns, ip string
+		if len(cert.DNSNames) > 0 {
+			dns = cert.DNSNames[0]
+		}
+		if len(cert.IPAddresses) > 0 {
+			ip = cert.IPAddresses[0].String()
+		}
+		if len(cert.DNSNames)+len(cert.IPAddresses) > 1 || dns != tc.dns || ip != tc.ip {
+			t.Fatalf("%s: expected dns=%q ip=%q, got dns=%v ip=%v", name, tc.dns, tc.ip, cert.DNSNames, cert.IPAddresses)
+		}
+	}
+}
+
+func TestForControlPlane_RejectsAgentCertificate(t *testing.T) {
+	t.Parallel()
+
+	ca, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New err=%v", err)
+	}
+	csr, key := mkCSR(t, "agent-1", "agent-1.local")
+	certPEM, _, err := ca.SignAgentCSR(csr, "agent-1", "https://agent-1.local:8443")
+	if err != nil {
+		t.Fatalf("SignAgentCSR err=%v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey err=%v", err)
+	}
+	agentCert, err := tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: pemKey, Bytes: keyDER}))
+	if err != nil {
+		t.Fatalf("X509KeyPair err=%v", err)
+	}
+
+	serve := func(cert tls.Certificate) string {
+		ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+		if err != nil {
+			t.Fatalf("Listen err=%v", err)
+		}
+		t.Cleanup(func() { _ = ln.Close() })
+
+		go func() {
+			for {
+				c, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				_ = c.(*tls.Conn).Handshake()
+				_ = c.Close()
+			}
+		}()
+		return ln.Addr().String()
+	}
+	dial := func(addr string) error {
+		c, err := tls.Dial("tcp", addr, ForControlPlane(&tls.Config{RootCAs: ca.Pool()}))
+		if err != nil {
+			return err
+		}
+		return c.Close()
+	}
+
+	if err = dial(serve(ca.ServerTLS().Certificates[0])); err != nil {
+		t.Fatalf("expected handshake with the control-plane to succeed, err=%v", err)
+	}
+	if err = dial(serve(agentCert)); !errors.Is(err, ErrIdentityMismatch) {
+		t.Fatalf("expected ErrIdentityMismatch for an agent certificate, err=%v", err)
+	}
+}
+
+func TestForAgent_VerifiesIdentityOverTLS(t *testing.T) {
+	t.Parallel()
+
+	ca, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New err=%v", err)
+	}
+	csr, key := mkCSR(t, "agent-1")
+	certPEM, _, err := ca.SignAgentCSR(csr, "agent-1", "")
+	if err != nil {
+		t.Fatalf("SignAgentCSR err=%v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey err=%v", err)
+	}
+	agentCert, err := tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: pemKey, Bytes: keyDER}))
+	if err != nil {
+		t.Fatalf("X509KeyPair err=%v", err)
+	}
+
+	// The agent serves with its certificate and requires the control-plane client certificate.
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{agentCert},
+		ClientCAs:    ca.Pool(),
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})
+	if err != nil {
+		t.Fatalf("Listen err=%v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			_ = c.(*tls.Conn).Handshake()
+			_ = c.Close()
+		}
+	}()
+
+	dial := func(agentID string) error {
+		c, err := tls.Dial("tcp", ln.Addr().String(), ForAgent(ca.ClientTLS(), agentID))
+		if err != nil {
+			return err
+		}
+		return c.Close()
+	}
+	if err = dial("agent-1"); err != nil {
+		t.Fatalf("expected handshake with agent-1 to succeed, err=%v", err)
+	}
+	if err = dial("agent-2"); !errors.Is(err, ErrIdentityMismatch) {
+		t.Fatalf("expected ErrIdentityMismatch, err=%v", err)
+	}
+}
+
+func TestPeerAgentID(t *testing.T) {
+	t.Parallel()
+
+	if _, err := PeerAgentID(&tls.ConnectionState{}); !errors.Is(err, ErrNoPeerCertificate) {
+		t.Fatalf("expected ErrNoPeerCertificate, err=%v", err)
+	}
+
+	ca, err := New(Config{})
+	if err != nil {
+		t.Fatalf("New err=%v", err)
+	}
+	csr, _ := mkCSR(t, "agent-1")
+	certPEM, _, err := ca.SignAgentCSR(csr, "agent-1", "")
+	if err != nil {
+		t.Fatalf("SignAgentCSR err=%v", err)
+	}
+	cs := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{parseCert(t, certPEM)}}}
+	if id, err := PeerAgentID(cs); err != nil || id != "agent-1" {
+		t.Fatalf("expected agent-1, got=%q err=%v", id, err)
+	}
+}
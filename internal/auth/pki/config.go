@@ -0,0 +1,38 @@
+package pki
+
+import "time"
+
+const (
+	defaultCertTTL   = 30 * 24 * time.Hour
+	defaultServerTTL = 365 * 24 * time.Hour
+)
+
+// Config configures the built-in certificate authority and mutual TLS with agents.
+//
+// When Enabled is false the discovery listeners stay plaintext, agent CSRs are not
+// signed and outbound agent calls do not use client certificates.
+type Config struct {
+	// CACert and CAKey are PEM file paths of the CA. Missing files are generated
+	// and written on first start; empty paths keep a generated CA in memory only.
+	CACert string `yaml:"ca_cert"`
+	CAKey  string `yaml:"ca_key"`
+
+	// ServerNames are DNS names and IP addresses agents use to reach the control-plane;
+	// they become SANs of its discovery certificate.
+	ServerNames []string `yaml:"server_names"`
+
+	// CertTTL is the lifetime of certificates issued to agents.
+	CertTTL time.Duration `yaml:"cert_ttl"`
+
+	Enabled bool `yaml:"enabled"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.CertTTL <= 0 {
+		c.CertTTL = defaultCertTTL
+	}
+	if len(c.ServerNames) == 0 {
+		c.ServerNames = []string{"localhost", "127.0.0.1"}
+	}
+	return c
+}
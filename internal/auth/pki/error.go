@@ -0,0 +1,15 @@
+package pki
+
+import "errors"
+
+var (
+	// ErrInvalidCSR indicates that a certificate signing request cannot be parsed
+	// or its signature does not verify.
+	ErrInvalidCSR = errors.New("pki: invalid certificate request")
+	// ErrInvalidCA indicates that the configured CA certificate or key cannot be loaded.
+	ErrInvalidCA = errors.New("pki: invalid ca")
+	// ErrNoPeerCertificate indicates that the peer did not present a verified certificate.
+	ErrNoPeerCertificate = errors.New("pki: no peer certificate")
+	// ErrIdentityMismatch indicates that a verified peer certificate belongs to another agent.
+	ErrIdentityMismatch = errors.New("pki: identity mismatch")
+)
@@ -0,0 +1,130 @@
+package pki
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// agentURIPrefix prefixes the agent ID in the URI SAN of agent certificates.
+	agentURIPrefix = "urn:solti:agent:"
+	// ControlPlaneURI is the URI SAN that identifies the control-plane certificate.
+	// Agent certificates never carry it, whatever names they hold.
+	ControlPlaneURI = "urn:solti:control-plane"
+)
+
+func controlPlaneURI() *url.URL {
+	return &url.URL{Scheme: "urn", Opaque: "solti:control-plane"}
+}
+
+func agentURI(agentID string) *url.URL {
+	return &url.URL{Scheme: "urn", Opaque: "solti:agent:" + agentID}
+}
+
+// AgentID returns the agent identity bound into a certificate issued by [CA.SignAgentCSR],
+// or "" if the certificate carries none.
+func AgentID(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+	for _, u := range cert.URIs {
+		if id, ok := strings.CutPrefix(u.String(), agentURIPrefix); ok && id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// PeerAgentID returns the agent identity of the verified client certificate of a
+// TLS connection. It returns [ErrNoPeerCertificate] if the client presented none.
+func PeerAgentID(cs *tls.ConnectionState) (string, error) {
+	if cs == nil || len(cs.VerifiedChains) == 0 || len(cs.VerifiedChains[0]) == 0 {
+		return "", ErrNoPeerCertificate
+	}
+	id := AgentID(cs.VerifiedChains[0][0])
+	if id == "" {
+		return "", fmt.Errorf("%w: certificate carries no agent identity", ErrIdentityMismatch)
+	}
+	return id, nil
+}
+
+// ForAgent returns a copy of an outbound TLS config that accepts only a server
+// certificate chaining to base.RootCAs and issued to agentID.
+//
+// Agent endpoints are often bare IP addresses, so the agent identity replaces
+// host name verification.
+func ForAgent(base *tls.Config, agentID string) *tls.Config {
+	cfg := base.Clone()
+	roots := base.RootCAs
+	cfg.InsecureSkipVerify = true // replaced by VerifyConnection below
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		leaf, err := verifyServerChain(cs, roots)
+		if err != nil {
+			return err
+		}
+		if got := AgentID(leaf); got != agentID {
+			return fmt.Errorf("%w: want agent %q, certificate issued to %q", ErrIdentityMismatch, agentID, got)
+		}
+		return nil
+	}
+	return cfg
+}
+
+// IsControlPlane reports whether cert carries the control-plane identity URI.
+func IsControlPlane(cert *x509.Certificate) bool {
+	if cert == nil {
+		return false
+	}
+	for _, u := range cert.URIs {
+		if u.String() == ControlPlaneURI {
+			return true
+		}
+	}
+	return false
+}
+
+// ForControlPlane returns a copy of a TLS config for agents dialing discovery that
+// accepts only a server certificate chaining to base.RootCAs and carrying [ControlPlaneURI].
+//
+// Agent certificates are issued by the same CA and may hold the same host names, so
+// the control-plane identity, not the host name, is what agents must verify.
+func ForControlPlane(base *tls.Config) *tls.Config {
+	cfg := base.Clone()
+	roots := base.RootCAs
+	cfg.InsecureSkipVerify = true // replaced by VerifyConnection below
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		leaf, err := verifyServerChain(cs, roots)
+		if err != nil {
+			return err
+		}
+		if !IsControlPlane(leaf) {
+			return fmt.Errorf("%w: certificate is not the control-plane's", ErrIdentityMismatch)
+		}
+		return nil
+	}
+	return cfg
+}
+
+// verifyServerChain verifies the peer chain of cs against roots for server authentication
+// and returns its leaf.
+func verifyServerChain(cs tls.ConnectionState, roots *x509.CertPool) (*x509.Certificate, error) {
+	if len(cs.PeerCertificates) == 0 {
+		return nil, ErrNoPeerCertificate
+	}
+	inter := x509.NewCertPool()
+	for _, c := range cs.PeerCertificates[1:] {
+		inter.AddCert(c)
+	}
+	leaf := cs.PeerCertificates[0]
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: inter,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}); err != nil {
+		return nil, err
+	}
+	return leaf, nil
+}
@@ -12,12 +12,17 @@ import (
 	"github.com/kelseyhightower/envconfig"
 	"gopkg.in/yaml.v3"
 
+	"github.com/soltiHQ/control-plane/internal/auth/pki"
 	"github.com/soltiHQ/control-plane/internal/auth/wire"
+	"github.com/soltiHQ/control-plane/internal/metrics"
 	"github.com/soltiHQ/control-plane/internal/server"
+	"github.com/soltiHQ/control-plane/internal/server/runner/gitops"
 	"github.com/soltiHQ/control-plane/internal/server/runner/grpcserver"
 	"github.com/soltiHQ/control-plane/internal/server/runner/httpserver"
 	"github.com/soltiHQ/control-plane/internal/server/runner/lifecycle"
+	"github.com/soltiHQ/control-plane/internal/server/runner/oneshot"
 	syncrunner "github.com/soltiHQ/control-plane/internal/server/runner/sync"
+	"github.com/soltiHQ/control-plane/internal/service/enrollment"
 	"github.com/soltiHQ/control-plane/internal/transport/http/middleware"
 	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
 )
@@ -33,10 +38,15 @@ type Config struct {
 	GRPC          grpcserver.Config     `yaml:"grpc"           envconfig:"GRPC"`
 	Sync          syncrunner.Config     `yaml:"sync"           envconfig:"SYNC"`
 	Lifecycle     lifecycle.Config      `yaml:"lifecycle"      envconfig:"LIFECYCLE"`
+	Oneshot       oneshot.Config        `yaml:"oneshot"        envconfig:"ONESHOT"`
+	GitOps        gitops.Config         `yaml:"gitops"         envconfig:"GITOPS"`
 	Triggers      htmx.Config           `yaml:"triggers"       envconfig:"TRIGGERS"`
 	Server        server.Config         `yaml:"server"         envconfig:"SERVER"`
 	Auth          wire.Config           `yaml:"auth"           envconfig:"AUTH"`
 	CORS          middleware.CORSConfig `yaml:"cors"           envconfig:"CORS"`
+	TLS           pki.Config            `yaml:"tls"            envconfig:"TLS"`
+	Enrollment    enrollment.Config     `yaml:"enrollment"     envconfig:"ENROLLMENT"`
+	Metrics       metrics.Config        `yaml:"metrics"        envconfig:"METRICS"`
 }
 
 // Default returns the default development configuration.
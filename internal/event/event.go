@@ -32,10 +32,30 @@ const (
 	AgentInactive     = "agent_inactive"
 	AgentDisconnected = "agent_disconnected"
 	AgentDeleted      = "agent_deleted"
+	AgentEnrolled     = "agent_enrolled"
+	AgentRejected     = "agent_rejected"
 
-	SpecCreated  = "spec_created"
-	SpecUpdated  = "spec_updated"
-	SpecDeployed = "spec_deployed"
+	AgentInventoryChanged = "agent_inventory_changed"
+
+	AgentCordoned   = "agent_cordoned"
+	AgentUncordoned = "agent_uncordoned"
+	AgentDrained    = "agent_drained"
+
+	AgentUnreachable = "agent_unreachable"
+	AgentReachable   = "agent_reachable"
+
+	AgentPendingApproval  = "agent_pending_approval"
+	AgentApproved         = "agent_approved"
+	AgentApprovalRejected = "agent_approval_rejected"
+
+	TaskCanceled  = "task_canceled"
+	TaskRestarted = "task_restarted"
+
+	SpecCreated    = "spec_created"
+	SpecUpdated    = "spec_updated"
+	SpecDeployed   = "spec_deployed"
+	SpecUndeployed = "spec_undeployed"
+	SpecDeleted    = "spec_deleted"
 
 	UserCreated         = "user_created"
 	UserUpdated         = "user_updated"
@@ -43,6 +63,18 @@ const (
 	UserPasswordChanged = "user_password_changed"
 	UserStatusChanged   = "user_status_changed"
 
+	SecretCreated = "secret_created"
+	SecretUpdated = "secret_updated"
+	SecretDeleted = "secret_deleted"
+
+	EnrollmentTokenCreated = "enrollment_token_created"
+	EnrollmentTokenDeleted = "enrollment_token_deleted"
+
+	RunCreated  = "run_created"
+	RunFinished = "run_finished"
+	RunFailed   = "run_failed"
+	RunDeleted  = "run_deleted"
+
 	SessionCreated = "session_created"
 
 	RateLimited = "rate_limited"
@@ -50,15 +82,23 @@ const (
 	IssueClosed = "issue_closed"
 
 	SyncFailed = "sync_failed"
+
+	GitOpsSynced = "gitops_synced"
+	GitOpsFailed = "gitops_failed"
 )
 
 // issueKinds defines which event kinds are classified as issues.
 var issueKinds = map[string]struct{}{
-	AgentDisconnected: {},
-	AgentInactive:     {},
-	AgentDeleted:      {},
-	RateLimited:       {},
-	SyncFailed:        {},
+	AgentDisconnected:    {},
+	AgentInactive:        {},
+	AgentDeleted:         {},
+	AgentRejected:        {},
+	AgentPendingApproval: {},
+	AgentUnreachable:     {},
+	RateLimited:          {},
+	SyncFailed:           {},
+	RunFailed:            {},
+	GitOpsFailed:         {},
 }
 
 // IsIssueKind reports whether the event kind is classified as an issue.
@@ -105,6 +105,13 @@ func (h *Hub) DeleteIssues(kind, id string) int {
 	})
 }
 
+// HasIssue reports whether an issue matching kind and payload ID is open.
+func (h *Hub) HasIssue(kind, id string) bool {
+	return h.issues.ContainsFunc(func(ev Record) bool {
+		return ev.Kind == kind && ev.Payload.ID == id
+	})
+}
+
 // Subscribe registers a new listener.
 func (h *Hub) Subscribe(ctx context.Context) <-chan string {
 	ch := make(chan string, clientBufSize)
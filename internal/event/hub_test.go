@@ -175,6 +175,29 @@ func TestHub_DeleteIssues(t *testing.T) {
 	}
 }
 
+func TestHub_HasIssue(t *testing.T) {
+	h := newTestHub()
+	defer h.Close()
+
+	h.Record(AgentDisconnected, Payload{ID: "a1", Name: "alpha"})
+	h.Record(SpecCreated, Payload{ID: "s1", Name: "spec"})
+
+	if !h.HasIssue(AgentDisconnected, "a1") {
+		t.Fatal("expected issue for a1")
+	}
+	if h.HasIssue(AgentDisconnected, "a2") {
+		t.Fatal("unexpected issue for a2")
+	}
+	if h.HasIssue(SpecCreated, "s1") {
+		t.Fatal("non-issue event reported as issue")
+	}
+
+	h.DeleteIssues(AgentDisconnected, "a1")
+	if h.HasIssue(AgentDisconnected, "a1") {
+		t.Fatal("expected issue for a1 to be deleted")
+	}
+}
+
 func TestHub_NotifyDropsForSlowClient(t *testing.T) {
 	h := newTestHub()
 	defer h.Close()
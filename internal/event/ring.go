@@ -62,3 +62,16 @@ func (r *Ring[T]) DeleteFunc(match func(T) bool) int {
 	r.buf = filtered
 	return n
 }
+
+// ContainsFunc reports whether any item matches the predicate.
+func (r *Ring[T]) ContainsFunc(match func(T) bool) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, item := range r.buf {
+		if match(item) {
+			return true
+		}
+	}
+	return false
+}
@@ -9,11 +9,17 @@ import (
 	"github.com/soltiHQ/control-plane/domain/kind"
 	"github.com/soltiHQ/control-plane/internal/auth/identity"
 	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/metrics"
 	"github.com/soltiHQ/control-plane/internal/proxy"
+	"github.com/soltiHQ/control-plane/internal/server"
 	"github.com/soltiHQ/control-plane/internal/service"
 	"github.com/soltiHQ/control-plane/internal/service/access"
 	"github.com/soltiHQ/control-plane/internal/service/agent"
 	"github.com/soltiHQ/control-plane/internal/service/credential"
+	"github.com/soltiHQ/control-plane/internal/service/enrollment"
+	"github.com/soltiHQ/control-plane/internal/service/manifest"
+	"github.com/soltiHQ/control-plane/internal/service/run"
+	"github.com/soltiHQ/control-plane/internal/service/secret"
 	"github.com/soltiHQ/control-plane/internal/service/session"
 	"github.com/soltiHQ/control-plane/internal/service/spec"
 	"github.com/soltiHQ/control-plane/internal/service/user"
@@ -42,15 +48,22 @@ type API struct {
 	sessionSVC    *session.Service
 	accessSVC     *access.Service
 	agentSVC      *agent.Service
+	enrollSVC     *enrollment.Service
 	specSVC       *spec.Service
+	secretSVC     *secret.Service
+	runSVC        *run.Service
+	manifestSVC   *manifest.Service
 	userSVC       *user.Service
 	proxyPool     *proxy.Pool
+	metrics       *metrics.Store
 	hub           *event.Hub
+	runners       []server.Reporter
 
 	logger zerolog.Logger
 }
 
 // NewAPI creates a new API handler.
+// The status of each runner is shown on the dashboard.
 func NewAPI(
 	logger zerolog.Logger,
 	userSVC *user.Service,
@@ -58,9 +71,15 @@ func NewAPI(
 	sessionSVC *session.Service,
 	credentialSVC *credential.Service,
 	agentSVC *agent.Service,
+	enrollSVC *enrollment.Service,
 	specSVC *spec.Service,
+	secretSVC *secret.Service,
+	runSVC *run.Service,
+	manifestSVC *manifest.Service,
 	proxyPool *proxy.Pool,
+	metricStore *metrics.Store,
 	hub *event.Hub,
+	runners ...server.Reporter,
 ) *API {
 	if accessSVC == nil {
 		panic(service.ErrNilService)
@@ -77,12 +96,27 @@ func NewAPI(
 	if agentSVC == nil {
 		panic(service.ErrNilService)
 	}
+	if enrollSVC == nil {
+		panic(service.ErrNilService)
+	}
 	if specSVC == nil {
 		panic(service.ErrNilService)
 	}
+	if secretSVC == nil {
+		panic(service.ErrNilService)
+	}
+	if runSVC == nil {
+		panic(service.ErrNilService)
+	}
+	if manifestSVC == nil {
+		panic(service.ErrNilService)
+	}
 	if proxyPool == nil {
 		panic(proxy.ErrNilPool)
 	}
+	if metricStore == nil {
+		panic(metrics.ErrNilStore)
+	}
 	if hub == nil {
 		panic(event.ErrNilHub)
 	}
@@ -93,23 +127,39 @@ func NewAPI(
 		sessionSVC:    sessionSVC,
 		accessSVC:     accessSVC,
 		agentSVC:      agentSVC,
+		enrollSVC:     enrollSVC,
 		specSVC:       specSVC,
+		secretSVC:     secretSVC,
+		runSVC:        runSVC,
+		manifestSVC:   manifestSVC,
 		userSVC:       userSVC,
 		proxyPool:     proxyPool,
+		metrics:       metricStore,
 		hub:           hub,
+		runners:       runners,
 	}
 }
 
 // Routes registers API routes.
 // Auth runs at mux level once. Permissions are enforced per-method/per-subroute below.
 func (a *API) Routes(mux *http.ServeMux, auth route.BaseMW, _ route.PermMW, common ...route.BaseMW) {
+	route.HandleFunc(mux, routepath.ApiAuthLogin, a.AuthLogin, common...)
+	route.HandleFunc(mux, routepath.ApiAuthRefresh, a.AuthRefresh, common...)
+	route.HandleFunc(mux, routepath.ApiAuthLogout, a.AuthLogout, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiUsers, a.Users, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiUser, a.UsersRouter, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiSession, a.SessionsRouter, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiAgents, a.Agents, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiAgent, a.AgentsRouter, append(common, auth)...)
+	route.HandleFunc(mux, routepath.ApiEnrollmentTokens, a.EnrollmentTokens, append(common, auth)...)
+	route.HandleFunc(mux, routepath.ApiEnrollmentToken, a.EnrollmentTokensRouter, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiSpecs, a.Specs, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiSpec, a.SpecsRouter, append(common, auth)...)
+	route.HandleFunc(mux, routepath.ApiSecrets, a.Secrets, append(common, auth)...)
+	route.HandleFunc(mux, routepath.ApiSecret, a.SecretsRouter, append(common, auth)...)
+	route.HandleFunc(mux, routepath.ApiRuns, a.Runs, append(common, auth)...)
+	route.HandleFunc(mux, routepath.ApiRun, a.RunsRouter, append(common, auth)...)
+	route.HandleFunc(mux, routepath.ApiApply, a.Apply, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiDashboard, a.Dashboard, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiDashboardIssues, a.IssuesDelete, append(common, auth)...)
 	route.HandleFunc(mux, routepath.ApiPermissions, a.Permissions, append(common, auth)...)
@@ -189,6 +239,12 @@ func queryInt(r *http.Request, key string, fallback int) int {
 	return fallback
 }
 
+// queryBool reads a query parameter as bool, returning false if absent or invalid.
+func queryBool(r *http.Request, key string) bool {
+	b, _ := strconv.ParseBool(r.URL.Query().Get(key))
+	return b
+}
+
 // mapSlice converts a slice of pointers using fn, skipping nils.
 func mapSlice[T any, U any](src []*T, fn func(*T) U) []U {
 	out := make([]U, 0, len(src))
@@ -2,12 +2,19 @@ package handler
 
 import (
 	"cmp"
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/event"
 	"github.com/soltiHQ/control-plane/internal/proxy"
 	"github.com/soltiHQ/control-plane/internal/service/agent"
 	"github.com/soltiHQ/control-plane/internal/storage"
@@ -26,10 +33,13 @@ import (
 	contentAgent "github.com/soltiHQ/control-plane/ui/templates/content/agent"
 )
 
+// defaultMetricsWindow is the metrics history shown when a query names no range.
+const defaultMetricsWindow = time.Hour
+
 // Agents handles /api/v1/agents.
 //
 // Supported:
-//   - GET /api/v1/agents
+//   - GET /api/v1/agents (?approval=pending|approved|rejected filters the approval queue)
 func (a *API) Agents(w http.ResponseWriter, r *http.Request) {
 	route.Resource(w, r, routepath.ApiAgents,
 		route.Endpoint{Method: http.MethodGet, Perm: kind.AgentsGet, Fn: a.agentList},
@@ -41,12 +51,37 @@ func (a *API) Agents(w http.ResponseWriter, r *http.Request) {
 // Supported:
 //   - GET  /api/v1/agents/{id}
 //   - PUT  /api/v1/agents/{id}/labels
+//   - POST /api/v1/agents/{id}/approve
+//   - POST /api/v1/agents/{id}/reject
+//   - POST /api/v1/agents/{id}/cordon
+//   - POST /api/v1/agents/{id}/uncordon
+//   - POST /api/v1/agents/{id}/drain (?retarget=true re-deploys label-selected specs)
 //   - GET  /api/v1/agents/{id}/tasks
+//   - GET  /api/v1/agents/{id}/metrics
+//   - GET  /api/v1/agents/{id}/history
+//   - POST /api/v1/agents/{id}/tasks/{taskID}/cancel
+//   - POST /api/v1/agents/{id}/tasks/{taskID}/restart
+//   - GET  /api/v1/agents/{id}/tasks/{taskID}/logs
 func (a *API) AgentsRouter(w http.ResponseWriter, r *http.Request) {
+	if agentID, ok := agentTaskPath(r.URL.Path); ok {
+		route.Router(w, r, routepath.ApiAgentTask(agentID),
+			route.Subroute{Action: "cancel", Method: http.MethodPost, Perm: kind.AgentsTasks, Fn: a.agentTaskCancel(agentID)},
+			route.Subroute{Action: "restart", Method: http.MethodPost, Perm: kind.AgentsTasks, Fn: a.agentTaskRestart(agentID)},
+			route.Subroute{Action: "logs", Method: http.MethodGet, Perm: kind.AgentsGet, Fn: a.agentTaskLogs(agentID)},
+		)
+		return
+	}
 	route.Router(w, r, routepath.ApiAgent,
 		route.Subroute{Action: "", Method: http.MethodGet, Perm: kind.AgentsGet, Fn: a.agentDetails},
 		route.Subroute{Action: "labels", Method: http.MethodPut, Perm: kind.AgentsEdit, Fn: a.agentPatchLabels},
+		route.Subroute{Action: "approve", Method: http.MethodPost, Perm: kind.AgentsApprove, Fn: a.agentApprove},
+		route.Subroute{Action: "reject", Method: http.MethodPost, Perm: kind.AgentsApprove, Fn: a.agentReject},
+		route.Subroute{Action: "cordon", Method: http.MethodPost, Perm: kind.AgentsDrain, Fn: a.agentCordon},
+		route.Subroute{Action: "uncordon", Method: http.MethodPost, Perm: kind.AgentsDrain, Fn: a.agentUncordon},
+		route.Subroute{Action: "drain", Method: http.MethodPost, Perm: kind.AgentsDrain, Fn: a.agentDrain},
 		route.Subroute{Action: "tasks", Method: http.MethodGet, Perm: kind.AgentsGet, Fn: a.agentTasksList},
+		route.Subroute{Action: "metrics", Method: http.MethodGet, Perm: kind.AgentsGet, Fn: a.agentMetrics},
+		route.Subroute{Action: "history", Method: http.MethodGet, Perm: kind.AgentsGet, Fn: a.agentHistory},
 	)
 }
 
@@ -55,11 +90,21 @@ func (a *API) agentList(w http.ResponseWriter, r *http.Request, mode httpctx.Ren
 		limit  = queryInt(r, "limit", 0)
 		filter storage.AgentFilter
 
-		cursor = r.URL.Query().Get("cursor")
-		q      = r.URL.Query().Get("q")
+		cursor   = r.URL.Query().Get("cursor")
+		q        = r.URL.Query().Get("q")
+		approval = r.URL.Query().Get("approval")
 	)
-	if q != "" {
-		filter = inmemory.NewAgentFilter().Query(q)
+	if q != "" || approval != "" {
+		f := inmemory.NewAgentFilter().Query(q)
+		if approval != "" {
+			s, ok := kind.ParseAgentApproval(approval)
+			if !ok {
+				response.BadRequest(w, r, mode)
+				return
+			}
+			f.ByApproval(s)
+		}
+		filter = f
 	}
 
 	res, err := a.agentSVC.List(r.Context(), agent.ListQuery{
@@ -73,13 +118,19 @@ func (a *API) agentList(w http.ResponseWriter, r *http.Request, mode httpctx.Ren
 		return
 	}
 
-	items := mapSlice(res.Items, apimapv1.Agent)
+	var (
+		items     = mapSlice(res.Items, apimapv1.Agent)
+		component = contentAgent.List(res.Items, res.NextCursor, q)
+	)
+	if approval != "" {
+		component = contentAgent.ApprovalQueue(res.Items, policy.BuildAgentDetail(a.identity(r)))
+	}
 	response.OK(w, r, mode, &responder.View{
 		Data: restv1.AgentListResponse{
 			Items:      items,
 			NextCursor: res.NextCursor,
 		},
-		Component: contentAgent.List(res.Items, res.NextCursor, q),
+		Component: component,
 	})
 }
 
@@ -95,13 +146,105 @@ func (a *API) agentDetails(w http.ResponseWriter, r *http.Request, mode httpctx.
 		return
 	}
 
-	apiAgent := apimapv1.Agent(ag)
+	var (
+		apiAgent = apimapv1.Agent(ag)
+		samples  = apimapv1.AgentMetricSamples(a.metrics.Range(id, time.Now().Add(-defaultMetricsWindow), time.Time{}))
+	)
 	response.OK(w, r, mode, &responder.View{
 		Data:      apiAgent,
-		Component: contentAgent.Detail(apiAgent, policy.BuildAgentDetail(a.identity(r))),
+		Component: contentAgent.Detail(apiAgent, samples, policy.BuildAgentDetail(a.identity(r))),
+	})
+}
+
+// agentMetrics returns the agent's metrics history, oldest first.
+//
+// The range is ?from=…&to=… (RFC 3339), or ?since=DURATION back from now;
+// it defaults to the last hour. Only the in-memory history is queried.
+func (a *API) agentMetrics(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	if _, err := a.agentSVC.Get(r.Context(), id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("agent_id", id).Msg("agent get failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	from, to, err := metricsRange(r, time.Now())
+	if err != nil {
+		response.BadRequestMsg(w, r, mode, err.Error())
+		return
+	}
+
+	items := apimapv1.AgentMetricSamples(a.metrics.Range(id, from, to))
+	response.OK(w, r, mode, &responder.View{
+		Data:      restv1.AgentMetricsResponse{Items: items},
+		Component: contentAgent.Metrics(items),
+	})
+}
+
+// agentHistory returns a page of the agent's inventory change history, newest first.
+func (a *API) agentHistory(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	if _, err := a.agentSVC.Get(r.Context(), id); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("agent_id", id).Msg("agent get failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	res, err := a.agentSVC.History(r.Context(), agent.HistoryQuery{
+		Filter:  inmemory.NewAgentChangeFilter().ByAgentID(id),
+		AgentID: id,
+		Cursor:  r.URL.Query().Get("cursor"),
+		Limit:   queryInt(r, "limit", 0),
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrInvalidArgument) {
+			response.BadRequest(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("agent_id", id).Msg("agent history failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	response.OK(w, r, mode, &responder.View{
+		Data: restv1.AgentHistoryResponse{
+			Items:      mapSlice(res.Items, apimapv1.AgentChange),
+			NextCursor: res.NextCursor,
+		},
+		Component: contentAgent.History(id, res.Items, res.NextCursor),
 	})
 }
 
+// metricsRange parses the time range of a metrics query.
+func metricsRange(r *http.Request, now time.Time) (from, to time.Time, err error) {
+	q := r.URL.Query()
+	if raw := q.Get("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			return from, to, fmt.Errorf("invalid to: %q", raw)
+		}
+	}
+	if raw := q.Get("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			return from, to, fmt.Errorf("invalid from: %q", raw)
+		}
+		return from, to, nil
+	}
+
+	since := defaultMetricsWindow
+	if raw := q.Get("since"); raw != "" {
+		if since, err = time.ParseDuration(raw); err != nil || since <= 0 {
+			return from, to, fmt.Errorf("invalid since: %q", raw)
+		}
+	}
+	return now.Add(-since), to, nil
+}
+
 func (a *API) agentPatchLabels(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
 	labels, err := decodeJSON[map[string]string](r)
 	if err != nil {
@@ -129,19 +272,129 @@ func (a *API) agentPatchLabels(w http.ResponseWriter, r *http.Request, mode http
 	response.NoContent(w, r)
 }
 
-// TODO: remove "q" - need to understand a correct way for getting tasks from agent with paginator and etc.
-func (a *API) agentTasksList(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, agentID string) {
-	ag, err := a.agentSVC.Get(r.Context(), agentID)
+func (a *API) agentApprove(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	a.agentSetApproval(w, r, mode, id, kind.AgentApprovalApproved, event.AgentApproved)
+}
+
+func (a *API) agentReject(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	a.agentSetApproval(w, r, mode, id, kind.AgentApprovalRejected, event.AgentApprovalRejected)
+}
+
+// agentSetApproval approves or rejects an agent and closes its pending-approval issue.
+func (a *API) agentSetApproval(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string, approval kind.AgentApproval, kindEvent string) {
+	ag, err := a.agentSVC.SetApproval(r.Context(), id, approval)
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
 			response.NotFound(w, r, mode)
 			return
 		}
+		a.logger.Error().Err(err).Str("agent_id", id).Msg("agent approval failed")
 		response.Unavailable(w, r, mode)
 		return
 	}
-	if ag.Endpoint() == "" {
+
+	by := a.actor(r)
+	a.hub.Record(kindEvent, event.Payload{ID: id, Name: ag.Name(), By: by})
+	if a.hub.DeleteIssues(event.AgentPendingApproval, id) > 0 {
+		a.hub.Record(event.IssueClosed, event.Payload{ID: id, Name: ag.Name(), By: by})
+	}
+	a.logger.Info().Str("agent_id", id).Str("approval", approval.String()).Msg("agent approval changed")
+	htmx.Trigger(w, htmx.AgentUpdate)
+	a.hub.Notify(htmx.AgentUpdate)
+	a.hub.Notify(htmx.DashboardUpdate)
+	response.NoContent(w, r)
+}
+
+func (a *API) agentCordon(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	if _, ok := a.agentSetCordon(w, r, mode, id, true); !ok {
+		return
+	}
+	response.NoContent(w, r)
+}
+
+func (a *API) agentUncordon(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	if _, ok := a.agentSetCordon(w, r, mode, id, false); !ok {
+		return
+	}
+	response.NoContent(w, r)
+}
+
+// agentSetCordon cordons or uncordons an agent and records the change.
+// An agent already in the requested state is returned as is, without an event.
+// It writes the error response and reports false when the agent could not be updated.
+func (a *API) agentSetCordon(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string, cordoned bool) (*model.Agent, bool) {
+	ag, err := a.agentSVC.Get(r.Context(), id)
+	if err == nil {
+		if ag.Cordoned() == cordoned {
+			return ag, true
+		}
+		ag, err = a.agentSVC.SetCordon(r.Context(), id, cordoned)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return nil, false
+		}
+		a.logger.Error().Err(err).Str("agent_id", id).Msg("agent cordon failed")
 		response.Unavailable(w, r, mode)
+		return nil, false
+	}
+
+	kindEvent := event.AgentCordoned
+	if !cordoned {
+		kindEvent = event.AgentUncordoned
+	}
+	a.hub.Record(kindEvent, event.Payload{ID: id, Name: ag.Name(), By: a.actor(r)})
+	a.logger.Info().Str("agent_id", id).Bool("cordoned", cordoned).Msg("agent cordon changed")
+	htmx.Trigger(w, htmx.AgentUpdate)
+	a.hub.Notify(htmx.AgentUpdate)
+	return ag, true
+}
+
+// agentDrain cordons an agent and removes all of its rollouts.
+// With ?retarget=true the label-selected specs it ran are rolled out to matching agents that lack them.
+func (a *API) agentDrain(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	ag, ok := a.agentSetCordon(w, r, mode, id, true)
+	if !ok {
+		return
+	}
+
+	res, err := a.specSVC.Drain(r.Context(), id, queryBool(r, "retarget"), inmemory.NewRolloutFilter().ByAgentID(id))
+	if err != nil {
+		a.logger.Error().Err(err).Str("agent_id", id).Msg("agent drain failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+	for specID, reason := range res.Failed {
+		a.logger.Warn().Str("agent_id", id).Str("spec", specID).Str("reason", reason).Msg("drain: retarget failed")
+	}
+
+	a.logger.Info().
+		Str("agent_id", id).
+		Int("undeployed", len(res.Undeployed)).
+		Int("retargeted", len(res.Retargeted)).
+		Msg("agent drained")
+	a.hub.Record(event.AgentDrained, event.Payload{
+		ID:     id,
+		Name:   ag.Name(),
+		By:     a.actor(r),
+		Detail: fmt.Sprintf("%d specs undeployed, %d retargeted", len(res.Undeployed), len(res.Retargeted)),
+	})
+	a.hub.Notify(htmx.SpecUpdate)
+
+	response.OK(w, r, mode, &responder.View{
+		Data: restv1.AgentDrainResponse{
+			Undeployed: res.Undeployed,
+			Retargeted: res.Retargeted,
+			Failed:     res.Failed,
+		},
+	})
+}
+
+// TODO: remove "q" - need to understand a correct way for getting tasks from agent with paginator and etc.
+func (a *API) agentTasksList(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, agentID string) {
+	ag, p, ok := a.agentProxy(w, r, mode, agentID)
+	if !ok {
 		return
 	}
 
@@ -154,22 +407,6 @@ func (a *API) agentTasksList(w http.ResponseWriter, r *http.Request, mode httpct
 		q = r.URL.Query().Get("q")
 	)
 
-	p, err := a.proxyPool.Get(ag.Endpoint(), ag.EndpointType(), ag.APIVersion())
-	if err != nil {
-		a.logger.Error().Err(err).
-			Str("agent_id", agentID).
-			Str("endpoint", ag.Endpoint()).
-			Msg("proxy: pool get failed")
-		switch {
-		case errors.Is(err, proxy.ErrUnsupportedAPIVersion),
-			errors.Is(err, proxy.ErrUnsupportedEndpointType):
-			response.BadRequest(w, r, mode)
-		default:
-			response.Unavailable(w, r, mode)
-		}
-		return
-	}
-
 	result, err := p.ListTasks(r.Context(), filter)
 	if err != nil {
 		a.logger.Warn().Err(err).
@@ -196,6 +433,179 @@ func (a *API) agentTasksList(w http.ResponseWriter, r *http.Request, mode httpct
 	})
 	response.OK(w, r, mode, &responder.View{
 		Data:      result,
-		Component: contentAgent.Tasks(agentID, result.Tasks, result.Total, q, filter.Offset),
+		Component: contentAgent.Tasks(agentID, result.Tasks, result.Total, q, filter.Offset, policy.BuildAgentDetail(a.identity(r))),
 	})
 }
+
+func (a *API) agentTaskCancel(agentID string) route.EntityHandler {
+	return func(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, taskID string) {
+		a.agentTaskControl(w, r, mode, agentID, taskID, event.TaskCanceled, proxy.AgentProxy.CancelTask)
+	}
+}
+
+func (a *API) agentTaskRestart(agentID string) route.EntityHandler {
+	return func(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, taskID string) {
+		a.agentTaskControl(w, r, mode, agentID, taskID, event.TaskRestarted, proxy.AgentProxy.RestartTask)
+	}
+}
+
+// agentTaskControl forwards a task control operation to the agent and records it as evKind.
+func (a *API) agentTaskControl(
+	w http.ResponseWriter,
+	r *http.Request,
+	mode httpctx.RenderMode,
+	agentID, taskID, evKind string,
+	op func(proxy.AgentProxy, context.Context, string) error,
+) {
+	ag, p, ok := a.agentProxy(w, r, mode, agentID)
+	if !ok {
+		return
+	}
+
+	if err := op(p, r.Context(), taskID); err != nil {
+		a.logger.Warn().Err(err).
+			Str("agent_id", agentID).
+			Str("task_id", taskID).
+			Str("endpoint", ag.Endpoint()).
+			Msg("proxy: task control failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	a.logger.Info().Str("agent_id", agentID).Str("task_id", taskID).Str("op", evKind).Msg("agent task control sent")
+	a.hub.Record(evKind, event.Payload{ID: agentID, Name: ag.Name(), Detail: "task " + taskID, By: a.actor(r)})
+	htmx.Trigger(w, htmx.AgentUpdate)
+	a.hub.Notify(htmx.AgentUpdate)
+	response.NoContent(w, r)
+}
+
+// agentTaskLogs relays task output from the agent as Server-Sent Events.
+//
+// Query: tail=N (last N lines, 0 = agent default), follow=true (keep streaming new lines).
+// Each line is sent as a "data:" JSON [proxyv1.LogLine]; the stream always closes with
+// an "end" event carrying [restv1.TaskLogEnd], so browsers know not to reconnect.
+// Errors before the first line are returned as regular HTTP errors.
+func (a *API) agentTaskLogs(agentID string) route.EntityHandler {
+	return func(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, taskID string) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			response.Unavailable(w, r, mode)
+			return
+		}
+		ag, p, ok := a.agentProxy(w, r, mode, agentID)
+		if !ok {
+			return
+		}
+
+		opts := proxy.LogOptions{
+			Tail:   queryInt(r, "tail", 0),
+			Follow: queryBool(r, "follow"),
+		}
+		if opts.Follow {
+			if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+				response.Unavailable(w, r, mode)
+				return
+			}
+		}
+
+		started := false
+		start := func() {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("Connection", "keep-alive")
+			w.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		err := p.TaskLogs(r.Context(), taskID, opts, func(line proxyv1.LogLine) error {
+			if !started {
+				start()
+			}
+			if err := writeSSE(w, "", line); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+		if r.Context().Err() != nil {
+			return
+		}
+
+		var end restv1.TaskLogEnd
+		if err != nil {
+			a.logger.Warn().Err(err).
+				Str("agent_id", agentID).
+				Str("task_id", taskID).
+				Str("endpoint", ag.Endpoint()).
+				Msg("proxy: TaskLogs failed")
+			if !started {
+				response.Unavailable(w, r, mode)
+				return
+			}
+			end.Error = "agent log stream interrupted"
+		}
+		if !started {
+			start()
+		}
+		_ = writeSSE(w, "end", end)
+		flusher.Flush()
+	}
+}
+
+// writeSSE writes v as a JSON Server-Sent Event, named ev unless empty.
+func writeSSE(w io.Writer, ev string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if ev != "" {
+		if _, err = fmt.Fprintf(w, "event: %s\n", ev); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+// agentProxy loads an agent and returns a proxy for its endpoint or open Connect session.
+// On failure the error response is already written and ok is false.
+func (a *API) agentProxy(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, agentID string) (*model.Agent, proxy.AgentProxy, bool) {
+	ag, err := a.agentSVC.Get(r.Context(), agentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return nil, nil, false
+		}
+		response.Unavailable(w, r, mode)
+		return nil, nil, false
+	}
+	if (ag.Endpoint() == "" || ag.PullMode()) && !a.proxyPool.Connected(ag.ID()) {
+		response.Unavailable(w, r, mode)
+		return nil, nil, false
+	}
+
+	p, err := a.proxyPool.Get(ag.ID(), ag.Endpoint(), ag.EndpointType(), ag.APIVersion())
+	if err != nil {
+		a.logger.Error().Err(err).
+			Str("agent_id", agentID).
+			Str("endpoint", ag.Endpoint()).
+			Msg("proxy: pool get failed")
+		switch {
+		case errors.Is(err, proxy.ErrUnsupportedAPIVersion),
+			errors.Is(err, proxy.ErrUnsupportedEndpointType):
+			response.BadRequest(w, r, mode)
+		default:
+			response.Unavailable(w, r, mode)
+		}
+		return nil, nil, false
+	}
+	return ag, p, true
+}
+
+// agentTaskPath reports whether path addresses a single agent task
+// (/api/v1/agents/{id}/tasks/{taskID}[/...]) and returns the agent ID.
+func agentTaskPath(path string) (string, bool) {
+	id, tail, _ := strings.Cut(strings.TrimPrefix(path, routepath.ApiAgent), "/")
+	rest, ok := strings.CutPrefix(tail, "tasks/")
+	return id, ok && id != "" && rest != ""
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/service/manifest"
+	"github.com/soltiHQ/control-plane/internal/transport/http/responder"
+	"github.com/soltiHQ/control-plane/internal/transport/http/response"
+	"github.com/soltiHQ/control-plane/internal/transport/http/route"
+	"github.com/soltiHQ/control-plane/internal/transport/httpctx"
+	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
+	"github.com/soltiHQ/control-plane/internal/uikit/routepath"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	apimapv1 "github.com/soltiHQ/control-plane/internal/transport/http/apimap/v1"
+)
+
+// maxManifestBytes caps the size of an apply request body.
+const maxManifestBytes = 4 << 20
+
+// Apply handles /api/v1/apply.
+//
+// Supported:
+//   - POST /api/v1/apply?prune={bool}&dry_run={bool}
+//
+// The body is a multi-document YAML (or JSON) manifest of restv1.ManifestDocument.
+func (a *API) Apply(w http.ResponseWriter, r *http.Request) {
+	route.Resource(w, r, routepath.ApiApply,
+		route.Endpoint{Method: http.MethodPost, Perm: kind.SpecsEdit, Fn: a.apply},
+	)
+}
+
+func (a *API) apply(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxManifestBytes))
+	if err != nil {
+		response.BadRequestMsg(w, r, mode, "manifest too large or unreadable")
+		return
+	}
+	parsed, err := manifest.Parse(data)
+	if err != nil {
+		response.BadRequestMsg(w, r, mode, err.Error())
+		return
+	}
+	docs := apimapv1.ManifestDocuments(parsed)
+
+	opts := manifest.Options{
+		Prune:  queryBool(r, "prune"),
+		DryRun: queryBool(r, "dry_run"),
+	}
+	if !a.canApply(r, docs, opts) {
+		response.Forbidden(w, r, mode)
+		return
+	}
+	opts.DenySecrets = !a.identity(r).HasPermission(kind.SecretsGet)
+
+	res, err := a.manifestSVC.Apply(r.Context(), docs, opts)
+	if err != nil {
+		a.logger.Error().Err(err).Msg("manifest apply failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	if !res.DryRun {
+		a.recordApply(r, res)
+	}
+	a.logger.Info().
+		Int("objects", len(res.Items)).
+		Int("failed", res.Failed()).
+		Bool("dry_run", res.DryRun).
+		Msg("manifest applied")
+	response.OK(w, r, mode, &responder.View{Data: applyResponse(res)})
+}
+
+// canApply checks the permissions the manifest needs beyond SpecsEdit:
+// SpecsAdd to create specs, AgentsEdit for label documents, SpecsDelete to prune.
+func (a *API) canApply(r *http.Request, docs []manifest.Document, opts manifest.Options) bool {
+	id := a.identity(r)
+	if id == nil || !id.HasPermission(kind.SpecsAdd) {
+		return false
+	}
+	if opts.Prune && !id.HasPermission(kind.SpecsDelete) {
+		return false
+	}
+	for _, doc := range docs {
+		if doc.Kind == manifest.KindAgentLabels && !id.HasPermission(kind.AgentsEdit) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordApply publishes activity events for every object that changed.
+func (a *API) recordApply(r *http.Request, res *manifest.Result) {
+	var specs, agents bool
+	for _, it := range res.Items {
+		p := event.Payload{ID: it.ID, Name: it.Name, By: a.actor(r)}
+		switch {
+		case it.Kind == manifest.KindSpec && it.Action == manifest.ActionCreated:
+			a.hub.Record(event.SpecCreated, p)
+			specs = true
+		case it.Kind == manifest.KindSpec && it.Action == manifest.ActionUpdated:
+			a.hub.Record(event.SpecUpdated, p)
+			specs = true
+		case it.Kind == manifest.KindSpec && it.Action == manifest.ActionDeleted:
+			a.hub.Record(event.SpecDeleted, p)
+			specs = true
+		case it.Kind == manifest.KindAgentLabels && it.Action == manifest.ActionUpdated:
+			agents = true
+		}
+	}
+	if specs {
+		a.hub.Notify(htmx.SpecUpdate)
+	}
+	if agents {
+		a.hub.Notify(htmx.AgentUpdate)
+	}
+}
+
+// applyResponse maps a manifest apply result to its REST DTO.
+func applyResponse(res *manifest.Result) restv1.ApplyResponse {
+	out := restv1.ApplyResponse{
+		Items:  make([]restv1.ApplyResult, 0, len(res.Items)),
+		DryRun: res.DryRun,
+		Failed: res.Failed(),
+	}
+	for _, it := range res.Items {
+		item := restv1.ApplyResult{
+			Fields: it.Fields,
+			Kind:   it.Kind,
+			Name:   it.Name,
+			ID:     it.ID,
+			Action: string(it.Action),
+		}
+		if it.Err != nil {
+			item.Error = it.Err.Error()
+			if errors.Is(it.Err, domain.ErrInvalidSpec) {
+				item.Error = domain.ErrInvalidSpec.Error()
+			}
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/soltiHQ/control-plane/internal/auth"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/service/access"
+	"github.com/soltiHQ/control-plane/internal/transport/http/ratelimitkey"
+	"github.com/soltiHQ/control-plane/internal/transport/http/responder"
+	"github.com/soltiHQ/control-plane/internal/transport/http/response"
+	"github.com/soltiHQ/control-plane/internal/transport/httpctx"
+	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
+	"github.com/soltiHQ/control-plane/internal/uikit/routepath"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// AuthLogin handles POST /api/v1/auth/login.
+//
+// Token-based counterpart of the /login form for API clients: returns the
+// token pair in the body instead of setting cookies. Not behind auth.
+func (a *API) AuthLogin(w http.ResponseWriter, r *http.Request) {
+	mode := httpctx.ModeFromRequest(r)
+	if !a.authPost(w, r, mode, routepath.ApiAuthLogin) {
+		return
+	}
+
+	in, err := decodeJSON[restv1.LoginRequest](r)
+	if err != nil {
+		response.BadRequest(w, r, mode)
+		return
+	}
+	id, res, err := a.accessSVC.Login(r.Context(), access.LoginRequest{
+		Subject:  in.Subject,
+		Password: in.Password,
+		RateKey:  ratelimitkey.LoginKey(r, in.Subject),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrRateLimited):
+			a.hub.Record(event.RateLimited, event.Payload{
+				ID: in.Subject, Name: in.Subject, By: "auth",
+			})
+			response.AuthRateLimit(w, r, mode)
+		case errors.Is(err, auth.ErrInvalidCredentials),
+			errors.Is(err, auth.ErrInvalidRequest):
+			response.Unauthorized(w, r, mode)
+		default:
+			a.logger.Error().Err(err).Str("subject", in.Subject).Msg("api login error")
+			response.Unavailable(w, r, mode)
+		}
+		return
+	}
+
+	a.logger.Info().Str("subject", in.Subject).Msg("api login success")
+	a.hub.Record(event.SessionCreated, event.Payload{
+		ID: id.UserID, Name: id.Name, By: "auth",
+	})
+	a.hub.Notify(htmx.SessionUpdate)
+	response.OK(w, r, mode, &responder.View{Data: restv1.LoginResponse{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+		SessionID:    res.SessionID,
+	}})
+}
+
+// AuthRefresh handles POST /api/v1/auth/refresh.
+//
+// Exchanges a session's refresh token for a new token pair. Not behind auth,
+// since it is called once the access token has expired.
+func (a *API) AuthRefresh(w http.ResponseWriter, r *http.Request) {
+	mode := httpctx.ModeFromRequest(r)
+	if !a.authPost(w, r, mode, routepath.ApiAuthRefresh) {
+		return
+	}
+
+	in, err := decodeJSON[restv1.RefreshRequest](r)
+	if err != nil {
+		response.BadRequest(w, r, mode)
+		return
+	}
+	_, res, err := a.accessSVC.Refresh(r.Context(), access.RefreshRequest{
+		SessionID:    in.SessionID,
+		RefreshToken: in.RefreshToken,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidRequest),
+			errors.Is(err, auth.ErrInvalidRefresh),
+			errors.Is(err, auth.ErrExpiredToken),
+			errors.Is(err, auth.ErrRevoked),
+			errors.Is(err, auth.ErrUserDisabled):
+			response.Unauthorized(w, r, mode)
+		default:
+			a.logger.Error().Err(err).Str("session_id", in.SessionID).Msg("api refresh error")
+			response.Unavailable(w, r, mode)
+		}
+		return
+	}
+	response.OK(w, r, mode, &responder.View{Data: restv1.LoginResponse{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+		SessionID:    res.SessionID,
+	}})
+}
+
+// AuthLogout handles POST /api/v1/auth/logout.
+//
+// Revokes the caller's own session. The body may name the session explicitly;
+// it must match the session of the access token. Revoking other sessions
+// goes through /api/v1/session/{id}/revoke.
+func (a *API) AuthLogout(w http.ResponseWriter, r *http.Request) {
+	mode := httpctx.ModeFromRequest(r)
+	if !a.authPost(w, r, mode, routepath.ApiAuthLogout) {
+		return
+	}
+
+	id := a.identity(r)
+	if id == nil || id.SessionID == "" {
+		response.Unauthorized(w, r, mode)
+		return
+	}
+	var in restv1.LogoutRequest
+	if r.ContentLength != 0 {
+		var err error
+		if in, err = decodeJSON[restv1.LogoutRequest](r); err != nil {
+			response.BadRequest(w, r, mode)
+			return
+		}
+	}
+	if in.SessionID != "" && in.SessionID != id.SessionID {
+		response.Forbidden(w, r, mode)
+		return
+	}
+
+	if err := a.accessSVC.Logout(r.Context(), access.LogoutRequest{SessionID: id.SessionID}); err != nil {
+		a.logger.Error().Err(err).Str("session_id", id.SessionID).Msg("api logout error")
+		response.Unavailable(w, r, mode)
+		return
+	}
+	a.logger.Info().Str("subject", id.Name).Msg("api logout")
+	a.hub.Notify(htmx.SessionUpdate)
+	response.NoContent(w, r)
+}
+
+// authPost checks the exact path and POST method of an auth endpoint.
+func (a *API) authPost(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, path string) bool {
+	if r.URL.Path != path {
+		response.NotFound(w, r, mode)
+		return false
+	}
+	if r.Method != http.MethodPost {
+		response.NotAllowed(w, r, mode)
+		return false
+	}
+	return true
+}
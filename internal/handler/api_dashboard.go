@@ -5,6 +5,7 @@ import (
 
 	"github.com/soltiHQ/control-plane/domain/kind"
 	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/server"
 	"github.com/soltiHQ/control-plane/internal/service/agent"
 	"github.com/soltiHQ/control-plane/internal/service/spec"
 	"github.com/soltiHQ/control-plane/internal/service/user"
@@ -99,8 +100,9 @@ func (a *API) Dashboard(w http.ResponseWriter, r *http.Request) {
 		FailedRollouts:     failed,
 		DriftRollouts:      drift,
 
-		Events: a.hub.RecentEvents(30),
-		Issues: contentHome.GroupIssues(a.hub.RecentIssues(100)),
+		Runners: a.runnerStatus(),
+		Events:  a.hub.RecentEvents(30),
+		Issues:  contentHome.GroupIssues(a.hub.RecentIssues(100)),
 	}
 	response.OK(w, r, mode, &responder.View{
 		Data:      stats,
@@ -108,6 +110,15 @@ func (a *API) Dashboard(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// runnerStatus collects the status of every reporting runner.
+func (a *API) runnerStatus() []server.Status {
+	out := make([]server.Status, 0, len(a.runners))
+	for _, r := range a.runners {
+		out = append(out, r.Status())
+	}
+	return out
+}
+
 // IssuesDelete handles DELETE /api/v1/dashboard/issues.
 func (a *API) IssuesDelete(w http.ResponseWriter, r *http.Request) {
 	mode := httpctx.ModeFromRequest(r)
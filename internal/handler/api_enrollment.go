@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/service/enrollment"
+	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+	"github.com/soltiHQ/control-plane/internal/transport/http/responder"
+	"github.com/soltiHQ/control-plane/internal/transport/http/response"
+	"github.com/soltiHQ/control-plane/internal/transport/http/route"
+	"github.com/soltiHQ/control-plane/internal/transport/httpctx"
+	"github.com/soltiHQ/control-plane/internal/uikit/routepath"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	apimapv1 "github.com/soltiHQ/control-plane/internal/transport/http/apimap/v1"
+)
+
+// EnrollmentTokens handles /api/v1/enrollment-tokens.
+//
+// Supported:
+//   - GET  /api/v1/enrollment-tokens
+//   - POST /api/v1/enrollment-tokens
+func (a *API) EnrollmentTokens(w http.ResponseWriter, r *http.Request) {
+	route.Resource(w, r, routepath.ApiEnrollmentTokens,
+		route.Endpoint{Method: http.MethodGet, Perm: kind.EnrollmentGet, Fn: a.enrollmentTokenList},
+		route.Endpoint{Method: http.MethodPost, Perm: kind.EnrollmentAdd, Fn: a.enrollmentTokenCreate},
+	)
+}
+
+// EnrollmentTokensRouter handles /api/v1/enrollment-tokens/{id}.
+//
+// Supported:
+//   - GET    /api/v1/enrollment-tokens/{id}
+//   - DELETE /api/v1/enrollment-tokens/{id}
+func (a *API) EnrollmentTokensRouter(w http.ResponseWriter, r *http.Request) {
+	route.Router(w, r, routepath.ApiEnrollmentToken,
+		route.Subroute{Action: "", Method: http.MethodGet, Perm: kind.EnrollmentGet, Fn: a.enrollmentTokenDetails},
+		route.Subroute{Action: "", Method: http.MethodDelete, Perm: kind.EnrollmentDelete, Fn: a.enrollmentTokenDelete},
+	)
+}
+
+func (a *API) enrollmentTokenList(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode) {
+	var (
+		limit  = queryInt(r, "limit", 0)
+		filter storage.EnrollmentTokenFilter
+
+		cursor = r.URL.Query().Get("cursor")
+		q      = r.URL.Query().Get("q")
+	)
+	if q != "" {
+		filter = inmemory.NewEnrollmentTokenFilter().Query(q)
+	}
+
+	res, err := a.enrollSVC.List(r.Context(), enrollment.ListQuery{
+		Limit:  limit,
+		Cursor: cursor,
+		Filter: filter,
+	})
+	if err != nil {
+		a.logger.Error().Err(err).Msg("enrollment token list failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	response.OK(w, r, mode, &responder.View{
+		Data: restv1.EnrollmentTokenListResponse{
+			Items:      mapSlice(res.Items, apimapv1.EnrollmentToken),
+			NextCursor: res.NextCursor,
+		},
+	})
+}
+
+func (a *API) enrollmentTokenDetails(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	t, err := a.enrollSVC.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("token_id", id).Msg("enrollment token get failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	response.OK(w, r, mode, &responder.View{
+		Data: apimapv1.EnrollmentToken(t),
+	})
+}
+
+func (a *API) enrollmentTokenCreate(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode) {
+	in, err := decodeJSON[restv1.EnrollmentTokenCreateRequest](r)
+	if err != nil {
+		response.BadRequest(w, r, mode)
+		return
+	}
+	if in.Name == "" {
+		response.BadRequestMsg(w, r, mode, "name is required")
+		return
+	}
+	if in.TTLSeconds < 0 || in.MaxUses < 0 {
+		response.BadRequestMsg(w, r, mode, "ttl_s and max_uses must not be negative")
+		return
+	}
+
+	created, err := a.enrollSVC.Create(r.Context(), enrollment.CreateRequest{
+		Name:    in.Name,
+		Labels:  in.Labels,
+		TTL:     time.Duration(in.TTLSeconds) * time.Second,
+		MaxUses: in.MaxUses,
+	})
+	if err != nil {
+		a.logger.Error().Err(err).Msg("enrollment token create failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	t := created.Token
+	a.logger.Info().Str("token_id", t.ID()).Str("name", t.Name()).Msg("enrollment token created")
+	a.hub.Record(event.EnrollmentTokenCreated, event.Payload{ID: t.ID(), Name: t.Name(), By: a.actor(r)})
+	response.OK(w, r, mode, &responder.View{
+		Data: restv1.EnrollmentTokenCreateResponse{
+			EnrollmentToken: apimapv1.EnrollmentToken(t),
+			Token:           created.Value,
+		},
+	})
+}
+
+func (a *API) enrollmentTokenDelete(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	var name string
+	if t, err := a.enrollSVC.Get(r.Context(), id); err == nil {
+		name = t.Name()
+	}
+
+	err := a.enrollSVC.Delete(r.Context(), id)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		a.logger.Error().Err(err).Str("token_id", id).Msg("enrollment token delete failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+	a.logger.Info().Str("token_id", id).Msg("enrollment token deleted")
+	a.hub.Record(event.EnrollmentTokenDeleted, event.Payload{ID: id, Name: name, By: a.actor(r)})
+	response.NoContent(w, r)
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/segmentio/ksuid"
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/service/run"
+	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+	"github.com/soltiHQ/control-plane/internal/transport/http/responder"
+	"github.com/soltiHQ/control-plane/internal/transport/http/response"
+	"github.com/soltiHQ/control-plane/internal/transport/http/route"
+	"github.com/soltiHQ/control-plane/internal/transport/httpctx"
+	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
+	"github.com/soltiHQ/control-plane/internal/uikit/policy"
+	"github.com/soltiHQ/control-plane/internal/uikit/routepath"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	apimapv1 "github.com/soltiHQ/control-plane/internal/transport/http/apimap/v1"
+	contentRun "github.com/soltiHQ/control-plane/ui/templates/content/run"
+)
+
+// Runs handles /api/v1/runs.
+//
+// Supported:
+//   - GET  /api/v1/runs
+//   - POST /api/v1/runs
+func (a *API) Runs(w http.ResponseWriter, r *http.Request) {
+	route.Resource(w, r, routepath.ApiRuns,
+		route.Endpoint{Method: http.MethodGet, Perm: kind.RunsGet, Fn: a.runList},
+		route.Endpoint{Method: http.MethodPost, Perm: kind.RunsAdd, Fn: a.runCreate},
+	)
+}
+
+// RunsRouter handles /api/v1/runs/{id}.
+//
+// Supported:
+//   - GET    /api/v1/runs/{id}
+//   - DELETE /api/v1/runs/{id}
+func (a *API) RunsRouter(w http.ResponseWriter, r *http.Request) {
+	route.Router(w, r, routepath.ApiRun,
+		route.Subroute{Action: "", Method: http.MethodGet, Perm: kind.RunsGet, Fn: a.runDetails},
+		route.Subroute{Action: "", Method: http.MethodDelete, Perm: kind.RunsDelete, Fn: a.runDelete},
+	)
+}
+
+func (a *API) runList(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode) {
+	var (
+		limit  = queryInt(r, "limit", 0)
+		filter storage.RunFilter
+
+		cursor = r.URL.Query().Get("cursor")
+		q      = r.URL.Query().Get("q")
+	)
+	if q != "" {
+		filter = inmemory.NewRunFilter().Query(q)
+	}
+
+	res, err := a.runSVC.List(r.Context(), run.ListQuery{
+		Limit:  limit,
+		Cursor: cursor,
+		Filter: filter,
+	})
+	if err != nil {
+		a.logger.Error().Err(err).Msg("run list failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	items := mapSlice(res.Items, apimapv1.Run)
+	response.OK(w, r, mode, &responder.View{
+		Data: restv1.RunListResponse{
+			Items:      items,
+			NextCursor: res.NextCursor,
+		},
+		Component: contentRun.List(items, res.NextCursor, q),
+	})
+}
+
+func (a *API) runDetails(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	x, err := a.runSVC.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("run_id", id).Msg("run get failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	dto := apimapv1.Run(x)
+	response.OK(w, r, mode, &responder.View{
+		Data:      dto,
+		Component: contentRun.Detail(dto, policy.BuildRunDetail(a.identity(r))),
+	})
+}
+
+func (a *API) runCreate(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode) {
+	in, err := decodeJSON[restv1.RunCreateRequest](r)
+	if err != nil {
+		response.BadRequest(w, r, mode)
+		return
+	}
+	if len(in.Targets) == 0 && len(in.TargetLabels) == 0 {
+		response.BadRequestMsg(w, r, mode, "targets or target_labels are required")
+		return
+	}
+
+	x, err := model.NewRun(ksuid.New().String(), in.Name)
+	if err != nil {
+		response.BadRequest(w, r, mode)
+		return
+	}
+	if in.KindType != "" {
+		x.SetKindType(kind.TaskKindType(in.KindType))
+	}
+	x.SetKindConfig(in.KindConfig)
+	if in.TimeoutMs > 0 {
+		x.SetTimeoutMs(in.TimeoutMs)
+	}
+	if len(in.RunnerLabels) > 0 {
+		x.SetRunnerLabels(in.RunnerLabels)
+	}
+	if len(in.TargetLabels) > 0 {
+		x.SetTargetLabels(in.TargetLabels)
+	}
+	x.SetCreatedBy(a.actor(r))
+
+	if err = x.Validate(); err != nil {
+		var ve *model.ValidationError
+		if errors.As(err, &ve) {
+			response.Invalid(w, r, mode, ve.FieldMap())
+			return
+		}
+		response.BadRequestMsg(w, r, mode, err.Error())
+		return
+	}
+
+	if !a.checkSecretRefs(w, r, mode, x.SecretRefs) {
+		return
+	}
+
+	if err = a.runSVC.Create(r.Context(), x, in.Targets); err != nil {
+		if errors.Is(err, domain.ErrNoTargets) || errors.Is(err, domain.ErrUnknownTarget) ||
+			errors.Is(err, domain.ErrAgentNotApproved) || errors.Is(err, domain.ErrAgentCordoned) {
+			response.BadRequestMsg(w, r, mode, err.Error())
+			return
+		}
+		a.logger.Error().Err(err).Msg("run create failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	targets := len(x.Results())
+	a.logger.Info().Str("run_id", x.ID()).Int("targets", targets).Msg("run created")
+	a.hub.Record(event.RunCreated, event.Payload{ID: x.ID(), Name: x.Name(), Detail: strconv.Itoa(targets) + " agents", By: x.CreatedBy()})
+	a.hub.Notify(htmx.RunUpdate)
+	response.OK(w, r, mode, &responder.View{Data: apimapv1.Run(x)})
+}
+
+func (a *API) runDelete(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	var name string
+	if x, err := a.runSVC.Get(r.Context(), id); err == nil {
+		name = x.Name()
+	}
+
+	err := a.runSVC.Delete(r.Context(), id)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		a.logger.Error().Err(err).Str("run_id", id).Msg("run delete failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+	a.logger.Info().Str("run_id", id).Msg("run deleted")
+	a.hub.Record(event.RunDeleted, event.Payload{ID: id, Name: name, By: a.actor(r)})
+	a.hub.Notify(htmx.RunUpdate)
+	htmx.Redirect(w, routepath.PageRuns)
+	response.NoContent(w, r)
+}
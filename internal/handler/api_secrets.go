@@ -0,0 +1,220 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/segmentio/ksuid"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/service/secret"
+	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+	"github.com/soltiHQ/control-plane/internal/transport/http/responder"
+	"github.com/soltiHQ/control-plane/internal/transport/http/response"
+	"github.com/soltiHQ/control-plane/internal/transport/http/route"
+	"github.com/soltiHQ/control-plane/internal/transport/httpctx"
+	"github.com/soltiHQ/control-plane/internal/uikit/routepath"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	apimapv1 "github.com/soltiHQ/control-plane/internal/transport/http/apimap/v1"
+)
+
+// Secrets handles /api/v1/secrets.
+//
+// Supported:
+//   - GET  /api/v1/secrets
+//   - POST /api/v1/secrets
+func (a *API) Secrets(w http.ResponseWriter, r *http.Request) {
+	route.Resource(w, r, routepath.ApiSecrets,
+		route.Endpoint{Method: http.MethodGet, Perm: kind.SecretsGet, Fn: a.secretList},
+		route.Endpoint{Method: http.MethodPost, Perm: kind.SecretsAdd, Fn: func(w http.ResponseWriter, r *http.Request, m httpctx.RenderMode) {
+			a.secretUpsert(w, r, m, "", modeCreate)
+		}},
+	)
+}
+
+// SecretsRouter handles /api/v1/secrets/{id}.
+//
+// Supported:
+//   - GET    /api/v1/secrets/{id}
+//   - PUT    /api/v1/secrets/{id}
+//   - DELETE /api/v1/secrets/{id}
+func (a *API) SecretsRouter(w http.ResponseWriter, r *http.Request) {
+	route.Router(w, r, routepath.ApiSecret,
+		route.Subroute{Action: "", Method: http.MethodGet, Perm: kind.SecretsGet, Fn: a.secretDetails},
+		route.Subroute{Action: "", Method: http.MethodPut, Perm: kind.SecretsEdit, Fn: func(w http.ResponseWriter, r *http.Request, m httpctx.RenderMode, id string) {
+			a.secretUpsert(w, r, m, id, modeUpdate)
+		}},
+		route.Subroute{Action: "", Method: http.MethodDelete, Perm: kind.SecretsDelete, Fn: a.secretDelete},
+	)
+}
+
+func (a *API) secretList(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode) {
+	var (
+		limit  = queryInt(r, "limit", 0)
+		filter storage.SecretFilter
+
+		cursor = r.URL.Query().Get("cursor")
+		q      = r.URL.Query().Get("q")
+	)
+	if q != "" {
+		filter = inmemory.NewSecretFilter().Query(q)
+	}
+
+	res, err := a.secretSVC.List(r.Context(), secret.ListQuery{
+		Limit:  limit,
+		Cursor: cursor,
+		Filter: filter,
+	})
+	if err != nil {
+		a.logger.Error().Err(err).Msg("secret list failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	response.OK(w, r, mode, &responder.View{
+		Data: restv1.SecretListResponse{
+			Items:      mapSlice(res.Items, apimapv1.Secret),
+			NextCursor: res.NextCursor,
+		},
+	})
+}
+
+func (a *API) secretDetails(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	sec, err := a.secretSVC.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("secret_id", id).Msg("secret get failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	response.OK(w, r, mode, &responder.View{
+		Data: apimapv1.Secret(sec),
+	})
+}
+
+func (a *API) secretUpsert(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string, action upsertMode) {
+	in, err := decodeJSON[restv1.SecretUpsertRequest](r)
+	if err != nil {
+		response.BadRequest(w, r, mode)
+		return
+	}
+
+	var sec *model.Secret
+
+	switch action {
+	case modeCreate:
+		if in.Name == "" || in.Value == "" {
+			response.BadRequestMsg(w, r, mode, "name and value are required")
+			return
+		}
+		x, err := model.NewSecret(ksuid.New().String(), in.Name, in.Value)
+		if err != nil {
+			response.BadRequest(w, r, mode)
+			return
+		}
+		sec = x
+	case modeUpdate:
+		x, err := a.secretSVC.Get(r.Context(), id)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				response.NotFound(w, r, mode)
+				return
+			}
+			a.logger.Error().Err(err).Str("secret_id", id).Msg("secret get failed")
+			response.Unavailable(w, r, mode)
+			return
+		}
+		if in.Value != "" {
+			x.SetValue(in.Value)
+		}
+		sec = x
+	default:
+		response.BadRequest(w, r, mode)
+		return
+	}
+	if in.Description != "" {
+		sec.SetDescription(in.Description)
+	}
+
+	by := a.actor(r)
+	if action == modeCreate {
+		if err = a.secretSVC.Create(r.Context(), sec); err != nil {
+			if errors.Is(err, storage.ErrAlreadyExists) {
+				response.Conflict(w, r, mode, "secret with this name already exists")
+				return
+			}
+			a.logger.Error().Err(err).Msg("secret create failed")
+			response.Unavailable(w, r, mode)
+			return
+		}
+		a.logger.Info().Str("secret_id", sec.ID()).Str("name", sec.Name()).Msg("secret created")
+		a.hub.Record(event.SecretCreated, event.Payload{ID: sec.ID(), Name: sec.Name(), By: by})
+		response.OK(w, r, mode, &responder.View{Data: apimapv1.Secret(sec)})
+		return
+	}
+
+	if err = a.secretSVC.Update(r.Context(), sec); err != nil {
+		a.logger.Error().Err(err).Str("secret_id", id).Msg("secret update failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+	a.logger.Info().Str("secret_id", id).Msg("secret updated")
+	a.hub.Record(event.SecretUpdated, event.Payload{ID: id, Name: sec.Name(), By: by})
+	response.NoContent(w, r)
+}
+
+func (a *API) secretDelete(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	var name string
+	if sec, err := a.secretSVC.Get(r.Context(), id); err == nil {
+		name = sec.Name()
+	}
+
+	err := a.secretSVC.Delete(r.Context(), id)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		a.logger.Error().Err(err).Str("secret_id", id).Msg("secret delete failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+	a.logger.Info().Str("secret_id", id).Msg("secret deleted")
+	a.hub.Record(event.SecretDeleted, event.Payload{ID: id, Name: name, By: a.actor(r)})
+	response.NoContent(w, r)
+}
+
+// checkSecretRefs validates the secret references of a spec or run before it is stored.
+//
+// A referenced secret ends up in what the agent runs, so referencing one is as good as
+// reading it: references require SecretsGet (403 otherwise), and unknown names or invalid
+// templates are a 400. It writes the response and reports false when the request is rejected.
+func (a *API) checkSecretRefs(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, refs func() ([]string, error)) bool {
+	names, err := refs()
+	if err != nil {
+		response.BadRequestMsg(w, r, mode, err.Error())
+		return false
+	}
+	if len(names) == 0 {
+		return true
+	}
+	if id := a.identity(r); id == nil || !id.HasPermission(kind.SecretsGet) {
+		response.Forbidden(w, r, mode)
+		return false
+	}
+	for _, name := range names {
+		if _, err = a.secretSVC.GetByName(r.Context(), name); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				response.BadRequestMsg(w, r, mode, "unknown secret: "+name)
+				return false
+			}
+			a.logger.Error().Err(err).Str("secret", name).Msg("secret get failed")
+			response.Unavailable(w, r, mode)
+			return false
+		}
+	}
+	return true
+}
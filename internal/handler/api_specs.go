@@ -6,6 +6,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/segmentio/ksuid"
+	"github.com/soltiHQ/control-plane/domain"
 	"github.com/soltiHQ/control-plane/domain/kind"
 	"github.com/soltiHQ/control-plane/domain/model"
 	"github.com/soltiHQ/control-plane/internal/event"
@@ -46,7 +47,10 @@ func (a *API) Specs(w http.ResponseWriter, r *http.Request) {
 //   - PUT    /api/v1/specs/{id}
 //   - DELETE /api/v1/specs/{id}
 //   - POST   /api/v1/specs/{id}/deploy
+//   - POST   /api/v1/specs/{id}/undeploy
 //   - GET    /api/v1/specs/{id}/sync
+//   - GET    /api/v1/specs/{id}/preview?agent={agentID}
+//   - GET    /api/v1/specs/{id}/plan
 func (a *API) SpecsRouter(w http.ResponseWriter, r *http.Request) {
 	route.Router(w, r, routepath.ApiSpec,
 		route.Subroute{Action: "", Method: http.MethodGet, Perm: kind.SpecsGet, Fn: a.specDetails},
@@ -55,7 +59,10 @@ func (a *API) SpecsRouter(w http.ResponseWriter, r *http.Request) {
 		}},
 		route.Subroute{Action: "", Method: http.MethodDelete, Perm: kind.SpecsEdit, Fn: a.specDelete},
 		route.Subroute{Action: "deploy", Method: http.MethodPost, Perm: kind.SpecsDeploy, Fn: a.specDeploy},
+		route.Subroute{Action: "undeploy", Method: http.MethodPost, Perm: kind.SpecsDeploy, Fn: a.specUndeploy},
 		route.Subroute{Action: "sync", Method: http.MethodGet, Perm: kind.SpecsGet, Fn: a.specRollouts},
+		route.Subroute{Action: "preview", Method: http.MethodGet, Perm: kind.SpecsGet, Fn: a.specPreview},
+		route.Subroute{Action: "plan", Method: http.MethodGet, Perm: kind.SpecsGet, Fn: a.specPlan},
 	)
 }
 
@@ -114,7 +121,7 @@ func (a *API) specDetails(w http.ResponseWriter, r *http.Request, mode httpctx.R
 	dto := apimapv1.RolloutSpec(ts, states)
 	response.OK(w, r, mode, &responder.View{
 		Data:      dto,
-		Component: contentSpec.Detail(dto, policy.BuildSpecDetail(a.identity(r))),
+		Component: contentSpec.Detail(dto, policy.BuildSpecDetail(a.identity(r), ts.ManagedBy() != "")),
 	})
 }
 
@@ -150,6 +157,10 @@ func (a *API) specUpsert(w http.ResponseWriter, r *http.Request, mode httpctx.Re
 			response.Unavailable(w, r, mode)
 			return
 		}
+		if x.ManagedBy() != "" {
+			response.Conflict(w, r, mode, "spec is managed by "+x.ManagedBy())
+			return
+		}
 		if in.Name != "" {
 			x.SetName(in.Name)
 		}
@@ -162,76 +173,28 @@ func (a *API) specUpsert(w http.ResponseWriter, r *http.Request, mode httpctx.Re
 		return
 	}
 
-	// Kind
-	if in.KindType != "" {
-		ts.SetKindType(kind.TaskKindType(in.KindType))
-	}
-	if in.KindConfig != nil {
-		ts.SetKindConfig(in.KindConfig)
-	}
+	apimapv1.ApplySpecRequest(ts, in, action == modeCreate)
 
-	// Lifecycle
-	if in.TimeoutMs > 0 {
-		ts.SetTimeoutMs(in.TimeoutMs)
-	}
-	if in.RestartType != "" {
-		ts.SetRestartType(kind.RestartType(in.RestartType))
-	}
-	if in.IntervalMs > 0 {
-		ts.SetIntervalMs(in.IntervalMs)
-	}
-	if action == modeCreate {
-		ts.SetBackoff(model.BackoffConfig{
-			Jitter:  kind.JitterStrategy(in.Jitter),
-			FirstMs: in.BackoffFirstMs,
-			MaxMs:   in.BackoffMaxMs,
-			Factor:  in.BackoffFactor,
-		})
-	} else if in.Jitter != "" || in.BackoffFirstMs > 0 || in.BackoffMaxMs > 0 || in.BackoffFactor > 0 {
-		b := ts.Backoff()
-		if in.Jitter != "" {
-			b.Jitter = kind.JitterStrategy(in.Jitter)
-		}
-		if in.BackoffFirstMs > 0 {
-			b.FirstMs = in.BackoffFirstMs
-		}
-		if in.BackoffMaxMs > 0 {
-			b.MaxMs = in.BackoffMaxMs
-		}
-		if in.BackoffFactor > 0 {
-			b.Factor = in.BackoffFactor
+	if err = ts.Validate(); err != nil {
+		var ve *model.ValidationError
+		if errors.As(err, &ve) {
+			response.Invalid(w, r, mode, ve.FieldMap())
+			return
 		}
-		ts.SetBackoff(b)
-	}
-	if in.Admission != "" {
-		ts.SetAdmission(kind.AdmissionStrategy(in.Admission))
+		response.BadRequestMsg(w, r, mode, err.Error())
+		return
 	}
 
-	// Targets
-	if action == modeCreate {
-		if len(in.Targets) > 0 {
-			ts.SetTargets(in.Targets)
-		}
-		if len(in.TargetLabels) > 0 {
-			ts.SetTargetLabels(in.TargetLabels)
-		}
-		if len(in.RunnerLabels) > 0 {
-			ts.SetRunnerLabels(in.RunnerLabels)
-		}
-	} else {
-		if in.Targets != nil {
-			ts.SetTargets(in.Targets)
-		}
-		if in.TargetLabels != nil {
-			ts.SetTargetLabels(in.TargetLabels)
-		}
-		if in.RunnerLabels != nil {
-			ts.SetRunnerLabels(in.RunnerLabels)
-		}
+	if !a.checkSecretRefs(w, r, mode, ts.SecretRefs) {
+		return
 	}
 
 	if action == modeCreate {
 		if err := a.specSVC.Create(r.Context(), ts); err != nil {
+			if fields, ok := dependencyFields(err); ok {
+				response.Invalid(w, r, mode, fields)
+				return
+			}
 			a.logger.Error().Err(err).Msg("spec create failed")
 			response.Unavailable(w, r, mode)
 			return
@@ -245,6 +208,10 @@ func (a *API) specUpsert(w http.ResponseWriter, r *http.Request, mode httpctx.Re
 	}
 
 	if err = a.specSVC.Upsert(r.Context(), ts); err != nil {
+		if fields, ok := dependencyFields(err); ok {
+			response.Invalid(w, r, mode, fields)
+			return
+		}
 		a.logger.Error().Err(err).Str("spec", id).Msg("spec update failed")
 		response.Unavailable(w, r, mode)
 		return
@@ -256,24 +223,53 @@ func (a *API) specUpsert(w http.ResponseWriter, r *http.Request, mode httpctx.Re
 	response.NoContent(w, r)
 }
 
+// dependencyFields reports a missing or cyclic dependency as a field error on depends_on.
+func dependencyFields(err error) (map[string]string, bool) {
+	if !errors.Is(err, domain.ErrDependencyCycle) && !errors.Is(err, domain.ErrDependencyMissing) {
+		return nil, false
+	}
+	return map[string]string{"depends_on": err.Error()}, true
+}
+
 func (a *API) specDelete(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
-	err := a.specSVC.Delete(r.Context(), id)
+	ts, err := a.specSVC.Get(r.Context(), id)
+	if err == nil && ts.ManagedBy() != "" {
+		response.Conflict(w, r, mode, "spec is managed by "+ts.ManagedBy())
+		return
+	}
+	if err == nil {
+		err = a.specSVC.Delete(r.Context(), id)
+	}
+	if errors.Is(err, domain.ErrDependencyInUse) {
+		response.Conflict(w, r, mode, err.Error())
+		return
+	}
 	if err != nil && !errors.Is(err, storage.ErrNotFound) {
 		a.logger.Error().Err(err).Str("spec", id).Msg("spec delete failed")
 		response.Unavailable(w, r, mode)
 		return
 	}
 	a.logger.Info().Str("spec", id).Msg("spec deleted")
+	if err == nil {
+		a.hub.Record(event.SpecDeleted, event.Payload{ID: id, Name: ts.Name(), By: a.actor(r)})
+	}
 	a.hub.Notify(htmx.SpecUpdate)
 	htmx.Redirect(w, routepath.PageSpecs)
 	response.NoContent(w, r)
 }
 
 func (a *API) specDeploy(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
-	if err := a.specSVC.Deploy(r.Context(), id); err != nil {
-		if errors.Is(err, storage.ErrNotFound) {
+	if _, err := a.specSVC.Deploy(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
 			response.NotFound(w, r, mode)
 			return
+		case errors.Is(err, domain.ErrDependencyCycle):
+			response.Conflict(w, r, mode, err.Error())
+			return
+		case errors.Is(err, domain.ErrDependencyMissing):
+			response.BadRequestMsg(w, r, mode, err.Error())
+			return
 		}
 		a.logger.Error().Err(err).Str("spec", id).Msg("spec deploy failed")
 		response.Unavailable(w, r, mode)
@@ -308,6 +304,38 @@ func (a *API) specDeploy(w http.ResponseWriter, r *http.Request, mode httpctx.Re
 	response.NoContent(w, r)
 }
 
+func (a *API) specUndeploy(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	ts, err := a.specSVC.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("spec", id).Msg("spec get failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+	if err = a.specSVC.Undeploy(r.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			response.NotFound(w, r, mode)
+			return
+		case errors.Is(err, domain.ErrDependencyInUse):
+			response.Conflict(w, r, mode, err.Error())
+			return
+		}
+		a.logger.Error().Err(err).Str("spec", id).Msg("spec undeploy failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	a.logger.Info().Str("spec", id).Msg("spec undeployed")
+	a.hub.Record(event.SpecUndeployed, event.Payload{ID: id, Name: ts.Name(), By: a.actor(r)})
+	htmx.Trigger(w, htmx.SpecUpdate)
+	a.hub.Notify(htmx.SpecUpdate)
+	response.NoContent(w, r)
+}
+
 func (a *API) specRollouts(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
 	states, err := a.specSVC.RolloutsBySpec(r.Context(), id, inmemory.NewRolloutFilter().BySpecID(id))
 	if err != nil {
@@ -337,3 +365,83 @@ func (a *API) specRollouts(w http.ResponseWriter, r *http.Request, mode httpctx.
 		Component: contentSpec.Rollouts(items),
 	})
 }
+
+func (a *API) specPreview(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	agentID := r.URL.Query().Get("agent")
+	if agentID == "" {
+		response.BadRequestMsg(w, r, mode, "agent is required")
+		return
+	}
+
+	ts, err := a.specSVC.Get(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("spec", id).Msg("spec get failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	ag, err := a.agentSVC.Get(r.Context(), agentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("agent", agentID).Msg("agent get failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	payload, err := ts.RenderCreateSpec(ag, model.MaskedSecret)
+	if err != nil {
+		response.BadRequestMsg(w, r, mode, err.Error())
+		return
+	}
+
+	dto := restv1.SpecPreview{
+		CreateSpec: payload,
+		SpecID:     ts.ID(),
+		AgentID:    ag.ID(),
+	}
+	response.OK(w, r, mode, &responder.View{
+		Data:      dto,
+		Component: contentSpec.Preview(dto),
+	})
+}
+
+func (a *API) specPlan(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, id string) {
+	plan, err := a.specSVC.Plan(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			response.NotFound(w, r, mode)
+			return
+		}
+		a.logger.Error().Err(err).Str("spec", id).Msg("spec plan failed")
+		response.Unavailable(w, r, mode)
+		return
+	}
+
+	dto := restv1.SpecPlan{SpecID: plan.SpecID, Items: make([]restv1.SpecPlanEntry, 0, len(plan.Entries))}
+	for _, e := range plan.Entries {
+		item := restv1.SpecPlanEntry{
+			AgentID: e.AgentID,
+			Source:  "target",
+			Action:  string(e.Action),
+			Reason:  e.Reason,
+		}
+		if e.Selected {
+			item.Source = "selector"
+		}
+		if e.Agent != nil {
+			item.AgentName = e.Agent.Name()
+		}
+		dto.Items = append(dto.Items, item)
+	}
+	response.OK(w, r, mode, &responder.View{
+		Data:      dto,
+		Component: contentSpec.Plan(dto),
+	})
+}
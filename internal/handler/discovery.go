@@ -2,12 +2,19 @@ package handler
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	"github.com/soltiHQ/control-plane/internal/transport/grpc/status"
 
@@ -15,10 +22,17 @@ import (
 	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
 	"github.com/soltiHQ/control-plane/domain/kind"
 	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/auth"
+	"github.com/soltiHQ/control-plane/internal/auth/pki"
 	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/metrics"
+	"github.com/soltiHQ/control-plane/internal/proxy"
 	"github.com/soltiHQ/control-plane/internal/service"
 	"github.com/soltiHQ/control-plane/internal/service/agent"
+	"github.com/soltiHQ/control-plane/internal/service/enrollment"
+	"github.com/soltiHQ/control-plane/internal/service/spec"
 	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
 	"github.com/soltiHQ/control-plane/internal/transport/http/responder"
 	"github.com/soltiHQ/control-plane/internal/transport/http/response"
 	"github.com/soltiHQ/control-plane/internal/transport/httpctx"
@@ -27,27 +41,50 @@ import (
 
 // HTTPDiscovery handles agent discovery over HTTP.
 type HTTPDiscovery struct {
-	logger   zerolog.Logger
-	agentSVC *agent.Service
-	eventHub *event.Hub
+	logger    zerolog.Logger
+	agentSVC  *agent.Service
+	enrollSVC *enrollment.Service
+	specSVC   *spec.Service
+	metrics   *metrics.Store
+	ca        *pki.CA
+	eventHub  *event.Hub
 }
 
 // NewHTTPDiscovery creates a new HTTP discovery handler.
-func NewHTTPDiscovery(logger zerolog.Logger, agentSVC *agent.Service, eventHub *event.Hub) *HTTPDiscovery {
+// A nil ca disables mutual TLS checks and CSR signing.
+func NewHTTPDiscovery(logger zerolog.Logger, agentSVC *agent.Service, enrollSVC *enrollment.Service, specSVC *spec.Service, metricStore *metrics.Store, ca *pki.CA, eventHub *event.Hub) *HTTPDiscovery {
 	if agentSVC == nil {
 		panic(service.ErrNilService)
 	}
+	if enrollSVC == nil {
+		panic(service.ErrNilService)
+	}
+	if specSVC == nil {
+		panic(service.ErrNilService)
+	}
+	if metricStore == nil {
+		panic(metrics.ErrNilStore)
+	}
 	if eventHub == nil {
 		panic(event.ErrNilHub)
 	}
 	return &HTTPDiscovery{
-		logger:   logger.With().Str("handler", "discovery-http").Logger(),
-		agentSVC: agentSVC,
-		eventHub: eventHub,
+		logger:    logger.With().Str("handler", "discovery-http").Logger(),
+		agentSVC:  agentSVC,
+		enrollSVC: enrollSVC,
+		specSVC:   specSVC,
+		metrics:   metricStore,
+		ca:        ca,
+		eventHub:  eventHub,
 	}
 }
 
 // Sync handles POST /api/v1/discovery/sync.
+//
+// The agent must authenticate with its credential ("Authorization: Bearer …"),
+// or enroll with an enrollment token on its first sync. With mutual TLS enabled
+// a CSR in the request is signed for the agent and returned with the CA certificate.
+// Pull-mode agents get their desired specs in the response.
 func (h *HTTPDiscovery) Sync(w http.ResponseWriter, r *http.Request) {
 	mode := httpctx.ModeFromRequest(r)
 
@@ -74,11 +111,38 @@ func (h *HTTPDiscovery) Sync(w http.ResponseWriter, r *http.Request) {
 		UptimeSeconds:      in.UptimeSeconds,
 		HeartbeatIntervalS: in.HeartbeatIntervalS,
 		Metadata:           in.Metadata,
+		Capabilities:       httpCapabilities(in.Capabilities),
+		PullMode:           in.Pull,
 	})
 	if err != nil {
 		response.BadRequest(w, r, mode)
 		return
 	}
+	grant, err := authenticateSync(r.Context(), h.enrollSVC, h.ca, r.TLS, enrollment.SyncAuth{
+		AgentID:         in.ID,
+		Credential:      bearer(r.Header.Get("Authorization")),
+		EnrollmentToken: in.EnrollmentToken,
+	})
+	if err != nil {
+		switch {
+		case !rejectSync(r.Context(), h.logger, h.eventHub, h.agentSVC, in.ID, err):
+			response.Unavailable(w, r, mode)
+		case errors.Is(err, auth.ErrUnauthorized):
+			response.Forbidden(w, r, mode)
+		default:
+			response.Unauthorized(w, r, mode)
+		}
+		return
+	}
+	defer grant.Done()
+	applyGrant(h.eventHub, h.enrollSVC, a, grant)
+
+	cert, err := signCSR(h.logger, h.ca, a, in.CSR)
+	if err != nil {
+		response.BadRequest(w, r, mode)
+		return
+	}
+
 	existing, getErr := h.agentSVC.Get(r.Context(), in.ID)
 	if err = h.agentSVC.Upsert(r.Context(), a); err != nil {
 		h.logger.Error().Err(err).Str("agent_id", in.ID).Msg("upsert failed")
@@ -98,37 +162,168 @@ func (h *HTTPDiscovery) Sync(w http.ResponseWriter, r *http.Request) {
 			h.eventHub.Notify(htmx.DashboardUpdate)
 		}
 	}
+	recordChanges(r.Context(), h.logger, h.eventHub, h.agentSVC, existing, a)
 	h.eventHub.Notify(htmx.AgentUpdate)
+	recordMetrics(h.logger, h.metrics, in.ID, httpMetrics(in.Metrics))
+
+	var specs []discoveryv1.DesiredSpec
+	if a.PullMode() {
+		acks := make([]spec.Ack, 0, len(in.Applied))
+		for _, ack := range in.Applied {
+			acks = append(acks, spec.Ack{SpecID: ack.SpecID, Version: ack.Version, Error: ack.Error})
+		}
+		desired, err := pullSpecs(r.Context(), h.logger, h.eventHub, h.specSVC, a, acks)
+		if err != nil {
+			response.Unavailable(w, r, mode)
+			return
+		}
+		specs = make([]discoveryv1.DesiredSpec, 0, len(desired))
+		for _, d := range desired {
+			specs = append(specs, discoveryv1.DesiredSpec{SpecID: d.SpecID, Version: d.Version, Spec: d.Spec})
+		}
+	}
 	response.OK(w, r, mode, &responder.View{
-		Data: discoveryv1.SyncResponse{Success: true},
+		Data: discoveryv1.SyncResponse{
+			Success:       true,
+			Credential:    grant.Credential,
+			Certificate:   cert.pem,
+			CACertificate: cert.caPEM,
+			Specs:         specs,
+		},
 	})
 }
 
 // GRPCDiscovery implements genv1.DiscoverServiceServer.
 type GRPCDiscovery struct {
 	genv1.UnimplementedDiscoverServiceServer
-	logger   zerolog.Logger
-	agentSVC *agent.Service
-	hub      *event.Hub
+	logger    zerolog.Logger
+	agentSVC  *agent.Service
+	enrollSVC *enrollment.Service
+	specSVC   *spec.Service
+	proxyPool *proxy.Pool
+	metrics   *metrics.Store
+	ca        *pki.CA
+	hub       *event.Hub
 }
 
 // NewGRPCDiscovery creates a new gRPC discovery handler.
-func NewGRPCDiscovery(logger zerolog.Logger, agentSVC *agent.Service, hub *event.Hub) *GRPCDiscovery {
+// A nil ca disables mutual TLS checks and CSR signing.
+// Connect sessions are attached to proxyPool so agent calls can be routed over them.
+func NewGRPCDiscovery(logger zerolog.Logger, agentSVC *agent.Service, enrollSVC *enrollment.Service, specSVC *spec.Service, proxyPool *proxy.Pool, metricStore *metrics.Store, ca *pki.CA, hub *event.Hub) *GRPCDiscovery {
 	if agentSVC == nil {
 		panic(service.ErrNilService)
 	}
+	if enrollSVC == nil {
+		panic(service.ErrNilService)
+	}
+	if specSVC == nil {
+		panic(service.ErrNilService)
+	}
+	if proxyPool == nil {
+		panic(proxy.ErrNilPool)
+	}
+	if metricStore == nil {
+		panic(metrics.ErrNilStore)
+	}
 	if hub == nil {
 		panic(event.ErrNilHub)
 	}
 	return &GRPCDiscovery{
-		logger:   logger.With().Str("handler", "discovery-grpc").Logger(),
-		agentSVC: agentSVC,
-		hub:      hub,
+		logger:    logger.With().Str("handler", "discovery-grpc").Logger(),
+		agentSVC:  agentSVC,
+		enrollSVC: enrollSVC,
+		specSVC:   specSVC,
+		proxyPool: proxyPool,
+		metrics:   metricStore,
+		ca:        ca,
+		hub:       hub,
 	}
 }
 
 // Sync implements genv1.DiscoverServiceServer.
+//
+// The agent must authenticate with its credential ("authorization: Bearer …" metadata),
+// or enroll with an enrollment token on its first sync. With mutual TLS enabled
+// a CSR in the request is signed for the agent and returned with the CA certificate.
+// Pull-mode agents get their desired specs in the response.
 func (g *GRPCDiscovery) Sync(ctx context.Context, req *genv1.SyncRequest) (*genv1.SyncResponse, error) {
+	return g.sync(ctx, req, true)
+}
+
+// Connect implements genv1.DiscoverServiceServer.
+//
+// The first message must be a heartbeat, authenticated like Sync. Later heartbeats
+// reuse that authentication, must carry the same agent ID, and end the stream once
+// the agent is deleted or rejected. While the stream is open it is attached to the
+// proxy pool, so agent calls are sent over it as commands; task events refresh the
+// agent's task list.
+func (g *GRPCDiscovery) Connect(stream genv1.DiscoverService_ConnectServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	hb := first.GetHeartbeat()
+	if hb == nil {
+		return status.Errorf(ctx, codes.InvalidArgument, "first message must be a heartbeat")
+	}
+	res, err := g.sync(ctx, hb, true)
+	if err != nil {
+		return err
+	}
+
+	var (
+		id   = hb.GetId()
+		sess = proxy.NewSession(id, stream.Send)
+	)
+	if err = sess.Send(&genv1.ControlMessage{Msg: &genv1.ControlMessage_Sync{Sync: res}}); err != nil {
+		return err
+	}
+	detach := g.proxyPool.Attach(sess)
+	defer detach()
+
+	g.logger.Info().Str("agent_id", id).Msg("agent session opened")
+	defer g.logger.Info().Str("agent_id", id).Msg("agent session closed")
+
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch m := msg.GetMsg().(type) {
+		case *genv1.AgentMessage_Heartbeat:
+			if m.Heartbeat.GetId() != id {
+				return status.Errorf(ctx, codes.InvalidArgument, "heartbeat for agent %q on session of %q", m.Heartbeat.GetId(), id)
+			}
+			if res, err = g.sync(ctx, m.Heartbeat, false); err != nil {
+				return err
+			}
+			if err = sess.Send(&genv1.ControlMessage{Msg: &genv1.ControlMessage_Sync{Sync: res}}); err != nil {
+				return err
+			}
+		case *genv1.AgentMessage_TaskEvent:
+			g.logger.Debug().
+				Str("agent_id", id).
+				Str("task_id", m.TaskEvent.GetTaskId()).
+				Str("status", m.TaskEvent.GetStatus().String()).
+				Msg("task event")
+			g.hub.Notify(htmx.AgentUpdate)
+		case *genv1.AgentMessage_Result:
+			if !sess.Deliver(m.Result) {
+				g.logger.Debug().Str("agent_id", id).Str("command_id", m.Result.GetId()).Msg("result for unknown command dropped")
+			}
+		}
+	}
+}
+
+// sync handles a heartbeat. With authenticate false the caller already authenticated
+// the agent on its session; the agent is only checked to still be enrolled and not rejected.
+func (g *GRPCDiscovery) sync(ctx context.Context, req *genv1.SyncRequest, authenticate bool) (*genv1.SyncResponse, error) {
 	a, err := model.NewAgentFrom(model.AgentParams{
 		ID:                 req.GetId(),
 		Name:               req.GetName(),
@@ -141,10 +336,28 @@ func (g *GRPCDiscovery) Sync(ctx context.Context, req *genv1.SyncRequest) (*genv
 		UptimeSeconds:      req.GetUptimeSeconds(),
 		HeartbeatIntervalS: int(req.GetHeartbeatIntervalS()),
 		Metadata:           req.GetMetadata(),
+		Capabilities:       grpcCapabilities(req.GetCapabilities()),
+		PullMode:           req.GetPull(),
 	})
 	if err != nil {
 		return nil, status.Errorf(ctx, codes.InvalidArgument, "invalid agent data: %v", err)
 	}
+	var grant *enrollment.Grant
+	if authenticate {
+		grant, err = g.authenticate(ctx, req)
+	} else {
+		grant, err = g.resume(ctx, req.GetId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer grant.Done()
+	applyGrant(g.hub, g.enrollSVC, a, grant)
+
+	cert, err := signCSR(g.logger, g.ca, a, req.GetCsr())
+	if err != nil {
+		return nil, status.Errorf(ctx, codes.InvalidArgument, "%v", err)
+	}
 
 	existing, getErr := g.agentSVC.Get(ctx, req.GetId())
 	if err = g.agentSVC.Upsert(ctx, a); err != nil {
@@ -164,6 +377,323 @@ func (g *GRPCDiscovery) Sync(ctx context.Context, req *genv1.SyncRequest) (*genv
 			g.hub.Notify(htmx.DashboardUpdate)
 		}
 	}
+	recordChanges(ctx, g.logger, g.hub, g.agentSVC, existing, a)
 	g.hub.Notify(htmx.AgentUpdate)
-	return &genv1.SyncResponse{Success: true}, nil
+	recordMetrics(g.logger, g.metrics, req.GetId(), grpcMetrics(req.GetMetrics()))
+
+	var specs []*genv1.DesiredSpec
+	if a.PullMode() {
+		acks := make([]spec.Ack, 0, len(req.GetApplied()))
+		for _, ack := range req.GetApplied() {
+			acks = append(acks, spec.Ack{SpecID: ack.GetSpecId(), Version: int(ack.GetVersion()), Error: ack.GetError()})
+		}
+		desired, err := pullSpecs(ctx, g.logger, g.hub, g.specSVC, a, acks)
+		if err != nil {
+			return nil, status.FromError(ctx, err).Err()
+		}
+		for _, d := range desired {
+			cs, err := proxy.ProtoCreateSpec(d.Spec)
+			if err != nil {
+				g.logger.Warn().Err(err).Str("agent_id", a.ID()).Str("spec_id", d.SpecID).Msg("pull: convert spec failed")
+				if err = failPull(ctx, g.hub, g.specSVC, a, d, "encode error: "+err.Error()); err != nil {
+					g.logger.Error().Err(err).Str("agent_id", a.ID()).Str("spec_id", d.SpecID).Msg("pull: mark rollout failed")
+				}
+				continue
+			}
+			specs = append(specs, &genv1.DesiredSpec{SpecId: d.SpecID, Version: int64(d.Version), Spec: cs})
+		}
+	}
+	return &genv1.SyncResponse{
+		Success:       true,
+		Credential:    grant.Credential,
+		Certificate:   cert.pem,
+		CaCertificate: cert.caPEM,
+		Specs:         specs,
+	}, nil
+}
+
+// authenticate authenticates a heartbeat from its credential, enrollment token and client certificate.
+func (g *GRPCDiscovery) authenticate(ctx context.Context, req *genv1.SyncRequest) (*enrollment.Grant, error) {
+	var credential string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("authorization"); len(vals) > 0 {
+			credential = bearer(vals[0])
+		}
+	}
+	var cs *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			cs = &info.State
+		}
+	}
+	grant, err := authenticateSync(ctx, g.enrollSVC, g.ca, cs, enrollment.SyncAuth{
+		AgentID:         req.GetId(),
+		Credential:      credential,
+		EnrollmentToken: req.GetEnrollmentToken(),
+	})
+	if err != nil {
+		rejectSync(ctx, g.logger, g.hub, g.agentSVC, req.GetId(), err)
+		return nil, status.FromError(ctx, err).Err()
+	}
+	return grant, nil
+}
+
+// resume checks that the agent of an open session is still enrolled and not rejected.
+func (g *GRPCDiscovery) resume(ctx context.Context, id string) (*enrollment.Grant, error) {
+	ag, err := g.agentSVC.Get(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(ctx, codes.Unauthenticated, "agent %s is no longer enrolled", id)
+		}
+		return nil, status.FromError(ctx, err).Err()
+	}
+	if ag.Approval() == kind.AgentApprovalRejected {
+		return nil, status.Errorf(ctx, codes.PermissionDenied, "%v", ag.CheckApproval())
+	}
+	return &enrollment.Grant{}, nil
+}
+
+// authenticateSync authenticates a discovery sync, binding the verified client
+// certificate of the connection (if any) to the agent ID when mutual TLS is enabled.
+func authenticateSync(ctx context.Context, svc *enrollment.Service, ca *pki.CA, cs *tls.ConnectionState, in enrollment.SyncAuth) (*enrollment.Grant, error) {
+	if ca != nil {
+		in.MutualTLS = true
+		id, err := pki.PeerAgentID(cs)
+		switch {
+		case err == nil:
+			in.PeerID = id
+		case !errors.Is(err, pki.ErrNoPeerCertificate):
+			return nil, fmt.Errorf("%w: %w", auth.ErrInvalidCredentials, err)
+		}
+	}
+	return svc.Authenticate(ctx, in)
+}
+
+// issuedCert is a certificate signed for an agent CSR, empty when none was requested.
+type issuedCert struct {
+	pem   string
+	caPEM string
+}
+
+// signCSR signs an agent CSR and records the certificate expiry on the agent.
+// It is a no-op when the CSR is empty or mutual TLS is disabled.
+func signCSR(logger zerolog.Logger, ca *pki.CA, a *model.Agent, csr string) (issuedCert, error) {
+	if ca == nil || csr == "" {
+		return issuedCert{}, nil
+	}
+	certPEM, notAfter, err := ca.SignAgentCSR([]byte(csr), a.ID(), a.Endpoint())
+	if err != nil {
+		logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("agent csr rejected")
+		return issuedCert{}, err
+	}
+	a.SetCertExpiresAt(notAfter)
+
+	logger.Info().Str("agent_id", a.ID()).Time("expires_at", notAfter).Msg("agent certificate issued")
+	return issuedCert{pem: string(certPEM), caPEM: string(ca.CertPEM())}, nil
+}
+
+// applyGrant stores a newly issued credential and the enrollment token labels on the agent
+// and decides whether it is approved or held for operator approval.
+func applyGrant(hub *event.Hub, svc *enrollment.Service, a *model.Agent, g *enrollment.Grant) {
+	if !g.Enrolled() {
+		return
+	}
+	a.SetCredentialHash(g.CredentialHash)
+	for k, v := range g.Token.LabelsAll() {
+		a.LabelAdd(k, v)
+	}
+	a.SetApproval(svc.Admission(a))
+
+	hub.Record(event.AgentEnrolled, event.Payload{ID: a.ID(), Name: a.Name(), By: "enrollment", Detail: g.Token.Name()})
+	n := hub.DeleteIssues(event.AgentRejected, a.ID())
+	if a.Approval() == kind.AgentApprovalPending {
+		hub.Record(event.AgentPendingApproval, event.Payload{ID: a.ID(), Name: a.Name(), By: "enrollment"})
+		n++
+	}
+	if n > 0 {
+		hub.Notify(htmx.DashboardUpdate)
+	}
+}
+
+// pullSpecs applies the acknowledgements of a pull-mode agent and returns its desired specs,
+// recording a sync failure for every rollout the agent (or rendering) failed.
+func pullSpecs(ctx context.Context, logger zerolog.Logger, hub *event.Hub, svc *spec.Service, a *model.Agent, acks []spec.Ack) ([]spec.Desired, error) {
+	res, err := svc.Pull(ctx, a, acks, inmemory.NewRolloutFilter().ByAgentID(a.ID()))
+	if err != nil {
+		logger.Error().Err(err).Str("agent_id", a.ID()).Msg("pull failed")
+		return nil, err
+	}
+	for _, ts := range res.Failed {
+		hub.Record(event.SyncFailed, event.Payload{ID: ts.ID(), Name: ts.Name(), Detail: a.ID(), By: "sync"})
+	}
+	if res.Changed {
+		hub.Notify(htmx.SpecUpdate)
+	}
+	return res.Specs, nil
+}
+
+// failPull marks the rollout of a desired spec that could not be delivered to a pull-mode
+// agent failed and records the sync failure, like the failures pullSpecs reports.
+func failPull(ctx context.Context, hub *event.Hub, svc *spec.Service, a *model.Agent, d spec.Desired, msg string) error {
+	res, err := svc.Fail(ctx, a.ID(), d, msg)
+	if err != nil {
+		return err
+	}
+	for _, ts := range res.Failed {
+		hub.Record(event.SyncFailed, event.Payload{ID: ts.ID(), Name: ts.Name(), Detail: a.ID(), By: "sync"})
+	}
+	if res.Changed {
+		hub.Notify(htmx.SpecUpdate)
+	}
+	return nil
+}
+
+// rejectSync logs an unauthenticated sync. A sync claiming the ID of a known agent is
+// also raised as an issue, once until it is closed; syncs for unknown IDs (anyone can
+// send those) and of agents an operator rejected are only logged. It reports false
+// when the failure was not an authentication error.
+func rejectSync(ctx context.Context, logger zerolog.Logger, hub *event.Hub, agents *agent.Service, id string, err error) bool {
+	if errors.Is(err, auth.ErrUnauthorized) {
+		logger.Debug().Err(err).Str("agent_id", id).Msg("sync from rejected agent refused")
+		return true
+	}
+	if !errors.Is(err, auth.ErrInvalidCredentials) && !errors.Is(err, auth.ErrInvalidToken) {
+		logger.Error().Err(err).Str("agent_id", id).Msg("discovery authentication failed")
+		return false
+	}
+	logger.Warn().Err(err).Str("agent_id", id).Msg("discovery sync rejected")
+
+	if id == "" || hub.HasIssue(event.AgentRejected, id) {
+		return true
+	}
+	a, getErr := agents.Get(ctx, id)
+	if getErr != nil {
+		return true
+	}
+	hub.Record(event.AgentRejected, event.Payload{ID: id, Name: a.Name(), By: "discovery", Detail: err.Error()})
+	hub.Notify(htmx.DashboardUpdate)
+	return true
+}
+
+// bearer returns the token of an "Authorization: Bearer <token>" value, or "".
+func bearer(v string) string {
+	const prefix = "bearer "
+	if len(v) < len(prefix) || !strings.EqualFold(v[:len(prefix)], prefix) {
+		return ""
+	}
+	return strings.TrimSpace(v[len(prefix):])
+}
+
+// httpCapabilities converts HTTP discovery capabilities to the domain form.
+func httpCapabilities(c *discoveryv1.Capabilities) model.Capabilities {
+	if c == nil {
+		return model.Capabilities{}
+	}
+	return model.Capabilities{
+		TaskKinds: taskKinds(c.TaskKinds),
+		Runtimes:  c.Runtimes,
+		Limits: model.ResourceLimits{
+			CPUMillicores: c.Limits.CPUMillicores,
+			MemoryBytes:   c.Limits.MemoryBytes,
+			MaxTasks:      c.Limits.MaxTasks,
+		},
+	}
+}
+
+// grpcCapabilities converts gRPC discovery capabilities to the domain form.
+func grpcCapabilities(c *genv1.AgentCapabilities) model.Capabilities {
+	if c == nil {
+		return model.Capabilities{}
+	}
+	return model.Capabilities{
+		TaskKinds: taskKinds(c.GetTaskKinds()),
+		Runtimes:  c.GetRuntimes(),
+		Limits: model.ResourceLimits{
+			CPUMillicores: c.GetLimits().GetCpuMillicores(),
+			MemoryBytes:   c.GetLimits().GetMemoryBytes(),
+			MaxTasks:      int(c.GetLimits().GetMaxTasks()),
+		},
+	}
+}
+
+// httpMetrics converts an HTTP discovery resource snapshot to a sample; nil means none was sent.
+func httpMetrics(m *discoveryv1.Metrics) *metrics.Sample {
+	if m == nil {
+		return nil
+	}
+	return &metrics.Sample{
+		CPUPercent:       m.CPUPercent,
+		Load1:            m.Load1,
+		Load5:            m.Load5,
+		Load15:           m.Load15,
+		MemoryUsedBytes:  m.MemoryUsedBytes,
+		MemoryTotalBytes: m.MemoryTotalBytes,
+		DiskUsedBytes:    m.DiskUsedBytes,
+		DiskTotalBytes:   m.DiskTotalBytes,
+		TasksRunning:     m.TasksRunning,
+		TasksTotal:       m.TasksTotal,
+	}
+}
+
+// grpcMetrics converts a gRPC discovery resource snapshot to a sample; nil means none was sent.
+func grpcMetrics(m *genv1.AgentMetrics) *metrics.Sample {
+	if m == nil {
+		return nil
+	}
+	return &metrics.Sample{
+		CPUPercent:       m.GetCpuPercent(),
+		Load1:            m.GetLoad1(),
+		Load5:            m.GetLoad5(),
+		Load15:           m.GetLoad15(),
+		MemoryUsedBytes:  m.GetMemoryUsedBytes(),
+		MemoryTotalBytes: m.GetMemoryTotalBytes(),
+		DiskUsedBytes:    m.GetDiskUsedBytes(),
+		DiskTotalBytes:   m.GetDiskTotalBytes(),
+		TasksRunning:     int(m.GetTasksRunning()),
+		TasksTotal:       int(m.GetTasksTotal()),
+	}
+}
+
+// recordChanges appends the inventory fields an agent changed since its previous sync
+// to its history and records them in the activity feed. A failure is only logged;
+// it never fails the sync.
+func recordChanges(ctx context.Context, logger zerolog.Logger, hub *event.Hub, svc *agent.Service, existing, a *model.Agent) {
+	change, err := svc.RecordChanges(ctx, existing, a)
+	if err != nil {
+		logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("inventory change not recorded")
+		return
+	}
+	if change == nil {
+		return
+	}
+	hub.Record(event.AgentInventoryChanged, event.Payload{
+		ID:     a.ID(),
+		Name:   a.Name(),
+		By:     "discovery",
+		Detail: strings.Join(change.FieldNames(), ", "),
+	})
+}
+
+// recordMetrics appends a synced resource snapshot to the agent's history.
+// An invalid snapshot is dropped with a warning; it never fails the sync.
+func recordMetrics(logger zerolog.Logger, store *metrics.Store, agentID string, s *metrics.Sample) {
+	if s == nil {
+		return
+	}
+	if err := s.Validate(); err != nil {
+		logger.Warn().Err(err).Str("agent_id", agentID).Msg("metrics dropped")
+		return
+	}
+	store.Append(agentID, *s)
+}
+
+// taskKinds normalizes agent-reported kind names ("Wasm " → "wasm").
+func taskKinds(names []string) []kind.TaskKindType {
+	out := make([]kind.TaskKindType, 0, len(names))
+	for _, n := range names {
+		if n = strings.ToLower(strings.TrimSpace(n)); n != "" {
+			out = append(out, kind.TaskKindType(n))
+		}
+	}
+	return out
 }
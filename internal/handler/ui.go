@@ -23,6 +23,7 @@ import (
 	"github.com/soltiHQ/control-plane/internal/uikit/routepath"
 	pageAgent "github.com/soltiHQ/control-plane/ui/templates/page/agent"
 	pageHome "github.com/soltiHQ/control-plane/ui/templates/page/home"
+	pageRun "github.com/soltiHQ/control-plane/ui/templates/page/run"
 	pageSpec "github.com/soltiHQ/control-plane/ui/templates/page/spec"
 	pageSystem "github.com/soltiHQ/control-plane/ui/templates/page/system"
 	pageUser "github.com/soltiHQ/control-plane/ui/templates/page/user"
@@ -65,6 +66,9 @@ func (u *UI) Routes(mux *http.ServeMux, auth route.BaseMW, perm route.PermMW, co
 	route.HandleFunc(mux, routepath.PageSpecNew, u.SpecNew, append(common, auth, perm(kind.SpecsAdd))...)
 	route.HandleFunc(mux, routepath.PageSpecInfo, u.SpecDetail, append(common, auth, perm(kind.SpecsGet))...)
 
+	route.HandleFunc(mux, routepath.PageRuns, u.Runs, append(common, auth, perm(kind.RunsGet))...)
+	route.HandleFunc(mux, routepath.PageRunInfo, u.RunDetail, append(common, auth, perm(kind.RunsGet))...)
+
 	route.HandleFunc(mux, routepath.PageHome, u.Main, append(common, auth)...)
 }
 
@@ -205,6 +209,16 @@ func (u *UI) SpecDetail(w http.ResponseWriter, r *http.Request) {
 	u.pageParam(w, r, http.MethodGet, routepath.PageSpecInfo, func(nav policy.Nav, specID string) templ.Component { return pageSpec.Detail(nav, specID) })
 }
 
+// Runs handle GET /runs.
+func (u *UI) Runs(w http.ResponseWriter, r *http.Request) {
+	u.page(w, r, http.MethodGet, routepath.PageRuns, func(nav policy.Nav) templ.Component { return pageRun.Runs(nav) })
+}
+
+// RunDetail handle GET /runs/info/{}.
+func (u *UI) RunDetail(w http.ResponseWriter, r *http.Request) {
+	u.pageParam(w, r, http.MethodGet, routepath.PageRunInfo, func(nav policy.Nav, runID string) templ.Component { return pageRun.Detail(nav, runID) })
+}
+
 func (u *UI) page(w http.ResponseWriter, r *http.Request, m, p string, render func(nav policy.Nav) templ.Component) {
 	mode := httpctx.ModeFromRequest(r)
 	if r.Method != m {
@@ -0,0 +1,26 @@
+package metrics
+
+import "time"
+
+const (
+	defaultMaxSamples = 720
+	defaultRetention  = 6 * time.Hour
+)
+
+// Config bounds the per-agent series kept by a Store.
+type Config struct {
+	// MaxSamples is the number of samples kept per agent; older ones are evicted first.
+	MaxSamples int `yaml:"max_samples"`
+	// Retention is how long a sample is kept.
+	Retention time.Duration `yaml:"retention"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxSamples <= 0 {
+		c.MaxSamples = defaultMaxSamples
+	}
+	if c.Retention <= 0 {
+		c.Retention = defaultRetention
+	}
+	return c
+}
@@ -0,0 +1,10 @@
+package metrics
+
+import "errors"
+
+var (
+	// ErrNilStore indicates a nil *Store was provided to a constructor.
+	ErrNilStore = errors.New("metrics: nil store")
+	// ErrInvalidSample indicates a sample with out-of-range values.
+	ErrInvalidSample = errors.New("metrics: invalid sample")
+)
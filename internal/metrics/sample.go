@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sample is one resource snapshot reported by an agent on a discovery sync.
+// Zero values mean not reported.
+type Sample struct {
+	Time time.Time
+
+	CPUPercent float64
+	Load1      float64
+	Load5      float64
+	Load15     float64
+
+	MemoryUsedBytes  int64
+	MemoryTotalBytes int64
+	DiskUsedBytes    int64
+	DiskTotalBytes   int64
+
+	TasksRunning int
+	TasksTotal   int
+}
+
+// MemoryPercent returns used memory as a percentage of total, or 0 if total is not reported.
+func (s Sample) MemoryPercent() float64 { return percent(s.MemoryUsedBytes, s.MemoryTotalBytes) }
+
+// DiskPercent returns used disk as a percentage of total, or 0 if total is not reported.
+func (s Sample) DiskPercent() float64 { return percent(s.DiskUsedBytes, s.DiskTotalBytes) }
+
+// Validate reports whether the sample values are in range:
+// CPU within 0–100 %, no negative values, and used never above a reported total.
+func (s Sample) Validate() error {
+	switch {
+	case s.CPUPercent < 0 || s.CPUPercent > 100:
+		return fmt.Errorf("%w: cpu_percent %.1f out of range", ErrInvalidSample, s.CPUPercent)
+	case s.Load1 < 0 || s.Load5 < 0 || s.Load15 < 0:
+		return fmt.Errorf("%w: negative load", ErrInvalidSample)
+	case s.MemoryUsedBytes < 0 || s.MemoryTotalBytes < 0 || s.DiskUsedBytes < 0 || s.DiskTotalBytes < 0:
+		return fmt.Errorf("%w: negative byte count", ErrInvalidSample)
+	case s.MemoryTotalBytes > 0 && s.MemoryUsedBytes > s.MemoryTotalBytes:
+		return fmt.Errorf("%w: memory used above total", ErrInvalidSample)
+	case s.DiskTotalBytes > 0 && s.DiskUsedBytes > s.DiskTotalBytes:
+		return fmt.Errorf("%w: disk used above total", ErrInvalidSample)
+	case s.TasksRunning < 0 || s.TasksTotal < 0:
+		return fmt.Errorf("%w: negative task count", ErrInvalidSample)
+	}
+	return nil
+}
+
+func percent(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
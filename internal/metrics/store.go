@@ -0,0 +1,98 @@
+// Package metrics keeps a bounded, in-memory time series of resource samples per agent.
+//
+// Samples arrive with discovery syncs and are neither persisted nor replicated:
+// a restart starts every series empty. Each series is capped by Config.MaxSamples
+// and Config.Retention, and is dropped when its agent is deleted.
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Store holds one capped series of samples per agent, oldest first.
+type Store struct {
+	mu     sync.RWMutex
+	cfg    Config
+	now    func() time.Time
+	series map[string][]Sample
+}
+
+// NewStore creates an empty store bounded by cfg.
+func NewStore(cfg Config) *Store {
+	return &Store{
+		cfg:    cfg.withDefaults(),
+		now:    time.Now,
+		series: make(map[string][]Sample),
+	}
+}
+
+// Append adds a sample to the agent's series, stamping it with the current time
+// if it has none, and evicts samples beyond the size and age bounds.
+// Samples older than the newest one in the series are dropped.
+func (s *Store) Append(agentID string, sample Sample) {
+	now := s.now()
+	if sample.Time.IsZero() {
+		sample.Time = now
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	buf := s.series[agentID]
+	if n := len(buf); n > 0 && sample.Time.Before(buf[n-1].Time) {
+		return
+	}
+	buf = append(buf, sample)
+	if len(buf) > s.cfg.MaxSamples {
+		buf = buf[len(buf)-s.cfg.MaxSamples:]
+	}
+	s.series[agentID] = buf[s.expired(buf, now):]
+}
+
+// Range returns the agent's samples with from <= Time <= to, oldest first.
+// A zero from or to leaves that side unbounded; expired samples are never returned.
+func (s *Store) Range(agentID string, from, to time.Time) []Sample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	buf := s.series[agentID]
+	buf = buf[s.expired(buf, s.now()):]
+	if !from.IsZero() {
+		buf = buf[sort.Search(len(buf), func(i int) bool { return !buf[i].Time.Before(from) }):]
+	}
+	if !to.IsZero() {
+		buf = buf[:sort.Search(len(buf), func(i int) bool { return buf[i].Time.After(to) })]
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	return append([]Sample(nil), buf...)
+}
+
+// Latest returns the agent's newest sample that has not expired.
+func (s *Store) Latest(agentID string) (Sample, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	buf := s.series[agentID]
+	if len(buf) == 0 || s.expired(buf[len(buf)-1:], s.now()) == 1 {
+		return Sample{}, false
+	}
+	return buf[len(buf)-1], true
+}
+
+// Delete drops the agent's series.
+func (s *Store) Delete(agentID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.series, agentID)
+}
+
+// expired returns the number of leading samples in buf older than the retention.
+func (s *Store) expired(buf []Sample, now time.Time) int {
+	cutoff := now.Add(-s.cfg.Retention)
+	return sort.Search(len(buf), func(i int) bool { return !buf[i].Time.Before(cutoff) })
+}
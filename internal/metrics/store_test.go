@@ -0,0 +1,144 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestStore(cfg Config, now *time.Time) *Store {
+	s := NewStore(cfg)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestStore_AppendAndRange(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	s := newTestStore(Config{}, &now)
+
+	for i := range 5 {
+		s.Append("a1", Sample{Time: now.Add(time.Duration(i-5) * time.Minute), CPUPercent: float64(i)})
+	}
+	s.Append("a2", Sample{CPUPercent: 50})
+
+	got := s.Range("a1", time.Time{}, time.Time{})
+	if len(got) != 5 {
+		t.Fatalf("expected 5 samples, got %d", len(got))
+	}
+	for i, v := range got {
+		if v.CPUPercent != float64(i) {
+			t.Fatalf("expected oldest first, got %v at %d", v.CPUPercent, i)
+		}
+	}
+
+	got = s.Range("a1", now.Add(-4*time.Minute), now.Add(-2*time.Minute))
+	if len(got) != 3 || got[0].CPUPercent != 1 || got[2].CPUPercent != 3 {
+		t.Fatalf("expected samples 1..3, got %+v", got)
+	}
+
+	if got = s.Range("a2", time.Time{}, time.Time{}); len(got) != 1 || !got[0].Time.Equal(now) {
+		t.Fatalf("expected one sample stamped now, got %+v", got)
+	}
+	if got = s.Range("missing", time.Time{}, time.Time{}); got != nil {
+		t.Fatalf("expected nil for unknown agent, got %+v", got)
+	}
+}
+
+func TestStore_EvictsBeyondMaxSamples(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	s := newTestStore(Config{MaxSamples: 3}, &now)
+
+	for i := range 5 {
+		s.Append("a1", Sample{Time: now.Add(time.Duration(i) * time.Second), CPUPercent: float64(i)})
+	}
+
+	got := s.Range("a1", time.Time{}, time.Time{})
+	if len(got) != 3 || got[0].CPUPercent != 2 {
+		t.Fatalf("expected last 3 samples, got %+v", got)
+	}
+}
+
+func TestStore_DropsExpiredSamples(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	s := newTestStore(Config{Retention: time.Hour}, &now)
+
+	s.Append("a1", Sample{Time: now.Add(-2 * time.Hour)})
+	s.Append("a1", Sample{Time: now.Add(-30 * time.Minute), CPUPercent: 1})
+
+	if got := s.Range("a1", time.Time{}, time.Time{}); len(got) != 1 || got[0].CPUPercent != 1 {
+		t.Fatalf("expected only the unexpired sample, got %+v", got)
+	}
+
+	now = now.Add(time.Hour)
+	if got := s.Range("a1", time.Time{}, time.Time{}); got != nil {
+		t.Fatalf("expected no samples after retention, got %+v", got)
+	}
+	if _, ok := s.Latest("a1"); ok {
+		t.Fatal("expected no latest sample after retention")
+	}
+}
+
+func TestStore_IgnoresOutOfOrderSamples(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	s := newTestStore(Config{}, &now)
+
+	s.Append("a1", Sample{Time: now, CPUPercent: 1})
+	s.Append("a1", Sample{Time: now.Add(-time.Minute), CPUPercent: 2})
+
+	got, ok := s.Latest("a1")
+	if !ok || got.CPUPercent != 1 {
+		t.Fatalf("expected latest sample to stay, got %+v", got)
+	}
+	if n := len(s.Range("a1", time.Time{}, time.Time{})); n != 1 {
+		t.Fatalf("expected 1 sample, got %d", n)
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	now := time.Unix(1_000_000, 0)
+	s := newTestStore(Config{}, &now)
+
+	s.Append("a1", Sample{})
+	s.Delete("a1")
+
+	if _, ok := s.Latest("a1"); ok {
+		t.Fatal("expected series to be deleted")
+	}
+}
+
+func TestSample_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Sample
+		ok   bool
+	}{
+		{"empty", Sample{}, true},
+		{"full", Sample{CPUPercent: 42, Load1: 1.5, MemoryUsedBytes: 1, MemoryTotalBytes: 2, DiskUsedBytes: 3, DiskTotalBytes: 4, TasksRunning: 1, TasksTotal: 2}, true},
+		{"cpu above 100", Sample{CPUPercent: 101}, false},
+		{"negative load", Sample{Load5: -1}, false},
+		{"negative bytes", Sample{DiskUsedBytes: -1}, false},
+		{"memory used above total", Sample{MemoryUsedBytes: 3, MemoryTotalBytes: 2}, false},
+		{"negative tasks", Sample{TasksRunning: -1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.s.Validate()
+			if tt.ok && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidSample) {
+				t.Fatalf("expected ErrInvalidSample, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSample_Percentages(t *testing.T) {
+	s := Sample{MemoryUsedBytes: 1, MemoryTotalBytes: 4}
+	if got := s.MemoryPercent(); got != 25 {
+		t.Fatalf("expected 25, got %v", got)
+	}
+	if got := s.DiskPercent(); got != 0 {
+		t.Fatalf("expected 0 without total, got %v", got)
+	}
+}
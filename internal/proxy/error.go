@@ -25,8 +25,24 @@ var (
 	ErrUnsupportedAPIVersion = errors.New("proxy: unsupported api version")
 	// ErrSubmitTask indicates a task submission call failed.
 	ErrSubmitTask = errors.New("proxy: submit task")
+	// ErrDeleteTask indicates a task delete call failed.
+	ErrDeleteTask = errors.New("proxy: delete task")
+	// ErrCancelTask indicates a task cancel call failed.
+	ErrCancelTask = errors.New("proxy: cancel task")
+	// ErrRestartTask indicates a task restart call failed.
+	ErrRestartTask = errors.New("proxy: restart task")
+	// ErrTaskLogs indicates a task log stream failed.
+	ErrTaskLogs = errors.New("proxy: task logs")
+	// ErrProbe indicates a reachability probe of the agent failed or reported it not serving.
+	ErrProbe = errors.New("proxy: probe")
 	// ErrExportSpecs indicates an export call failed.
 	ErrExportSpecs = errors.New("proxy: export task specs")
+	// ErrInsecureEndpoint indicates a plain http:// agent endpoint while mutual TLS is enabled.
+	ErrInsecureEndpoint = errors.New("proxy: insecure endpoint")
+	// ErrSessionClosed indicates the agent's Connect stream ended before answering a command.
+	ErrSessionClosed = errors.New("proxy: agent session closed")
+	// ErrSessionUnsupported indicates a call that cannot be sent over an agent session.
+	ErrSessionUnsupported = errors.New("proxy: not supported over agent session")
 	// ErrNilPool indicates a required *Pool dependency is nil.
 	ErrNilPool = errors.New("proxy: nil pool")
 )
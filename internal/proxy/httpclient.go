@@ -1,14 +1,19 @@
 package proxy
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
+// maxStreamLine bounds a single line read by doStream.
+const maxStreamLine = 1 << 20
+
 // httpClient is the subset of *http.Client used by the proxy helpers.
 type httpClient interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -39,7 +44,7 @@ func doGet[T any](ctx context.Context, client httpClient, url string) (*T, error
 	return &dst, nil
 }
 
-// doPost performs a POST request with a JSON body [statuses: 200, 201, 204].
+// doPost performs a POST request with a JSON body [statuses: 200, 201, 202, 204].
 func doPost(ctx context.Context, client httpClient, url string, body any) error {
 	payload, err := json.Marshal(body)
 	if err != nil {
@@ -52,6 +57,32 @@ func doPost(ctx context.Context, client httpClient, url string, body any) error
 	}
 	req.Header.Set("Content-Type", "application/json")
 
+	return doNoContent(client, req)
+}
+
+// doDelete performs a DELETE request [statuses: 200, 202, 204].
+func doDelete(ctx context.Context, client httpClient, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCreateRequest, err)
+	}
+
+	return doNoContent(client, req)
+}
+
+// doCheck performs a GET request and discards the response body, accepting the statuses of doNoContent.
+func doCheck(ctx context.Context, client httpClient, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCreateRequest, err)
+	}
+
+	return doNoContent(client, req)
+}
+
+// doNoContent sends req and discards the response body, accepting any success status
+// used by the agent API for writes (200, 201, 202, 204).
+func doNoContent(client httpClient, req *http.Request) error {
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrRequest, err)
@@ -62,9 +93,54 @@ func doPost(ctx context.Context, client httpClient, url string, body any) error
 	_, _ = io.Copy(io.Discard, resp.Body)
 
 	switch resp.StatusCode {
-	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
 		return nil
 	default:
 		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
 	}
 }
+
+// doStream performs a GET request and JSON-decodes each streamed record into T.
+//
+// Both Server-Sent Events ("data: {...}") and newline-delimited JSON are accepted;
+// other SSE fields and comments are skipped. Returns nil when the agent ends the stream.
+func doStream[T any](ctx context.Context, client httpClient, url string, fn func(T) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCreateRequest, err)
+	}
+	req.Header.Set("Accept", "text/event-stream, application/x-ndjson")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRequest, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	sc := bufio.NewScanner(resp.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), maxStreamLine)
+	for sc.Scan() {
+		line := sc.Text()
+		if data, ok := strings.CutPrefix(line, "data:"); ok {
+			line = data
+		} else if !strings.HasPrefix(strings.TrimSpace(line), "{") {
+			continue
+		}
+
+		var rec T
+		if err = json.Unmarshal([]byte(line), &rec); err != nil {
+			return fmt.Errorf("%w: %v", ErrDecode, err)
+		}
+		if err = fn(rec); err != nil {
+			return err
+		}
+	}
+	if err = sc.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrRequest, err)
+	}
+	return nil
+}
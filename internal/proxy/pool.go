@@ -12,59 +12,129 @@ import (
 
 	"github.com/soltiHQ/control-plane/domain/kind"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// AgentTLS returns the client TLS config used to call the agent with the given ID.
+// The config must verify that the server certificate belongs to that agent.
+type AgentTLS func(agentID string) *tls.Config
+
 // Pool manages shared outbound connections to agents.
 //
-// For HTTP it holds a single *http.Client whose Transport pools TCP connections.
-// For gRPC it caches one *grpc.ClientConn per endpoint address.
+// Without mutual TLS it holds a single *http.Client whose Transport pools TCP
+// connections. With mutual TLS every agent gets its own *http.Client, since the
+// TLS config pins the agent identity.
+// For gRPC it caches one *grpc.ClientConn per agent and endpoint address.
+// Agents with an attached Session are reached over their Connect stream instead.
 type Pool struct {
 	mu sync.RWMutex
 
+	agentTLS  AgentTLS
 	httpCli   *http.Client
+	httpClis  map[string]*http.Client
 	grpcConns map[string]*grpc.ClientConn
+	sessions  map[string]*Session
 }
 
 // NewPool creates a Pool with a configured HTTP transport.
-func NewPool() *Pool {
+// A nil agentTLS disables mutual TLS: agents are called over plain HTTP/gRPC,
+// or over server-verified TLS for https:// endpoints.
+func NewPool(agentTLS AgentTLS) *Pool {
 	return &Pool{
-		httpCli: &http.Client{
-			Transport: &http.Transport{
-				DialContext: (&net.Dialer{
-					Timeout:   5 * time.Second,
-					KeepAlive: 30 * time.Second,
-				}).DialContext,
-				TLSClientConfig:     &tls.Config{MinVersion: tls.VersionTLS12},
-				IdleConnTimeout:     90 * time.Second,
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 10,
-			},
-		},
+		agentTLS:  agentTLS,
+		httpCli:   newHTTPClient(&tls.Config{MinVersion: tls.VersionTLS12}),
+		httpClis:  make(map[string]*http.Client),
 		grpcConns: make(map[string]*grpc.ClientConn),
+		sessions:  make(map[string]*Session),
+	}
+}
+
+func newHTTPClient(tlsCfg *tls.Config) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   5 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSClientConfig:     tlsCfg,
+			IdleConnTimeout:     90 * time.Second,
+			MaxIdleConns:        100,
+			MaxIdleConnsPerHost: 10,
+		},
 	}
 }
 
-// Get returns an AgentProxy for the given endpoint, selecting the implementation
+// Get returns an AgentProxy for the given agent endpoint, selecting the implementation
 // based on api version and endpoint type.
-func (p *Pool) Get(endpoint string, epType kind.EndpointType, apiVersion kind.APIVersion) (AgentProxy, error) {
+//
+// With mutual TLS the agent ID is the identity its certificate must carry, and HTTP
+// endpoints must use https:// (ErrInsecureEndpoint otherwise).
+//
+// If the agent has an attached Session, calls are sent over its stream; only task
+// logs still dial the endpoint, when there is one.
+func (p *Pool) Get(agentID, endpoint string, epType kind.EndpointType, apiVersion kind.APIVersion) (AgentProxy, error) {
+	if s := p.session(agentID); s != nil {
+		sp := &streamProxyV1{session: s}
+		if endpoint != "" {
+			sp.direct, _ = p.get(agentID, endpoint, epType, apiVersion)
+		}
+		return sp, nil
+	}
+	return p.get(agentID, endpoint, epType, apiVersion)
+}
+
+// Attach routes calls for the session's agent over its stream until detach is called.
+// A newer session of the same agent replaces the older one; detach then leaves it in place.
+// Detach closes the session.
+func (p *Pool) Attach(s *Session) (detach func()) {
+	p.mu.Lock()
+	p.sessions[s.AgentID()] = s
+	p.mu.Unlock()
+
+	return func() {
+		p.mu.Lock()
+		if p.sessions[s.AgentID()] == s {
+			delete(p.sessions, s.AgentID())
+		}
+		p.mu.Unlock()
+		s.Close()
+	}
+}
+
+// Connected reports whether agentID has an attached Session.
+func (p *Pool) Connected(agentID string) bool {
+	return p.session(agentID) != nil
+}
+
+func (p *Pool) session(agentID string) *Session {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.sessions[agentID]
+}
+
+func (p *Pool) get(agentID, endpoint string, epType kind.EndpointType, apiVersion kind.APIVersion) (AgentProxy, error) {
 	switch apiVersion {
 	case kind.APIVersionV1:
-		return p.getV1(endpoint, epType)
+		return p.getV1(agentID, endpoint, epType)
 	default:
 		return nil, ErrUnsupportedAPIVersion
 	}
 }
 
-func (p *Pool) getV1(endpoint string, epType kind.EndpointType) (AgentProxy, error) {
+func (p *Pool) getV1(agentID, endpoint string, epType kind.EndpointType) (AgentProxy, error) {
 	switch epType {
 	case kind.EndpointHTTP:
+		cli, err := p.httpClient(agentID, endpoint)
+		if err != nil {
+			return nil, err
+		}
 		return &httpProxyV1{
 			endpoint: strings.TrimRight(endpoint, "/"),
-			client:   p.httpCli,
+			client:   cli,
 		}, nil
 	case kind.EndpointGRPC:
-		conn, err := p.grpcConn(endpoint)
+		conn, err := p.grpcConn(agentID, endpoint)
 		if err != nil {
 			return nil, err
 		}
@@ -74,10 +144,39 @@ func (p *Pool) getV1(endpoint string, epType kind.EndpointType) (AgentProxy, err
 	}
 }
 
+// httpClient returns the shared client, or with mutual TLS the cached per-agent client.
+func (p *Pool) httpClient(agentID, endpoint string) (*http.Client, error) {
+	if p.agentTLS == nil {
+		return p.httpCli, nil
+	}
+	if !strings.HasPrefix(strings.ToLower(endpoint), "https://") {
+		return nil, fmt.Errorf("%w: %s", ErrInsecureEndpoint, endpoint)
+	}
+
+	p.mu.RLock()
+	cli, ok := p.httpClis[agentID]
+	p.mu.RUnlock()
+	if ok {
+		return cli, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if cli, ok = p.httpClis[agentID]; ok {
+		return cli, nil
+	}
+	cli = newHTTPClient(p.agentTLS(agentID))
+	p.httpClis[agentID] = cli
+	return cli, nil
+}
+
 // grpcConn returns a cached *grpc.ClientConn or creates one.
-func (p *Pool) grpcConn(endpoint string) (*grpc.ClientConn, error) {
+func (p *Pool) grpcConn(agentID, endpoint string) (*grpc.ClientConn, error) {
+	key := agentID + "|" + endpoint
+
 	p.mu.RLock()
-	conn, ok := p.grpcConns[endpoint]
+	conn, ok := p.grpcConns[key]
 	p.mu.RUnlock()
 	if ok {
 		return conn, nil
@@ -86,17 +185,18 @@ func (p *Pool) grpcConn(endpoint string) (*grpc.ClientConn, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if conn, ok = p.grpcConns[endpoint]; ok {
+	if conn, ok = p.grpcConns[key]; ok {
 		return conn, nil
 	}
-	conn, err := grpc.NewClient(
-		endpoint,
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	)
+	creds := insecure.NewCredentials()
+	if p.agentTLS != nil {
+		creds = credentials.NewTLS(p.agentTLS(agentID))
+	}
+	conn, err := grpc.NewClient(endpoint, grpc.WithTransportCredentials(creds))
 	if err != nil {
 		return nil, fmt.Errorf("%w %s: %v", ErrDial, endpoint, err)
 	}
-	p.grpcConns[endpoint] = conn
+	p.grpcConns[key] = conn
 	return conn, nil
 }
 
@@ -106,13 +206,20 @@ func (p *Pool) Close() error {
 	defer p.mu.Unlock()
 
 	var errs []error
-	for ep, conn := range p.grpcConns {
+	for key, conn := range p.grpcConns {
 		if err := conn.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("%w %s: %v", ErrClose, ep, err))
+			errs = append(errs, fmt.Errorf("%w %s: %v", ErrClose, key, err))
 		}
 	}
 	p.grpcConns = nil
 	p.httpCli.CloseIdleConnections()
+	for _, cli := range p.httpClis {
+		cli.CloseIdleConnections()
+	}
+	p.httpClis = nil
+	for _, s := range p.sessions {
+		s.Close()
+	}
 
 	return errors.Join(errs...)
 }
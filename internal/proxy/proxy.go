@@ -26,14 +26,35 @@ type TaskSubmission struct {
 }
 
 // SpecExport describes a task spec as reported by an agent via export.
+// Kind uses the CreateSpec "kind" shape ({"<type>": {config}}).
 type SpecExport struct {
 	Version int            `json:"version"`
 	Slot    string         `json:"slot"`
 	Kind    map[string]any `json:"kind,omitempty"`
 }
 
+// SpecExportList is the agent response for exporting task specs.
+type SpecExportList struct {
+	Specs []SpecExport `json:"specs"`
+}
+
+// LogOptions selects the task output to stream.
+// Tail limits the backlog to the last N lines (0 = agent default); Follow keeps the stream open for new lines.
+type LogOptions struct {
+	Tail   int
+	Follow bool
+}
+
 // AgentProxy is the interface for outbound communication with an agent.
 type AgentProxy interface {
 	ListTasks(ctx context.Context, filter TaskFilter) (*proxyv1.TaskListResponse, error)
 	SubmitTask(ctx context.Context, sub TaskSubmission) error
+	ExportSpecs(ctx context.Context) ([]SpecExport, error)
+	DeleteTask(ctx context.Context, taskID string) error
+	CancelTask(ctx context.Context, taskID string) error
+	RestartTask(ctx context.Context, taskID string) error
+	// TaskLogs calls fn for each output line until the agent ends the stream, fn fails or ctx is done.
+	TaskLogs(ctx context.Context, taskID string, opts LogOptions, fn func(proxyv1.LogLine) error) error
+	// Probe checks that the agent answers on its endpoint; nil means reachable.
+	Probe(ctx context.Context) error
 }
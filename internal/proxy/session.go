@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
+)
+
+// Session is an agent's open Connect stream.
+//
+// While a Session is attached to the Pool, AgentProxy calls for its agent are sent
+// over the stream as commands and complete when the agent returns the matching result.
+// Every message to the agent, including heartbeat replies, must go through Send,
+// since a gRPC stream does not allow concurrent sends.
+type Session struct {
+	agentID string
+	send    func(*genv1.ControlMessage) error
+	sendSem chan struct{} // holds a token while a message is being sent
+
+	mu      sync.Mutex
+	pending map[string]chan *genv1.CommandResult
+	seq     atomic.Uint64
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSession creates a session for agentID that writes messages with send.
+func NewSession(agentID string, send func(*genv1.ControlMessage) error) *Session {
+	return &Session{
+		agentID: agentID,
+		send:    send,
+		sendSem: make(chan struct{}, 1),
+		pending: make(map[string]chan *genv1.CommandResult),
+		done:    make(chan struct{}),
+	}
+}
+
+// AgentID returns the ID of the agent holding the session.
+func (s *Session) AgentID() string { return s.agentID }
+
+// Send writes a message to the agent; safe for concurrent use.
+func (s *Session) Send(m *genv1.ControlMessage) error {
+	s.sendSem <- struct{}{}
+	defer func() { <-s.sendSem }()
+	return s.send(m)
+}
+
+// sendContext is Send that gives up when ctx ends or the session closes, both while
+// waiting for another send and while its own send is blocked on the stream.
+// A send given up on finishes in the background, still holding off other sends.
+func (s *Session) sendContext(ctx context.Context, m *genv1.ControlMessage) error {
+	select {
+	case s.sendSem <- struct{}{}:
+	case <-s.done:
+		return ErrSessionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	errc := make(chan error, 1)
+	go func() {
+		defer func() { <-s.sendSem }()
+		errc <- s.send(m)
+	}()
+	select {
+	case err := <-errc:
+		return err
+	case <-s.done:
+		return ErrSessionClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Deliver hands a command result to the call waiting for it.
+// It reports false if no call is waiting for the result ID.
+func (s *Session) Deliver(res *genv1.CommandResult) bool {
+	s.mu.Lock()
+	ch, ok := s.pending[res.GetId()]
+	delete(s.pending, res.GetId())
+	s.mu.Unlock()
+
+	if ok {
+		ch <- res
+	}
+	return ok
+}
+
+// Close fails every waiting call with ErrSessionClosed. Safe to call multiple times.
+func (s *Session) Close() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+// call sends cmd and waits for its result, the session to close or ctx to end.
+// A result carrying an error is returned as that error.
+func (s *Session) call(ctx context.Context, cmd *genv1.Command) (*genv1.CommandResult, error) {
+	cmd.Id = strconv.FormatUint(s.seq.Add(1), 10)
+	ch := make(chan *genv1.CommandResult, 1)
+
+	s.mu.Lock()
+	s.pending[cmd.Id] = ch
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.pending, cmd.Id)
+		s.mu.Unlock()
+	}()
+
+	if err := s.sendContext(ctx, &genv1.ControlMessage{Msg: &genv1.ControlMessage_Command{Command: cmd}}); err != nil {
+		return nil, err
+	}
+	select {
+	case res := <-ch:
+		if res.GetError() != "" {
+			return nil, errors.New(res.GetError())
+		}
+		return res, nil
+	case <-s.done:
+		return nil, ErrSessionClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
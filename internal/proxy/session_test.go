@@ -0,0 +1,224 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
+)
+
+// fakeStream is a send func that hands every message to sent.
+type fakeStream struct {
+	sent chan *genv1.ControlMessage
+}
+
+func newFakeStream() *fakeStream {
+	return &fakeStream{sent: make(chan *genv1.ControlMessage, 8)}
+}
+
+func (f *fakeStream) send(m *genv1.ControlMessage) error {
+	f.sent <- m
+	return nil
+}
+
+// command waits for the next message and returns the command it carries.
+func (f *fakeStream) command(t *testing.T) *genv1.Command {
+	t.Helper()
+
+	select {
+	case m := <-f.sent:
+		cmd := m.GetCommand()
+		if cmd == nil {
+			t.Fatalf("expected a command, got %v", m)
+		}
+		return cmd
+	case <-time.After(5 * time.Second):
+		t.Fatal("no command sent")
+		return nil
+	}
+}
+
+type callResult struct {
+	res *genv1.CommandResult
+	err error
+}
+
+// goCall runs s.call in the background.
+func goCall(ctx context.Context, s *Session) <-chan callResult {
+	out := make(chan callResult, 1)
+	go func() {
+		res, err := s.call(ctx, &genv1.Command{})
+		out <- callResult{res: res, err: err}
+	}()
+	return out
+}
+
+func wait(t *testing.T, out <-chan callResult) callResult {
+	t.Helper()
+
+	select {
+	case r := <-out:
+		return r
+	case <-time.After(5 * time.Second):
+		t.Fatal("call did not return")
+		return callResult{}
+	}
+}
+
+func TestSession_CallDeliver(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeStream()
+	s := NewSession("a1", fs.send)
+
+	out := goCall(context.Background(), s)
+	cmd := fs.command(t)
+	if s.Deliver(&genv1.CommandResult{Id: cmd.GetId() + "0"}) {
+		t.Fatal("Deliver accepted a result for an unknown command")
+	}
+	if !s.Deliver(&genv1.CommandResult{Id: cmd.GetId()}) {
+		t.Fatal("Deliver dropped the result of a waiting call")
+	}
+	if r := wait(t, out); r.err != nil || r.res.GetId() != cmd.GetId() {
+		t.Fatalf("call = (%v, %v), want result %s", r.res, r.err, cmd.GetId())
+	}
+	if s.Deliver(&genv1.CommandResult{Id: cmd.GetId()}) {
+		t.Fatal("Deliver accepted a second result for the same command")
+	}
+
+	// The next call gets a fresh ID and a result error becomes the call error.
+	out = goCall(context.Background(), s)
+	next := fs.command(t)
+	if next.GetId() == cmd.GetId() {
+		t.Fatalf("command ID %s reused", next.GetId())
+	}
+	s.Deliver(&genv1.CommandResult{Id: next.GetId(), Error: "task not found"})
+	if r := wait(t, out); r.err == nil || r.err.Error() != "task not found" {
+		t.Fatalf("call error = %v, want task not found", r.err)
+	}
+}
+
+func TestSession_CallContext(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeStream()
+	s := NewSession("a1", fs.send)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := goCall(ctx, s)
+	cmd := fs.command(t)
+	cancel()
+	if r := wait(t, out); !errors.Is(r.err, context.Canceled) {
+		t.Fatalf("call error = %v, want context.Canceled", r.err)
+	}
+	if s.Deliver(&genv1.CommandResult{Id: cmd.GetId()}) {
+		t.Fatal("Deliver accepted a result for a canceled call")
+	}
+}
+
+func TestSession_CallBlockedSend(t *testing.T) {
+	t.Parallel()
+
+	var (
+		entered = make(chan struct{}, 2)
+		release = make(chan struct{})
+	)
+	s := NewSession("a1", func(*genv1.ControlMessage) error {
+		entered <- struct{}{}
+		<-release
+		return nil
+	})
+
+	// The first call is stuck in a send the stream does not complete.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := goCall(ctx, s)
+	<-entered
+	cancel()
+	if r := wait(t, first); !errors.Is(r.err, context.Canceled) {
+		t.Fatalf("blocked send: call error = %v, want context.Canceled", r.err)
+	}
+
+	// The second waits behind it until its own ctx ends.
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel2()
+	if r := wait(t, goCall(ctx2, s)); !errors.Is(r.err, context.DeadlineExceeded) {
+		t.Fatalf("waiting send: call error = %v, want context.DeadlineExceeded", r.err)
+	}
+	select {
+	case <-entered:
+		t.Fatal("a second send started while the first was still in flight")
+	default:
+	}
+
+	// Once the stalled send returns, sends go through again.
+	close(release)
+	if err := s.Send(&genv1.ControlMessage{}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+}
+
+func TestSession_Close(t *testing.T) {
+	t.Parallel()
+
+	fs := newFakeStream()
+	s := NewSession("a1", fs.send)
+
+	out := goCall(context.Background(), s)
+	fs.command(t)
+	s.Close()
+	s.Close()
+	if r := wait(t, out); !errors.Is(r.err, ErrSessionClosed) {
+		t.Fatalf("call error = %v, want ErrSessionClosed", r.err)
+	}
+
+	// Calls on a closed session fail without sending.
+	if _, err := s.call(context.Background(), &genv1.Command{}); !errors.Is(err, ErrSessionClosed) {
+		t.Fatalf("call on closed session = %v, want ErrSessionClosed", err)
+	}
+}
+
+func TestPool_Attach(t *testing.T) {
+	t.Parallel()
+
+	p := NewPool(nil)
+	t.Cleanup(func() { _ = p.Close() })
+
+	var (
+		noop  = func(*genv1.ControlMessage) error { return nil }
+		older = NewSession("a1", noop)
+		newer = NewSession("a1", noop)
+	)
+	detachOlder := p.Attach(older)
+	detachNewer := p.Attach(newer)
+	if got := p.session("a1"); got != newer {
+		t.Fatal("a newer session did not replace the older one")
+	}
+
+	// Detaching the replaced session closes it and leaves the newer one attached.
+	detachOlder()
+	if got := p.session("a1"); got != newer {
+		t.Fatal("detaching the replaced session removed the newer one")
+	}
+	if !isClosed(older) || isClosed(newer) {
+		t.Fatalf("closed: older=%v newer=%v, want true false", isClosed(older), isClosed(newer))
+	}
+
+	detachNewer()
+	if p.Connected("a1") {
+		t.Fatal("agent still connected after detach")
+	}
+	if !isClosed(newer) {
+		t.Fatal("detach did not close the session")
+	}
+}
+
+func isClosed(s *Session) bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -2,12 +2,15 @@ package proxy
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 
 	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
 	proxyv1 "github.com/soltiHQ/control-plane/api/proxy/v1"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // grpcProxyV1 implements AgentProxy over gRPC (solti.v1.SoltiApi).
@@ -18,6 +21,15 @@ type grpcProxyV1 struct {
 func (p *grpcProxyV1) ListTasks(ctx context.Context, f TaskFilter) (*proxyv1.TaskListResponse, error) {
 	client := genv1.NewSoltiApiClient(p.conn)
 
+	resp, err := client.ListTasks(ctx, v1ListTasksRequest(f))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrListTasks, err)
+	}
+	return v1TaskList(resp), nil
+}
+
+// v1ListTasksRequest converts a TaskFilter into its proto request.
+func v1ListTasksRequest(f TaskFilter) *genv1.ListTasksRequest {
 	req := &genv1.ListTasksRequest{
 		Limit:  clampUint32(f.Limit),
 		Offset: clampUint32(f.Offset),
@@ -30,12 +42,11 @@ func (p *grpcProxyV1) ListTasks(ctx context.Context, f TaskFilter) (*proxyv1.Tas
 			req.Status = &s
 		}
 	}
+	return req
+}
 
-	resp, err := client.ListTasks(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("%w: %v", ErrListTasks, err)
-	}
-
+// v1TaskList converts a proto task list into its proxy DTO.
+func v1TaskList(resp *genv1.ListTasksResponse) *proxyv1.TaskListResponse {
 	tasks := make([]proxyv1.Task, len(resp.GetTasks()))
 	for i, t := range resp.GetTasks() {
 		tasks[i] = proxyv1.Task{
@@ -52,12 +63,104 @@ func (p *grpcProxyV1) ListTasks(ctx context.Context, f TaskFilter) (*proxyv1.Tas
 	return &proxyv1.TaskListResponse{
 		Tasks: tasks,
 		Total: int(resp.GetTotal()),
-	}, nil
+	}
 }
 
-// SubmitTask is not yet supported over gRPC — the proto does not define the RPC.
-func (p *grpcProxyV1) SubmitTask(_ context.Context, _ TaskSubmission) error {
-	return fmt.Errorf("%w: not available over gRPC (no proto RPC defined)", ErrSubmitTask)
+func (p *grpcProxyV1) SubmitTask(ctx context.Context, sub TaskSubmission) error {
+	spec, err := v1CreateSpec(sub.Spec)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSubmitTask, err)
+	}
+
+	client := genv1.NewSoltiApiClient(p.conn)
+	if _, err = client.SubmitTask(ctx, &genv1.SubmitTaskRequest{Spec: spec}); err != nil {
+		return fmt.Errorf("%w: %v", ErrSubmitTask, err)
+	}
+	return nil
+}
+
+func (p *grpcProxyV1) ExportSpecs(ctx context.Context) ([]SpecExport, error) {
+	client := genv1.NewSoltiApiClient(p.conn)
+
+	resp, err := client.ExportSpecs(ctx, &genv1.ExportSpecsRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportSpecs, err)
+	}
+	return v1SpecExports(resp), nil
+}
+
+// v1SpecExports converts exported proto specs into their proxy form.
+func v1SpecExports(resp *genv1.ExportSpecsResponse) []SpecExport {
+	specs := make([]SpecExport, len(resp.GetSpecs()))
+	for i, s := range resp.GetSpecs() {
+		specs[i] = SpecExport{
+			Version: int(s.GetVersion()),
+			Slot:    s.GetSlot(),
+			Kind:    v1TaskKindMap(s.GetKind()),
+		}
+	}
+	return specs
+}
+
+func (p *grpcProxyV1) DeleteTask(ctx context.Context, taskID string) error {
+	client := genv1.NewSoltiApiClient(p.conn)
+	if _, err := client.DeleteTask(ctx, &genv1.DeleteTaskRequest{TaskId: taskID}); err != nil {
+		return fmt.Errorf("%w: %v", ErrDeleteTask, err)
+	}
+	return nil
+}
+
+func (p *grpcProxyV1) CancelTask(ctx context.Context, taskID string) error {
+	client := genv1.NewSoltiApiClient(p.conn)
+	if _, err := client.CancelTask(ctx, &genv1.CancelTaskRequest{TaskId: taskID}); err != nil {
+		return fmt.Errorf("%w: %v", ErrCancelTask, err)
+	}
+	return nil
+}
+
+func (p *grpcProxyV1) RestartTask(ctx context.Context, taskID string) error {
+	client := genv1.NewSoltiApiClient(p.conn)
+	if _, err := client.RestartTask(ctx, &genv1.RestartTaskRequest{TaskId: taskID}); err != nil {
+		return fmt.Errorf("%w: %v", ErrRestartTask, err)
+	}
+	return nil
+}
+
+func (p *grpcProxyV1) TaskLogs(ctx context.Context, taskID string, opts LogOptions, fn func(proxyv1.LogLine) error) error {
+	client := genv1.NewSoltiApiClient(p.conn)
+
+	stream, err := client.StreamTaskLogs(ctx, &genv1.StreamTaskLogsRequest{
+		TaskId: taskID,
+		Follow: opts.Follow,
+		Tail:   clampUint32(opts.Tail),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrTaskLogs, err)
+	}
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrTaskLogs, err)
+		}
+		if err = fn(proxyv1.LogLine{TS: msg.GetTs(), Stream: msg.GetStream(), Line: msg.GetLine()}); err != nil {
+			return err
+		}
+	}
+}
+
+// Probe runs the standard grpc.health.v1 check for the whole server.
+func (p *grpcProxyV1) Probe(ctx context.Context) error {
+	resp, err := healthpb.NewHealthClient(p.conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProbe, err)
+	}
+	if st := resp.GetStatus(); st != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("%w: %s", ErrProbe, st)
+	}
+	return nil
 }
 
 // v1TaskStatusString converts a v1 proto TaskStatus enum to a lowercase string.
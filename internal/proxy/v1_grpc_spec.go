@@ -0,0 +1,340 @@
+package proxy
+
+import (
+	"fmt"
+	"math"
+
+	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
+	"github.com/soltiHQ/control-plane/domain/kind"
+)
+
+var (
+	v1RestartTypes = map[kind.RestartType]genv1.RestartType{
+		kind.RestartNever:     genv1.RestartType_RESTART_TYPE_NEVER,
+		kind.RestartAlways:    genv1.RestartType_RESTART_TYPE_ALWAYS,
+		kind.RestartOnFailure: genv1.RestartType_RESTART_TYPE_ON_FAILURE,
+	}
+	v1JitterStrategies = map[kind.JitterStrategy]genv1.JitterStrategy{
+		kind.JitterNone:         genv1.JitterStrategy_JITTER_STRATEGY_NONE,
+		kind.JitterFull:         genv1.JitterStrategy_JITTER_STRATEGY_FULL,
+		kind.JitterEqual:        genv1.JitterStrategy_JITTER_STRATEGY_EQUAL,
+		kind.JitterDecorrelated: genv1.JitterStrategy_JITTER_STRATEGY_DECORRELATED,
+	}
+	v1AdmissionStrategies = map[kind.AdmissionStrategy]genv1.AdmissionStrategy{
+		kind.AdmissionDropIfRunning: genv1.AdmissionStrategy_ADMISSION_STRATEGY_DROP_IF_RUNNING,
+		kind.AdmissionReplace:       genv1.AdmissionStrategy_ADMISSION_STRATEGY_REPLACE,
+		kind.AdmissionQueue:         genv1.AdmissionStrategy_ADMISSION_STRATEGY_QUEUE,
+	}
+)
+
+// ProtoCreateSpec converts the agent CreateSpec JSON (see TaskSubmission) into
+// the v1 proto form sent by gRPC SubmitTask and pull-mode gRPC syncs.
+func ProtoCreateSpec(m map[string]any) (*genv1.CreateSpec, error) {
+	return v1CreateSpec(m)
+}
+
+// v1CreateSpec converts the agent CreateSpec JSON (see TaskSubmission) into its proto form.
+//
+// Values are accepted both as built by the control-plane (int64, []string, map[string]string)
+// and as decoded from JSON (float64, []any, map[string]any). A value of any other type
+// is an error rather than being dropped, so the agent never runs a partial spec.
+func v1CreateSpec(m map[string]any) (*genv1.CreateSpec, error) {
+	var (
+		r    specReader
+		spec = &genv1.CreateSpec{
+			Slot:      r.string(m["slot"], "slot"),
+			TimeoutMs: r.int64(m["timeoutMs"], "timeoutMs"),
+			Labels:    r.stringMap(m["labels"], "labels"),
+		}
+	)
+	if r.err != nil {
+		return nil, r.err
+	}
+	if spec.Slot == "" {
+		return nil, fmt.Errorf("slot is required")
+	}
+
+	tk, err := v1TaskKind(m["kind"])
+	if err != nil {
+		return nil, err
+	}
+	spec.Kind = tk
+
+	if rm := r.object(m["restart"], "restart"); rm != nil {
+		typ := r.string(rm["type"], "restart.type")
+		rt, ok := v1RestartTypes[kind.RestartType(typ)]
+		if !ok && r.err == nil {
+			return nil, fmt.Errorf("unknown restart type %q", typ)
+		}
+		spec.Restart = &genv1.RestartPolicy{Type: rt}
+		if v, ok := rm["intervalMs"]; ok {
+			interval := r.int64(v, "restart.intervalMs")
+			spec.Restart.IntervalMs = &interval
+		}
+	}
+
+	if bm := r.object(m["backoff"], "backoff"); bm != nil {
+		jitter := r.string(bm["jitter"], "backoff.jitter")
+		js, ok := v1JitterStrategies[kind.JitterStrategy(jitter)]
+		if !ok && r.err == nil {
+			return nil, fmt.Errorf("unknown jitter strategy %q", jitter)
+		}
+		spec.Backoff = &genv1.BackoffPolicy{
+			Jitter:  js,
+			FirstMs: r.int64(bm["firstMs"], "backoff.firstMs"),
+			MaxMs:   r.int64(bm["maxMs"], "backoff.maxMs"),
+			Factor:  r.float64(bm["factor"], "backoff.factor"),
+		}
+	}
+
+	if v, ok := m["admission"]; ok {
+		admission := r.string(v, "admission")
+		as, ok := v1AdmissionStrategies[kind.AdmissionStrategy(admission)]
+		if !ok && r.err == nil {
+			return nil, fmt.Errorf("unknown admission strategy %q", admission)
+		}
+		spec.Admission = as
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+	return spec, nil
+}
+
+// v1TaskKind converts a CreateSpec "kind" object ({"<type>": {config}}) into the proto oneof.
+func v1TaskKind(v any) (*genv1.TaskKind, error) {
+	km, ok := v.(map[string]any)
+	if !ok || len(km) != 1 {
+		return nil, fmt.Errorf("kind must hold exactly one task kind")
+	}
+
+	var (
+		r    specReader
+		name string
+		cfg  map[string]any
+	)
+	for k, raw := range km {
+		name = k
+		cfg = r.object(raw, "kind."+k)
+	}
+	field := func(key string) string { return "kind." + name + "." + key }
+
+	var tk *genv1.TaskKind
+	switch kind.TaskKindType(name) {
+	case kind.TaskKindSubprocess:
+		sp := &genv1.SubprocessKind{
+			Command: r.string(cfg["command"], field("command")),
+			Args:    r.strings(cfg["args"], field("args")),
+			Env:     r.stringMap(cfg["env"], field("env")),
+		}
+		if v, ok := cfg["cwd"]; ok {
+			cwd := r.string(v, field("cwd"))
+			sp.Cwd = &cwd
+		}
+		if v, ok := cfg["failOnNonZero"]; ok {
+			fail := r.bool(v, field("failOnNonZero"))
+			sp.FailOnNonZero = &fail
+		}
+		tk = &genv1.TaskKind{Kind: &genv1.TaskKind_Subprocess{Subprocess: sp}}
+	case kind.TaskKindContainer:
+		tk = &genv1.TaskKind{Kind: &genv1.TaskKind_Container{Container: &genv1.ContainerKind{
+			Image:   r.string(cfg["image"], field("image")),
+			Command: r.strings(cfg["command"], field("command")),
+			Args:    r.strings(cfg["args"], field("args")),
+			Env:     r.stringMap(cfg["env"], field("env")),
+		}}}
+	case kind.TaskKindWasm:
+		tk = &genv1.TaskKind{Kind: &genv1.TaskKind_Wasm{Wasm: &genv1.WasmKind{
+			Module: r.string(cfg["module"], field("module")),
+			Args:   r.strings(cfg["args"], field("args")),
+			Env:    r.stringMap(cfg["env"], field("env")),
+		}}}
+	default:
+		return nil, fmt.Errorf("unknown task kind %q", name)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	return tk, nil
+}
+
+// v1TaskKindMap converts a proto TaskKind back into the CreateSpec "kind" object.
+func v1TaskKindMap(tk *genv1.TaskKind) map[string]any {
+	switch k := tk.GetKind().(type) {
+	case *genv1.TaskKind_Subprocess:
+		cfg := map[string]any{"command": k.Subprocess.GetCommand()}
+		putStrings(cfg, "args", k.Subprocess.GetArgs())
+		putStringMap(cfg, "env", k.Subprocess.GetEnv())
+		if k.Subprocess.Cwd != nil {
+			cfg["cwd"] = k.Subprocess.GetCwd()
+		}
+		if k.Subprocess.FailOnNonZero != nil {
+			cfg["failOnNonZero"] = k.Subprocess.GetFailOnNonZero()
+		}
+		return map[string]any{string(kind.TaskKindSubprocess): cfg}
+	case *genv1.TaskKind_Container:
+		cfg := map[string]any{"image": k.Container.GetImage()}
+		putStrings(cfg, "command", k.Container.GetCommand())
+		putStrings(cfg, "args", k.Container.GetArgs())
+		putStringMap(cfg, "env", k.Container.GetEnv())
+		return map[string]any{string(kind.TaskKindContainer): cfg}
+	case *genv1.TaskKind_Wasm:
+		cfg := map[string]any{"module": k.Wasm.GetModule()}
+		putStrings(cfg, "args", k.Wasm.GetArgs())
+		putStringMap(cfg, "env", k.Wasm.GetEnv())
+		return map[string]any{string(kind.TaskKindWasm): cfg}
+	default:
+		return nil
+	}
+}
+
+// specReader reads typed CreateSpec values and keeps the first type error.
+//
+// A missing (nil) value reads as the zero value; a value of the wrong type
+// sets err and also reads as the zero value.
+type specReader struct {
+	err error
+}
+
+func (r *specReader) fail(field, want string, v any) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%s must be %s, got %T", field, want, v)
+	}
+}
+
+func (r *specReader) string(v any, field string) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	default:
+		r.fail(field, "a string", v)
+		return ""
+	}
+}
+
+func (r *specReader) bool(v any, field string) bool {
+	switch b := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return b
+	default:
+		r.fail(field, "a boolean", v)
+		return false
+	}
+}
+
+func (r *specReader) int64(v any, field string) int64 {
+	switch n := v.(type) {
+	case nil:
+		return 0
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case float64:
+		if n != math.Trunc(n) || n < math.MinInt64 || n >= math.MaxInt64 {
+			r.fail(field, "an integer", v)
+			return 0
+		}
+		return int64(n)
+	default:
+		r.fail(field, "an integer", v)
+		return 0
+	}
+}
+
+func (r *specReader) float64(v any, field string) float64 {
+	switch n := v.(type) {
+	case nil:
+		return 0
+	case float64:
+		return n
+	case int64:
+		return float64(n)
+	case int:
+		return float64(n)
+	default:
+		r.fail(field, "a number", v)
+		return 0
+	}
+}
+
+// strings accepts a single string as a one-element list (container "command").
+func (r *specReader) strings(v any, field string) []string {
+	switch xs := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{xs}
+	case []string:
+		return xs
+	case []any:
+		out := make([]string, 0, len(xs))
+		for i, x := range xs {
+			s, ok := x.(string)
+			if !ok {
+				r.fail(fmt.Sprintf("%s[%d]", field, i), "a string", x)
+				return nil
+			}
+			out = append(out, s)
+		}
+		return out
+	default:
+		r.fail(field, "a list of strings", v)
+		return nil
+	}
+}
+
+func (r *specReader) stringMap(v any, field string) map[string]string {
+	switch m := v.(type) {
+	case nil:
+		return nil
+	case map[string]string:
+		return m
+	case map[string]any:
+		out := make(map[string]string, len(m))
+		for k, x := range m {
+			s, ok := x.(string)
+			if !ok {
+				r.fail(field+"."+k, "a string", x)
+				return nil
+			}
+			out[k] = s
+		}
+		return out
+	default:
+		r.fail(field, "a map of strings", v)
+		return nil
+	}
+}
+
+// object reads a nested JSON object; nil when v is missing.
+func (r *specReader) object(v any, field string) map[string]any {
+	switch m := v.(type) {
+	case nil:
+		return nil
+	case map[string]any:
+		return m
+	default:
+		r.fail(field, "an object", v)
+		return nil
+	}
+}
+
+func putStrings(cfg map[string]any, key string, xs []string) {
+	if len(xs) > 0 {
+		cfg[key] = xs
+	}
+}
+
+func putStringMap(cfg map[string]any, key string, m map[string]string) {
+	if len(m) > 0 {
+		cfg[key] = m
+	}
+}
@@ -0,0 +1,210 @@
+package proxy
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+
+	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+)
+
+// jsonRoundTrip returns m as an agent would receive it over HTTP: float64
+// numbers, []any lists and map[string]any objects.
+func jsonRoundTrip(t *testing.T, m map[string]any) map[string]any {
+	t.Helper()
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var out map[string]any
+	if err = json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return out
+}
+
+func TestV1CreateSpec_FromSpec(t *testing.T) {
+	t.Parallel()
+
+	ts, err := model.NewSpec("s1", "web", "web")
+	if err != nil {
+		t.Fatalf("new spec: %v", err)
+	}
+	ts.SetKindType(kind.TaskKindSubprocess)
+	ts.SetKindConfig(map[string]any{
+		"command":       "./web",
+		"args":          []string{"--port", "8080"},
+		"env":           map[string]string{"MODE": "prod"},
+		"cwd":           "/srv",
+		"failOnNonZero": true,
+	})
+	ts.SetTimeoutMs(5000)
+	ts.SetRestartType(kind.RestartAlways)
+	ts.SetIntervalMs(1000)
+	ts.SetBackoff(model.BackoffConfig{Jitter: kind.JitterEqual, FirstMs: 100, MaxMs: 2000, Factor: 1.5})
+	ts.SetAdmission(kind.AdmissionReplace)
+	ts.SetRunnerLabels(map[string]string{"pool": "a"})
+
+	got, err := v1CreateSpec(ts.ToCreateSpec())
+	if err != nil {
+		t.Fatalf("v1CreateSpec: %v", err)
+	}
+
+	interval, cwd, fail := int64(1000), "/srv", true
+	want := &genv1.CreateSpec{
+		Slot:      "web",
+		TimeoutMs: 5000,
+		Kind: &genv1.TaskKind{Kind: &genv1.TaskKind_Subprocess{Subprocess: &genv1.SubprocessKind{
+			Command:       "./web",
+			Args:          []string{"--port", "8080"},
+			Env:           map[string]string{"MODE": "prod"},
+			Cwd:           &cwd,
+			FailOnNonZero: &fail,
+		}}},
+		Restart: &genv1.RestartPolicy{Type: genv1.RestartType_RESTART_TYPE_ALWAYS, IntervalMs: &interval},
+		Backoff: &genv1.BackoffPolicy{
+			Jitter:  genv1.JitterStrategy_JITTER_STRATEGY_EQUAL,
+			FirstMs: 100,
+			MaxMs:   2000,
+			Factor:  1.5,
+		},
+		Admission: genv1.AdmissionStrategy_ADMISSION_STRATEGY_REPLACE,
+		Labels:    map[string]string{"pool": "a"},
+	}
+	if !proto.Equal(got, want) {
+		t.Fatalf("v1CreateSpec =\n%v\nwant\n%v", got, want)
+	}
+
+	// The same spec decoded from JSON converts to the same proto.
+	fromJSON, err := v1CreateSpec(jsonRoundTrip(t, ts.ToCreateSpec()))
+	if err != nil {
+		t.Fatalf("v1CreateSpec from JSON: %v", err)
+	}
+	if !proto.Equal(fromJSON, want) {
+		t.Fatalf("v1CreateSpec from JSON =\n%v\nwant\n%v", fromJSON, want)
+	}
+
+	// And the kind converts back to the CreateSpec "kind" object.
+	back := jsonRoundTrip(t, map[string]any{"kind": v1TaskKindMap(got.GetKind())})
+	orig := jsonRoundTrip(t, ts.ToCreateSpec())
+	if !reflect.DeepEqual(back["kind"], orig["kind"]) {
+		t.Fatalf("kind round trip = %v, want %v", back["kind"], orig["kind"])
+	}
+}
+
+func TestV1TaskKind_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		kind map[string]any
+	}{
+		{name: "subprocess", kind: map[string]any{"subprocess": map[string]any{"command": "uptime"}}},
+		{name: "container", kind: map[string]any{"container": map[string]any{
+			"image":   "nginx:1",
+			"command": []any{"nginx", "-g"},
+			"args":    []any{"daemon off;"},
+			"env":     map[string]any{"A": "1"},
+		}}},
+		{name: "wasm", kind: map[string]any{"wasm": map[string]any{
+			"module": "app.wasm",
+			"args":   []any{"-v"},
+			"env":    map[string]any{"B": "2"},
+		}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tk, err := v1TaskKind(tt.kind)
+			if err != nil {
+				t.Fatalf("v1TaskKind: %v", err)
+			}
+			back := jsonRoundTrip(t, v1TaskKindMap(tk))
+			if !reflect.DeepEqual(back, tt.kind) {
+				t.Fatalf("round trip = %v, want %v", back, tt.kind)
+			}
+		})
+	}
+
+	// A single string is a one-element container command.
+	tk, err := v1TaskKind(map[string]any{"container": map[string]any{"image": "nginx:1", "command": "nginx"}})
+	if err != nil {
+		t.Fatalf("v1TaskKind: %v", err)
+	}
+	if got := tk.GetContainer().GetCommand(); len(got) != 1 || got[0] != "nginx" {
+		t.Fatalf("container command = %v, want [nginx]", got)
+	}
+}
+
+func TestV1CreateSpec_Errors(t *testing.T) {
+	t.Parallel()
+
+	// valid returns a JSON-decoded CreateSpec with the given changes applied.
+	valid := func(change func(m map[string]any)) map[string]any {
+		m := map[string]any{
+			"slot":      "web",
+			"timeoutMs": float64(5000),
+			"kind":      map[string]any{"subprocess": map[string]any{"command": "./web"}},
+			"restart":   map[string]any{"type": "never"},
+			"backoff":   map[string]any{"jitter": "full", "firstMs": float64(100), "maxMs": float64(1000), "factor": float64(2)},
+			"admission": "replace",
+		}
+		change(m)
+		return m
+	}
+	sub := func(m map[string]any) map[string]any {
+		return m["kind"].(map[string]any)["subprocess"].(map[string]any)
+	}
+
+	if _, err := v1CreateSpec(valid(func(map[string]any) {})); err != nil {
+		t.Fatalf("valid spec: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(m map[string]any)
+		err    string
+	}{
+		{name: "missing slot", change: func(m map[string]any) { delete(m, "slot") }, err: "slot is required"},
+		{name: "slot not a string", change: func(m map[string]any) { m["slot"] = float64(1) }, err: "slot must be a string"},
+		{name: "fractional timeout", change: func(m map[string]any) { m["timeoutMs"] = 1.5 }, err: "timeoutMs must be an integer"},
+		{name: "timeout string", change: func(m map[string]any) { m["timeoutMs"] = "5s" }, err: "timeoutMs must be an integer"},
+		{name: "label not a string", change: func(m map[string]any) { m["labels"] = map[string]any{"pool": float64(1)} }, err: "labels.pool must be a string"},
+		{name: "labels not a map", change: func(m map[string]any) { m["labels"] = []any{"pool"} }, err: "labels must be a map of strings"},
+		{name: "missing kind", change: func(m map[string]any) { delete(m, "kind") }, err: "exactly one task kind"},
+		{name: "two kinds", change: func(m map[string]any) {
+			m["kind"].(map[string]any)["wasm"] = map[string]any{"module": "a.wasm"}
+		}, err: "exactly one task kind"},
+		{name: "unknown kind", change: func(m map[string]any) { m["kind"] = map[string]any{"vm": map[string]any{}} }, err: `unknown task kind "vm"`},
+		{name: "kind config not an object", change: func(m map[string]any) { m["kind"] = map[string]any{"subprocess": "./web"} }, err: "kind.subprocess must be an object"},
+		{name: "command not a string", change: func(m map[string]any) { sub(m)["command"] = []any{"./web"} }, err: "kind.subprocess.command must be a string"},
+		{name: "env value not a string", change: func(m map[string]any) { sub(m)["env"] = map[string]any{"PORT": float64(8080)} }, err: "kind.subprocess.env.PORT must be a string, got float64"},
+		{name: "arg not a string", change: func(m map[string]any) { sub(m)["args"] = []any{"--port", float64(8080)} }, err: "kind.subprocess.args[1] must be a string"},
+		{name: "args not a list", change: func(m map[string]any) { sub(m)["args"] = map[string]any{} }, err: "kind.subprocess.args must be a list of strings"},
+		{name: "cwd not a string", change: func(m map[string]any) { sub(m)["cwd"] = true }, err: "kind.subprocess.cwd must be a string"},
+		{name: "failOnNonZero not a boolean", change: func(m map[string]any) { sub(m)["failOnNonZero"] = "yes" }, err: "kind.subprocess.failOnNonZero must be a boolean"},
+		{name: "restart not an object", change: func(m map[string]any) { m["restart"] = "never" }, err: "restart must be an object"},
+		{name: "unknown restart type", change: func(m map[string]any) { m["restart"] = map[string]any{"type": "sometimes"} }, err: `unknown restart type "sometimes"`},
+		{name: "restart type not a string", change: func(m map[string]any) { m["restart"] = map[string]any{"type": float64(1)} }, err: "restart.type must be a string"},
+		{name: "interval not an integer", change: func(m map[string]any) {
+			m["restart"] = map[string]any{"type": "always", "intervalMs": "1s"}
+		}, err: "restart.intervalMs must be an integer"},
+		{name: "unknown jitter", change: func(m map[string]any) { m["backoff"].(map[string]any)["jitter"] = "some" }, err: `unknown jitter strategy "some"`},
+		{name: "factor not a number", change: func(m map[string]any) { m["backoff"].(map[string]any)["factor"] = "2" }, err: "backoff.factor must be a number"},
+		{name: "unknown admission", change: func(m map[string]any) { m["admission"] = "later" }, err: `unknown admission strategy "later"`},
+		{name: "admission not a string", change: func(m map[string]any) { m["admission"] = float64(1) }, err: "admission must be a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v1CreateSpec(valid(tt.change))
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("v1CreateSpec error = %v, want containing %q", err, tt.err)
+			}
+		})
+	}
+}
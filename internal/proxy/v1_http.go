@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
 	"strconv"
 
 	proxyv1 "github.com/soltiHQ/control-plane/api/proxy/v1"
 )
 
 const (
-	v1PathTasks = "/api/v1/tasks"
+	v1PathTasks  = "/api/v1/tasks"
+	v1PathSpecs  = "/api/v1/specs"
+	v1PathHealth = "/api/v1/health"
 )
 
 // httpProxyV1 implements AgentProxy over HTTP for API v1.
@@ -51,3 +54,82 @@ func (p *httpProxyV1) SubmitTask(ctx context.Context, sub TaskSubmission) error
 
 	return doPost(ctx, p.client, u.String(), map[string]any{"spec": sub.Spec})
 }
+
+func (p *httpProxyV1) ExportSpecs(ctx context.Context) ([]SpecExport, error) {
+	u, err := url.Parse(p.endpoint + v1PathSpecs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadEndpointURL, err)
+	}
+
+	list, err := doGet[SpecExportList](ctx, p.client, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExportSpecs, err)
+	}
+	return list.Specs, nil
+}
+
+func (p *httpProxyV1) DeleteTask(ctx context.Context, taskID string) error {
+	u, err := url.Parse(p.endpoint + path.Join(v1PathTasks, url.PathEscape(taskID)))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadEndpointURL, err)
+	}
+
+	if err = doDelete(ctx, p.client, u.String()); err != nil {
+		return fmt.Errorf("%w: %w", ErrDeleteTask, err)
+	}
+	return nil
+}
+
+func (p *httpProxyV1) CancelTask(ctx context.Context, taskID string) error {
+	return p.taskAction(ctx, taskID, "cancel", ErrCancelTask)
+}
+
+func (p *httpProxyV1) RestartTask(ctx context.Context, taskID string) error {
+	return p.taskAction(ctx, taskID, "restart", ErrRestartTask)
+}
+
+// taskAction posts to /api/v1/tasks/{id}/{action}; failures are wrapped with sentinel.
+func (p *httpProxyV1) taskAction(ctx context.Context, taskID, action string, sentinel error) error {
+	u, err := url.Parse(p.endpoint + path.Join(v1PathTasks, url.PathEscape(taskID), action))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadEndpointURL, err)
+	}
+
+	if err = doPost(ctx, p.client, u.String(), struct{}{}); err != nil {
+		return fmt.Errorf("%w: %w", sentinel, err)
+	}
+	return nil
+}
+
+func (p *httpProxyV1) TaskLogs(ctx context.Context, taskID string, opts LogOptions, fn func(proxyv1.LogLine) error) error {
+	u, err := url.Parse(p.endpoint + path.Join(v1PathTasks, url.PathEscape(taskID), "logs"))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadEndpointURL, err)
+	}
+
+	q := u.Query()
+	if opts.Follow {
+		q.Set("follow", "true")
+	}
+	if opts.Tail > 0 {
+		q.Set("tail", strconv.Itoa(opts.Tail))
+	}
+	u.RawQuery = q.Encode()
+
+	if err = doStream(ctx, p.client, u.String(), fn); err != nil {
+		return fmt.Errorf("%w: %w", ErrTaskLogs, err)
+	}
+	return nil
+}
+
+func (p *httpProxyV1) Probe(ctx context.Context) error {
+	u, err := url.Parse(p.endpoint + v1PathHealth)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrBadEndpointURL, err)
+	}
+
+	if err = doCheck(ctx, p.client, u.String()); err != nil {
+		return fmt.Errorf("%w: %w", ErrProbe, err)
+	}
+	return nil
+}
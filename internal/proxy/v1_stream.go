@@ -0,0 +1,82 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+
+	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
+	proxyv1 "github.com/soltiHQ/control-plane/api/proxy/v1"
+)
+
+// streamProxyV1 implements AgentProxy over an agent's Connect stream.
+// Commands carry the same v1 messages as the SoltiApi gRPC service.
+//
+// Task logs are not carried by the stream; they go through direct, the dialed
+// proxy of agents that also report an endpoint.
+type streamProxyV1 struct {
+	session *Session
+	direct  AgentProxy
+}
+
+func (p *streamProxyV1) ListTasks(ctx context.Context, f TaskFilter) (*proxyv1.TaskListResponse, error) {
+	res, err := p.session.call(ctx, &genv1.Command{Cmd: &genv1.Command_ListTasks{ListTasks: v1ListTasksRequest(f)}})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrListTasks, err)
+	}
+	return v1TaskList(res.GetListTasks()), nil
+}
+
+func (p *streamProxyV1) SubmitTask(ctx context.Context, sub TaskSubmission) error {
+	spec, err := v1CreateSpec(sub.Spec)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrSubmitTask, err)
+	}
+	cmd := &genv1.Command{Cmd: &genv1.Command_SubmitTask{SubmitTask: &genv1.SubmitTaskRequest{Spec: spec}}}
+	if _, err = p.session.call(ctx, cmd); err != nil {
+		return fmt.Errorf("%w: %v", ErrSubmitTask, err)
+	}
+	return nil
+}
+
+func (p *streamProxyV1) ExportSpecs(ctx context.Context) ([]SpecExport, error) {
+	res, err := p.session.call(ctx, &genv1.Command{Cmd: &genv1.Command_ExportSpecs{ExportSpecs: &genv1.ExportSpecsRequest{}}})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExportSpecs, err)
+	}
+	return v1SpecExports(res.GetExportSpecs()), nil
+}
+
+func (p *streamProxyV1) DeleteTask(ctx context.Context, taskID string) error {
+	cmd := &genv1.Command{Cmd: &genv1.Command_DeleteTask{DeleteTask: &genv1.DeleteTaskRequest{TaskId: taskID}}}
+	if _, err := p.session.call(ctx, cmd); err != nil {
+		return fmt.Errorf("%w: %v", ErrDeleteTask, err)
+	}
+	return nil
+}
+
+func (p *streamProxyV1) CancelTask(ctx context.Context, taskID string) error {
+	cmd := &genv1.Command{Cmd: &genv1.Command_CancelTask{CancelTask: &genv1.CancelTaskRequest{TaskId: taskID}}}
+	if _, err := p.session.call(ctx, cmd); err != nil {
+		return fmt.Errorf("%w: %v", ErrCancelTask, err)
+	}
+	return nil
+}
+
+func (p *streamProxyV1) RestartTask(ctx context.Context, taskID string) error {
+	cmd := &genv1.Command{Cmd: &genv1.Command_RestartTask{RestartTask: &genv1.RestartTaskRequest{TaskId: taskID}}}
+	if _, err := p.session.call(ctx, cmd); err != nil {
+		return fmt.Errorf("%w: %v", ErrRestartTask, err)
+	}
+	return nil
+}
+
+func (p *streamProxyV1) TaskLogs(ctx context.Context, taskID string, opts LogOptions, fn func(proxyv1.LogLine) error) error {
+	if p.direct == nil {
+		return fmt.Errorf("%w: %w", ErrTaskLogs, ErrSessionUnsupported)
+	}
+	return p.direct.TaskLogs(ctx, taskID, opts, fn)
+}
+
+// Probe succeeds while the session is attached: the agent holds its stream open,
+// whether or not its endpoint can be dialed.
+func (p *streamProxyV1) Probe(_ context.Context) error { return nil }
@@ -1,6 +1,9 @@
 package server
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Runner is a managed runtime component.
 //
@@ -12,3 +15,17 @@ type Runner interface {
 	Stop(ctx context.Context) error
 	Start(ctx context.Context) error
 }
+
+// Status is a point-in-time view of a runner's progress, shown on the dashboard.
+type Status struct {
+	Name    string
+	RanAt   time.Time // zero until the first tick
+	Backlog int       // actionable items seen by the last tick
+	Detail  string    // runner-specific note, e.g. the revision applied
+	Err     string    // error of the last tick; empty on success
+}
+
+// Reporter is implemented by runners that report their progress.
+type Reporter interface {
+	Status() Status
+}
@@ -0,0 +1,96 @@
+// Package backlog helps periodic runners drain paginated store listings:
+//   - Collect walks every page of a listing so nothing past MaxListLimit is starved
+//   - Cursor hands out bounded windows round-robin across ticks, so a backlog
+//     larger than one tick's budget is served fairly instead of head-first.
+package backlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+// Entity is the subset of domain.Entity needed to order items.
+type Entity interface {
+	ID() string
+	CreatedAt() time.Time
+}
+
+// ListFunc fetches one page of a listing.
+type ListFunc[T any] func(ctx context.Context, opts storage.ListOptions) (*storage.ListResult[T], error)
+
+// Collect follows NextCursor until the listing is exhausted and returns all items
+// in storage order.
+func Collect[T any](ctx context.Context, list ListFunc[T]) ([]T, error) {
+	var (
+		out    []T
+		cursor string
+	)
+	for {
+		res, err := list(ctx, storage.ListOptions{Cursor: cursor, Limit: storage.MaxListLimit})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res.Items...)
+		if res.NextCursor == "" {
+			return out, nil
+		}
+		cursor = res.NextCursor
+	}
+}
+
+// Cursor remembers the last item handed out by Next.
+//
+// It follows the storage ordering contract (CreatedAt DESC, ID ASC), so it stays
+// meaningful when items are added or removed between ticks.
+// The zero value starts at the head of the list. Not safe for concurrent use.
+type Cursor struct {
+	createdAt time.Time
+	id        string
+}
+
+// Next returns up to limit items starting right after the cursor, wrapping
+// around to the head, and advances the cursor to the last returned item.
+//
+// Items must be in storage order. A non-positive limit returns all items.
+func Next[T Entity](c *Cursor, items []T, limit int) []T {
+	if len(items) == 0 {
+		return nil
+	}
+	if limit <= 0 || limit > len(items) {
+		limit = len(items)
+	}
+
+	start := 0
+	if c.id != "" {
+		start = len(items)
+		for i, it := range items {
+			if c.before(it) {
+				start = i
+				break
+			}
+		}
+		if start == len(items) {
+			start = 0
+		}
+	}
+
+	out := make([]T, 0, limit)
+	for i := 0; i < limit; i++ {
+		out = append(out, items[(start+i)%len(items)])
+	}
+
+	last := out[len(out)-1]
+	c.createdAt, c.id = last.CreatedAt(), last.ID()
+	return out
+}
+
+// before reports whether the cursor position sorts before it.
+func (c *Cursor) before(it Entity) bool {
+	ts := it.CreatedAt()
+	if !ts.Equal(c.createdAt) {
+		return ts.Before(c.createdAt)
+	}
+	return it.ID() > c.id
+}
@@ -0,0 +1,114 @@
+package backlog
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+type item struct {
+	id string
+	ts time.Time
+}
+
+func (i item) ID() string           { return i.id }
+func (i item) CreatedAt() time.Time { return i.ts }
+
+// mkItems returns n items in storage order (CreatedAt DESC).
+func mkItems(n int) []item {
+	base := time.Date(2026, 2, 8, 12, 0, 0, 0, time.UTC)
+	out := make([]item, n)
+	for i := range out {
+		out[i] = item{id: "i" + strconv.Itoa(i), ts: base.Add(-time.Duration(i) * time.Second)}
+	}
+	return out
+}
+
+func ids(items []item) []string {
+	out := make([]string, len(items))
+	for i, it := range items {
+		out[i] = it.id
+	}
+	return out
+}
+
+func TestCollect_FollowsCursor(t *testing.T) {
+	var calls int
+	list := func(_ context.Context, opts storage.ListOptions) (*storage.ListResult[int], error) {
+		calls++
+		if opts.Limit != storage.MaxListLimit {
+			t.Fatalf("unexpected limit: %d", opts.Limit)
+		}
+		switch opts.Cursor {
+		case "":
+			return &storage.ListResult[int]{Items: []int{1, 2}, NextCursor: "p2"}, nil
+		case "p2":
+			return &storage.ListResult[int]{Items: []int{3}}, nil
+		}
+		t.Fatalf("unexpected cursor: %q", opts.Cursor)
+		return nil, nil
+	}
+
+	got, err := Collect(context.Background(), list)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) || calls != 2 {
+		t.Fatalf("unexpected result: %v (calls=%d)", got, calls)
+	}
+}
+
+func TestCollect_Error(t *testing.T) {
+	list := func(_ context.Context, _ storage.ListOptions) (*storage.ListResult[int], error) {
+		return nil, storage.ErrInternal
+	}
+	if _, err := Collect(context.Background(), list); !errors.Is(err, storage.ErrInternal) {
+		t.Fatalf("expected ErrInternal, err=%v", err)
+	}
+}
+
+func TestNext_RoundRobin(t *testing.T) {
+	var (
+		c     Cursor
+		items = mkItems(5)
+	)
+
+	if got := ids(Next(&c, items, 2)); !reflect.DeepEqual(got, []string{"i0", "i1"}) {
+		t.Fatalf("window 1: %v", got)
+	}
+	if got := ids(Next(&c, items, 2)); !reflect.DeepEqual(got, []string{"i2", "i3"}) {
+		t.Fatalf("window 2: %v", got)
+	}
+	if got := ids(Next(&c, items, 2)); !reflect.DeepEqual(got, []string{"i4", "i0"}) {
+		t.Fatalf("window 3: %v", got)
+	}
+}
+
+func TestNext_CursorItemRemoved(t *testing.T) {
+	var (
+		c     Cursor
+		items = mkItems(5)
+	)
+	Next(&c, items, 2) // cursor at i1
+
+	// i1 was processed and dropped out of the listing.
+	rest := append(append([]item{}, items[:1]...), items[2:]...)
+	if got := ids(Next(&c, rest, 2)); !reflect.DeepEqual(got, []string{"i2", "i3"}) {
+		t.Fatalf("unexpected window: %v", got)
+	}
+}
+
+func TestNext_NoLimitAndEmpty(t *testing.T) {
+	var c Cursor
+	if got := Next(&c, []item{}, 3); got != nil {
+		t.Fatalf("expected nil, got %v", got)
+	}
+	if got := Next(&c, mkItems(3), 0); len(got) != 3 {
+		t.Fatalf("expected all items, got %v", ids(got))
+	}
+}
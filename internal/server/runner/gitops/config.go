@@ -0,0 +1,42 @@
+package gitops
+
+import "time"
+
+const (
+	defaultTickInterval = 30 * time.Second
+	defaultReadTimeout  = 15 * time.Second
+
+	defaultName = "gitops"
+)
+
+// Config configures the GitOps runner.
+//
+// The runner is disabled when Path is empty.
+type Config struct {
+	TickInterval time.Duration `yaml:"tick_interval"`
+	// ReadTimeout bounds one reconciliation: reading the source and applying it.
+	ReadTimeout time.Duration `yaml:"read_timeout"`
+
+	// Path is a directory of manifests or a local bare git repository (HEAD is read).
+	Path string `yaml:"path"`
+	// Prune deletes specs this runner manages once they disappear from the source.
+	Prune bool `yaml:"prune"`
+
+	Name string `yaml:"name"`
+}
+
+// Enabled reports whether a source path is configured.
+func (c Config) Enabled() bool { return c.Path != "" }
+
+func (c Config) withDefaults() Config {
+	if c.Name == "" {
+		c.Name = defaultName
+	}
+	if c.TickInterval <= 0 {
+		c.TickInterval = defaultTickInterval
+	}
+	if c.ReadTimeout <= 0 {
+		c.ReadTimeout = defaultReadTimeout
+	}
+	return c
+}
@@ -0,0 +1,10 @@
+package gitops
+
+import "errors"
+
+var (
+	// ErrAlreadyStarted indicates Start was called more than once.
+	ErrAlreadyStarted = errors.New("gitops: already started")
+	// ErrEmptyPath indicates that no source path is configured.
+	ErrEmptyPath = errors.New("gitops: empty source path")
+)
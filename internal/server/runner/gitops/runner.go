@@ -0,0 +1,244 @@
+// Package gitops implements a server.Runner that reconciles specs to manifests
+// kept in a local directory or bare git repository:
+//   - Reads every *.yaml / *.yml / *.json manifest at the current revision
+//   - Applies them through the manifest service as owner "gitops:<name>"
+//   - Owned specs become managed (read-only in the UI and REST API)
+//   - Optionally prunes owned specs that disappeared from the source
+//   - Records sync results and failures on the dashboard activity feed.
+package gitops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	gosync "sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/server"
+	"github.com/soltiHQ/control-plane/internal/service"
+	"github.com/soltiHQ/control-plane/internal/service/manifest"
+	apimapv1 "github.com/soltiHQ/control-plane/internal/transport/http/apimap/v1"
+	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
+)
+
+// Runner is a server.Runner that periodically applies the manifests found at
+// Config.Path.
+//
+// On each tick it:
+//  1. Reads the manifests at the current revision of the source.
+//  2. Parses them; a broken file fails the whole tick so nothing is pruned by mistake.
+//  3. Applies them with owner "gitops:<name>" (and Prune, if configured).
+//  4. Records a synced event when something changed, or a failed event
+//     (a dashboard issue) when the error differs from the previous tick.
+type Runner struct {
+	manifest *manifest.Service
+	hub      *event.Hub
+
+	mu      gosync.Mutex
+	status  server.Status
+	lastErr string
+
+	logger zerolog.Logger
+	cfg    Config
+
+	stop    chan struct{}
+	started atomic.Bool
+}
+
+// New creates a GitOps runner.
+func New(cfg Config, logger zerolog.Logger, manifestSVC *manifest.Service, hub *event.Hub) (*Runner, error) {
+	if cfg.Path == "" {
+		return nil, ErrEmptyPath
+	}
+	if manifestSVC == nil {
+		return nil, fmt.Errorf("gitops: %w", service.ErrNilService)
+	}
+	if hub == nil {
+		return nil, fmt.Errorf("gitops: %w", event.ErrNilHub)
+	}
+
+	cfg = cfg.withDefaults()
+	return &Runner{
+		logger: logger.With().Str("runner", cfg.Name).Logger(),
+		stop:   make(chan struct{}),
+
+		manifest: manifestSVC,
+		cfg:      cfg,
+		hub:      hub,
+	}, nil
+}
+
+// Name returns the runner name.
+func (r *Runner) Name() string { return r.cfg.Name }
+
+// Owner returns the managed-by marker set on specs this runner applies.
+func (r *Runner) Owner() string { return "gitops:" + r.cfg.Name }
+
+// Status returns the outcome of the most recent reconciliation: when it ran,
+// the revision it read, and its error, if any.
+func (r *Runner) Status() server.Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st := r.status
+	st.Name = r.cfg.Name
+	return st
+}
+
+// Start runs the reconciliation loop until Stop is called.
+// The first reconciliation runs immediately.
+func (r *Runner) Start(_ context.Context) error {
+	if !r.started.CompareAndSwap(false, true) {
+		return ErrAlreadyStarted
+	}
+
+	ticker := time.NewTicker(r.cfg.TickInterval)
+	defer ticker.Stop()
+
+	r.logger.Debug().
+		Str("path", r.cfg.Path).
+		Dur("tick", r.cfg.TickInterval).
+		Bool("prune", r.cfg.Prune).
+		Msg("gitops runner started")
+
+	r.tick()
+	for {
+		select {
+		case <-ticker.C:
+			r.tick()
+		case <-r.stop:
+			r.logger.Info().Msg("gitops runner stopped")
+			return nil
+		}
+	}
+}
+
+// Stop signals the runner to exit. Safe to call multiple times.
+func (r *Runner) Stop(_ context.Context) error {
+	if !r.started.Load() {
+		return nil
+	}
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	return nil
+}
+
+func (r *Runner) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), r.cfg.ReadTimeout)
+	defer cancel()
+
+	// Abort reading and applying as soon as the runner stops.
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	snap, err := read(ctx, r.cfg.Path)
+	if err != nil {
+		r.fail("", fmt.Sprintf("read %s: %v", r.cfg.Path, err))
+		return
+	}
+	// An empty source is far more likely a bad checkout than an intent to prune everything.
+	if len(snap.files) == 0 {
+		r.fail(snap.revision, "no manifest files")
+		return
+	}
+
+	var docs []manifest.Document
+	for _, f := range snap.files {
+		d, err := manifest.Parse(f.data)
+		if err != nil {
+			r.fail(snap.revision, f.name+": "+err.Error())
+			return
+		}
+		docs = append(docs, apimapv1.ManifestDocuments(d)...)
+	}
+
+	res, err := r.manifest.Apply(ctx, docs, manifest.Options{
+		Prune: r.cfg.Prune,
+		Owner: r.Owner(),
+	})
+	if err != nil {
+		r.fail(snap.revision, "apply: "+err.Error())
+		return
+	}
+	if res.Failed() > 0 {
+		r.fail(snap.revision, failures(res))
+		return
+	}
+	r.succeed(snap.revision, res)
+}
+
+func (r *Runner) fail(revision, msg string) {
+	select {
+	case <-r.stop:
+		// Interrupted by Stop; not a failure of the source.
+		return
+	default:
+	}
+
+	r.mu.Lock()
+	r.status = server.Status{RanAt: time.Now(), Detail: revision, Err: msg}
+	repeated := r.lastErr == msg
+	r.lastErr = msg
+	r.mu.Unlock()
+
+	if repeated {
+		return
+	}
+	r.logger.Warn().Str("revision", revision).Str("error", msg).Msg("gitops sync failed")
+	r.hub.Record(event.GitOpsFailed, event.Payload{ID: r.cfg.Name, Name: r.cfg.Name, Detail: msg, By: "gitops"})
+}
+
+func (r *Runner) succeed(revision string, res *manifest.Result) {
+	counts := make(map[manifest.Action]int)
+	for _, it := range res.Items {
+		counts[it.Action]++
+	}
+	changed := counts[manifest.ActionCreated] + counts[manifest.ActionUpdated] + counts[manifest.ActionDeleted]
+
+	r.mu.Lock()
+	r.status = server.Status{RanAt: time.Now(), Detail: revision}
+	recovered := r.lastErr != ""
+	r.lastErr = ""
+	r.mu.Unlock()
+
+	if changed == 0 && !recovered {
+		return
+	}
+	detail := fmt.Sprintf("%s: %d created, %d updated, %d deleted",
+		revision, counts[manifest.ActionCreated], counts[manifest.ActionUpdated], counts[manifest.ActionDeleted])
+
+	r.logger.Info().Str("revision", revision).Int("changed", changed).Msg("gitops synced")
+	r.hub.Record(event.GitOpsSynced, event.Payload{ID: r.cfg.Name, Name: r.cfg.Name, Detail: detail, By: "gitops"})
+	if changed > 0 {
+		r.hub.Notify(htmx.SpecUpdate)
+		r.hub.Notify(htmx.AgentUpdate)
+	}
+}
+
+// failures summarizes the failed items of an apply result.
+func failures(res *manifest.Result) string {
+	var parts []string
+	for _, it := range res.Items {
+		if it.Action != manifest.ActionFailed {
+			continue
+		}
+		name := it.Name
+		if name == "" {
+			name = "<unnamed>"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s: %v", it.Kind, name, it.Err))
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,139 @@
+package gitops
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/service/agent"
+	"github.com/soltiHQ/control-plane/internal/service/manifest"
+	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+const webManifest = `kind: Spec
+spec:
+  name: web
+  slot: web
+  kind_type: subprocess
+  kind_config: {command: ./web}
+`
+
+func newTestRunner(t *testing.T, path string) (*Runner, *inmemory.Store, *event.Hub) {
+	t.Helper()
+
+	store := inmemory.New()
+	hub := event.NewHub(zerolog.Nop())
+	t.Cleanup(hub.Close)
+
+	r, err := New(Config{Path: path}, zerolog.Nop(), manifest.New(store, agent.New(store, zerolog.Nop()), zerolog.Nop()), hub)
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+	return r, store, hub
+}
+
+func countEvents(hub *event.Hub, kind string) int {
+	var n int
+	for _, ev := range hub.RecentEvents(100) {
+		if ev.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRunner_TickStatus(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"web.yaml": webManifest})
+	r, store, hub := newTestRunner(t, root)
+
+	if st := r.Status(); st.Name != "gitops" || !st.RanAt.IsZero() {
+		t.Fatalf("status before the first tick = %+v", st)
+	}
+
+	r.tick()
+	st := r.Status()
+	if st.Err != "" || st.RanAt.IsZero() || len(st.Detail) != 12 {
+		t.Fatalf("status after a clean tick = %+v", st)
+	}
+	specs, err := store.ListSpecs(context.Background(), nil, storage.ListOptions{Limit: storage.MaxListLimit})
+	if err != nil {
+		t.Fatalf("list specs: %v", err)
+	}
+	if len(specs.Items) != 1 || specs.Items[0].ManagedBy() != r.Owner() {
+		t.Fatalf("expected one spec managed by %s, got %v", r.Owner(), specs.Items)
+	}
+	if n := countEvents(hub, event.GitOpsSynced); n != 1 {
+		t.Fatalf("expected 1 gitops_synced event, got %d", n)
+	}
+
+	// A broken file fails the tick once, however often it is retried.
+	writeFiles(t, root, map[string]string{"broken.yaml": "kind: [unterminated"})
+	r.tick()
+	r.tick()
+	st = r.Status()
+	if !strings.HasPrefix(st.Err, "broken.yaml: ") || st.Detail == "" {
+		t.Fatalf("status after a broken tick = %+v", st)
+	}
+	if n := countEvents(hub, event.GitOpsFailed); n != 1 {
+		t.Fatalf("expected 1 gitops_failed event, got %d", n)
+	}
+}
+
+func TestRunner_StoppedTickIsNotAFailure(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"broken.yaml": "kind: [unterminated"})
+	r, _, hub := newTestRunner(t, root)
+
+	close(r.stop)
+	r.tick()
+	if st := r.Status(); st.Err != "" || !st.RanAt.IsZero() {
+		t.Fatalf("status after a stopped tick = %+v", st)
+	}
+	if n := countEvents(hub, event.GitOpsFailed); n != 0 {
+		t.Fatalf("expected no gitops_failed event, got %d", n)
+	}
+}
+
+func TestRunner_EmptySourceIsAFailure(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{"web.yaml": webManifest})
+	r, store, hub := newTestRunner(t, root)
+	r.cfg.Prune = true
+
+	r.tick()
+	if st := r.Status(); st.Err != "" {
+		t.Fatalf("status after a clean tick = %+v", st)
+	}
+
+	// Every manifest disappears, e.g. after a bad checkout: nothing is pruned.
+	if err := os.Remove(filepath.Join(root, "web.yaml")); err != nil {
+		t.Fatalf("remove manifest: %v", err)
+	}
+	r.tick()
+	if st := r.Status(); st.Err != "no manifest files" {
+		t.Fatalf("status after an empty tick = %+v", st)
+	}
+	specs, err := store.ListSpecs(context.Background(), nil, storage.ListOptions{Limit: storage.MaxListLimit})
+	if err != nil {
+		t.Fatalf("list specs: %v", err)
+	}
+	if len(specs.Items) != 1 {
+		t.Fatalf("expected the spec to be kept, got %v", specs.Items)
+	}
+	if n := countEvents(hub, event.GitOpsFailed); n != 1 {
+		t.Fatalf("expected 1 gitops_failed event, got %d", n)
+	}
+}
@@ -0,0 +1,144 @@
+package gitops
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// file is one manifest file read from the source.
+type file struct {
+	name string
+	data []byte
+}
+
+// snapshot is the content of the source at one revision.
+type snapshot struct {
+	revision string
+	files    []file
+}
+
+// manifestExt reports whether name looks like a manifest file.
+func manifestExt(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// read loads all manifest files from path: HEAD of a bare git repository,
+// or every manifest file under a directory (hidden entries skipped).
+func read(ctx context.Context, path string) (*snapshot, error) {
+	if isBareRepo(path) {
+		return readGit(ctx, path)
+	}
+	return readDir(path)
+}
+
+func isBareRepo(path string) bool {
+	for _, p := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(path, p)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func readDir(root string) (*snapshot, error) {
+	var files []file
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p != root && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !manifestExt(d.Name()) {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		files = append(files, file{name: filepath.ToSlash(rel), data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	h := sha256.New()
+	for _, f := range files {
+		h.Write([]byte(f.name))
+		h.Write([]byte{0})
+		h.Write(f.data)
+		h.Write([]byte{0})
+	}
+	return &snapshot{revision: hex.EncodeToString(h.Sum(nil))[:12], files: files}, nil
+}
+
+func readGit(ctx context.Context, dir string) (*snapshot, error) {
+	out, err := git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	rev := strings.TrimSpace(string(out))
+
+	out, err = git(ctx, dir, "ls-tree", "-r", "-z", "--name-only", rev)
+	if err != nil {
+		return nil, err
+	}
+	var files []file
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name == "" || !manifestExt(name) || hiddenPath(name) {
+			continue
+		}
+		data, err := git(ctx, dir, "show", rev+":"+name)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file{name: name, data: data})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+
+	if len(rev) > 12 {
+		rev = rev[:12]
+	}
+	return &snapshot{revision: rev, files: files}, nil
+}
+
+func hiddenPath(name string) bool {
+	for _, part := range strings.Split(name, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", append([]string{"--git-dir", dir}, args...)...)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
@@ -0,0 +1,209 @@
+package gitops
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// writeFiles creates files (slash-separated paths) under root.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, data := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+}
+
+func fileNames(snap *snapshot) []string {
+	names := make([]string, 0, len(snap.files))
+	for _, f := range snap.files {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+func TestManifestExt(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "app.yaml", want: true},
+		{name: "app.yml", want: true},
+		{name: "app.json", want: true},
+		{name: "APP.YAML", want: true},
+		{name: "README.md"},
+		{name: "yaml"},
+		{name: "app.yaml.bak"},
+	}
+	for _, tt := range tests {
+		if got := manifestExt(tt.name); got != tt.want {
+			t.Errorf("manifestExt(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"b.yaml":            "b",
+		"a.yml":             "a",
+		"nested/c.json":     "c",
+		"README.md":         "not a manifest",
+		".hidden.yaml":      "hidden file",
+		".git/config.yaml":  "hidden dir",
+		"nested/.d/e.yaml":  "nested hidden dir",
+		"nested/deep/f.yml": "f",
+	})
+
+	snap, err := read(context.Background(), root)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := []string{"a.yml", "b.yaml", "nested/c.json", "nested/deep/f.yml"}
+	if got := fileNames(snap); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if string(snap.files[0].data) != "a" {
+		t.Fatalf("a.yml data = %q", snap.files[0].data)
+	}
+	if len(snap.revision) != 12 {
+		t.Fatalf("revision = %q, want 12 hex characters", snap.revision)
+	}
+
+	// The revision is a content hash: stable across reads, changed by edits and renames.
+	again, err := read(context.Background(), root)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if again.revision != snap.revision {
+		t.Fatalf("revision changed without edits: %s -> %s", snap.revision, again.revision)
+	}
+	writeFiles(t, root, map[string]string{"a.yml": "a2"})
+	edited, err := read(context.Background(), root)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if edited.revision == snap.revision {
+		t.Fatal("revision did not change after an edit")
+	}
+	if err = os.Rename(filepath.Join(root, "a.yml"), filepath.Join(root, "z.yml")); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	renamed, err := read(context.Background(), root)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if renamed.revision == edited.revision {
+		t.Fatal("revision did not change after a rename")
+	}
+
+	// Hidden files do not affect the revision.
+	writeFiles(t, root, map[string]string{".hidden.yaml": "changed"})
+	if hidden, err := read(context.Background(), root); err != nil || hidden.revision != renamed.revision {
+		t.Fatalf("hidden edit changed the revision: %v", err)
+	}
+}
+
+func TestReadDir_Missing(t *testing.T) {
+	t.Parallel()
+
+	if _, err := read(context.Background(), filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("expected an error for a missing directory")
+	}
+}
+
+// gitRun runs git in dir and fails the test on error.
+func gitRun(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_CONFIG_GLOBAL="+os.DevNull,
+		"GIT_CONFIG_SYSTEM="+os.DevNull,
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, out)
+	}
+	return string(out)
+}
+
+func TestReadGit(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	var (
+		work = t.TempDir()
+		bare = filepath.Join(t.TempDir(), "repo.git")
+	)
+	gitRun(t, work, "init", "-q")
+	writeFiles(t, work, map[string]string{
+		"specs/web.yaml":  "web",
+		"db.yml":          "db",
+		"notes.txt":       "not a manifest",
+		".ci/deploy.yaml": "hidden",
+	})
+	gitRun(t, work, "add", "-A")
+	gitRun(t, work, "commit", "-q", "-m", "initial")
+	gitRun(t, work, "clone", "-q", "--bare", work, bare)
+
+	if !isBareRepo(bare) {
+		t.Fatal("expected a bare repository")
+	}
+	if isBareRepo(work) {
+		t.Fatal("a work tree is not a bare repository")
+	}
+
+	// An uncommitted file in the work tree is not part of HEAD.
+	writeFiles(t, work, map[string]string{"draft.yaml": "draft"})
+
+	snap, err := read(context.Background(), bare)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := []string{"db.yml", "specs/web.yaml"}
+	if got := fileNames(snap); !slices.Equal(got, want) {
+		t.Fatalf("files = %v, want %v", got, want)
+	}
+	if string(snap.files[1].data) != "web" {
+		t.Fatalf("specs/web.yaml data = %q", snap.files[1].data)
+	}
+	head := gitRun(t, bare, "--git-dir", bare, "rev-parse", "HEAD")
+	if snap.revision != head[:12] {
+		t.Fatalf("revision = %q, want HEAD %q", snap.revision, head[:12])
+	}
+}
+
+func TestReadGit_NoCommits(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	bare := t.TempDir()
+	gitRun(t, bare, "init", "-q", "--bare")
+	if _, err := read(context.Background(), bare); err == nil {
+		t.Fatal("expected an error for a repository without commits")
+	}
+}
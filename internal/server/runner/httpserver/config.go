@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"net"
 	"time"
 )
@@ -27,6 +28,9 @@ type Config struct {
 
 	BaseContext func(net.Listener) context.Context                   `yaml:"-"`
 	ConnContext func(ctx context.Context, c net.Conn) context.Context `yaml:"-"`
+
+	// TLSConfig, when set, serves HTTPS with it (certificates are taken from the config).
+	TLSConfig *tls.Config `yaml:"-" ignored:"true"`
 }
 
 func (c Config) withDefaults() Config {
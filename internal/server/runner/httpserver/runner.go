@@ -1,12 +1,13 @@
 // Package httpserver implements a server.Runner that manages the lifecycle
 // of an [http.Server]:
 //   - Builds the server from a provided [http.Handler] and timeout config
-//   - Binds a TCP listener on the configured address
+//   - Binds a TCP listener on the configured address, wrapped in TLS when configured
 //   - Graceful shutdown via [http.Server.Shutdown] with hard-close fallback.
 package httpserver
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"net"
 	"net/http"
@@ -62,6 +63,9 @@ func (r *Runner) Start(_ context.Context) error {
 		close(r.ready)
 		return err
 	}
+	if r.cfg.TLSConfig != nil {
+		ln = tls.NewListener(ln, r.cfg.TLSConfig)
+	}
 	r.ln = ln
 
 	r.srv = &http.Server{
@@ -90,6 +94,7 @@ func (r *Runner) Start(_ context.Context) error {
 	r.logger.Info().
 		Str("runner", r.cfg.Name).
 		Str("addr", r.cfg.Addr).
+		Bool("tls", r.cfg.TLSConfig != nil).
 		Msg("http server listening")
 
 	err = r.srv.Serve(ln)
@@ -8,6 +8,10 @@ const (
 
 	defaultName           = "lifecycle"
 	defaultMaxConcurrency = 4
+	defaultMaxPerTick     = 5000
+
+	defaultProbeInterval = 30 * time.Second
+	defaultProbeTimeout  = 5 * time.Second
 
 	defaultInactiveMultiplier   = 2
 	defaultDisconnectMultiplier = 5
@@ -22,7 +26,23 @@ type Config struct {
 	DisconnectMultiplier int           `yaml:"disconnect_multiplier"`
 	DeleteMultiplier     int           `yaml:"delete_multiplier"`
 	MaxConcurrency       int           `yaml:"max_concurrency"`
+	MaxPerTick           int           `yaml:"max_per_tick"`
 	Name                 string        `yaml:"name"`
+
+	// Probe enables active reachability checks of agent endpoints.
+	Probe ProbeConfig `yaml:"probe"`
+}
+
+// ProbeConfig configures the active reachability probe.
+//
+// When enabled, every Interval the runner probes each active agent that is dialed by
+// the control-plane (not in pull mode) through the proxy pool: an HTTP GET of the
+// agent health path, or a grpc.health.v1 check. An attached Connect session counts
+// as reachable.
+type ProbeConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+	Timeout  time.Duration `yaml:"timeout"`
 }
 
 func (c Config) withDefaults() Config {
@@ -47,6 +67,15 @@ func (c Config) withDefaults() Config {
 	if c.MaxConcurrency <= 0 {
 		c.MaxConcurrency = defaultMaxConcurrency
 	}
+	if c.MaxPerTick <= 0 {
+		c.MaxPerTick = defaultMaxPerTick
+	}
+	if c.Probe.Interval <= 0 {
+		c.Probe.Interval = defaultProbeInterval
+	}
+	if c.Probe.Timeout <= 0 {
+		c.Probe.Timeout = defaultProbeTimeout
+	}
 	if c.DisconnectMultiplier <= c.InactiveMultiplier {
 		c.DisconnectMultiplier = c.InactiveMultiplier + 1
 	}
@@ -0,0 +1,143 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/server/runner/backlog"
+	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
+)
+
+// probeLoop probes agent endpoints every Probe.Interval until the runner stops.
+// It runs beside the liveness loop so slow or hanging endpoints never delay reconciling.
+func (r *Runner) probeLoop() {
+	defer close(r.probeDone)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Abort in-flight probes as soon as the runner stops.
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(r.cfg.Probe.Interval)
+	defer ticker.Stop()
+
+	r.logger.Debug().
+		Dur("interval", r.cfg.Probe.Interval).
+		Dur("timeout", r.cfg.Probe.Timeout).
+		Msg("probe loop started")
+
+	for {
+		select {
+		case <-ticker.C:
+			r.probeAll(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *Runner) probeAll(ctx context.Context) {
+	all, err := backlog.Collect(ctx, func(ctx context.Context, opts storage.ListOptions) (*storage.ListResult[*model.Agent], error) {
+		return r.store.ListAgents(ctx, inmemory.NewAgentFilter(), opts)
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Msg("probe: list agents failed")
+		return
+	}
+
+	var g errgroup.Group
+	g.SetLimit(r.cfg.MaxConcurrency)
+
+	for _, a := range all {
+		if !r.probeable(a) {
+			continue
+		}
+
+		g.Go(func() error {
+			r.probe(ctx, a)
+			return nil
+		})
+	}
+	_ = g.Wait()
+}
+
+// probeable reports whether a is dialed by the control-plane and alive by heartbeat.
+// Agents that went silent are already handled by the liveness stages.
+func (r *Runner) probeable(a *model.Agent) bool {
+	if a == nil || a.PullMode() || a.Status() != kind.AgentStatusActive {
+		return false
+	}
+	return a.Endpoint() != "" || r.pool.Connected(a.ID())
+}
+
+// probe checks a single agent and stores the result, recording an event when its
+// reachability flips between reachable and unreachable.
+func (r *Runner) probe(ctx context.Context, a *model.Agent) {
+	pctx, cancel := context.WithTimeout(ctx, r.cfg.Probe.Timeout)
+	defer cancel()
+
+	var (
+		reach  = kind.AgentReachabilityReachable
+		errMsg string
+	)
+	ap, err := r.pool.Get(a.ID(), a.Endpoint(), a.EndpointType(), a.APIVersion())
+	if err == nil {
+		err = ap.Probe(pctx)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		reach, errMsg = kind.AgentReachabilityUnreachable, err.Error()
+	}
+
+	prev, err := r.agents.SetReachability(ctx, a.ID(), reach, errMsg, time.Now())
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			r.logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("probe: store result failed")
+		}
+		return
+	}
+	if prev == reach {
+		return
+	}
+
+	switch reach {
+	case kind.AgentReachabilityUnreachable:
+		r.logger.Warn().
+			Str("agent_id", a.ID()).
+			Str("endpoint", a.Endpoint()).
+			Str("reason", errMsg).
+			Msg("agent → unreachable")
+
+		r.hub.Record(event.AgentUnreachable, event.Payload{ID: a.ID(), Name: a.Name(), By: "lifecycle", Detail: errMsg})
+	case kind.AgentReachabilityReachable:
+		if prev == kind.AgentReachabilityUnreachable {
+			r.logger.Info().
+				Str("agent_id", a.ID()).
+				Msg("agent → reachable")
+
+			r.hub.Record(event.AgentReachable, event.Payload{ID: a.ID(), Name: a.Name(), By: "lifecycle"})
+			if r.hub.DeleteIssues(event.AgentUnreachable, a.ID()) > 0 {
+				r.hub.Record(event.IssueClosed, event.Payload{ID: a.ID(), Name: a.Name(), By: "lifecycle"})
+				r.hub.Notify(htmx.DashboardUpdate)
+			}
+		}
+	}
+	r.hub.Notify(htmx.AgentUpdate)
+}
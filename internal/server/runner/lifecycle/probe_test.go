@@ -0,0 +1,227 @@
+package lifecycle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	genv1 "github.com/soltiHQ/control-plane/api/gen/v1"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/metrics"
+	"github.com/soltiHQ/control-plane/internal/proxy"
+	"github.com/soltiHQ/control-plane/internal/service/agent"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+func newTestRunner(t *testing.T, cfg Config) (*Runner, *inmemory.Store, *event.Hub) {
+	t.Helper()
+
+	var (
+		store = inmemory.New()
+		pool  = proxy.NewPool(nil)
+		hub   = event.NewHub(zerolog.Nop())
+	)
+	t.Cleanup(func() {
+		_ = pool.Close()
+		hub.Close()
+	})
+
+	r, err := New(cfg, zerolog.Nop(), store, agent.New(store, zerolog.Nop()), metrics.NewStore(metrics.Config{}), pool, hub)
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+	return r, store, hub
+}
+
+// mkHTTPAgent stores an active push-mode HTTP agent with the given endpoint.
+func mkHTTPAgent(t *testing.T, store *inmemory.Store, id, endpoint string, pull bool) *model.Agent {
+	t.Helper()
+
+	a, err := model.NewAgentFrom(model.AgentParams{
+		ID:           id,
+		Name:         id,
+		Endpoint:     endpoint,
+		EndpointType: 1,
+		APIVersion:   1,
+		PullMode:     pull,
+	})
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	if err = store.UpsertAgent(context.Background(), a); err != nil {
+		t.Fatalf("upsert agent: %v", err)
+	}
+	return a
+}
+
+func countEvents(hub *event.Hub, kind, id string) int {
+	var n int
+	for _, ev := range hub.RecentEvents(100) {
+		if ev.Kind == kind && ev.Payload.ID == id {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRunner_Probeable(t *testing.T) {
+	t.Parallel()
+
+	r, store, _ := newTestRunner(t, Config{})
+
+	inactive := mkHTTPAgent(t, store, "inactive", "http://inactive:8080", false)
+	inactive.SetStatus(kind.AgentStatusInactive)
+
+	connected := mkHTTPAgent(t, store, "connected", "", false)
+	detach := r.pool.Attach(proxy.NewSession("connected", func(*genv1.ControlMessage) error { return nil }))
+	t.Cleanup(detach)
+
+	tests := []struct {
+		name  string
+		agent *model.Agent
+		want  bool
+	}{
+		{name: "nil agent"},
+		{name: "push agent", agent: mkHTTPAgent(t, store, "push", "http://push:8080", false), want: true},
+		{name: "pull agent", agent: mkHTTPAgent(t, store, "pull", "http://pull:8080", true)},
+		{name: "inactive agent", agent: inactive},
+		{name: "no endpoint", agent: mkHTTPAgent(t, store, "silent", "", false)},
+		{name: "no endpoint but connected", agent: connected, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.probeable(tt.agent); got != tt.want {
+				t.Fatalf("probeable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunner_ProbeFlips(t *testing.T) {
+	t.Parallel()
+
+	var healthy atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/health" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := context.Background()
+	r, store, hub := newTestRunner(t, Config{Probe: ProbeConfig{Timeout: time.Second}})
+	a := mkHTTPAgent(t, store, "a1", srv.URL, false)
+
+	probe := func(ok bool, want kind.AgentReachability) *model.Agent {
+		t.Helper()
+
+		healthy.Store(ok)
+		r.probe(ctx, a)
+		cur, err := store.GetAgent(ctx, "a1")
+		if err != nil {
+			t.Fatalf("get agent: %v", err)
+		}
+		if cur.Reachability() != want {
+			t.Fatalf("reachability = %s, want %s", cur.Reachability(), want)
+		}
+		return cur
+	}
+
+	// The first successful probe is not a flip.
+	probe(true, kind.AgentReachabilityReachable)
+	if n := countEvents(hub, event.AgentReachable, "a1"); n != 0 {
+		t.Fatalf("expected no agent_reachable event, got %d", n)
+	}
+
+	// Turning unreachable records one issue, however often it is probed.
+	cur := probe(false, kind.AgentReachabilityUnreachable)
+	if cur.ProbeError() == "" || cur.ProbedAt().IsZero() {
+		t.Fatalf("expected probe error and time, got %q at %v", cur.ProbeError(), cur.ProbedAt())
+	}
+	probe(false, kind.AgentReachabilityUnreachable)
+	if n := countEvents(hub, event.AgentUnreachable, "a1"); n != 1 {
+		t.Fatalf("expected 1 agent_unreachable event, got %d", n)
+	}
+	if !hub.HasIssue(event.AgentUnreachable, "a1") {
+		t.Fatal("expected an agent_unreachable issue")
+	}
+
+	// Recovering records agent_reachable and closes the issue.
+	cur = probe(true, kind.AgentReachabilityReachable)
+	if cur.ProbeError() != "" {
+		t.Fatalf("expected probe error to be cleared, got %q", cur.ProbeError())
+	}
+	if n := countEvents(hub, event.AgentReachable, "a1"); n != 1 {
+		t.Fatalf("expected 1 agent_reachable event, got %d", n)
+	}
+	if hub.HasIssue(event.AgentUnreachable, "a1") {
+		t.Fatal("expected the agent_unreachable issue to be closed")
+	}
+	if n := countEvents(hub, event.IssueClosed, "a1"); n != 1 {
+		t.Fatalf("expected 1 issue_closed event, got %d", n)
+	}
+}
+
+func TestRunner_ProbeKeepsSyncedFields(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r, store, _ := newTestRunner(t, Config{Probe: ProbeConfig{Timeout: time.Second}})
+	a := mkHTTPAgent(t, store, "a1", "http://127.0.0.1:1", false)
+
+	// A sync stored new labels after the probe loop listed the agent.
+	cur, err := store.GetAgent(ctx, "a1")
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	cur.LabelAdd("env", "prod")
+	if err = store.UpsertAgent(ctx, cur); err != nil {
+		t.Fatalf("upsert agent: %v", err)
+	}
+
+	r.probe(ctx, a)
+	if cur, err = store.GetAgent(ctx, "a1"); err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if cur.Reachability() != kind.AgentReachabilityUnreachable {
+		t.Fatalf("reachability = %s, want unreachable", cur.Reachability())
+	}
+	if cur.LabelsAll()["env"] != "prod" {
+		t.Fatalf("probe overwrote labels stored by a sync: %v", cur.LabelsAll())
+	}
+}
+
+func TestRunner_StopWaitsForProbeLoop(t *testing.T) {
+	t.Parallel()
+
+	r, _, _ := newTestRunner(t, Config{Probe: ProbeConfig{Enabled: true, Interval: time.Millisecond}})
+
+	started := make(chan error, 1)
+	go func() { started <- r.Start(context.Background()) }()
+	for !r.started.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.Stop(ctx); err != nil {
+		t.Fatalf("stop: %v", err)
+	}
+	select {
+	case <-r.probeDone:
+	default:
+		t.Fatal("Stop returned before the probe loop exited")
+	}
+	if err := <-started; err != nil {
+		t.Fatalf("start: %v", err)
+	}
+}
@@ -1,7 +1,20 @@
 // Package lifecycle implements a server.Runner that periodically checks agent liveness
 //   - Transitions agents through status stages: (active → inactive → disconnected → deleted)
+//   - Optionally probes agent endpoints and records their reachability
+//
+// Deleting an agent also drops its metrics and inventory change history. Agents holding
+// an enrollment credential are never deleted, only kept disconnected: the credential is
+// stored on the agent record, so deleting it would lock the agent out when it returns
+// and free its ID for anyone holding an enrollment token.
 //
 // Thresholds are expressed as multiples of each agent's heartbeat interval.
+// Stale agents are listed across all pages; each tick reconciles at most MaxPerTick
+// of them, continuing round-robin from where the previous tick stopped.
+//
+// Heartbeats only prove the agent can reach the control-plane. With Probe enabled a
+// separate loop dials every active push-mode agent through the proxy pool, so an agent
+// that heartbeats but whose endpoint is unreachable is marked unreachable; the sync
+// runner then defers pushes to it until a probe succeeds again.
 package lifecycle
 
 import (
@@ -16,6 +29,12 @@ import (
 	"github.com/soltiHQ/control-plane/domain/kind"
 	"github.com/soltiHQ/control-plane/domain/model"
 	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/metrics"
+	"github.com/soltiHQ/control-plane/internal/proxy"
+	"github.com/soltiHQ/control-plane/internal/server"
+	"github.com/soltiHQ/control-plane/internal/server/runner/backlog"
+	"github.com/soltiHQ/control-plane/internal/service"
+	"github.com/soltiHQ/control-plane/internal/service/agent"
 	"github.com/soltiHQ/control-plane/internal/storage"
 	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
 	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
@@ -23,37 +42,68 @@ import (
 
 // Runner is a server.Runner that periodically checks agent liveness.
 type Runner struct {
-	hub *event.Hub
+	hub     *event.Hub
+	metrics *metrics.Store
+	pool    *proxy.Pool
+	agents  *agent.Service
 
 	logger zerolog.Logger
-	store  storage.AgentStore
+	store  storage.Storage
 	cfg    Config
 
-	stop    chan struct{}
-	started atomic.Bool
+	cursor  backlog.Cursor
+	backlog atomic.Int64
+	ranAt   atomic.Int64 // unix nanoseconds of the last tick
+
+	stop      chan struct{}
+	probeDone chan struct{} // closed once the probe loop returned, or at Start when probing is disabled
+	started   atomic.Bool
 }
 
 // New creates a lifecycle runner.
-func New(cfg Config, logger zerolog.Logger, store storage.AgentStore, hub *event.Hub) (*Runner, error) {
+func New(cfg Config, logger zerolog.Logger, store storage.Storage, agentSVC *agent.Service, metricStore *metrics.Store, pool *proxy.Pool, hub *event.Hub) (*Runner, error) {
 	if store == nil {
 		return nil, fmt.Errorf("lifecycle: %w", storage.ErrNilStore)
 	}
+	if agentSVC == nil {
+		return nil, fmt.Errorf("lifecycle: %w", service.ErrNilService)
+	}
+	if metricStore == nil {
+		return nil, fmt.Errorf("lifecycle: %w", metrics.ErrNilStore)
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("lifecycle: %w", proxy.ErrNilPool)
+	}
 	if hub == nil {
 		return nil, fmt.Errorf("lifecycle: %w", event.ErrNilHub)
 	}
 	cfg = cfg.withDefaults()
 	return &Runner{
-		logger: logger.With().Str("runner", cfg.Name).Logger(),
-		cfg:    cfg,
-		store:  store,
-		hub:    hub,
-		stop:   make(chan struct{}),
+		logger:  logger.With().Str("runner", cfg.Name).Logger(),
+		cfg:     cfg,
+		store:   store,
+		agents:  agentSVC,
+		metrics: metricStore,
+		pool:    pool,
+		hub:     hub,
+
+		stop:      make(chan struct{}),
+		probeDone: make(chan struct{}),
 	}, nil
 }
 
 // Name returns the runner name.
 func (r *Runner) Name() string { return r.cfg.Name }
 
+// Status reports when the last tick ran and how many stale agents it saw.
+func (r *Runner) Status() server.Status {
+	var ranAt time.Time
+	if ns := r.ranAt.Load(); ns != 0 {
+		ranAt = time.Unix(0, ns)
+	}
+	return server.Status{Name: r.cfg.Name, RanAt: ranAt, Backlog: int(r.backlog.Load())}
+}
+
 // Start runs the lifecycle check loop until Stop is called.
 func (r *Runner) Start(_ context.Context) error {
 	if !r.started.CompareAndSwap(false, true) {
@@ -69,8 +119,15 @@ func (r *Runner) Start(_ context.Context) error {
 		Int("disconnect", r.cfg.DisconnectMultiplier).
 		Int("delete", r.cfg.DeleteMultiplier).
 		Int("max_concurrency", r.cfg.MaxConcurrency).
+		Int("max_per_tick", r.cfg.MaxPerTick).
+		Bool("probe", r.cfg.Probe.Enabled).
 		Msg("lifecycle runner started")
 
+	if r.cfg.Probe.Enabled {
+		go r.probeLoop()
+	} else {
+		close(r.probeDone)
+	}
 	for {
 		select {
 		case <-ticker.C:
@@ -82,8 +139,9 @@ func (r *Runner) Start(_ context.Context) error {
 	}
 }
 
-// Stop signals the runner to exit. Safe to call multiple times.
-func (r *Runner) Stop(_ context.Context) error {
+// Stop signals the runner to exit and waits until in-flight probes are aborted
+// and the probe loop returned, or ctx is done. Safe to call multiple times.
+func (r *Runner) Stop(ctx context.Context) error {
 	if !r.started.Load() {
 		return nil
 	}
@@ -92,7 +150,13 @@ func (r *Runner) Stop(_ context.Context) error {
 	default:
 		close(r.stop)
 	}
-	return nil
+
+	select {
+	case <-r.probeDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 func (r *Runner) tick() {
@@ -101,19 +165,29 @@ func (r *Runner) tick() {
 		ctx    = context.Background()
 		filter = inmemory.NewAgentFilter().StaleAtBefore(now)
 
-		res, err = r.store.ListAgents(ctx, filter, storage.ListOptions{
-			Limit: storage.MaxListLimit,
+		all, err = backlog.Collect(ctx, func(ctx context.Context, opts storage.ListOptions) (*storage.ListResult[*model.Agent], error) {
+			return r.store.ListAgents(ctx, filter, opts)
 		})
 	)
 	if err != nil {
 		r.logger.Error().Err(err).Msg("tick: list agents failed")
 		return
 	}
+	r.backlog.Store(int64(len(all)))
+	r.ranAt.Store(now.UnixNano())
+
+	window := backlog.Next(&r.cursor, all, r.cfg.MaxPerTick)
+	if len(window) < len(all) {
+		r.logger.Warn().
+			Int("backlog", len(all)).
+			Int("window", len(window)).
+			Msg("tick: backlog exceeds per-tick budget")
+	}
 
 	var g errgroup.Group
 	g.SetLimit(r.cfg.MaxConcurrency)
 
-	for _, a := range res.Items {
+	for _, a := range window {
 		if a == nil {
 			continue
 		}
@@ -136,11 +210,15 @@ func (r *Runner) reconcile(ctx context.Context, now time.Time, a *model.Agent) {
 
 	silence := now.Sub(a.LastSeenAt())
 	switch {
-	case silence > hb*time.Duration(r.cfg.DeleteMultiplier):
+	case silence > hb*time.Duration(r.cfg.DeleteMultiplier) && a.CredentialHash() == "":
 		if err := r.store.DeleteAgent(ctx, a.ID()); err != nil {
 			r.logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("reconcile: delete failed")
 			return
 		}
+		r.metrics.Delete(a.ID())
+		if err := r.store.DeleteAgentChanges(ctx, a.ID()); err != nil {
+			r.logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("reconcile: delete history failed")
+		}
 		r.logger.Info().
 			Str("agent_id", a.ID()).
 			Dur("silence", silence).
@@ -151,10 +229,12 @@ func (r *Runner) reconcile(ctx context.Context, now time.Time, a *model.Agent) {
 
 	case silence > hb*time.Duration(r.cfg.DisconnectMultiplier):
 		if a.Status() != kind.AgentStatusDisconnected {
-			a.SetStatus(kind.AgentStatusDisconnected)
-
-			if err := r.store.UpsertAgent(ctx, a); err != nil {
-				r.logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("reconcile: upsert disconnected failed")
+			prev, err := r.agents.SetStatus(ctx, a.ID(), kind.AgentStatusDisconnected)
+			if err != nil {
+				r.logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("reconcile: set disconnected failed")
+				return
+			}
+			if prev == kind.AgentStatusDisconnected {
 				return
 			}
 			r.logger.Info().
@@ -167,10 +247,12 @@ func (r *Runner) reconcile(ctx context.Context, now time.Time, a *model.Agent) {
 
 	case silence > hb*time.Duration(r.cfg.InactiveMultiplier):
 		if a.Status() != kind.AgentStatusInactive {
-			a.SetStatus(kind.AgentStatusInactive)
-
-			if err := r.store.UpsertAgent(ctx, a); err != nil {
-				r.logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("reconcile: upsert inactive failed")
+			prev, err := r.agents.SetStatus(ctx, a.ID(), kind.AgentStatusInactive)
+			if err != nil {
+				r.logger.Warn().Err(err).Str("agent_id", a.ID()).Msg("reconcile: set inactive failed")
+				return
+			}
+			if prev == kind.AgentStatusInactive {
 				return
 			}
 			r.logger.Info().
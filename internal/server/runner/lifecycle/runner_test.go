@@ -0,0 +1,108 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/internal/auth"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/service/enrollment"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+func TestRunner_ReconcileKeepsEnrolledAgents(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx              = context.Background()
+		r, store, _      = newTestRunner(t, Config{})
+		enroll           = enrollment.New(enrollment.Config{}, store, zerolog.Nop())
+		enrolled, legacy = mkHTTPAgent(t, store, "enrolled", "http://enrolled:8080", false), mkHTTPAgent(t, store, "legacy", "http://legacy:8080", false)
+	)
+
+	token, err := enroll.Create(ctx, enrollment.CreateRequest{Name: "test"})
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	g, err := enroll.Authenticate(ctx, enrollment.SyncAuth{AgentID: enrolled.ID(), EnrollmentToken: token.Value})
+	if err != nil {
+		t.Fatalf("enroll: %v", err)
+	}
+	enrolled.SetCredentialHash(g.CredentialHash)
+	if err = store.UpsertAgent(ctx, enrolled); err != nil {
+		t.Fatalf("upsert agent: %v", err)
+	}
+	g.Done()
+
+	// Far beyond the delete threshold of the default heartbeat.
+	now := time.Now().Add(1000 * defaultHeartbeat)
+	for _, id := range []string{enrolled.ID(), legacy.ID()} {
+		a, err := store.GetAgent(ctx, id)
+		if err != nil {
+			t.Fatalf("get %s: %v", id, err)
+		}
+		r.reconcile(ctx, now, a)
+	}
+
+	if _, err = store.GetAgent(ctx, legacy.ID()); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("agent without credential: err = %v, want ErrNotFound", err)
+	}
+	got, err := store.GetAgent(ctx, enrolled.ID())
+	if err != nil {
+		t.Fatalf("enrolled agent deleted: %v", err)
+	}
+	if got.Status() != kind.AgentStatusDisconnected {
+		t.Fatalf("enrolled agent status = %v, want disconnected", got.Status())
+	}
+
+	// The agent returns after the outage with its credential.
+	if _, err = enroll.Authenticate(ctx, enrollment.SyncAuth{AgentID: enrolled.ID(), Credential: g.Credential}); err != nil {
+		t.Fatalf("returning agent refused: %v", err)
+	}
+	// Its ID cannot be taken over with another enrollment token.
+	other, err := enroll.Create(ctx, enrollment.CreateRequest{Name: "other"})
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	if _, err = enroll.Authenticate(ctx, enrollment.SyncAuth{AgentID: enrolled.ID(), EnrollmentToken: other.Value}); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Fatalf("re-enroll: err = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestRunner_ReconcileKeepsConcurrentUpdates(t *testing.T) {
+	t.Parallel()
+
+	var (
+		ctx           = context.Background()
+		r, store, hub = newTestRunner(t, Config{})
+		stale         = mkHTTPAgent(t, store, "a1", "http://a1:8080", false)
+	)
+
+	// The agent is cordoned after the tick listed it.
+	if _, err := r.agents.SetCordon(ctx, stale.ID(), true); err != nil {
+		t.Fatalf("cordon: %v", err)
+	}
+
+	now := time.Now().Add(time.Duration(defaultInactiveMultiplier+1) * defaultHeartbeat)
+	r.reconcile(ctx, now, stale)
+	r.reconcile(ctx, now, stale)
+
+	got, err := store.GetAgent(ctx, stale.ID())
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if got.Status() != kind.AgentStatusInactive {
+		t.Fatalf("status = %v, want inactive", got.Status())
+	}
+	if !got.Cordoned() {
+		t.Fatal("cordon lost by reconcile")
+	}
+	if n := countEvents(hub, event.AgentInactive, stale.ID()); n != 1 {
+		t.Fatalf("%d inactive events, want 1", n)
+	}
+}
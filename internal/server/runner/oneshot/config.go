@@ -0,0 +1,46 @@
+package oneshot
+
+import "time"
+
+const (
+	defaultTickInterval   = 5 * time.Second
+	defaultRequestTimeout = 10 * time.Second
+	defaultGrace          = 30 * time.Second
+
+	defaultName           = "oneshot"
+	defaultMaxConcurrency = 8
+)
+
+// Config configures the oneshot runner.
+type Config struct {
+	TickInterval time.Duration `yaml:"tick_interval"`
+	// RequestTimeout bounds a single submit or task list call to an agent.
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// Grace is how long past the task timeout a result may stay unfinished
+	// before it is marked timed out.
+	Grace time.Duration `yaml:"grace"`
+
+	// MaxConcurrency caps parallel agent calls within one run.
+	MaxConcurrency int `yaml:"max_concurrency"`
+
+	Name string `yaml:"name"`
+}
+
+func (c Config) withDefaults() Config {
+	if c.Name == "" {
+		c.Name = defaultName
+	}
+	if c.TickInterval <= 0 {
+		c.TickInterval = defaultTickInterval
+	}
+	if c.RequestTimeout <= 0 {
+		c.RequestTimeout = defaultRequestTimeout
+	}
+	if c.Grace <= 0 {
+		c.Grace = defaultGrace
+	}
+	if c.MaxConcurrency <= 0 {
+		c.MaxConcurrency = defaultMaxConcurrency
+	}
+	return c
+}
@@ -0,0 +1,8 @@
+package oneshot
+
+import "errors"
+
+var (
+	// ErrAlreadyStarted indicates Start was called more than once.
+	ErrAlreadyStarted = errors.New("oneshot: already started")
+)
@@ -0,0 +1,338 @@
+// Package oneshot implements a server.Runner that executes ad-hoc runs:
+//   - Lists unfinished runs across all pages
+//   - Submits the run task (restart "never") to every target not yet submitted
+//   - Polls each submitted agent for the task in the run slot until it reaches a terminal status
+//   - Times out results that stay unfinished past the task timeout plus a grace period
+//   - Records per-agent status and errors on the run; records an event once every target finished.
+package oneshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+
+	proxyv1 "github.com/soltiHQ/control-plane/api/proxy/v1"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/proxy"
+	"github.com/soltiHQ/control-plane/internal/server/runner/backlog"
+	"github.com/soltiHQ/control-plane/internal/service/secret"
+	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
+)
+
+// Runner is a server.Runner that periodically advances unfinished runs.
+//
+// On each tick, for every unfinished run and every target without a final result:
+//  1. Not submitted yet: renders the run task for the agent and calls "SubmitTask".
+//     A failed submission is final, as is an agent that lost its approval.
+//  2. Submitted: calls "ListTasks" for the run slot and copies the task status,
+//     attempt and error to the result; a terminal status is final.
+//  3. Past the deadline (task timeout + Grace since submission): marks the result timed out.
+//
+// Agent calls within a run are bounded by MaxConcurrency.
+type Runner struct {
+	pool *proxy.Pool
+	hub  *event.Hub
+
+	logger zerolog.Logger
+	store  storage.Storage
+	cfg    Config
+
+	stop    chan struct{}
+	started atomic.Bool
+}
+
+// New creates a oneshot runner.
+func New(cfg Config, logger zerolog.Logger, store storage.Storage, pool *proxy.Pool, hub *event.Hub) (*Runner, error) {
+	if store == nil {
+		return nil, fmt.Errorf("oneshot: %w", storage.ErrNilStore)
+	}
+	if pool == nil {
+		return nil, fmt.Errorf("oneshot: %w", proxy.ErrNilPool)
+	}
+	if hub == nil {
+		return nil, fmt.Errorf("oneshot: %w", event.ErrNilHub)
+	}
+
+	cfg = cfg.withDefaults()
+	return &Runner{
+		logger: logger.With().Str("runner", cfg.Name).Logger(),
+		stop:   make(chan struct{}),
+
+		store: store,
+		pool:  pool,
+		cfg:   cfg,
+		hub:   hub,
+	}, nil
+}
+
+// Name returns the runner name.
+func (r *Runner) Name() string { return r.cfg.Name }
+
+// Start runs the execution loop until Stop is called.
+func (r *Runner) Start(_ context.Context) error {
+	if !r.started.CompareAndSwap(false, true) {
+		return ErrAlreadyStarted
+	}
+
+	ticker := time.NewTicker(r.cfg.TickInterval)
+	defer ticker.Stop()
+
+	r.logger.Debug().
+		Dur("tick", r.cfg.TickInterval).
+		Dur("grace", r.cfg.Grace).
+		Int("max_concurrency", r.cfg.MaxConcurrency).
+		Msg("oneshot runner started")
+
+	for {
+		select {
+		case <-ticker.C:
+			r.tick()
+		case <-r.stop:
+			r.logger.Info().Msg("oneshot runner stopped")
+			return nil
+		}
+	}
+}
+
+// Stop signals the runner to exit. Safe to call multiple times.
+func (r *Runner) Stop(_ context.Context) error {
+	if !r.started.Load() {
+		return nil
+	}
+	select {
+	case <-r.stop:
+	default:
+		close(r.stop)
+	}
+	return nil
+}
+
+func (r *Runner) tick() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Abort in-flight agent calls as soon as the runner stops.
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	filter := inmemory.NewRunFilter().ByDone(false)
+	runs, err := backlog.Collect(ctx, func(ctx context.Context, opts storage.ListOptions) (*storage.ListResult[*model.Run], error) {
+		return r.store.ListRuns(ctx, filter, opts)
+	})
+	if err != nil {
+		r.logger.Error().Err(err).Msg("tick: list runs failed")
+		return
+	}
+
+	for _, run := range runs {
+		if ctx.Err() != nil {
+			return
+		}
+		r.advance(ctx, run)
+	}
+}
+
+// advance steps every unfinished result of run once and stores the changes.
+func (r *Runner) advance(ctx context.Context, run *model.Run) {
+	var (
+		results = run.Results()
+		next    = make([]model.RunResult, len(results))
+		g, gctx = errgroup.WithContext(ctx)
+	)
+	g.SetLimit(r.cfg.MaxConcurrency)
+	for i, res := range results {
+		if res.Done() {
+			next[i] = res
+			continue
+		}
+		g.Go(func() error {
+			next[i] = r.step(gctx, run, res)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	changed := false
+	for i := range next {
+		if next[i] != results[i] {
+			run.SetResult(next[i])
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	// The run may have been deleted while agents were polled.
+	if _, err := r.store.GetRun(ctx, run.ID()); err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			r.logger.Error().Err(err).Str("run_id", run.ID()).Msg("advance: get run failed")
+		}
+		return
+	}
+	if err := r.store.UpsertRun(ctx, run); err != nil {
+		r.logger.Error().Err(err).Str("run_id", run.ID()).Msg("advance: upsert run failed")
+		return
+	}
+	r.hub.Notify(htmx.RunUpdate)
+
+	if run.Done() {
+		r.finish(run)
+	}
+}
+
+// step advances a single result by one submission or poll.
+func (r *Runner) step(ctx context.Context, run *model.Run, res model.RunResult) model.RunResult {
+	var (
+		now   = time.Now()
+		since = run.CreatedAt()
+	)
+	if res.Submitted() {
+		since = res.SubmittedAt
+	}
+	deadline := since.Add(time.Duration(run.TimeoutMs())*time.Millisecond + r.cfg.Grace)
+
+	ag, err := r.store.GetAgent(ctx, res.AgentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return finished(res, kind.TaskStatusUnknown, "agent not found", now)
+		}
+		r.logger.Warn().Err(err).Str("run_id", run.ID()).Str("agent_id", res.AgentID).Msg("step: get agent failed")
+		return res
+	}
+
+	if ag.PullMode() && !r.pool.Connected(ag.ID()) {
+		return finished(res, kind.TaskStatusFailed, "agent is in pull mode and has no open session", now)
+	}
+	ap, err := r.pool.Get(ag.ID(), ag.Endpoint(), ag.EndpointType(), ag.APIVersion())
+	if err != nil {
+		return finished(res, kind.TaskStatusFailed, "proxy error: "+err.Error(), now)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, r.cfg.RequestTimeout)
+	defer cancel()
+
+	if !res.Submitted() {
+		if err = ag.CheckApproval(); err != nil {
+			return finished(res, kind.TaskStatusFailed, err.Error(), now)
+		}
+		payload, err := run.RenderCreateSpec(ag, secret.Resolver(ctx, r.store))
+		if err != nil {
+			return finished(res, kind.TaskStatusFailed, "template error: "+err.Error(), now)
+		}
+		if err = ap.SubmitTask(callCtx, proxy.TaskSubmission{Spec: payload}); err != nil {
+			r.logger.Warn().Err(err).
+				Str("run_id", run.ID()).
+				Str("agent_id", res.AgentID).
+				Msg("step: submit task failed")
+			return finished(res, kind.TaskStatusFailed, "submit error: "+err.Error(), now)
+		}
+
+		r.logger.Debug().Str("run_id", run.ID()).Str("agent_id", res.AgentID).Msg("run task submitted")
+		res.SubmittedAt = now
+		return res
+	}
+
+	list, err := ap.ListTasks(callCtx, proxy.TaskFilter{Slot: run.Slot()})
+	if err != nil {
+		r.logger.Warn().Err(err).
+			Str("run_id", run.ID()).
+			Str("agent_id", res.AgentID).
+			Msg("step: list tasks failed")
+		if now.After(deadline) {
+			return finished(res, kind.TaskStatusUnknown, "agent unreachable: "+err.Error(), now)
+		}
+		return res
+	}
+
+	var task *proxyv1.Task
+	for i := range list.Tasks {
+		t := &list.Tasks[i]
+		if t.Slot != run.Slot() {
+			continue
+		}
+		if task == nil || t.CreatedAt > task.CreatedAt {
+			task = t
+		}
+	}
+	if task != nil {
+		res.TaskID = task.ID
+		res.Attempt = task.Attempt
+		res.Error = task.Error
+		res.Status = kind.ParseTaskStatus(task.Status)
+		if res.Status.Terminal() {
+			res.FinishedAt = now
+			return res
+		}
+	}
+	if now.After(deadline) {
+		msg := "no result before deadline"
+		if task == nil {
+			msg = "task not reported by agent"
+		}
+		return finished(res, kind.TaskStatusTimeout, msg, now)
+	}
+	return res
+}
+
+// finish records the outcome of a run whose targets are all done.
+func (r *Runner) finish(run *model.Run) {
+	var (
+		counts = make(map[kind.TaskStatus]int)
+		failed int
+	)
+	for _, res := range run.Results() {
+		counts[res.Status]++
+		if res.Status != kind.TaskStatusSucceeded {
+			failed++
+		}
+	}
+
+	parts := make([]string, 0, len(counts))
+	for s := kind.TaskStatusRunning; s <= kind.TaskStatusUnknown; s++ {
+		if n := counts[s]; n > 0 {
+			parts = append(parts, strconv.Itoa(n)+" "+s.String())
+		}
+	}
+	detail := strings.Join(parts, ", ")
+
+	kindName := event.RunFinished
+	if failed > 0 {
+		kindName = event.RunFailed
+	}
+	r.hub.Record(kindName, event.Payload{ID: run.ID(), Name: run.Name(), Detail: detail, By: run.CreatedBy()})
+	r.hub.Notify(htmx.DashboardUpdate)
+
+	r.logger.Info().
+		Str("run_id", run.ID()).
+		Int("targets", len(run.Results())).
+		Int("failed", failed).
+		Msg("run finished")
+}
+
+// finished returns res marked final with status and errMsg.
+func finished(res model.RunResult, status kind.TaskStatus, errMsg string, at time.Time) model.RunResult {
+	res.Status = status
+	res.Error = errMsg
+	res.FinishedAt = at
+	return res
+}
@@ -9,6 +9,10 @@ const (
 	defaultName           = "sync"
 	defaultMaxRetries     = 5
 	defaultMaxConcurrency = 4
+	defaultMaxPerTick     = 1000
+
+	defaultAgentRate  = 2.0
+	defaultGlobalRate = 20.0
 )
 
 // Config configures the sync runner.
@@ -18,6 +22,14 @@ type Config struct {
 
 	MaxConcurrency int `yaml:"max_concurrency"`
 	MaxRetries     int `yaml:"max_retries"`
+	// MaxPerTick bounds how many rollouts one tick pushes; the rest are picked up
+	// round-robin by the following ticks.
+	MaxPerTick int `yaml:"max_per_tick"`
+
+	// AgentRate caps pushes per second to a single agent.
+	AgentRate float64 `yaml:"agent_rate"`
+	// GlobalRate caps pushes per second across all agents.
+	GlobalRate float64 `yaml:"global_rate"`
 
 	Name string `yaml:"name"`
 }
@@ -38,5 +50,14 @@ func (c Config) withDefaults() Config {
 	if c.MaxConcurrency <= 0 {
 		c.MaxConcurrency = defaultMaxConcurrency
 	}
+	if c.MaxPerTick <= 0 {
+		c.MaxPerTick = defaultMaxPerTick
+	}
+	if c.AgentRate <= 0 {
+		c.AgentRate = defaultAgentRate
+	}
+	if c.GlobalRate <= 0 {
+		c.GlobalRate = defaultGlobalRate
+	}
 	return c
 }
@@ -0,0 +1,86 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPacer_SpacesEvents(t *testing.T) {
+	t.Parallel()
+
+	const (
+		rate     = 20.0 // one event every 50ms
+		interval = 50 * time.Millisecond
+		events   = 4
+	)
+	p := newPacer(rate)
+
+	start := time.Now()
+	for range events {
+		if err := p.wait(context.Background()); err != nil {
+			t.Fatalf("wait: %v", err)
+		}
+	}
+	// The first event is admitted at once, every later one an interval after the previous.
+	if elapsed, want := time.Since(start), (events-1)*interval; elapsed < want {
+		t.Fatalf("%d events took %v, want at least %v", events, elapsed, want)
+	}
+	if p.idle(time.Now()) {
+		t.Fatal("pacer idle right after an event, want the next slot reserved")
+	}
+	if !p.idle(time.Now().Add(interval)) {
+		t.Fatal("pacer not idle one interval after the last event")
+	}
+}
+
+func TestPacer_WaitCanceled(t *testing.T) {
+	t.Parallel()
+
+	p := newPacer(0.1) // one event every 10s
+	if err := p.wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := p.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRunner_AgentPacers(t *testing.T) {
+	t.Parallel()
+
+	r := &Runner{cfg: Config{AgentRate: 0.1}.withDefaults(), agents: make(map[string]*pacer)}
+	if r.agentPacer("a1") != r.agentPacer("a1") {
+		t.Fatal("agentPacer returned a new pacer for the same agent")
+	}
+
+	// Agents are paced independently: a slot reserved on a1 does not delay a2.
+	ctx := context.Background()
+	if err := r.agentPacer("a1").wait(ctx); err != nil {
+		t.Fatalf("wait a1: %v", err)
+	}
+	start := time.Now()
+	if err := r.agentPacer("a2").wait(ctx); err != nil {
+		t.Fatalf("wait a2: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("a2 waited %v for a slot reserved by a1", elapsed)
+	}
+
+	r.agentPacer("a3") // never used, so idle
+	r.prunePacers(time.Now())
+	if _, ok := r.agents["a3"]; ok {
+		t.Fatal("idle pacer of a3 not pruned")
+	}
+	if len(r.agents) != 2 {
+		t.Fatalf("pruned pacers with reserved slots, %d left", len(r.agents))
+	}
+	r.prunePacers(time.Now().Add(time.Minute))
+	if len(r.agents) != 0 {
+		t.Fatalf("%d pacers left after their slots passed", len(r.agents))
+	}
+}
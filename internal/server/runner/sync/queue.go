@@ -0,0 +1,143 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+// queued is a rollout with the spec attributes that decide its push order.
+type queued struct {
+	rollout   *model.Rollout
+	dependsOn []string
+	priority  int
+	depth     int
+}
+
+// agentQueue is the ordered list of rollouts to push to one agent.
+type agentQueue struct {
+	agentID  string
+	priority int
+	items    []queued
+}
+
+// specOrder holds the ordering attributes of a spec.
+type specOrder struct {
+	dependsOn []string
+	priority  int
+	depth     int // length of the longest dependency chain below the spec
+}
+
+// queues groups actionable rollouts by agent.
+//
+// Within a queue rollouts are ordered by dependency depth (dependencies first),
+// then spec priority (highest first), then spec ID for a stable order. Queues
+// themselves are ordered by their highest priority so that urgent agents take
+// the first concurrency slots.
+// Rollouts whose spec cannot be resolved get priority 0; push reports the error.
+// Items must already be filtered to actionable rollouts.
+func (r *Runner) queues(ctx context.Context, items []*model.Rollout) []*agentQueue {
+	var (
+		specs   = make(map[string]*specOrder)
+		byAgent = make(map[string]*agentQueue)
+		out     []*agentQueue
+	)
+
+	for _, ss := range items {
+		so := r.specOrder(ctx, specs, ss.SpecID())
+
+		q, ok := byAgent[ss.AgentID()]
+		if !ok {
+			q = &agentQueue{agentID: ss.AgentID(), priority: so.priority}
+			byAgent[ss.AgentID()] = q
+			out = append(out, q)
+		}
+		q.items = append(q.items, queued{
+			rollout:   ss,
+			dependsOn: so.dependsOn,
+			priority:  so.priority,
+			depth:     so.depth,
+		})
+		if so.priority > q.priority {
+			q.priority = so.priority
+		}
+	}
+
+	for _, q := range out {
+		sort.SliceStable(q.items, func(i, j int) bool {
+			a, b := q.items[i], q.items[j]
+			if a.depth != b.depth {
+				return a.depth < b.depth
+			}
+			if a.priority != b.priority {
+				return a.priority > b.priority
+			}
+			return a.rollout.SpecID() < b.rollout.SpecID()
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].priority != out[j].priority {
+			return out[i].priority > out[j].priority
+		}
+		return out[i].agentID < out[j].agentID
+	})
+	return out
+}
+
+// specOrder resolves and caches the ordering attributes of a spec.
+//
+// Depth is computed over the dependency graph; cycles are rejected when specs are
+// saved, but one is still cut at the revisited spec rather than recursing forever.
+func (r *Runner) specOrder(ctx context.Context, cache map[string]*specOrder, specID string) *specOrder {
+	if so, ok := cache[specID]; ok {
+		return so
+	}
+	so := &specOrder{}
+	cache[specID] = so
+
+	ts, err := r.store.GetSpec(ctx, specID)
+	if err != nil {
+		return so
+	}
+	so.priority = ts.Priority()
+	so.dependsOn = ts.DependsOn()
+
+	for _, dep := range so.dependsOn {
+		if d := r.specOrder(ctx, cache, dep).depth + 1; d > so.depth {
+			so.depth = d
+		}
+	}
+	return so
+}
+
+// blockedBy returns the first dependency that holds back a rollout on agentID.
+//
+// A dependency whose rollout on the agent is not synced yet only delays the push, and
+// reason is empty. One that can never sync there (it has no rollout on the agent, the
+// rollout is excluded, or it ran out of retries) blocks it for good; reason says why
+// and the caller fails the rollout instead of letting it wait forever.
+func (r *Runner) blockedBy(ctx context.Context, agentID string, dependsOn []string) (dep, reason string, blocked bool) {
+	for _, dep = range dependsOn {
+		ss, err := r.store.GetRollout(ctx, model.RolloutID(dep, agentID))
+		switch {
+		case errors.Is(err, storage.ErrNotFound):
+			return dep, "dependency " + dep + " is not deployed to the agent", true
+		case err != nil:
+			return dep, "", true
+		}
+		switch {
+		case ss.Status() == kind.SyncStatusSynced:
+			continue
+		case ss.Status() == kind.SyncStatusExcluded:
+			return dep, "dependency " + dep + " is excluded on the agent: " + ss.Error(), true
+		case ss.Status() == kind.SyncStatusFailed && ss.Attempts() >= r.cfg.MaxRetries:
+			return dep, "dependency " + dep + " failed on the agent: " + ss.Error(), true
+		}
+		return dep, "", true
+	}
+	return "", "", false
+}
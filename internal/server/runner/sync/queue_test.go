@@ -0,0 +1,143 @@
+package sync
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+func TestRunner_BlockedBy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := inmemory.New()
+	r := &Runner{store: store, cfg: Config{MaxRetries: 2}.withDefaults(), logger: zerolog.Nop()}
+
+	rollout := func(specID string, mark func(*model.Rollout)) {
+		ro, err := model.NewRollout(specID, "a1", 1)
+		if err != nil {
+			t.Fatalf("new rollout: %v", err)
+		}
+		if mark != nil {
+			mark(ro)
+		}
+		if err = store.UpsertRollout(ctx, ro); err != nil {
+			t.Fatalf("upsert rollout: %v", err)
+		}
+	}
+	rollout("synced", func(ro *model.Rollout) { ro.MarkSynced(1) })
+	rollout("pending", nil)
+	rollout("retrying", func(ro *model.Rollout) { ro.MarkFailed("boom") })
+	rollout("exhausted", func(ro *model.Rollout) { ro.MarkFailed("boom"); ro.MarkFailed("boom") })
+	rollout("excluded", func(ro *model.Rollout) { ro.MarkExcluded("os mismatch") })
+
+	tests := []struct {
+		name      string
+		dependsOn []string
+		dep       string
+		reason    string // substring; empty means the rollout only waits
+		blocked   bool
+	}{
+		{name: "no dependencies"},
+		{name: "all synced", dependsOn: []string{"synced"}},
+		{name: "pending dependency waits", dependsOn: []string{"synced", "pending"}, dep: "pending", blocked: true},
+		{name: "retrying dependency waits", dependsOn: []string{"retrying"}, dep: "retrying", blocked: true},
+		{name: "missing dependency fails", dependsOn: []string{"synced", "gone"}, dep: "gone", reason: "not deployed", blocked: true},
+		{name: "excluded dependency fails", dependsOn: []string{"excluded"}, dep: "excluded", reason: "os mismatch", blocked: true},
+		{name: "exhausted dependency fails", dependsOn: []string{"exhausted"}, dep: "exhausted", reason: "failed on the agent", blocked: true},
+		{name: "first blocking dependency wins", dependsOn: []string{"pending", "gone"}, dep: "pending", blocked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dep, reason, blocked := r.blockedBy(ctx, "a1", tt.dependsOn)
+			if blocked != tt.blocked || dep != tt.dep {
+				t.Fatalf("blockedBy = (%q, %v), want (%q, %v)", dep, blocked, tt.dep, tt.blocked)
+			}
+			if (tt.reason == "") != (reason == "") || !strings.Contains(reason, tt.reason) {
+				t.Fatalf("reason = %q, want containing %q", reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestRunner_Queues(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := inmemory.New()
+	r := &Runner{store: store, cfg: Config{}.withDefaults(), logger: zerolog.Nop()}
+
+	spec := func(id string, priority int, deps ...string) {
+		ts, err := model.NewSpec(id, id, id)
+		if err != nil {
+			t.Fatalf("new spec: %v", err)
+		}
+		ts.SetPriority(priority)
+		if len(deps) > 0 {
+			ts.SetDependsOn(deps)
+		}
+		if err = store.UpsertSpec(ctx, ts); err != nil {
+			t.Fatalf("upsert spec: %v", err)
+		}
+	}
+	spec("base", 0)
+	spec("app", 100, "base") // depth 1: after base despite its priority
+	spec("hi", 50)
+	spec("lo", 1)
+	spec("b-tie", 5)
+	spec("a-tie", 5)
+
+	var items []*model.Rollout
+	rollouts := func(agentID string, specIDs ...string) {
+		for _, id := range specIDs {
+			ro, err := model.NewRollout(id, agentID, 1)
+			if err != nil {
+				t.Fatalf("new rollout: %v", err)
+			}
+			items = append(items, ro)
+		}
+	}
+	rollouts("a2", "lo")
+	rollouts("a1", "app", "base", "lo", "gone", "b-tie", "hi", "a-tie")
+	rollouts("a0", "lo")
+	rollouts("a3", "base")
+	rollouts("a4", "hi")
+
+	type queue struct {
+		agentID  string
+		priority int
+		specs    string
+	}
+	want := []queue{
+		// Agents by their highest priority, then ID.
+		{agentID: "a1", priority: 100, specs: "hi,a-tie,b-tie,lo,base,gone,app"},
+		{agentID: "a4", priority: 50, specs: "hi"},
+		{agentID: "a0", priority: 1, specs: "lo"},
+		{agentID: "a2", priority: 1, specs: "lo"},
+		{agentID: "a3", priority: 0, specs: "base"},
+	}
+
+	qs := r.queues(ctx, items)
+	got := make([]queue, 0, len(qs))
+	for _, q := range qs {
+		ids := make([]string, 0, len(q.items))
+		for _, it := range q.items {
+			ids = append(ids, it.rollout.SpecID())
+		}
+		got = append(got, queue{agentID: q.agentID, priority: q.priority, specs: strings.Join(ids, ",")})
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("queues =\n  %+v\nwant\n  %+v", got, want)
+	}
+	for _, it := range qs[0].items {
+		if it.rollout.SpecID() == "app" && (it.depth != 1 || !slices.Equal(it.dependsOn, []string{"base"})) {
+			t.Fatalf("app: depth = %d, dependsOn = %v; want 1, [base]", it.depth, it.dependsOn)
+		}
+	}
+}
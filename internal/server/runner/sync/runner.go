@@ -1,7 +1,15 @@
 // Package sync implements a server.Runner that reconciles pending rollouts
 // by pushing specs to agents via the proxy pool:
-//   - Lists actionable rollouts (pending, drift, failed under max retries)
-//   - Resolves spec and agent, gets a proxy, calls SubmitTask
+//   - Lists actionable rollouts (pending, drift, failed under max retries) across all pages,
+//     leaving out agents in pull mode, which receive their specs in the discovery response
+//   - Takes a bounded window of them, continuing round-robin from the previous tick
+//   - Groups them into per-agent queues ordered by dependencies, then spec priority
+//   - Holds back rollouts whose dependency is not yet synced on the same agent, and fails
+//     those whose dependency is not deployed there, excluded, or out of retries
+//   - Drains each queue serially, paced by per-agent and global rate limits
+//   - Resolves spec and agent, renders the spec for the agent, gets a proxy, calls SubmitTask
+//   - Leaves rollouts pending while the agent awaits approval or its last probe found it unreachable
+//   - Marks rollout excluded when the agent was rejected or no longer reports support for the spec's task kind
 //   - Marks rollout synced on success, failed (with attempt increment) on error.
 package sync
 
@@ -15,8 +23,12 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
 	"github.com/soltiHQ/control-plane/internal/event"
 	"github.com/soltiHQ/control-plane/internal/proxy"
+	"github.com/soltiHQ/control-plane/internal/server"
+	"github.com/soltiHQ/control-plane/internal/server/runner/backlog"
+	"github.com/soltiHQ/control-plane/internal/service/secret"
 	"github.com/soltiHQ/control-plane/internal/storage"
 	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
 	"github.com/soltiHQ/control-plane/internal/uikit/htmx"
@@ -26,15 +38,30 @@ import (
 // records by pushing Specs to agents via the proxy pool.
 //
 // On each tick it:
-//  1. Lists all rollouts with status pending, drift, or failed (under max retries).
-//  2. For each, resolves the Spec and agent.
-//  3. Gets an AgentProxy from the pool and calls "SubmitTask".
-//  4. On success: marks the rollout as synced.
-//  5. On failure: marks the rollout as failed (increment attempts).
+//  1. Lists all rollouts with status pending, drift, or failed (under max retries),
+//     except those of pull-mode agents.
+//  2. Takes up to MaxPerTick of them, resuming after the previous tick's window.
+//  3. Groups them per agent, dependencies first, then highest spec priority.
+//     A rollout is skipped until every spec it depends on is synced on that agent,
+//     and failed when a dependency can never sync there.
+//  4. Drains up to MaxConcurrency agent queues in parallel; pushes to a single
+//     agent are serialized and paced by AgentRate, all pushes by GlobalRate.
+//  5. For each, resolves the Spec and agent and renders the Spec templates for the agent.
+//     Agents that cannot run the Spec (see model.Spec.CheckPlacement) get the rollout excluded.
+//  6. Gets an AgentProxy from the pool and calls "SubmitTask".
+//  7. On success: marks the rollout as synced.
+//  8. On failure: marks the rollout as failed (increment attempts).
 type Runner struct {
 	pool *proxy.Pool
 	hub  *event.Hub
 
+	global *pacer
+	agents map[string]*pacer
+
+	cursor  backlog.Cursor
+	backlog atomic.Int64
+	ranAt   atomic.Int64 // unix nanoseconds of the last tick
+
 	logger zerolog.Logger
 	store  storage.Storage
 	cfg    Config
@@ -60,6 +87,9 @@ func New(cfg Config, logger zerolog.Logger, store storage.Storage, pool *proxy.P
 		logger: logger.With().Str("runner", cfg.Name).Logger(),
 		stop:   make(chan struct{}),
 
+		global: newPacer(cfg.GlobalRate),
+		agents: make(map[string]*pacer),
+
 		store: store,
 		pool:  pool,
 		cfg:   cfg,
@@ -70,6 +100,15 @@ func New(cfg Config, logger zerolog.Logger, store storage.Storage, pool *proxy.P
 // Name returns the runner name.
 func (r *Runner) Name() string { return r.cfg.Name }
 
+// Status reports when the last tick ran and how many actionable rollouts it saw.
+func (r *Runner) Status() server.Status {
+	var ranAt time.Time
+	if ns := r.ranAt.Load(); ns != 0 {
+		ranAt = time.Unix(0, ns)
+	}
+	return server.Status{Name: r.cfg.Name, RanAt: ranAt, Backlog: int(r.backlog.Load())}
+}
+
 // Start runs the sync reconciliation loop until Stop is called.
 func (r *Runner) Start(_ context.Context) error {
 	if !r.started.CompareAndSwap(false, true) {
@@ -83,6 +122,9 @@ func (r *Runner) Start(_ context.Context) error {
 		Dur("tick", r.cfg.TickInterval).
 		Int("max_retries", r.cfg.MaxRetries).
 		Int("max_concurrency", r.cfg.MaxConcurrency).
+		Float64("agent_rate", r.cfg.AgentRate).
+		Float64("global_rate", r.cfg.GlobalRate).
+		Int("max_per_tick", r.cfg.MaxPerTick).
 		Msg("sync runner started")
 
 	for {
@@ -110,37 +152,105 @@ func (r *Runner) Stop(_ context.Context) error {
 }
 
 func (r *Runner) tick() {
-	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Abort paced waits and in-flight pushes as soon as the runner stops.
+	go func() {
+		select {
+		case <-r.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
 	filter := inmemory.NewRolloutFilter().ByStatuses(
 		kind.SyncStatusPending,
 		kind.SyncStatusDrift,
 		kind.SyncStatusFailed,
 	)
-	res, err := r.store.ListRollouts(ctx, filter, storage.ListOptions{
-		Limit: storage.MaxListLimit,
+	all, err := backlog.Collect(ctx, func(ctx context.Context, opts storage.ListOptions) (*storage.ListResult[*model.Rollout], error) {
+		return r.store.ListRollouts(ctx, filter, opts)
 	})
 	if err != nil {
 		r.logger.Error().Err(err).Msg("tick: list rollouts failed")
 		return
 	}
 
-	var g errgroup.Group
-	g.SetLimit(r.cfg.MaxConcurrency)
-
-	for _, ss := range res.Items {
+	var (
+		actionable = all[:0]
+		pull       = make(map[string]bool)
+	)
+	for _, ss := range all {
 		if ss == nil {
 			continue
 		}
 		if ss.Status() == kind.SyncStatusFailed && ss.Attempts() >= r.cfg.MaxRetries {
 			continue
 		}
+		if r.pullMode(ctx, pull, ss.AgentID()) {
+			continue
+		}
+		actionable = append(actionable, ss)
+	}
+	r.backlog.Store(int64(len(actionable)))
+	r.ranAt.Store(time.Now().UnixNano())
+
+	window := backlog.Next(&r.cursor, actionable, r.cfg.MaxPerTick)
+	if len(window) < len(actionable) {
+		r.logger.Warn().
+			Int("backlog", len(actionable)).
+			Int("window", len(window)).
+			Msg("tick: backlog exceeds per-tick budget")
+	} else if len(window) > 0 {
+		r.logger.Debug().Int("backlog", len(actionable)).Msg("tick: pushing rollouts")
+	}
 
-		g.Go(func() error {
-			pushCtx, cancel := context.WithTimeout(ctx, r.cfg.PushTimeout)
-			defer cancel()
+	queues := r.queues(ctx, window)
+	r.prunePacers(time.Now())
 
-			r.push(pushCtx, ss.ID(), ss.SpecID(), ss.AgentID())
+	var g errgroup.Group
+	g.SetLimit(r.cfg.MaxConcurrency)
+
+	for _, q := range queues {
+		ap := r.agentPacer(q.agentID)
+
+		g.Go(func() error {
+			for _, it := range q.items {
+				ss := it.rollout
+				if dep, reason, ok := r.blockedBy(ctx, ss.AgentID(), it.dependsOn); ok {
+					if reason != "" {
+						r.logger.Warn().
+							Str("rid", ss.ID()).
+							Str("spec_id", ss.SpecID()).
+							Str("agent_id", ss.AgentID()).
+							Str("depends_on", dep).
+							Str("reason", reason).
+							Msg("push: dependency cannot sync")
+
+						r.markFailed(ctx, ss.ID(), "dependency error: "+reason)
+						r.hub.Record(event.SyncFailed, event.Payload{ID: ss.SpecID(), Detail: ss.AgentID(), By: "sync"})
+						continue
+					}
+					r.logger.Debug().
+						Str("rid", ss.ID()).
+						Str("spec_id", ss.SpecID()).
+						Str("agent_id", ss.AgentID()).
+						Str("depends_on", dep).
+						Msg("push: waiting for dependency")
+					continue
+				}
+				if err := ap.wait(ctx); err != nil {
+					return nil
+				}
+				if err := r.global.wait(ctx); err != nil {
+					return nil
+				}
+
+				pushCtx, cancel := context.WithTimeout(ctx, r.cfg.PushTimeout)
+				r.push(pushCtx, ss.ID(), ss.SpecID(), ss.AgentID())
+				cancel()
+			}
 			return nil
 		})
 	}
@@ -149,6 +259,38 @@ func (r *Runner) tick() {
 	}
 }
 
+// pullMode reports whether agentID syncs in pull mode; such agents fetch their specs
+// in the discovery response and are never pushed to. Results are cached per tick.
+func (r *Runner) pullMode(ctx context.Context, cache map[string]bool, agentID string) bool {
+	pull, ok := cache[agentID]
+	if !ok {
+		ag, err := r.store.GetAgent(ctx, agentID)
+		pull = err == nil && ag.PullMode()
+		cache[agentID] = pull
+	}
+	return pull
+}
+
+// agentPacer returns the pacer for agentID, creating it on first use.
+// Pacers outlive a single tick so the per-agent rate also holds across ticks.
+func (r *Runner) agentPacer(agentID string) *pacer {
+	p, ok := r.agents[agentID]
+	if !ok {
+		p = newPacer(r.cfg.AgentRate)
+		r.agents[agentID] = p
+	}
+	return p
+}
+
+// prunePacers drops per-agent pacers with no reserved slot left.
+func (r *Runner) prunePacers(now time.Time) {
+	for id, p := range r.agents {
+		if p.idle(now) {
+			delete(r.agents, id)
+		}
+	}
+}
+
 func (r *Runner) push(ctx context.Context, rID, specID, agentID string) {
 	ts, err := r.store.GetSpec(ctx, specID)
 	if err != nil {
@@ -174,7 +316,38 @@ func (r *Runner) push(ctx context.Context, rID, specID, agentID string) {
 		return
 	}
 
-	ap, err := r.pool.Get(ag.Endpoint(), ag.EndpointType(), ag.APIVersion())
+	if ag.Approval() == kind.AgentApprovalPending {
+		r.logger.Debug().
+			Str("rid", rID).
+			Str("agent_id", agentID).
+			Msg("push: agent pending approval")
+		return
+	}
+	if ag.Reachability() == kind.AgentReachabilityUnreachable {
+		r.logger.Debug().
+			Str("rid", rID).
+			Str("agent_id", agentID).
+			Str("reason", ag.ProbeError()).
+			Msg("push: agent unreachable")
+		return
+	}
+
+	if err = ag.CheckApproval(); err == nil {
+		err = ts.CheckPlacement(ag)
+	}
+	if err != nil {
+		r.logger.Info().
+			Str("rid", rID).
+			Str("spec_id", specID).
+			Str("agent_id", agentID).
+			Str("reason", err.Error()).
+			Msg("push: agent excluded")
+
+		r.markExcluded(ctx, rID, err.Error())
+		return
+	}
+
+	ap, err := r.pool.Get(ag.ID(), ag.Endpoint(), ag.EndpointType(), ag.APIVersion())
 	if err != nil {
 		r.logger.Warn().Err(err).
 			Str("rid", rID).
@@ -187,14 +360,27 @@ func (r *Runner) push(ctx context.Context, rID, specID, agentID string) {
 		return
 	}
 
-	err = ap.SubmitTask(ctx, proxy.TaskSubmission{Spec: ts.ToCreateSpec()})
+	payload, err := ts.RenderCreateSpec(ag, secret.Resolver(ctx, r.store))
+	if err != nil {
+		r.logger.Warn().Err(err).
+			Str("rid", rID).
+			Str("spec_id", specID).
+			Str("agent_id", agentID).
+			Msg("push: render spec failed")
+
+		r.markFailed(ctx, rID, "template error: "+err.Error())
+		r.hub.Record(event.SyncFailed, event.Payload{ID: specID, Name: ts.Name(), Detail: agentID, By: "sync"})
+		return
+	}
+
+	err = ap.SubmitTask(ctx, proxy.TaskSubmission{Spec: payload})
 	if err != nil {
 		r.logger.Warn().Err(err).
 			Str("rid", rID).
 			Str("spec_id", specID).
 			Str("agent_id", agentID).
 			Msg("push: submit task failed")
-		
+
 		r.markFailed(ctx, rID, "submit error: "+err.Error())
 		r.hub.Record(event.SyncFailed, event.Payload{ID: specID, Name: ts.Name(), Detail: agentID, By: "sync"})
 		return
@@ -223,6 +409,21 @@ func (r *Runner) markSynced(ctx context.Context, rID string, version int) {
 	r.hub.Notify(htmx.SpecUpdate)
 }
 
+func (r *Runner) markExcluded(ctx context.Context, rID, reason string) {
+	ss, err := r.store.GetRollout(ctx, rID)
+	if err != nil {
+		r.logger.Error().Err(err).Str("rid", rID).Msg("markExcluded: get failed")
+		return
+	}
+
+	ss.MarkExcluded(reason)
+	if err = r.store.UpsertRollout(ctx, ss); err != nil {
+		r.logger.Error().Err(err).Str("rid", rID).Msg("markExcluded: upsert failed")
+		return
+	}
+	r.hub.Notify(htmx.SpecUpdate)
+}
+
 func (r *Runner) markFailed(ctx context.Context, rID, errMsg string) {
 	ss, err := r.store.GetRollout(ctx, rID)
 	if err != nil {
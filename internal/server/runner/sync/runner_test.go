@@ -0,0 +1,79 @@
+package sync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/event"
+	"github.com/soltiHQ/control-plane/internal/proxy"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+func TestRunner_PushDefersUnreachableAgent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := inmemory.New()
+	pool := proxy.NewPool(nil)
+	hub := event.NewHub(zerolog.Nop())
+	t.Cleanup(func() {
+		_ = pool.Close()
+		hub.Close()
+	})
+	r, err := New(Config{PushTimeout: time.Second}, zerolog.Nop(), store, pool, hub)
+	if err != nil {
+		t.Fatalf("new runner: %v", err)
+	}
+
+	ts, err := model.NewSpec("s1", "s1", "s1")
+	if err != nil {
+		t.Fatalf("new spec: %v", err)
+	}
+	if err = store.UpsertSpec(ctx, ts); err != nil {
+		t.Fatalf("upsert spec: %v", err)
+	}
+
+	for _, tt := range []struct {
+		id           string
+		reachability kind.AgentReachability
+		wantStatus   kind.SyncStatus
+		wantAttempts int
+	}{
+		{id: "down", reachability: kind.AgentReachabilityUnreachable, wantStatus: kind.SyncStatusPending},
+		{id: "unprobed", reachability: kind.AgentReachabilityUnknown, wantStatus: kind.SyncStatusFailed, wantAttempts: 1},
+	} {
+		t.Run(tt.id, func(t *testing.T) {
+			// Nothing listens on the endpoint, so a push attempt fails.
+			a, err := model.NewAgentFrom(model.AgentParams{ID: tt.id, Endpoint: "http://127.0.0.1:1", EndpointType: 1, APIVersion: 1})
+			if err != nil {
+				t.Fatalf("new agent: %v", err)
+			}
+			a.SetReachability(tt.reachability, "connection refused", time.Now())
+			if err = store.UpsertAgent(ctx, a); err != nil {
+				t.Fatalf("upsert agent: %v", err)
+			}
+			ro, err := model.NewRollout("s1", tt.id, ts.Version())
+			if err != nil {
+				t.Fatalf("new rollout: %v", err)
+			}
+			if err = store.UpsertRollout(ctx, ro); err != nil {
+				t.Fatalf("upsert rollout: %v", err)
+			}
+
+			r.push(ctx, ro.ID(), "s1", tt.id)
+
+			got, err := store.GetRollout(ctx, ro.ID())
+			if err != nil {
+				t.Fatalf("get rollout: %v", err)
+			}
+			if got.Status() != tt.wantStatus || got.Attempts() != tt.wantAttempts {
+				t.Fatalf("rollout = %s with %d attempts, want %s with %d", got.Status(), got.Attempts(), tt.wantStatus, tt.wantAttempts)
+			}
+		})
+	}
+}
@@ -1,5 +1,6 @@
 // Package access implements authentication use-cases:
 //   - Login with rate-limiting
+//   - Token refresh for API clients
 //   - Logout (session revocation)
 //   - Permission/role listing.
 package access
@@ -72,6 +73,25 @@ func (s *Service) Login(ctx context.Context, req LoginRequest) (*identity.Identi
 	}, nil
 }
 
+// Refresh exchanges a session's refresh token for a new access token.
+// The refresh token is rotated when the auth config enables rotation.
+func (s *Service) Refresh(ctx context.Context, req RefreshRequest) (*identity.Identity, LoginResult, error) {
+	if req.SessionID == "" || req.RefreshToken == "" {
+		return nil, LoginResult{}, iauth.ErrInvalidRequest
+	}
+
+	pair, id, err := s.auth.Session.Refresh(ctx, req.SessionID, req.RefreshToken)
+	if err != nil {
+		return nil, LoginResult{}, err
+	}
+	s.logger.Debug().Str("session_id", req.SessionID).Msg("token refreshed")
+	return id, LoginResult{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		SessionID:    id.SessionID,
+	}, nil
+}
+
 // GetPermissions returns all available permissions in the system.
 func (s *Service) GetPermissions() []kind.Permission {
 	return kind.All
@@ -14,6 +14,12 @@ type LoginResult struct {
 	SessionID    string
 }
 
+// RefreshRequest exchanges a refresh token for a new token pair.
+type RefreshRequest struct {
+	SessionID    string
+	RefreshToken string
+}
+
 // LogoutRequest revokes a session.
 type LogoutRequest struct {
 	SessionID string
@@ -1,14 +1,22 @@
 // Package agent implements agent management use-cases:
 //   - Paginated listing and retrieval
 //   - Upsert with label and heartbeat preservation
-//   - Control-plane label patching.
+//   - Control-plane label patching
+//   - Approval of agents held by the enrollment approval workflow
+//   - Cordoning agents before maintenance
+//   - Recording endpoint probe results
+//   - Inventory change history recorded between syncs, capped per agent.
 package agent
 
 import (
 	"context"
 	"errors"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"github.com/soltiHQ/control-plane/domain/kind"
 	"github.com/soltiHQ/control-plane/domain/model"
 	"github.com/soltiHQ/control-plane/internal/service"
 	"github.com/soltiHQ/control-plane/internal/storage"
@@ -17,11 +25,15 @@ import (
 // Service provides agent management operations.
 type Service struct {
 	logger zerolog.Logger
-	store  storage.AgentStore
+	store  storage.Storage
+
+	// mu serializes read-modify-write updates, so a field set by one method (labels,
+	// approval, cordon, probe result) is not lost to a concurrent Upsert of the agent.
+	mu sync.Mutex
 }
 
 // New creates a new agent service.
-func New(store storage.AgentStore, logger zerolog.Logger) *Service {
+func New(store storage.Storage, logger zerolog.Logger) *Service {
 	if store == nil {
 		panic("agent.Service: store is nil")
 	}
@@ -72,19 +84,31 @@ func (s *Service) Get(ctx context.Context, id string) (*model.Agent, error) {
 
 // Upsert an agent.
 //
-// If the agent already exists, control-plane owned labels and the original
-// createdAt timestamp are preserved because they are not part of the
-// discovery payload reported by the agent.
+// If the agent already exists, control-plane owned labels, the approval and cordon state,
+// the last probe result, the discovery credential and the original createdAt timestamp
+// are preserved because they are not part of the discovery payload reported by the agent.
 func (s *Service) Upsert(ctx context.Context, m *model.Agent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var existed bool
 	existing, err := s.store.GetAgent(ctx, m.ID())
 	switch {
 	case err == nil:
 		existed = true
 		m.SetCreatedAt(existing.CreatedAt())
+		m.SetApproval(existing.Approval())
+		m.SetCordoned(existing.Cordoned())
+		m.SetReachability(existing.Reachability(), existing.ProbeError(), existing.ProbedAt())
 		for k, v := range existing.LabelsAll() {
 			m.LabelAdd(k, v)
 		}
+		if m.CredentialHash() == "" {
+			m.SetCredentialHash(existing.CredentialHash())
+		}
+		if m.CertExpiresAt().IsZero() {
+			m.SetCertExpiresAt(existing.CertExpiresAt())
+		}
 		if m.HeartbeatInterval() == 0 && existing.HeartbeatInterval() > 0 {
 			m.SetHeartbeatInterval(existing.HeartbeatInterval())
 		}
@@ -112,6 +136,8 @@ func (s *Service) PatchLabels(ctx context.Context, req PatchLabels) (*model.Agen
 	if req.ID == "" {
 		return nil, storage.ErrInvalidArgument
 	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	agent, err := s.store.GetAgent(ctx, req.ID)
 	if err != nil {
@@ -133,6 +159,186 @@ func (s *Service) PatchLabels(ctx context.Context, req PatchLabels) (*model.Agen
 	return agent.Clone(), nil
 }
 
+// SetApproval approves or rejects an agent.
+//
+// Only approved agents receive deployments and runs; rejected agents are refused on sync.
+func (s *Service) SetApproval(ctx context.Context, id string, approval kind.AgentApproval) (*model.Agent, error) {
+	if id == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.store.GetAgent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if agent == nil {
+		return nil, storage.ErrInternal
+	}
+
+	agent.SetApproval(approval)
+	if err = s.store.UpsertAgent(ctx, agent); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug().
+		Str("agent_id", id).
+		Str("approval", approval.String()).
+		Msg("approval set")
+	return agent.Clone(), nil
+}
+
+// SetCordon cordons or uncordons an agent.
+//
+// A cordoned agent keeps its rollouts but is left out of new deployments and runs.
+func (s *Service) SetCordon(ctx context.Context, id string, cordoned bool) (*model.Agent, error) {
+	if id == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.store.GetAgent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if agent == nil {
+		return nil, storage.ErrInternal
+	}
+
+	agent.SetCordoned(cordoned)
+	if err = s.store.UpsertAgent(ctx, agent); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug().
+		Str("agent_id", id).
+		Bool("cordoned", cordoned).
+		Msg("cordon set")
+	return agent.Clone(), nil
+}
+
+// SetStatus sets the liveness status of an agent and returns the status it had before.
+// The agent is re-read under the service lock, so concurrent updates are not lost.
+func (s *Service) SetStatus(ctx context.Context, id string, status kind.AgentStatus) (kind.AgentStatus, error) {
+	if id == "" {
+		return status, storage.ErrInvalidArgument
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.store.GetAgent(ctx, id)
+	if err != nil {
+		return status, err
+	}
+	if agent == nil {
+		return status, storage.ErrInternal
+	}
+
+	prev := agent.Status()
+	if prev == status {
+		return prev, nil
+	}
+	agent.SetStatus(status)
+	if err = s.store.UpsertAgent(ctx, agent); err != nil {
+		return prev, err
+	}
+
+	s.logger.Debug().
+		Str("agent_id", id).
+		Str("status", status.String()).
+		Msg("status set")
+	return prev, nil
+}
+
+// SetReachability records the result of an endpoint probe on an agent and returns
+// the reachability it had before. Only the probe fields change.
+func (s *Service) SetReachability(ctx context.Context, id string, r kind.AgentReachability, errMsg string, at time.Time) (kind.AgentReachability, error) {
+	if id == "" {
+		return kind.AgentReachabilityUnknown, storage.ErrInvalidArgument
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	agent, err := s.store.GetAgent(ctx, id)
+	if err != nil {
+		return kind.AgentReachabilityUnknown, err
+	}
+	if agent == nil {
+		return kind.AgentReachabilityUnknown, storage.ErrInternal
+	}
+
+	prev := agent.Reachability()
+	agent.SetReachability(r, errMsg, at)
+	if err = s.store.UpsertAgent(ctx, agent); err != nil {
+		return prev, err
+	}
+
+	s.logger.Trace().
+		Str("agent_id", id).
+		Str("reachability", r.String()).
+		Msg("reachability set")
+	return prev, nil
+}
+
+// RecordChanges appends the inventory fields that differ between existing and incoming
+// to the agent history and returns the recorded change. The history keeps the newest
+// maxHistory changes of each agent, so an agent that changes on every sync cannot grow
+// it without bound.
+//
+// It returns nil without error when there is no previous state or nothing changed.
+func (s *Service) RecordChanges(ctx context.Context, existing, incoming *model.Agent) (*model.AgentChange, error) {
+	fields := model.DiffInventory(existing, incoming)
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	change, err := model.NewAgentChange(ksuid.New().String(), incoming.ID(), fields)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.store.CreateAgentChange(ctx, change); err != nil {
+		return nil, err
+	}
+	if err = s.store.TrimAgentChanges(ctx, incoming.ID(), maxHistory); err != nil {
+		s.logger.Warn().Err(err).Str("agent_id", incoming.ID()).Msg("trim inventory history failed")
+	}
+
+	s.logger.Debug().
+		Str("agent_id", incoming.ID()).
+		Strs("fields", change.FieldNames()).
+		Msg("inventory changed")
+	return change.Clone(), nil
+}
+
+// History returns a page of inventory changes recorded for an agent, newest first.
+func (s *Service) History(ctx context.Context, q HistoryQuery) (*HistoryPage, error) {
+	if q.AgentID == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+
+	res, err := s.store.ListAgentChanges(ctx, q.Filter, storage.ListOptions{
+		Limit:  service.NormalizeListLimit(q.Limit, defaultListLimit),
+		Cursor: q.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*model.AgentChange, 0, len(res.Items))
+	for _, c := range res.Items {
+		if c == nil {
+			continue
+		}
+		out = append(out, c.Clone())
+	}
+	return &HistoryPage{
+		Items:      out,
+		NextCursor: res.NextCursor,
+	}, nil
+}
+
 func replaceLabels(a *model.Agent, labels map[string]string) {
 	for k := range a.LabelsAll() {
 		a.LabelDelete(k)
@@ -5,7 +5,12 @@ import (
 	"github.com/soltiHQ/control-plane/internal/storage"
 )
 
-const defaultListLimit = 30
+const (
+	defaultListLimit = 30
+
+	// maxHistory is the number of inventory changes kept per agent; older ones are trimmed first.
+	maxHistory = 500
+)
 
 // ListQuery describes a paginated agents listing request.
 type ListQuery struct {
@@ -32,3 +37,19 @@ type PatchLabels struct {
 	Labels map[string]string
 	ID     string
 }
+
+// HistoryQuery describes a paginated inventory history request for a single agent.
+type HistoryQuery struct {
+	// Filter is a storage-level filter that must select the changes of AgentID.
+	Filter storage.AgentChangeFilter
+
+	AgentID string
+	Cursor  string
+	Limit   int
+}
+
+// HistoryPage is a paginated inventory history result.
+type HistoryPage struct {
+	Items      []*model.AgentChange
+	NextCursor string
+}
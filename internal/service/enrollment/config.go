@@ -0,0 +1,55 @@
+package enrollment
+
+import (
+	"strings"
+
+	"github.com/soltiHQ/control-plane/domain/model"
+)
+
+// Config controls the enrollment approval workflow.
+type Config struct {
+	// AutoApprove lists rules that admit agents without operator approval.
+	// A rule is a ";"-separated list of placement constraints (see model.ParseConstraint)
+	// that must all match, e.g. "labels.env = dev; os in (ubuntu,debian)".
+	// Rules see the agent as it enrolls, with the enrollment token labels applied.
+	AutoApprove []string `yaml:"auto_approve"`
+
+	// RequireApproval holds newly enrolled agents as pending until an operator approves them.
+	RequireApproval bool `yaml:"require_approval"`
+}
+
+// rule is a parsed auto-approval rule.
+type rule struct {
+	expr        string
+	constraints []model.Constraint
+}
+
+// parseRule parses a ";"-separated list of placement constraints.
+func parseRule(expr string) (rule, error) {
+	r := rule{expr: expr}
+	for _, part := range strings.Split(expr, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		c, err := model.ParseConstraint(part)
+		if err != nil {
+			return rule{}, err
+		}
+		r.constraints = append(r.constraints, c)
+	}
+	return r, nil
+}
+
+// match reports whether a satisfies every constraint of the rule.
+// A rule without constraints matches nothing.
+func (r rule) match(a *model.Agent) bool {
+	if len(r.constraints) == 0 {
+		return false
+	}
+	for _, c := range r.constraints {
+		if !c.Match(a) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,287 @@
+// Package enrollment implements agent enrollment use-cases:
+//   - Enrollment token creation (labels, expiry, use limit), listing and deletion
+//   - Discovery authentication: exchanging a token for a per-agent credential
+//     on first sync and verifying that credential on every later sync
+//   - Admission of newly enrolled agents: approved, or pending operator approval.
+//
+// Token values and agent credentials are stored as SHA3-256 hashes only.
+package enrollment
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"golang.org/x/crypto/sha3"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/auth"
+	"github.com/soltiHQ/control-plane/internal/service"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+const secretBytes = 32
+
+// Service provides enrollment token management and discovery authentication.
+type Service struct {
+	logger zerolog.Logger
+	store  storage.Storage
+
+	requireApproval bool
+	autoApprove     []rule
+
+	// mu serializes enrollments so concurrent syncs cannot exceed a token's use limit
+	// or enroll the same agent twice. enrolling holds the agent IDs granted a credential
+	// the caller has not stored yet; they are released by Grant.Done.
+	mu        sync.Mutex
+	enrolling map[string]struct{}
+}
+
+// New creates a new enrollment service.
+//
+// Invalid auto-approval rules are logged and ignored, so the agents they were meant
+// to admit wait for operator approval instead.
+func New(cfg Config, store storage.Storage, logger zerolog.Logger) *Service {
+	if store == nil {
+		panic("enrollment.Service: store is nil")
+	}
+	s := &Service{
+		logger:          logger.With().Str("service", "enrollment").Logger(),
+		store:           store,
+		requireApproval: cfg.RequireApproval,
+		enrolling:       make(map[string]struct{}),
+	}
+	for _, expr := range cfg.AutoApprove {
+		r, err := parseRule(expr)
+		if err != nil {
+			s.logger.Error().Err(err).Str("rule", expr).Msg("invalid auto-approval rule ignored")
+			continue
+		}
+		s.autoApprove = append(s.autoApprove, r)
+	}
+	return s
+}
+
+// Admission decides the approval state of a newly enrolled agent.
+//
+// Without RequireApproval every agent is approved. Otherwise the agent is approved
+// if it matches an auto-approval rule and held as pending if it does not.
+func (s *Service) Admission(a *model.Agent) kind.AgentApproval {
+	if !s.requireApproval {
+		return kind.AgentApprovalApproved
+	}
+	for _, r := range s.autoApprove {
+		if r.match(a) {
+			s.logger.Info().Str("agent_id", a.ID()).Str("rule", r.expr).Msg("agent auto-approved")
+			return kind.AgentApprovalApproved
+		}
+	}
+	return kind.AgentApprovalPending
+}
+
+// List returns a page of enrollment tokens matching the query.
+func (s *Service) List(ctx context.Context, q ListQuery) (*Page, error) {
+	res, err := s.store.ListEnrollmentTokens(ctx, q.Filter, storage.ListOptions{
+		Limit:  service.NormalizeListLimit(q.Limit, defaultListLimit),
+		Cursor: q.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*model.EnrollmentToken, 0, len(res.Items))
+	for _, t := range res.Items {
+		if t == nil {
+			continue
+		}
+		out = append(out, t.Clone())
+	}
+	return &Page{Items: out, NextCursor: res.NextCursor}, nil
+}
+
+// Get returns a single enrollment token by ID.
+func (s *Service) Get(ctx context.Context, id string) (*model.EnrollmentToken, error) {
+	if id == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+	t, err := s.store.GetEnrollmentToken(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return t.Clone(), nil
+}
+
+// Create generates and persists a new enrollment token.
+//
+// The raw token value is returned once in Created.Value and never stored.
+func (s *Service) Create(ctx context.Context, req CreateRequest) (*Created, error) {
+	if req.Name == "" || req.TTL < 0 || req.MaxUses < 0 {
+		return nil, storage.ErrInvalidArgument
+	}
+
+	value, hash, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	t, err := model.NewEnrollmentToken(ksuid.New().String(), req.Name, hash)
+	if err != nil {
+		return nil, err
+	}
+	t.SetLabels(req.Labels)
+	t.SetMaxUses(req.MaxUses)
+	if req.TTL > 0 {
+		t.SetExpiresAt(time.Now().Add(req.TTL))
+	}
+	if err = s.store.UpsertEnrollmentToken(ctx, t); err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug().Str("token_id", t.ID()).Str("name", t.Name()).Msg("enrollment token created")
+	return &Created{Token: t.Clone(), Value: value}, nil
+}
+
+// Delete removes an enrollment token by ID.
+//
+// Agents already enrolled with the token keep their credentials.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return storage.ErrInvalidArgument
+	}
+	if err := s.store.DeleteEnrollmentToken(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Debug().Str("token_id", id).Msg("enrollment token deleted")
+	return nil
+}
+
+// Authenticate verifies the credentials of a discovery sync.
+//
+// An enrolled agent must present its credential. An agent that is not enrolled yet
+// must present a valid enrollment token, which is consumed and exchanged for a new
+// credential returned in the Grant; the caller stores Grant.CredentialHash on the agent
+// and then calls Grant.Done. Until then the agent ID stays reserved and further
+// enrollments of it are refused, so concurrent first syncs cannot both obtain a credential.
+//
+// A client certificate, when presented, must be issued to the agent ID. Over mutual
+// TLS an agent holding an unexpired certificate must present it, so a leaked credential
+// alone is not enough; once the certificate expires the credential is accepted again
+// and the agent can renew it with a new CSR.
+//
+// Returns:
+//   - auth.ErrInvalidCredentials if the credential is missing or wrong, the agent ID
+//     is already enrolled (or being enrolled) and no credential was presented, or the
+//     client certificate is missing or belongs to another agent.
+//   - auth.ErrInvalidToken if the enrollment token is unknown, expired or exhausted.
+//   - auth.ErrUnauthorized if an operator rejected the agent.
+func (s *Service) Authenticate(ctx context.Context, in SyncAuth) (*Grant, error) {
+	if in.AgentID == "" {
+		return nil, auth.ErrInvalidRequest
+	}
+	if in.PeerID != "" && in.PeerID != in.AgentID {
+		return nil, fmt.Errorf("%w: client certificate issued to agent %s", auth.ErrInvalidCredentials, in.PeerID)
+	}
+	if in.Credential != "" {
+		return s.verify(ctx, in)
+	}
+	if in.EnrollmentToken == "" {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.enrolling[in.AgentID]; ok {
+		return nil, fmt.Errorf("%w: agent %s is already being enrolled", auth.ErrInvalidCredentials, in.AgentID)
+	}
+	a, err := s.store.GetAgent(ctx, in.AgentID)
+	switch {
+	case err == nil && a.CredentialHash() != "":
+		return nil, fmt.Errorf("%w: agent %s is already enrolled", auth.ErrInvalidCredentials, in.AgentID)
+	case err != nil && !errors.Is(err, storage.ErrNotFound):
+		return nil, err
+	}
+
+	t, err := s.store.GetEnrollmentTokenByHash(ctx, hashSecret(in.EnrollmentToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, auth.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if err = t.Use(time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %w", auth.ErrInvalidToken, err)
+	}
+
+	credential, hash, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.store.UpsertEnrollmentToken(ctx, t); err != nil {
+		return nil, err
+	}
+
+	s.enrolling[in.AgentID] = struct{}{}
+
+	s.logger.Info().Str("agent_id", in.AgentID).Str("token_id", t.ID()).Msg("agent enrolled")
+	return &Grant{
+		Token:          t.Clone(),
+		Credential:     credential,
+		CredentialHash: hash,
+		release:        func() { s.release(in.AgentID) },
+	}, nil
+}
+
+// release drops the enrollment reservation of an agent ID.
+func (s *Service) release(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.enrolling, id)
+}
+
+func (s *Service) verify(ctx context.Context, in SyncAuth) (*Grant, error) {
+	a, err := s.store.GetAgent(ctx, in.AgentID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, auth.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	want := a.CredentialHash()
+	if want == "" || subtle.ConstantTimeCompare([]byte(want), []byte(hashSecret(in.Credential))) != 1 {
+		return nil, auth.ErrInvalidCredentials
+	}
+	if in.MutualTLS && in.PeerID == "" && time.Now().Before(a.CertExpiresAt()) {
+		return nil, fmt.Errorf("%w: agent %s must present its client certificate", auth.ErrInvalidCredentials, in.AgentID)
+	}
+	if a.Approval() == kind.AgentApprovalRejected {
+		return nil, fmt.Errorf("%w: %w", auth.ErrUnauthorized, a.CheckApproval())
+	}
+	return &Grant{}, nil
+}
+
+// newSecret generates a random token value and its hash suitable for storage.
+func newSecret() (raw, hash string, err error) {
+	var b [secretBytes]byte
+	if _, err = rand.Read(b[:]); err != nil {
+		return "", "", err
+	}
+	raw = base64.RawURLEncoding.EncodeToString(b[:])
+	return raw, hashSecret(raw), nil
+}
+
+// hashSecret returns the hex-encoded SHA3-256 hash of a raw token value.
+func hashSecret(raw string) string {
+	h := sha3.Sum256([]byte(raw))
+	return hex.EncodeToString(h[:])
+}
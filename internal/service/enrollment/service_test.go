@@ -0,0 +1,458 @@
+package enrollment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/auth"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+func newTestService(t *testing.T) (*Service, *inmemory.Store) {
+	t.Helper()
+
+	store := inmemory.New()
+	return New(Config{}, store, zerolog.Nop()), store
+}
+
+func mkToken(t *testing.T, s *Service, req CreateRequest) *Created {
+	t.Helper()
+
+	if req.Name == "" {
+		req.Name = "test"
+	}
+	c, err := s.Create(context.Background(), req)
+	if err != nil {
+		t.Fatalf("create token: %v", err)
+	}
+	return c
+}
+
+// enroll authenticates a first sync and stores the issued credential on the agent,
+// as the discovery handlers do.
+func enroll(t *testing.T, s *Service, store *inmemory.Store, id, token string) string {
+	t.Helper()
+
+	ctx := context.Background()
+	g, err := s.Authenticate(ctx, SyncAuth{AgentID: id, EnrollmentToken: token})
+	if err != nil {
+		t.Fatalf("enroll %s: %v", id, err)
+	}
+	defer g.Done()
+
+	a, err := model.NewAgent(id, id, "http://"+id+":8080")
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	a.SetCredentialHash(g.CredentialHash)
+	if err = store.UpsertAgent(ctx, a); err != nil {
+		t.Fatalf("upsert agent: %v", err)
+	}
+	return g.Credential
+}
+
+func TestAuthenticate_Enroll(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{Labels: map[string]string{"env": "dev"}})
+
+	g, err := s.Authenticate(ctx, SyncAuth{AgentID: "a1", EnrollmentToken: tok.Value})
+	if err != nil {
+		t.Fatalf("authenticate: %v", err)
+	}
+	defer g.Done()
+
+	if !g.Enrolled() {
+		t.Fatal("expected an enrollment grant")
+	}
+	if g.Credential == "" || g.CredentialHash != hashSecret(g.Credential) {
+		t.Fatalf("credential hash does not match credential")
+	}
+	if g.Token.LabelsAll()["env"] != "dev" {
+		t.Fatalf("expected token labels on grant, got %v", g.Token.LabelsAll())
+	}
+
+	stored, err := store.GetEnrollmentToken(ctx, tok.Token.ID())
+	if err != nil {
+		t.Fatalf("get token: %v", err)
+	}
+	if stored.Uses() != 1 {
+		t.Fatalf("expected 1 use, got %d", stored.Uses())
+	}
+}
+
+func TestAuthenticate_InvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{})
+
+	tests := []struct {
+		name string
+		in   SyncAuth
+		want error
+	}{
+		{name: "empty agent id", in: SyncAuth{EnrollmentToken: tok.Value}, want: auth.ErrInvalidRequest},
+		{name: "no credential or token", in: SyncAuth{AgentID: "a1"}, want: auth.ErrInvalidCredentials},
+		{name: "certificate of another agent", in: SyncAuth{AgentID: "a1", PeerID: "a2", EnrollmentToken: tok.Value}, want: auth.ErrInvalidCredentials},
+		{name: "unknown token", in: SyncAuth{AgentID: "a1", EnrollmentToken: "nope"}, want: auth.ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Authenticate(context.Background(), tt.in)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_UseLimit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{MaxUses: 2})
+
+	enroll(t, s, store, "a1", tok.Value)
+	enroll(t, s, store, "a2", tok.Value)
+
+	_, err := s.Authenticate(ctx, SyncAuth{AgentID: "a3", EnrollmentToken: tok.Value})
+	if !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for exhausted token, got %v", err)
+	}
+}
+
+func TestAuthenticate_ExpiredToken(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{TTL: time.Hour})
+
+	stored, err := store.GetEnrollmentToken(ctx, tok.Token.ID())
+	if err != nil {
+		t.Fatalf("get token: %v", err)
+	}
+	stored.SetExpiresAt(time.Now().Add(-time.Minute))
+	if err = store.UpsertEnrollmentToken(ctx, stored); err != nil {
+		t.Fatalf("upsert token: %v", err)
+	}
+
+	_, err = s.Authenticate(ctx, SyncAuth{AgentID: "a1", EnrollmentToken: tok.Value})
+	if !errors.Is(err, auth.ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken for expired token, got %v", err)
+	}
+}
+
+func TestAuthenticate_AlreadyEnrolled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{})
+	enroll(t, s, store, "a1", tok.Value)
+
+	_, err := s.Authenticate(ctx, SyncAuth{AgentID: "a1", EnrollmentToken: tok.Value})
+	if !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestAuthenticate_ConcurrentEnrollment(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{})
+
+	const n = 16
+	var (
+		wg     sync.WaitGroup
+		wins   atomic.Int32
+		grants = make(chan *Grant, n)
+	)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g, err := s.Authenticate(ctx, SyncAuth{AgentID: "a1", EnrollmentToken: tok.Value})
+			if err == nil {
+				wins.Add(1)
+				grants <- g
+			}
+		}()
+	}
+	wg.Wait()
+	close(grants)
+
+	if got := wins.Load(); got != 1 {
+		t.Fatalf("expected exactly one enrollment, got %d", got)
+	}
+	stored, err := store.GetEnrollmentToken(ctx, tok.Token.ID())
+	if err != nil {
+		t.Fatalf("get token: %v", err)
+	}
+	if stored.Uses() != 1 {
+		t.Fatalf("expected 1 use, got %d", stored.Uses())
+	}
+
+	// Once released without a stored credential the ID can enroll again.
+	for g := range grants {
+		g.Done()
+		g.Done()
+	}
+	g, err := s.Authenticate(ctx, SyncAuth{AgentID: "a1", EnrollmentToken: tok.Value})
+	if err != nil {
+		t.Fatalf("enroll after release: %v", err)
+	}
+	g.Done()
+}
+
+func TestAuthenticate_ReservationPerAgent(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, _ := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{})
+
+	g1, err := s.Authenticate(ctx, SyncAuth{AgentID: "a1", EnrollmentToken: tok.Value})
+	if err != nil {
+		t.Fatalf("enroll a1: %v", err)
+	}
+	defer g1.Done()
+
+	if _, err = s.Authenticate(ctx, SyncAuth{AgentID: "a1", EnrollmentToken: tok.Value}); !errors.Is(err, auth.ErrInvalidCredentials) {
+		t.Fatalf("expected reserved a1 to be refused, got %v", err)
+	}
+	g2, err := s.Authenticate(ctx, SyncAuth{AgentID: "a2", EnrollmentToken: tok.Value})
+	if err != nil {
+		t.Fatalf("enroll a2 while a1 is reserved: %v", err)
+	}
+	g2.Done()
+}
+
+func TestAuthenticate_Verify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{})
+	cred := enroll(t, s, store, "a1", tok.Value)
+
+	g, err := s.Authenticate(ctx, SyncAuth{AgentID: "a1", Credential: cred})
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if g.Enrolled() {
+		t.Fatal("verification must not issue a new credential")
+	}
+
+	tests := []struct {
+		name string
+		in   SyncAuth
+		want error
+	}{
+		{name: "wrong credential", in: SyncAuth{AgentID: "a1", Credential: "wrong"}, want: auth.ErrInvalidCredentials},
+		{name: "unknown agent", in: SyncAuth{AgentID: "a2", Credential: cred}, want: auth.ErrInvalidCredentials},
+		{name: "certificate of another agent", in: SyncAuth{AgentID: "a1", Credential: cred, PeerID: "a2"}, want: auth.ErrInvalidCredentials},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Authenticate(ctx, tt.in)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAuthenticate_VerifyRejected(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{})
+	cred := enroll(t, s, store, "a1", tok.Value)
+
+	a, err := store.GetAgent(ctx, "a1")
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	a.SetApproval(kind.AgentApprovalRejected)
+	if err = store.UpsertAgent(ctx, a); err != nil {
+		t.Fatalf("upsert agent: %v", err)
+	}
+
+	_, err = s.Authenticate(ctx, SyncAuth{AgentID: "a1", Credential: cred})
+	if !errors.Is(err, auth.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestAuthenticate_VerifyMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	tok := mkToken(t, s, CreateRequest{})
+
+	for i, tt := range []struct {
+		name     string
+		expires  time.Time
+		peerID   bool
+		wantFail bool
+	}{
+		{name: "no certificate issued"},
+		{name: "valid certificate presented", expires: time.Now().Add(time.Hour), peerID: true},
+		{name: "valid certificate withheld", expires: time.Now().Add(time.Hour), wantFail: true},
+		{name: "expired certificate withheld", expires: time.Now().Add(-time.Hour)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			id := fmt.Sprintf("a%d", i)
+			cred := enroll(t, s, store, id, tok.Value)
+
+			a, err := store.GetAgent(ctx, id)
+			if err != nil {
+				t.Fatalf("get agent: %v", err)
+			}
+			a.SetCertExpiresAt(tt.expires)
+			if err = store.UpsertAgent(ctx, a); err != nil {
+				t.Fatalf("upsert agent: %v", err)
+			}
+
+			in := SyncAuth{AgentID: id, Credential: cred, MutualTLS: true}
+			if tt.peerID {
+				in.PeerID = id
+			}
+			_, err = s.Authenticate(ctx, in)
+			switch {
+			case tt.wantFail && !errors.Is(err, auth.ErrInvalidCredentials):
+				t.Fatalf("expected ErrInvalidCredentials, got %v", err)
+			case !tt.wantFail && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr string
+		n    int    // number of constraints
+		err  string // substring; empty means the rule is valid
+	}{
+		{expr: "labels.env = dev", n: 1},
+		{expr: "labels.env = dev; os in (ubuntu,debian)", n: 2},
+		{expr: " labels.env = dev ;; arch = arm64 ; ", n: 2},
+		{expr: "", n: 0},
+		{expr: " ; ", n: 0},
+		{expr: "labels.env = dev; colour = red", err: "unknown key"},
+		{expr: "os in (ubuntu", err: "invalid key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			r, err := parseRule(tt.expr)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("parseRule(%q) error = %v, want containing %q", tt.expr, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRule(%q): %v", tt.expr, err)
+			}
+			if r.expr != tt.expr || len(r.constraints) != tt.n {
+				t.Fatalf("parseRule(%q) = %q with %d constraints, want %d", tt.expr, r.expr, len(r.constraints), tt.n)
+			}
+		})
+	}
+}
+
+func TestAdmission(t *testing.T) {
+	t.Parallel()
+
+	a, err := model.NewAgentFrom(model.AgentParams{
+		ID:           "a1",
+		Name:         "a1",
+		EndpointType: 1,
+		APIVersion:   1,
+		OS:           "ubuntu",
+		Arch:         "amd64",
+	})
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	a.LabelAdd("env", "dev")
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want kind.AgentApproval
+	}{
+		{name: "approval not required", want: kind.AgentApprovalApproved},
+		{
+			name: "approval not required ignores rules",
+			cfg:  Config{AutoApprove: []string{"labels.env = prod"}},
+			want: kind.AgentApprovalApproved,
+		},
+		{name: "no rules", cfg: Config{RequireApproval: true}, want: kind.AgentApprovalPending},
+		{
+			name: "matching rule",
+			cfg:  Config{RequireApproval: true, AutoApprove: []string{"labels.env = dev"}},
+			want: kind.AgentApprovalApproved,
+		},
+		{
+			name: "every constraint matches",
+			cfg:  Config{RequireApproval: true, AutoApprove: []string{"labels.env = dev; os in (ubuntu,debian)"}},
+			want: kind.AgentApprovalApproved,
+		},
+		{
+			name: "one constraint fails",
+			cfg:  Config{RequireApproval: true, AutoApprove: []string{"labels.env = dev; arch = arm64"}},
+			want: kind.AgentApprovalPending,
+		},
+		{
+			name: "any rule matches",
+			cfg:  Config{RequireApproval: true, AutoApprove: []string{"labels.env = prod", "os = ubuntu"}},
+			want: kind.AgentApprovalApproved,
+		},
+		{
+			name: "empty rule matches nothing",
+			cfg:  Config{RequireApproval: true, AutoApprove: []string{"", " ; "}},
+			want: kind.AgentApprovalPending,
+		},
+		{
+			name: "invalid rule is ignored",
+			cfg:  Config{RequireApproval: true, AutoApprove: []string{"labels.env = dev; colour = red"}},
+			want: kind.AgentApprovalPending,
+		},
+		{
+			name: "invalid rule does not drop the others",
+			cfg:  Config{RequireApproval: true, AutoApprove: []string{"colour = red", "labels.env = dev"}},
+			want: kind.AgentApprovalApproved,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(tt.cfg, inmemory.New(), zerolog.Nop())
+			if got := s.Admission(a); got != tt.want {
+				t.Fatalf("Admission = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
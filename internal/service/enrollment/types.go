@@ -0,0 +1,82 @@
+package enrollment
+
+import (
+	"sync"
+	"time"
+
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+const defaultListLimit = 30
+
+// ListQuery describes a paginated enrollment token listing request.
+type ListQuery struct {
+	Filter storage.EnrollmentTokenFilter
+	Cursor string
+	Limit  int
+}
+
+// Page is a paginated enrollment token listing result.
+type Page struct {
+	Items      []*model.EnrollmentToken
+	NextCursor string
+}
+
+// CreateRequest describes a new enrollment token.
+//
+// A zero TTL never expires; a zero MaxUses allows unlimited enrollments.
+type CreateRequest struct {
+	Name    string
+	Labels  map[string]string
+	TTL     time.Duration
+	MaxUses int
+}
+
+// Created is a newly created enrollment token together with its raw value.
+//
+// Value is only available here; the control-plane stores its hash.
+type Created struct {
+	Token *model.EnrollmentToken
+	Value string
+}
+
+// SyncAuth carries the credentials an agent presents on a discovery sync.
+//
+// PeerID is the agent identity of the verified TLS client certificate, empty if
+// none was presented; MutualTLS reports whether the sync arrived over mutual TLS.
+type SyncAuth struct {
+	AgentID         string
+	Credential      string
+	EnrollmentToken string
+	PeerID          string
+	MutualTLS       bool
+}
+
+// Grant is the outcome of a successful discovery authentication.
+//
+// Token is nil when the agent authenticated with its existing credential.
+// On enrollment, Credential holds the raw per-agent credential to return to the
+// agent once, and CredentialHash the value to store on the agent.
+type Grant struct {
+	Token          *model.EnrollmentToken
+	Credential     string
+	CredentialHash string
+
+	release func()
+	once    sync.Once
+}
+
+// Enrolled reports whether the grant issued a new agent credential.
+func (g *Grant) Enrolled() bool { return g != nil && g.Token != nil }
+
+// Done releases the agent ID reserved by an enrollment. The caller calls it once
+// CredentialHash is stored on the agent, or storing it failed.
+//
+// It is a no-op for grants that did not enroll the agent and safe to call more than once.
+func (g *Grant) Done() {
+	if g == nil || g.release == nil {
+		return
+	}
+	g.once.Do(g.release)
+}
@@ -0,0 +1,447 @@
+// Package manifest implements declarative apply of multi-document manifests:
+//   - Parsing YAML (or JSON) documents of specs and agent label assignments
+//   - Diffing each object against the store (created / updated / unchanged)
+//   - Optional pruning of specs missing from the manifest, and dry runs.
+//
+// Specs are matched by name; the manifest describes their full desired state,
+// so fields left out of a document reset to their defaults. Dependencies are named
+// too, and resolved to the IDs of existing specs or of specs created earlier in
+// the same manifest. With [Options.Owner]
+// set, applied specs become managed (read-only elsewhere); unmanaged specs with
+// a matching name are adopted.
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"gopkg.in/yaml.v3"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/service/agent"
+	"github.com/soltiHQ/control-plane/internal/service/spec"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+// Service applies manifests to the store.
+type Service struct {
+	logger zerolog.Logger
+	store  storage.Storage
+	agents *agent.Service
+}
+
+// New creates a new manifest service. Agent labels are written through agents,
+// so they serialize with the other agent updates.
+func New(store storage.Storage, agents *agent.Service, logger zerolog.Logger) *Service {
+	if store == nil {
+		panic("manifest.Service: store is nil")
+	}
+	if agents == nil {
+		panic("manifest.Service: agent service is nil")
+	}
+	return &Service{
+		logger: logger.With().Str("service", "manifest").Logger(),
+		store:  store,
+		agents: agents,
+	}
+}
+
+// Parse decodes a multi-document YAML manifest. JSON is accepted as well.
+//
+// Documents are decoded with the JSON field names of [restv1.ManifestDocument];
+// unknown fields and empty documents are rejected with [domain.ErrInvalidManifest].
+func Parse(data []byte) ([]restv1.ManifestDocument, error) {
+	var (
+		docs []restv1.ManifestDocument
+		dec  = yaml.NewDecoder(bytes.NewReader(data))
+	)
+	for n := 1; ; n++ {
+		var raw any
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("%w: document %d: %v", domain.ErrInvalidManifest, n, err)
+		}
+		if raw == nil {
+			continue
+		}
+
+		buf, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: document %d: %v", domain.ErrInvalidManifest, n, err)
+		}
+		var doc restv1.ManifestDocument
+		jd := json.NewDecoder(bytes.NewReader(buf))
+		jd.DisallowUnknownFields()
+		if err = jd.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("%w: document %d: %v", domain.ErrInvalidManifest, n, err)
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("%w: no documents", domain.ErrInvalidManifest)
+	}
+	return docs, nil
+}
+
+// Apply reconciles the store to docs.
+//
+// Each document is applied independently: a failing object is reported in the
+// result and does not stop the others. Pruning is skipped if any object failed,
+// so a broken document never deletes the spec it was meant to describe, and a
+// spec that a kept spec still depends on is reported as failed instead of deleted.
+//
+// Callers map the documents returned by [Parse] to their domain form first.
+// The returned error is reserved for store failures that prevent planning.
+func (s *Service) Apply(ctx context.Context, docs []Document, opts Options) (*Result, error) {
+	specs, err := s.allSpecs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string][]*model.Spec, len(specs))
+	for _, ts := range specs {
+		byName[ts.Name()] = append(byName[ts.Name()], ts)
+	}
+
+	var (
+		res     = &Result{DryRun: opts.DryRun}
+		seen    = make(map[string]struct{}, len(docs))
+		applied = make(map[string]*model.Spec)
+	)
+	for _, doc := range docs {
+		var it Item
+		switch doc.Kind {
+		case KindSpec:
+			it = s.applySpec(ctx, doc, byName, seen, applied, opts)
+		case KindAgentLabels:
+			it = s.applyLabels(ctx, doc, opts.DryRun)
+		default:
+			it = Item{Kind: doc.Kind, Action: ActionFailed, Err: fmt.Errorf("unknown kind %q", doc.Kind)}
+		}
+		res.Items = append(res.Items, it)
+	}
+
+	if opts.Prune && res.Failed() == 0 {
+		var candidates []*model.Spec
+		for _, ts := range specs {
+			if _, ok := seen[ts.Name()]; ok || ts.ManagedBy() != opts.Owner {
+				continue
+			}
+			candidates = append(candidates, ts)
+		}
+		held := heldBy(specs, applied, candidates)
+		for _, ts := range candidates {
+			if names := held[ts.ID()]; len(names) > 0 {
+				slices.Sort(names)
+				res.Items = append(res.Items, Item{
+					Kind:   KindSpec,
+					Name:   ts.Name(),
+					ID:     ts.ID(),
+					Action: ActionFailed,
+					Err:    fmt.Errorf("%w: %s", domain.ErrDependencyInUse, strings.Join(names, ", ")),
+				})
+				continue
+			}
+			res.Items = append(res.Items, s.prune(ctx, ts, opts.DryRun))
+		}
+	}
+
+	s.logger.Debug().
+		Int("objects", len(res.Items)).
+		Int("failed", res.Failed()).
+		Bool("dry_run", opts.DryRun).
+		Msg("manifest applied")
+	return res, nil
+}
+
+// applySpec applies one spec document. applied holds the desired state of the specs
+// created or updated by earlier documents of the manifest, by name; dependencies are
+// resolved and checked against it before the store, so dry runs see them too.
+func (s *Service) applySpec(ctx context.Context, doc Document, byName map[string][]*model.Spec, seen map[string]struct{}, applied map[string]*model.Spec, opts Options) Item {
+	it := Item{Kind: KindSpec, Name: doc.Name}
+	switch {
+	case doc.Spec == nil && doc.Err == nil:
+		it.Action, it.Err = ActionFailed, errors.New("missing spec")
+		return it
+	case doc.Name == "":
+		it.Action, it.Err = ActionFailed, domain.ErrEmptyName
+		return it
+	}
+	if _, dup := seen[doc.Name]; dup {
+		it.Action, it.Err = ActionFailed, errors.New("duplicate spec name in manifest")
+		return it
+	}
+	seen[doc.Name] = struct{}{}
+
+	if doc.Err != nil {
+		it.Action, it.Err = ActionFailed, doc.Err
+		return it
+	}
+
+	var current *model.Spec
+	switch matches := byName[doc.Name]; len(matches) {
+	case 0:
+	case 1:
+		current = matches[0]
+	default:
+		it.Action, it.Err = ActionFailed, fmt.Errorf("%d specs share this name", len(matches))
+		return it
+	}
+
+	id := ksuid.New().String()
+	if current != nil {
+		id = current.ID()
+		it.ID = id
+
+		if owner := current.ManagedBy(); owner != "" && owner != opts.Owner {
+			it.Action, it.Err = ActionFailed, errors.New("spec is managed by "+owner)
+			return it
+		}
+	}
+	deps, err := resolveDependencies(doc.Spec.DependsOn(), byName, applied)
+	if err != nil {
+		it.Action, it.Err, it.Fields = ActionFailed, err, map[string]string{"depends_on": err.Error()}
+		return it
+	}
+	desired, err := model.NewSpec(id, doc.Name, doc.Spec.Slot())
+	if err != nil {
+		it.Action, it.Err = ActionFailed, err
+		return it
+	}
+	desired.Assign(doc.Spec)
+	desired.SetManagedBy(opts.Owner)
+	if len(deps) > 0 {
+		desired.SetDependsOn(deps)
+	}
+
+	if err = desired.Validate(); err != nil {
+		var ve *model.ValidationError
+		if errors.As(err, &ve) {
+			it.Fields = ve.FieldMap()
+		}
+		it.Action, it.Err = ActionFailed, err
+		return it
+	}
+	if err = s.checkSecrets(ctx, desired, opts.DenySecrets); err != nil {
+		it.Action, it.Err = ActionFailed, err
+		return it
+	}
+	if err = spec.CheckDependencies(ctx, desired, s.lookup(applied)); err != nil {
+		it.Action, it.Err = ActionFailed, err
+		if errors.Is(err, domain.ErrDependencyCycle) || errors.Is(err, domain.ErrDependencyMissing) {
+			it.Fields = map[string]string{"depends_on": err.Error()}
+		}
+		return it
+	}
+
+	if current == nil {
+		it.Action = ActionCreated
+		if opts.DryRun {
+			applied[doc.Name] = desired
+			return it
+		}
+		if err = s.store.UpsertSpec(ctx, desired); err != nil {
+			it.Action, it.Err = ActionFailed, err
+			return it
+		}
+		applied[doc.Name] = desired
+		it.ID = id
+		return it
+	}
+
+	if current.SameDesiredState(desired) {
+		it.Action = ActionUnchanged
+		return it
+	}
+	it.Action = ActionUpdated
+	if !opts.DryRun {
+		next := current.Clone()
+		next.Assign(desired)
+		next.IncrementVersion()
+		if err = s.store.UpsertSpec(ctx, next); err != nil {
+			it.Action, it.Err = ActionFailed, err
+			return it
+		}
+	}
+	applied[doc.Name] = desired
+	return it
+}
+
+// lookup returns a spec getter that prefers the specs applied earlier in the manifest
+// over the stored ones.
+func (s *Service) lookup(applied map[string]*model.Spec) func(context.Context, string) (*model.Spec, error) {
+	return func(ctx context.Context, id string) (*model.Spec, error) {
+		for _, ts := range applied {
+			if ts.ID() == id {
+				return ts, nil
+			}
+		}
+		return s.store.GetSpec(ctx, id)
+	}
+}
+
+func (s *Service) applyLabels(ctx context.Context, doc Document, dryRun bool) Item {
+	it := Item{Kind: KindAgentLabels, Name: doc.Agent, ID: doc.Agent}
+	if doc.Agent == "" {
+		it.Action, it.Err = ActionFailed, domain.ErrEmptyID
+		return it
+	}
+
+	ag, err := s.store.GetAgent(ctx, doc.Agent)
+	if err != nil {
+		it.Action, it.Err = ActionFailed, err
+		return it
+	}
+	if ag.Name() != "" {
+		it.Name = ag.Name()
+	}
+
+	desired := make(map[string]string, len(doc.Labels))
+	for k, v := range doc.Labels {
+		if k != "" && v != "" {
+			desired[k] = v
+		}
+	}
+	if maps.Equal(ag.LabelsAll(), desired) {
+		it.Action = ActionUnchanged
+		return it
+	}
+
+	it.Action = ActionUpdated
+	if dryRun {
+		return it
+	}
+	if _, err = s.agents.PatchLabels(ctx, agent.PatchLabels{ID: doc.Agent, Labels: desired}); err != nil {
+		it.Action, it.Err = ActionFailed, err
+	}
+	return it
+}
+
+func (s *Service) prune(ctx context.Context, ts *model.Spec, dryRun bool) Item {
+	it := Item{Kind: KindSpec, Name: ts.Name(), ID: ts.ID(), Action: ActionDeleted}
+	if dryRun {
+		return it
+	}
+	if err := s.store.DeleteRolloutsBySpec(ctx, ts.ID()); err != nil {
+		it.Action, it.Err = ActionFailed, err
+		return it
+	}
+	if err := s.store.DeleteSpec(ctx, ts.ID()); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		it.Action, it.Err = ActionFailed, err
+	}
+	return it
+}
+
+// heldBy returns, by spec ID, the names of the kept specs depending on each prune
+// candidate. A candidate that is held is kept too, so the candidates it depends on
+// are held in turn. Kept specs are the applied ones, in their desired state, and the
+// stored specs that are not candidates.
+func heldBy(specs []*model.Spec, applied map[string]*model.Spec, candidates []*model.Spec) map[string][]string {
+	pruned := make(map[string]*model.Spec, len(candidates))
+	for _, ts := range candidates {
+		pruned[ts.ID()] = ts
+	}
+	appliedIDs := make(map[string]struct{}, len(applied))
+	kept := make([]*model.Spec, 0, len(specs)+len(applied))
+	for _, ts := range applied {
+		appliedIDs[ts.ID()] = struct{}{}
+		kept = append(kept, ts)
+	}
+	for _, ts := range specs {
+		_, isApplied := appliedIDs[ts.ID()]
+		_, isPruned := pruned[ts.ID()]
+		if !isApplied && !isPruned {
+			kept = append(kept, ts)
+		}
+	}
+
+	held := make(map[string][]string)
+	for len(kept) > 0 {
+		ts := kept[len(kept)-1]
+		kept = kept[:len(kept)-1]
+		for _, dep := range ts.DependsOn() {
+			d, ok := pruned[dep]
+			if !ok {
+				continue
+			}
+			if _, done := held[dep]; !done {
+				kept = append(kept, d)
+			}
+			held[dep] = append(held[dep], ts.Name())
+		}
+	}
+	return held
+}
+
+// resolveDependencies maps the spec names a document depends on to spec IDs, looking
+// them up among the specs applied earlier in the manifest, then the stored specs.
+//
+// Returns [domain.ErrDependencyMissing] for a name matching no spec, or more than one.
+func resolveDependencies(names []string, byName map[string][]*model.Spec, applied map[string]*model.Spec) ([]string, error) {
+	ids := make([]string, 0, len(names))
+	for _, name := range names {
+		if ts, ok := applied[name]; ok {
+			ids = append(ids, ts.ID())
+			continue
+		}
+		matches := byName[name]
+		if len(matches) != 1 {
+			return nil, fmt.Errorf("%w: %s", domain.ErrDependencyMissing, name)
+		}
+		ids = append(ids, matches[0].ID())
+	}
+	return ids, nil
+}
+
+// checkSecrets rejects invalid templates and references to unknown secrets,
+// or any secret reference when deny is set.
+func (s *Service) checkSecrets(ctx context.Context, ts *model.Spec, deny bool) error {
+	names, err := ts.SecretRefs()
+	if err != nil {
+		return err
+	}
+	if deny && len(names) > 0 {
+		return errors.New("referencing secrets requires the " + string(kind.SecretsGet) + " permission")
+	}
+	for _, name := range names {
+		if _, err = s.store.GetSecretByName(ctx, name); err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return errors.New("unknown secret: " + name)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// allSpecs lists every spec, following cursors across pages.
+func (s *Service) allSpecs(ctx context.Context) ([]*model.Spec, error) {
+	var (
+		out    []*model.Spec
+		cursor string
+	)
+	for {
+		res, err := s.store.ListSpecs(ctx, nil, storage.ListOptions{Limit: storage.MaxListLimit, Cursor: cursor})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, res.Items...)
+		if res.NextCursor == "" {
+			return out, nil
+		}
+		cursor = res.NextCursor
+	}
+}
@@ -0,0 +1,267 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/service/agent"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+func newTestService(t *testing.T) (*Service, *inmemory.Store) {
+	t.Helper()
+
+	store := inmemory.New()
+	return New(store, agent.New(store, zerolog.Nop()), zerolog.Nop()), store
+}
+
+// specDoc builds a valid spec document named name that depends on the named specs.
+func specDoc(t *testing.T, name string, deps ...string) Document {
+	t.Helper()
+
+	ts, err := model.NewSpec(name, name, name)
+	if err != nil {
+		t.Fatalf("new spec: %v", err)
+	}
+	ts.SetKindType(kind.TaskKindSubprocess)
+	ts.SetKindConfig(map[string]any{"command": "./" + name})
+	if len(deps) > 0 {
+		ts.SetDependsOn(deps)
+	}
+	return Document{Kind: KindSpec, Name: name, Spec: ts}
+}
+
+func apply(t *testing.T, s *Service, opts Options, docs ...Document) *Result {
+	t.Helper()
+
+	res, err := s.Apply(context.Background(), docs, opts)
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	return res
+}
+
+// specID returns the ID of the single stored spec named name.
+func specID(t *testing.T, store *inmemory.Store, name string) string {
+	t.Helper()
+
+	specs, err := New(store, agent.New(store, zerolog.Nop()), zerolog.Nop()).allSpecs(context.Background())
+	if err != nil {
+		t.Fatalf("list specs: %v", err)
+	}
+	for _, ts := range specs {
+		if ts.Name() == name {
+			return ts.ID()
+		}
+	}
+	t.Fatalf("spec %s not found", name)
+	return ""
+}
+
+func TestApply_DependsOnNames(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+
+	// "db" exists before the manifest; "cache" is created by an earlier document.
+	apply(t, s, Options{}, specDoc(t, "db"))
+	res := apply(t, s, Options{},
+		specDoc(t, "db"),
+		specDoc(t, "cache"),
+		specDoc(t, "web", "db", "cache"),
+	)
+	if res.Failed() != 0 {
+		t.Fatalf("apply failed: %+v", res.Items)
+	}
+
+	web, err := store.GetSpec(ctx, specID(t, store, "web"))
+	if err != nil {
+		t.Fatalf("get web: %v", err)
+	}
+	want := []string{specID(t, store, "db"), specID(t, store, "cache")}
+	if got := web.DependsOn(); !slices.Equal(got, want) {
+		t.Fatalf("depends_on = %v, want %v", got, want)
+	}
+
+	// Re-applying the same names leaves the spec unchanged.
+	res = apply(t, s, Options{}, specDoc(t, "web", "db", "cache"))
+	if got := res.Items[0].Action; got != ActionUnchanged {
+		t.Fatalf("re-apply action = %s, want unchanged", got)
+	}
+}
+
+func TestApply_DependsOnDryRun(t *testing.T) {
+	t.Parallel()
+
+	s, _ := newTestService(t)
+
+	res := apply(t, s, Options{DryRun: true}, specDoc(t, "db"), specDoc(t, "web", "db"))
+	if res.Failed() != 0 {
+		t.Fatalf("dry run failed: %+v", res.Items)
+	}
+}
+
+func TestApply_DependsOnUnknown(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		docs func(t *testing.T) []Document
+	}{
+		{
+			name: "unknown name",
+			docs: func(t *testing.T) []Document { return []Document{specDoc(t, "web", "db")} },
+		},
+		{
+			name: "declared later in the manifest",
+			docs: func(t *testing.T) []Document { return []Document{specDoc(t, "web", "db"), specDoc(t, "db")} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, _ := newTestService(t)
+			res := apply(t, s, Options{}, tt.docs(t)...)
+
+			it := res.Items[slices.IndexFunc(res.Items, func(it Item) bool { return it.Name == "web" })]
+			if it.Action != ActionFailed || !errors.Is(it.Err, domain.ErrDependencyMissing) {
+				t.Fatalf("web: action = %s, err = %v, want failed with ErrDependencyMissing", it.Action, it.Err)
+			}
+			if it.Fields["depends_on"] == "" {
+				t.Fatalf("web: fields = %v, want a depends_on error", it.Fields)
+			}
+		})
+	}
+}
+
+func TestApply_DependencyCycle(t *testing.T) {
+	t.Parallel()
+
+	for _, dryRun := range []bool{false, true} {
+		s, store := newTestService(t)
+		apply(t, s, Options{}, specDoc(t, "db"))
+
+		// "web" is created depending on "db", then "db" is edited to depend on "web".
+		res := apply(t, s, Options{DryRun: dryRun}, specDoc(t, "web", "db"), specDoc(t, "db", "web"))
+		it := res.Items[1]
+		if it.Action != ActionFailed || !errors.Is(it.Err, domain.ErrDependencyCycle) {
+			t.Fatalf("dry run %v: db: action = %s, err = %v, want failed with ErrDependencyCycle", dryRun, it.Action, it.Err)
+		}
+		if it.Fields["depends_on"] == "" {
+			t.Fatalf("dry run %v: db: fields = %v, want a depends_on error", dryRun, it.Fields)
+		}
+
+		db, err := store.GetSpec(context.Background(), specID(t, store, "db"))
+		if err != nil {
+			t.Fatalf("get db: %v", err)
+		}
+		if len(db.DependsOn()) != 0 {
+			t.Fatalf("dry run %v: cyclic edit was stored: %v", dryRun, db.DependsOn())
+		}
+	}
+}
+
+func TestApply_SpecDocumentErrors(t *testing.T) {
+	t.Parallel()
+
+	named := specDoc(t, "web")
+	named.Name = ""
+
+	tests := []struct {
+		name string
+		doc  Document
+		err  string
+	}{
+		{name: "missing spec body", doc: Document{Kind: KindSpec}, err: "missing spec"},
+		{name: "empty name", doc: named, err: domain.ErrEmptyName.Error()},
+		{name: "mapping error", doc: Document{Kind: KindSpec, Name: "web", Err: errors.New("bad slot")}, err: "bad slot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, _ := newTestService(t)
+			res := apply(t, s, Options{}, tt.doc)
+			if it := res.Items[0]; it.Action != ActionFailed || it.Err == nil || it.Err.Error() != tt.err {
+				t.Fatalf("action = %s, err = %v, want failed with %q", it.Action, it.Err, tt.err)
+			}
+		})
+	}
+}
+
+func TestApply_PruneKeepsDependencies(t *testing.T) {
+	t.Parallel()
+
+	for _, dryRun := range []bool{false, true} {
+		s, store := newTestService(t)
+		opts := Options{Owner: "gitops:test", Prune: true}
+		apply(t, s, opts, specDoc(t, "base"), specDoc(t, "db", "base"), specDoc(t, "cache"), specDoc(t, "web", "db"))
+
+		// "db" and "base" are left out but "web" still depends on them, directly or not.
+		opts.DryRun = dryRun
+		res := apply(t, s, opts, specDoc(t, "web", "db"))
+
+		actions := make(map[string]Item, len(res.Items))
+		for _, it := range res.Items {
+			actions[it.Name] = it
+		}
+		if it := actions["cache"]; it.Action != ActionDeleted {
+			t.Fatalf("dry run %v: cache: action = %s, err = %v, want deleted", dryRun, it.Action, it.Err)
+		}
+		for name, dependent := range map[string]string{"db": "web", "base": "db"} {
+			it := actions[name]
+			if it.Action != ActionFailed || !errors.Is(it.Err, domain.ErrDependencyInUse) {
+				t.Fatalf("dry run %v: %s: action = %s, err = %v, want failed with ErrDependencyInUse", dryRun, name, it.Action, it.Err)
+			}
+			if !strings.HasSuffix(it.Err.Error(), dependent) {
+				t.Fatalf("dry run %v: %s: err = %v, want naming %s", dryRun, name, it.Err, dependent)
+			}
+			specID(t, store, name) // still stored
+		}
+	}
+}
+
+func TestApply_AgentLabels(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	a, err := model.NewAgentFrom(model.AgentParams{ID: "a1", Name: "web-1", EndpointType: 1, APIVersion: 1})
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	a.LabelAdd("zone", "a")
+	if err = store.UpsertAgent(ctx, a); err != nil {
+		t.Fatalf("upsert agent: %v", err)
+	}
+
+	doc := Document{Kind: KindAgentLabels, Agent: "a1", Labels: map[string]string{"region": "eu"}}
+	if it := apply(t, s, Options{DryRun: true}, doc).Items[0]; it.Action != ActionUpdated || it.Name != "web-1" {
+		t.Fatalf("dry run: action = %s, name = %s, want updated web-1", it.Action, it.Name)
+	}
+	if it := apply(t, s, Options{}, doc).Items[0]; it.Action != ActionUpdated {
+		t.Fatalf("action = %s, err = %v, want updated", it.Action, it.Err)
+	}
+	got, err := store.GetAgent(ctx, "a1")
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	if want := map[string]string{"region": "eu"}; !maps.Equal(got.LabelsAll(), want) {
+		t.Fatalf("labels = %v, want %v", got.LabelsAll(), want)
+	}
+	if it := apply(t, s, Options{}, doc).Items[0]; it.Action != ActionUnchanged {
+		t.Fatalf("re-apply action = %s, want unchanged", it.Action)
+	}
+}
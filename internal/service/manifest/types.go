@@ -0,0 +1,81 @@
+package manifest
+
+import "github.com/soltiHQ/control-plane/domain/model"
+
+// Document kinds accepted in a manifest.
+const (
+	KindSpec        = "Spec"
+	KindAgentLabels = "AgentLabels"
+)
+
+// Document is one manifest object in domain form, as passed to [Service.Apply].
+//
+// For KindSpec, Spec holds the full desired state of the spec named Name; its ID,
+// version and timestamps are ignored, since Apply matches specs by name. A nil Spec
+// (the document had no spec body) is reported as a missing spec. For
+// KindAgentLabels, Agent and Labels describe the label assignment. Err is set when
+// the document could not be mapped to domain values; Apply reports it as the
+// document's failure.
+type Document struct {
+	Err    error
+	Spec   *model.Spec
+	Labels map[string]string
+
+	Kind  string
+	Name  string
+	Agent string
+}
+
+// Action is what Apply did (or would do, in a dry run) to one object.
+type Action string
+
+const (
+	ActionCreated   Action = "created"
+	ActionUpdated   Action = "updated"
+	ActionUnchanged Action = "unchanged"
+	ActionDeleted   Action = "deleted"
+	ActionFailed    Action = "failed"
+)
+
+// Options controls an Apply call.
+type Options struct {
+	// Prune deletes specs whose names do not appear in the manifest.
+	Prune bool
+	// DryRun computes the result without writing to the store.
+	DryRun bool
+	// Owner marks applied specs as managed by this source (e.g. "gitops:main").
+	// Specs owned by another source are never touched, and Prune only deletes
+	// specs with the same owner. Empty means unmanaged specs only.
+	Owner string
+	// DenySecrets fails specs that reference secrets; set it when the caller may not
+	// read secrets, since a spec renders them into what agents run.
+	DenySecrets bool
+}
+
+// Item is the outcome for one manifest object.
+type Item struct {
+	Err    error
+	Fields map[string]string // field-level validation errors, if any
+
+	Kind   string
+	Name   string
+	ID     string
+	Action Action
+}
+
+// Result lists per-object outcomes in manifest order, followed by pruned specs.
+type Result struct {
+	Items  []Item
+	DryRun bool
+}
+
+// Failed returns the number of objects that could not be applied.
+func (r *Result) Failed() int {
+	n := 0
+	for _, it := range r.Items {
+		if it.Action == ActionFailed {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,165 @@
+// Package run implements ad-hoc run use-cases:
+//   - Paginated listing and retrieval
+//   - Creation with target resolution (explicit agent IDs and/or a label selector)
+//   - Deletion.
+//
+// Runs are executed by the oneshot runner; this package only records them.
+package run
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/service"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+// Service provides run management operations.
+type Service struct {
+	logger zerolog.Logger
+	store  storage.Storage
+}
+
+// New creates a new run service.
+func New(store storage.Storage, logger zerolog.Logger) *Service {
+	if store == nil {
+		panic("run.Service: store is nil")
+	}
+	return &Service{
+		logger: logger.With().Str("service", "runs").Logger(),
+		store:  store,
+	}
+}
+
+// List returns a page of runs matching the query.
+func (s *Service) List(ctx context.Context, q ListQuery) (*Page, error) {
+	res, err := s.store.ListRuns(ctx, q.Filter, storage.ListOptions{
+		Limit:  service.NormalizeListLimit(q.Limit, defaultListLimit),
+		Cursor: q.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*model.Run, 0, len(res.Items))
+	for _, r := range res.Items {
+		if r == nil {
+			continue
+		}
+		out = append(out, r.Clone())
+	}
+	return &Page{Items: out, NextCursor: res.NextCursor}, nil
+}
+
+// Get returns a single run by ID.
+func (s *Service) Get(ctx context.Context, id string) (*model.Run, error) {
+	if id == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+	r, err := s.store.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.Clone(), nil
+}
+
+// Create resolves the targets of a run and persists it.
+//
+// Targets are the union of the explicit agent IDs and every agent matching the
+// run's label selector, in that order. An unknown explicit agent returns
+// [domain.ErrUnknownTarget], an unapproved one [domain.ErrAgentNotApproved] and a
+// cordoned one [domain.ErrAgentCordoned]; unapproved and cordoned selector matches
+// are left out. No targets at all returns [domain.ErrNoTargets].
+func (s *Service) Create(ctx context.Context, r *model.Run, agentIDs []string) error {
+	if r == nil {
+		return storage.ErrInvalidArgument
+	}
+
+	targets, err := s.resolveTargets(ctx, agentIDs, r.TargetLabels())
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return domain.ErrNoTargets
+	}
+	r.SetTargets(targets)
+
+	if err = s.store.UpsertRun(ctx, r); err != nil {
+		return err
+	}
+
+	s.logger.Debug().
+		Str("run_id", r.ID()).
+		Int("targets", len(targets)).
+		Msg("run created")
+	return nil
+}
+
+// Delete removes a run by ID.
+//
+// Tasks already submitted keep running on the agents; only the record is removed.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return storage.ErrInvalidArgument
+	}
+	if err := s.store.DeleteRun(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Debug().Str("run_id", id).Msg("run deleted")
+	return nil
+}
+
+// resolveTargets returns the deduplicated agent IDs named explicitly or matched by selector.
+func (s *Service) resolveTargets(ctx context.Context, agentIDs []string, selector map[string]string) ([]string, error) {
+	var (
+		seen = make(map[string]struct{}, len(agentIDs))
+		out  = make([]string, 0, len(agentIDs))
+	)
+	for _, id := range agentIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		ag, err := s.store.GetAgent(ctx, id)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, fmt.Errorf("%w: %s", domain.ErrUnknownTarget, id)
+			}
+			return nil, err
+		}
+		if err = ag.CheckApproval(); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, id)
+		}
+		if err = ag.CheckCordon(); err != nil {
+			return nil, fmt.Errorf("%w: %s", err, id)
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	if len(selector) == 0 {
+		return out, nil
+	}
+
+	var cursor string
+	for {
+		res, err := s.store.ListAgents(ctx, nil, storage.ListOptions{Cursor: cursor, Limit: storage.MaxListLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, ag := range res.Items {
+			if _, ok := seen[ag.ID()]; ok || !ag.MatchesLabels(selector) || ag.CheckApproval() != nil || ag.Cordoned() {
+				continue
+			}
+			seen[ag.ID()] = struct{}{}
+			out = append(out, ag.ID())
+		}
+		if res.NextCursor == "" {
+			return out, nil
+		}
+		cursor = res.NextCursor
+	}
+}
@@ -0,0 +1,21 @@
+package run
+
+import (
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+const defaultListLimit = 30
+
+// ListQuery describes a paginated run listing request.
+type ListQuery struct {
+	Filter storage.RunFilter
+	Cursor string
+	Limit  int
+}
+
+// Page is a paginated run listing result.
+type Page struct {
+	Items      []*model.Run
+	NextCursor string
+}
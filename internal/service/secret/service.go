@@ -0,0 +1,137 @@
+// Package secret implements secret management use-cases:
+//   - Paginated listing and retrieval (by ID or name)
+//   - Creation with unique names, update, and deletion.
+//
+// Secret values are returned to callers inside the control-plane only;
+// transport layers must never expose them.
+package secret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/service"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+// Service provides secret management operations.
+type Service struct {
+	logger zerolog.Logger
+	store  storage.SecretStore
+}
+
+// New creates a new secret service.
+func New(store storage.SecretStore, logger zerolog.Logger) *Service {
+	if store == nil {
+		panic("secret.Service: store is nil")
+	}
+	return &Service{
+		logger: logger.With().Str("service", "secrets").Logger(),
+		store:  store,
+	}
+}
+
+// List returns a page of secrets matching the query.
+func (s *Service) List(ctx context.Context, q ListQuery) (*Page, error) {
+	res, err := s.store.ListSecrets(ctx, q.Filter, storage.ListOptions{
+		Limit:  service.NormalizeListLimit(q.Limit, defaultListLimit),
+		Cursor: q.Cursor,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*model.Secret, 0, len(res.Items))
+	for _, sec := range res.Items {
+		if sec == nil {
+			continue
+		}
+		out = append(out, sec.Clone())
+	}
+	return &Page{Items: out, NextCursor: res.NextCursor}, nil
+}
+
+// Get returns a single secret by ID.
+func (s *Service) Get(ctx context.Context, id string) (*model.Secret, error) {
+	if id == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+	sec, err := s.store.GetSecret(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return sec.Clone(), nil
+}
+
+// GetByName returns a single secret by its unique name.
+func (s *Service) GetByName(ctx context.Context, name string) (*model.Secret, error) {
+	if name == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+	sec, err := s.store.GetSecretByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return sec.Clone(), nil
+}
+
+// Create persists a new secret.
+//
+// Returns storage.ErrAlreadyExists if a secret with the same name exists.
+func (s *Service) Create(ctx context.Context, sec *model.Secret) error {
+	if sec == nil {
+		return storage.ErrInvalidArgument
+	}
+
+	if err := s.store.CreateSecret(ctx, sec); err != nil {
+		return err
+	}
+
+	s.logger.Debug().Str("secret_id", sec.ID()).Str("name", sec.Name()).Msg("secret created")
+	return nil
+}
+
+// Update persists changes to an existing secret.
+func (s *Service) Update(ctx context.Context, sec *model.Secret) error {
+	if sec == nil {
+		return storage.ErrInvalidArgument
+	}
+	if _, err := s.store.GetSecret(ctx, sec.ID()); err != nil {
+		return err
+	}
+	if err := s.store.UpsertSecret(ctx, sec); err != nil {
+		return err
+	}
+
+	s.logger.Debug().Str("secret_id", sec.ID()).Msg("secret updated")
+	return nil
+}
+
+// Delete removes a secret by ID.
+func (s *Service) Delete(ctx context.Context, id string) error {
+	if id == "" {
+		return storage.ErrInvalidArgument
+	}
+	if err := s.store.DeleteSecret(ctx, id); err != nil {
+		return err
+	}
+
+	s.logger.Debug().Str("secret_id", id).Msg("secret deleted")
+	return nil
+}
+
+// Resolver returns a model.SecretResolver reading secret values from store, for
+// rendering the specs pushed or pulled to agents.
+//
+// Values only live in the rendered payload; errors name the secret, never its value.
+func Resolver(ctx context.Context, store storage.SecretStore) model.SecretResolver {
+	return func(name string) (string, error) {
+		sec, err := store.GetSecretByName(ctx, name)
+		if err != nil {
+			return "", fmt.Errorf("secret %q: %w", name, err)
+		}
+		return sec.Value(), nil
+	}
+}
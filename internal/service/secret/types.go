@@ -0,0 +1,21 @@
+package secret
+
+import (
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+const defaultListLimit = 30
+
+// ListQuery describes a paginated secret listing request.
+type ListQuery struct {
+	Filter storage.SecretFilter
+	Cursor string
+	Limit  int
+}
+
+// Page is a paginated secret listing result.
+type Page struct {
+	Items      []*model.Secret
+	NextCursor string
+}
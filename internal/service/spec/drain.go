@@ -0,0 +1,117 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+// Drain removes every rollout of an agent selected by filter, so no spec is pushed to it anymore.
+//
+// Like [Service.Undeploy], tasks already running on the agent are left in place.
+// With retarget, every drained spec that selects agents by labels gets a pending rollout
+// on each agent it is planned to deploy to but has no rollout on yet; existing rollouts
+// (including their synced versions) and the drained agent are left alone. The caller
+// is expected to have cordoned the agent first. A spec that fails to retarget is
+// reported in [DrainResult.Failed] and does not stop the drain.
+func (s *Service) Drain(ctx context.Context, agentID string, retarget bool, filter storage.RolloutFilter) (*DrainResult, error) {
+	if agentID == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+	out := &DrainResult{Undeployed: make([]string, 0), Failed: make(map[string]string)}
+
+	var ids []string
+	for cursor := ""; ; {
+		res, err := s.store.ListRollouts(ctx, filter, storage.ListOptions{Cursor: cursor, Limit: storage.MaxListLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, ro := range res.Items {
+			if ro.AgentID() != agentID {
+				continue
+			}
+			ids = append(ids, ro.ID())
+			out.Undeployed = append(out.Undeployed, ro.SpecID())
+		}
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+	for _, id := range ids {
+		if err := s.store.DeleteRollout(ctx, id); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+	}
+	sort.Strings(out.Undeployed)
+
+	if retarget {
+		for _, specID := range out.Undeployed {
+			ts, err := s.store.GetSpec(ctx, specID)
+			if err != nil {
+				if errors.Is(err, storage.ErrNotFound) {
+					continue
+				}
+				return nil, err
+			}
+			if len(ts.TargetLabels()) == 0 {
+				continue
+			}
+			added, err := s.retarget(ctx, ts, agentID)
+			if err != nil {
+				out.Failed[specID] = err.Error()
+				continue
+			}
+			if added > 0 {
+				out.Retargeted = append(out.Retargeted, specID)
+			}
+		}
+	}
+
+	s.logger.Debug().
+		Str("agent_id", agentID).
+		Int("undeployed", len(out.Undeployed)).
+		Int("retargeted", len(out.Retargeted)).
+		Msg("agent drained")
+	return out, nil
+}
+
+// retarget creates pending rollouts of ts on the agents its plan deploys to that have
+// no rollout of it yet, skipping the drained agent, and returns how many were created.
+func (s *Service) retarget(ctx context.Context, ts *model.Spec, drainedID string) (int, error) {
+	if err := s.checkDependencies(ctx, ts); err != nil {
+		return 0, err
+	}
+	plan, err := s.plan(ctx, ts)
+	if err != nil {
+		return 0, err
+	}
+
+	var added int
+	for _, e := range plan.Entries {
+		if e.Action != PlanDeploy || e.AgentID == drainedID {
+			continue
+		}
+		_, err = s.store.GetRollout(ctx, model.RolloutID(ts.ID(), e.AgentID))
+		switch {
+		case err == nil:
+			continue
+		case !errors.Is(err, storage.ErrNotFound):
+			return added, err
+		}
+
+		ro, err := model.NewRollout(ts.ID(), e.AgentID, ts.Version())
+		if err != nil {
+			return added, err
+		}
+		if err = s.store.UpsertRollout(ctx, ro); err != nil {
+			return added, err
+		}
+		added++
+		s.logger.Trace().Str("spec_id", ts.ID()).Str("agent_id", e.AgentID).Msg("rollout retargeted")
+	}
+	return added, nil
+}
@@ -0,0 +1,92 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+func TestDrain_RetargetOnlyAddsNewAgents(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	web := map[string]string{"role": "web"}
+
+	mkAgent(t, store, "a1", web)
+	mkAgent(t, store, "a2", web)
+	mkSpec(t, store, "s1", func(ts *model.Spec) {
+		ts.SetTargets([]string{"a1"})
+		ts.SetTargetLabels(web)
+	})
+	if _, err := s.Deploy(ctx, "s1"); err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+
+	synced := getRollout(t, store, "s1", "a2")
+	synced.MarkSynced(1)
+	if err := store.UpsertRollout(ctx, synced); err != nil {
+		t.Fatalf("upsert rollout: %v", err)
+	}
+	mkAgent(t, store, "a3", web)
+
+	drained := getRollout(t, store, "s1", "a1")
+	a1, err := store.GetAgent(ctx, "a1")
+	if err != nil {
+		t.Fatalf("get agent: %v", err)
+	}
+	a1.SetCordoned(true)
+	if err = store.UpsertAgent(ctx, a1); err != nil {
+		t.Fatalf("upsert agent: %v", err)
+	}
+
+	res, err := s.Drain(ctx, "a1", true, inmemory.NewRolloutFilter().ByAgentID("a1"))
+	if err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	if len(res.Undeployed) != 1 || res.Undeployed[0] != "s1" {
+		t.Fatalf("expected s1 undeployed, got %v", res.Undeployed)
+	}
+	if len(res.Retargeted) != 1 || res.Retargeted[0] != "s1" {
+		t.Fatalf("expected s1 retargeted, got %v (failed %v)", res.Retargeted, res.Failed)
+	}
+
+	if _, err = store.GetRollout(ctx, drained.ID()); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected no rollout on the drained agent, err=%v", err)
+	}
+	if ro := getRollout(t, store, "s1", "a2"); ro.Status() != kind.SyncStatusSynced {
+		t.Fatalf("expected synced rollout on a2 to be left alone, got %s", ro.Status())
+	}
+	if ro := getRollout(t, store, "s1", "a3"); ro.Status() != kind.SyncStatusPending {
+		t.Fatalf("expected pending rollout on a3, got %s", ro.Status())
+	}
+}
+
+func TestDrain_RetargetSkipsExplicitSpecs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+
+	mkAgent(t, store, "a1", nil)
+	mkSpec(t, store, "s1", func(ts *model.Spec) { ts.SetTargets([]string{"a1"}) })
+	if _, err := s.Deploy(ctx, "s1"); err != nil {
+		t.Fatalf("deploy: %v", err)
+	}
+
+	res, err := s.Drain(ctx, "a1", true, inmemory.NewRolloutFilter().ByAgentID("a1"))
+	if err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+	if len(res.Retargeted) != 0 || len(res.Failed) != 0 {
+		t.Fatalf("expected nothing retargeted, got %v (failed %v)", res.Retargeted, res.Failed)
+	}
+	if _, err = store.GetRollout(ctx, model.RolloutID("s1", "a1")); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected no rollout on the drained agent, err=%v", err)
+	}
+}
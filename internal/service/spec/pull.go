@@ -0,0 +1,225 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/service/secret"
+	"github.com/soltiHQ/control-plane/internal/storage"
+)
+
+// Pull applies the acknowledgements of a pull-mode agent and returns its desired specs.
+//
+// Acknowledgements move the matching rollout to synced, or to failed with the agent's
+// error; acks for another version than the desired one are stale and ignored.
+// Repeated acks do not change a rollout again.
+//
+// The desired set holds every rollout of the agent selected by filter that is not
+// excluded, ordered by dependency depth, then spec priority (highest first), then
+// spec ID. Rollouts whose agent can no longer run the spec are excluded, and those
+// whose spec fails to render are failed; both are left out of the set.
+// Agents that are not approved get an empty set.
+func (s *Service) Pull(ctx context.Context, ag *model.Agent, acks []Ack, filter storage.RolloutFilter) (*PullResult, error) {
+	if ag == nil {
+		return nil, storage.ErrInvalidArgument
+	}
+	out := &PullResult{}
+
+	for _, ack := range acks {
+		if err := s.acknowledge(ctx, ag.ID(), ack, out); err != nil {
+			return nil, err
+		}
+	}
+	if ag.CheckApproval() != nil {
+		return out, nil
+	}
+
+	var (
+		rollouts []*model.Rollout
+		cursor   string
+	)
+	for {
+		res, err := s.store.ListRollouts(ctx, filter, storage.ListOptions{Cursor: cursor, Limit: storage.MaxListLimit})
+		if err != nil {
+			return nil, err
+		}
+		rollouts = append(rollouts, res.Items...)
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+
+	var (
+		depths = make(map[string]int)
+		specs  = make(map[string]*model.Spec)
+	)
+	for _, ro := range rollouts {
+		if ro == nil || ro.AgentID() != ag.ID() || ro.Status() == kind.SyncStatusExcluded {
+			continue
+		}
+		ts, err := s.store.GetSpec(ctx, ro.SpecID())
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+
+		if err = ts.CheckPlacement(ag); err != nil {
+			ro.MarkExcluded(err.Error())
+			if err = s.store.UpsertRollout(ctx, ro); err != nil {
+				return nil, err
+			}
+			out.Changed = true
+			continue
+		}
+		payload, err := ts.RenderCreateSpec(ag, secret.Resolver(ctx, s.store))
+		if err != nil {
+			if err = s.fail(ctx, ro, ts, "template error: "+err.Error(), out); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		specs[ts.ID()] = ts
+		out.Specs = append(out.Specs, Desired{SpecID: ts.ID(), Version: ro.DesiredVersion(), Spec: payload})
+		s.depth(ctx, depths, ts.ID())
+	}
+
+	sort.SliceStable(out.Specs, func(i, j int) bool {
+		a, b := out.Specs[i].SpecID, out.Specs[j].SpecID
+		if depths[a] != depths[b] {
+			return depths[a] < depths[b]
+		}
+		if pa, pb := specs[a].Priority(), specs[b].Priority(); pa != pb {
+			return pa > pb
+		}
+		return a < b
+	})
+
+	s.logger.Trace().
+		Str("agent_id", ag.ID()).
+		Int("acks", len(acks)).
+		Int("specs", len(out.Specs)).
+		Msg("pull served")
+	return out, nil
+}
+
+// acknowledge applies one agent acknowledgement to its rollout.
+func (s *Service) acknowledge(ctx context.Context, agentID string, ack Ack, out *PullResult) error {
+	ro, err := s.store.GetRollout(ctx, model.RolloutID(ack.SpecID, agentID))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if ro.Status() == kind.SyncStatusExcluded || ack.Version != ro.DesiredVersion() {
+		return nil
+	}
+
+	if ack.Error != "" {
+		ts, err := s.store.GetSpec(ctx, ack.SpecID)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		return s.fail(ctx, ro, ts, "agent error: "+ack.Error, out)
+	}
+
+	if ro.Status() == kind.SyncStatusSynced && ro.ActualVersion() == ack.Version {
+		return nil
+	}
+	ro.MarkSynced(ack.Version)
+	if err = s.store.UpsertRollout(ctx, ro); err != nil {
+		return err
+	}
+	out.Changed = true
+
+	s.logger.Debug().
+		Str("spec_id", ack.SpecID).
+		Str("agent_id", agentID).
+		Int("version", ack.Version).
+		Msg("spec applied by agent")
+	return nil
+}
+
+// Fail marks the rollout behind a desired spec returned by [Service.Pull] failed with msg,
+// for specs the caller could not deliver to the agent (e.g. the transport cannot encode
+// the payload). Like the failures Pull finds itself, the spec is reported in
+// [PullResult.Failed] unless the rollout already failed with the same message.
+// Rollouts that are gone or whose desired version moved on are left alone.
+func (s *Service) Fail(ctx context.Context, agentID string, d Desired, msg string) (*PullResult, error) {
+	if agentID == "" || d.SpecID == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+	out := &PullResult{}
+
+	ro, err := s.store.GetRollout(ctx, model.RolloutID(d.SpecID, agentID))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return out, nil
+		}
+		return nil, err
+	}
+	if ro.DesiredVersion() != d.Version || ro.Status() == kind.SyncStatusExcluded {
+		return out, nil
+	}
+	ts, err := s.store.GetSpec(ctx, d.SpecID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return out, nil
+		}
+		return nil, err
+	}
+	if err = s.fail(ctx, ro, ts, msg, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// fail marks a rollout failed unless it already failed with the same message.
+func (s *Service) fail(ctx context.Context, ro *model.Rollout, ts *model.Spec, msg string, out *PullResult) error {
+	if ro.Status() == kind.SyncStatusFailed && ro.Error() == msg {
+		return nil
+	}
+	ro.MarkFailed(msg)
+	if err := s.store.UpsertRollout(ctx, ro); err != nil {
+		return err
+	}
+	out.Changed = true
+	out.Failed = append(out.Failed, ts)
+
+	s.logger.Debug().
+		Str("spec_id", ro.SpecID()).
+		Str("agent_id", ro.AgentID()).
+		Str("error", msg).
+		Msg("pull rollout failed")
+	return nil
+}
+
+// depth returns the length of the longest dependency chain below a spec.
+// A cycle (rejected at deploy time, but possible after later edits) is cut at the revisited spec.
+func (s *Service) depth(ctx context.Context, cache map[string]int, specID string) int {
+	if d, ok := cache[specID]; ok {
+		return d
+	}
+	cache[specID] = 0
+
+	ts, err := s.store.GetSpec(ctx, specID)
+	if err != nil {
+		return 0
+	}
+	var d int
+	for _, dep := range ts.DependsOn() {
+		d = max(d, s.depth(ctx, cache, dep)+1)
+	}
+	cache[specID] = d
+	return d
+}
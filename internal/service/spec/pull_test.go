@@ -0,0 +1,222 @@
+package spec
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+// deployTo creates a pending rollout of each spec on agentID at the spec's version.
+func deployTo(t *testing.T, store *inmemory.Store, agentID string, specs ...*model.Spec) {
+	t.Helper()
+
+	for _, ts := range specs {
+		ro, err := model.NewRollout(ts.ID(), agentID, ts.Version())
+		if err != nil {
+			t.Fatalf("new rollout: %v", err)
+		}
+		if err = store.UpsertRollout(context.Background(), ro); err != nil {
+			t.Fatalf("upsert rollout: %v", err)
+		}
+	}
+}
+
+func pull(t *testing.T, s *Service, ag *model.Agent, acks ...Ack) *PullResult {
+	t.Helper()
+
+	res, err := s.Pull(context.Background(), ag, acks, inmemory.NewRolloutFilter().ByAgentID(ag.ID()))
+	if err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	return res
+}
+
+func desiredIDs(res *PullResult) []string {
+	ids := make([]string, 0, len(res.Specs))
+	for _, d := range res.Specs {
+		ids = append(ids, d.SpecID)
+	}
+	return ids
+}
+
+func TestPull_Order(t *testing.T) {
+	t.Parallel()
+
+	s, store := newTestService(t)
+	ag := mkAgent(t, store, "a1", nil)
+	prio := func(p int, deps ...string) func(*model.Spec) {
+		return func(ts *model.Spec) {
+			ts.SetPriority(p)
+			ts.SetDependsOn(deps)
+		}
+	}
+
+	deployTo(t, store, "a1",
+		mkSpec(t, store, "app", prio(10, "base")),
+		mkSpec(t, store, "base", prio(0)),
+		mkSpec(t, store, "lo", prio(1)),
+		mkSpec(t, store, "alpha", prio(1)),
+		mkSpec(t, store, "hi", prio(5)),
+	)
+	mkSpec(t, store, "other", nil)
+	deployTo(t, store, "a2", mkSpec(t, store, "elsewhere", nil))
+
+	got := desiredIDs(pull(t, s, ag))
+	want := []string{"hi", "alpha", "lo", "base", "app"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("desired order = %v, want %v", got, want)
+	}
+}
+
+func TestPull_Acks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	ag := mkAgent(t, store, "a1", nil)
+	ts := mkSpec(t, store, "s1", nil)
+	deployTo(t, store, "a1", ts)
+
+	// An ack for another version is stale and ignored.
+	res := pull(t, s, ag, Ack{SpecID: "s1", Version: ts.Version() + 1})
+	if res.Changed {
+		t.Fatal("stale ack changed the rollout")
+	}
+	if ro := getRollout(t, store, "s1", "a1"); ro.Status() != kind.SyncStatusPending {
+		t.Fatalf("expected pending after stale ack, got %s", ro.Status())
+	}
+
+	// An ack with an error fails the rollout once.
+	res = pull(t, s, ag, Ack{SpecID: "s1", Version: ts.Version(), Error: "exit 1"})
+	if !res.Changed || len(res.Failed) != 1 || res.Failed[0].ID() != "s1" {
+		t.Fatalf("expected s1 failed, got changed=%v failed=%v", res.Changed, res.Failed)
+	}
+	ro := getRollout(t, store, "s1", "a1")
+	if ro.Status() != kind.SyncStatusFailed || !strings.Contains(ro.Error(), "exit 1") {
+		t.Fatalf("expected failed rollout with agent error, got %s %q", ro.Status(), ro.Error())
+	}
+	res = pull(t, s, ag, Ack{SpecID: "s1", Version: ts.Version(), Error: "exit 1"})
+	if res.Changed || len(res.Failed) != 0 {
+		t.Fatalf("repeated error ack changed the rollout: changed=%v failed=%v", res.Changed, res.Failed)
+	}
+
+	// A successful ack syncs it; repeating it changes nothing.
+	res = pull(t, s, ag, Ack{SpecID: "s1", Version: ts.Version()})
+	if !res.Changed {
+		t.Fatal("expected successful ack to change the rollout")
+	}
+	if ro = getRollout(t, store, "s1", "a1"); ro.Status() != kind.SyncStatusSynced || ro.ActualVersion() != ts.Version() {
+		t.Fatalf("expected synced at version %d, got %s v%d", ts.Version(), ro.Status(), ro.ActualVersion())
+	}
+	if res = pull(t, s, ag, Ack{SpecID: "s1", Version: ts.Version()}); res.Changed {
+		t.Fatal("repeated ack changed the rollout")
+	}
+
+	// Acks for unknown specs are ignored.
+	if _, err := s.Pull(ctx, ag, []Ack{{SpecID: "gone", Version: 1}}, inmemory.NewRolloutFilter().ByAgentID("a1")); err != nil {
+		t.Fatalf("ack for unknown spec: %v", err)
+	}
+}
+
+func TestPull_Unapproved(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	ag := mkAgent(t, store, "a1", nil)
+	ts := mkSpec(t, store, "s1", nil)
+	deployTo(t, store, "a1", ts)
+
+	for _, approval := range []kind.AgentApproval{kind.AgentApprovalPending, kind.AgentApprovalRejected} {
+		ag.SetApproval(approval)
+		if err := store.UpsertAgent(ctx, ag); err != nil {
+			t.Fatalf("upsert agent: %v", err)
+		}
+		if res := pull(t, s, ag); len(res.Specs) != 0 {
+			t.Fatalf("%s agent got specs %v", approval, desiredIDs(res))
+		}
+	}
+
+	// Acks of an unapproved agent are still applied.
+	pull(t, s, ag, Ack{SpecID: "s1", Version: ts.Version()})
+	if ro := getRollout(t, store, "s1", "a1"); ro.Status() != kind.SyncStatusSynced {
+		t.Fatalf("expected ack to be applied, got %s", ro.Status())
+	}
+}
+
+func TestPull_ExcludesAndFails(t *testing.T) {
+	t.Parallel()
+
+	s, store := newTestService(t)
+	ag := mkAgent(t, store, "a1", nil)
+
+	deployTo(t, store, "a1",
+		mkSpec(t, store, "ok", nil),
+		mkSpec(t, store, "windows", func(ts *model.Spec) { ts.SetPlacement([]string{"os = windows"}) }),
+		mkSpec(t, store, "broken", func(ts *model.Spec) {
+			ts.SetKindConfig(map[string]any{"command": `{{ secret "missing" }}`})
+		}),
+	)
+
+	res := pull(t, s, ag)
+	if got := desiredIDs(res); !slices.Equal(got, []string{"ok"}) {
+		t.Fatalf("desired = %v, want [ok]", got)
+	}
+	if !res.Changed || len(res.Failed) != 1 || res.Failed[0].ID() != "broken" {
+		t.Fatalf("expected broken failed, got changed=%v failed=%v", res.Changed, res.Failed)
+	}
+	if ro := getRollout(t, store, "windows", "a1"); ro.Status() != kind.SyncStatusExcluded {
+		t.Fatalf("expected windows excluded, got %s", ro.Status())
+	}
+	if ro := getRollout(t, store, "broken", "a1"); ro.Status() != kind.SyncStatusFailed || !strings.HasPrefix(ro.Error(), "template error:") {
+		t.Fatalf("expected broken failed with template error, got %s %q", ro.Status(), ro.Error())
+	}
+
+	// The next pull neither repeats the failure nor serves the excluded spec.
+	res = pull(t, s, ag)
+	if res.Changed || len(res.Failed) != 0 {
+		t.Fatalf("second pull changed rollouts: changed=%v failed=%v", res.Changed, res.Failed)
+	}
+}
+
+func TestFail(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	mkAgent(t, store, "a1", nil)
+	ts := mkSpec(t, store, "s1", nil)
+	deployTo(t, store, "a1", ts)
+	d := Desired{SpecID: "s1", Version: ts.Version()}
+
+	stale, err := s.Fail(ctx, "a1", Desired{SpecID: "s1", Version: ts.Version() + 1}, "encode error")
+	if err != nil {
+		t.Fatalf("fail stale: %v", err)
+	}
+	if stale.Changed {
+		t.Fatal("failing a stale version changed the rollout")
+	}
+
+	res, err := s.Fail(ctx, "a1", d, "encode error")
+	if err != nil {
+		t.Fatalf("fail: %v", err)
+	}
+	if !res.Changed || len(res.Failed) != 1 {
+		t.Fatalf("expected s1 failed, got changed=%v failed=%v", res.Changed, res.Failed)
+	}
+	if ro := getRollout(t, store, "s1", "a1"); ro.Status() != kind.SyncStatusFailed || ro.Error() != "encode error" {
+		t.Fatalf("expected failed rollout, got %s %q", ro.Status(), ro.Error())
+	}
+
+	if res, err = s.Fail(ctx, "a1", d, "encode error"); err != nil || res.Changed {
+		t.Fatalf("repeated failure changed the rollout: changed=%v err=%v", res.Changed, err)
+	}
+	if res, err = s.Fail(ctx, "a2", d, "encode error"); err != nil || res.Changed {
+		t.Fatalf("failing a missing rollout: changed=%v err=%v", res.Changed, err)
+	}
+}
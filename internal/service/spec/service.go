@@ -1,14 +1,23 @@
 // Package spec implements task spec management use-cases:
 //   - Paginated listing and retrieval
-//   - Creation, update with version increment, and deletion
-//   - Deployment (rollout creation for target agents)
-//   - Rollout querying by spec.
+//   - Creation, update with version increment, and deletion (refused while other specs depend on the spec)
+//   - Deployment planning (explicit and label-selected targets, placement checks)
+//   - Deployment (rollout creation for planned agents, dependency cycle checks)
+//   - Rollout querying by spec
+//   - Pull mode: desired specs for agents that cannot be dialed, rollout state from their acks
+//   - Draining an agent's rollouts, optionally re-targeting label-selected specs.
 package spec
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/rs/zerolog"
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/kind"
 	"github.com/soltiHQ/control-plane/domain/model"
 	"github.com/soltiHQ/control-plane/internal/service"
 	"github.com/soltiHQ/control-plane/internal/storage"
@@ -67,10 +76,15 @@ func (s *Service) Get(ctx context.Context, id string) (*model.Spec, error) {
 }
 
 // Create persists a new spec.
+//
+// Its dependencies are checked like in [Service.Deploy].
 func (s *Service) Create(ctx context.Context, ts *model.Spec) error {
 	if ts == nil {
 		return storage.ErrInvalidArgument
 	}
+	if err := s.checkDependencies(ctx, ts); err != nil {
+		return err
+	}
 	if err := s.store.UpsertSpec(ctx, ts); err != nil {
 		return err
 	}
@@ -80,6 +94,9 @@ func (s *Service) Create(ctx context.Context, ts *model.Spec) error {
 }
 
 // Upsert persists changes to an existing task spec and increments its version.
+//
+// Its dependencies are checked like in [Service.Deploy], so an edit cannot close a cycle
+// between specs that are already deployed.
 func (s *Service) Upsert(ctx context.Context, ts *model.Spec) error {
 	if ts == nil {
 		return storage.ErrInvalidArgument
@@ -88,6 +105,9 @@ func (s *Service) Upsert(ctx context.Context, ts *model.Spec) error {
 	if _, err := s.store.GetSpec(ctx, ts.ID()); err != nil {
 		return err
 	}
+	if err := s.checkDependencies(ctx, ts); err != nil {
+		return err
+	}
 	ts.IncrementVersion()
 	if err := s.store.UpsertSpec(ctx, ts); err != nil {
 		return err
@@ -101,10 +121,15 @@ func (s *Service) Upsert(ctx context.Context, ts *model.Spec) error {
 }
 
 // Delete removes a task spec and all associated rollouts.
+//
+// Returns [domain.ErrDependencyInUse] while other specs depend on it.
 func (s *Service) Delete(ctx context.Context, id string) error {
 	if id == "" {
 		return storage.ErrInvalidArgument
 	}
+	if err := s.checkDependents(ctx, id); err != nil {
+		return err
+	}
 	if err := s.store.DeleteRolloutsBySpec(ctx, id); err != nil {
 		return err
 	}
@@ -152,48 +177,248 @@ func (s *Service) RolloutsBySpec(ctx context.Context, specID string, filter stor
 	return out, nil
 }
 
-// Deploy initiates distribution of a spec to all its target agents.
+// Plan resolves the agents a spec would be deployed to without changing any state.
+//
+// Agents are the spec's explicit targets followed by every agent matching its
+// target label selector. Each known agent is checked with [model.Agent.CheckApproval]
+// and [model.Spec.CheckPlacement]: explicit targets that fail are excluded, selector
+// matches that fail are skipped. Explicit targets pending approval are deployed;
+// the sync runner holds their rollouts until the agent is approved.
+//
+// Cordoned agents are treated the same way unless they already have a rollout of the spec:
+// a cordon stops new work from landing on an agent but leaves its rollouts in place.
+func (s *Service) Plan(ctx context.Context, specID string) (*Plan, error) {
+	ts, err := s.store.GetSpec(ctx, specID)
+	if err != nil {
+		return nil, err
+	}
+	return s.plan(ctx, ts)
+}
+
+func (s *Service) plan(ctx context.Context, ts *model.Spec) (*Plan, error) {
+	var (
+		plan = &Plan{SpecID: ts.ID()}
+		seen = make(map[string]struct{})
+	)
+	decide := func(ag *model.Agent, id string, selected bool) error {
+		seen[id] = struct{}{}
+		e := PlanEntry{Agent: ag, AgentID: id, Selected: selected, Action: PlanDeploy}
+		if ag != nil {
+			err := ag.CheckApproval()
+			if err == nil || (!selected && ag.Approval() == kind.AgentApprovalPending) {
+				err = ts.CheckPlacement(ag)
+			}
+			if err == nil && ag.Cordoned() {
+				_, getErr := s.store.GetRollout(ctx, model.RolloutID(ts.ID(), id))
+				switch {
+				case errors.Is(getErr, storage.ErrNotFound):
+					err = ag.CheckCordon()
+				case getErr != nil:
+					return getErr
+				}
+			}
+			if err != nil {
+				e.Action, e.Reason = PlanExclude, err.Error()
+				if selected {
+					e.Action = PlanSkip
+				}
+			}
+		}
+		plan.Entries = append(plan.Entries, e)
+		return nil
+	}
+
+	for _, id := range ts.Targets() {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		ag, err := s.store.GetAgent(ctx, id)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, err
+		}
+		if err = decide(ag, id, false); err != nil {
+			return nil, err
+		}
+	}
+
+	selector := ts.TargetLabels()
+	if len(selector) == 0 {
+		return plan, nil
+	}
+	var cursor string
+	for {
+		res, err := s.store.ListAgents(ctx, nil, storage.ListOptions{Cursor: cursor, Limit: storage.MaxListLimit})
+		if err != nil {
+			return nil, err
+		}
+		for _, ag := range res.Items {
+			if _, ok := seen[ag.ID()]; ok || !ag.MatchesLabels(selector) {
+				continue
+			}
+			if err = decide(ag, ag.ID(), true); err != nil {
+				return nil, err
+			}
+		}
+		if res.NextCursor == "" {
+			return plan, nil
+		}
+		cursor = res.NextCursor
+	}
+}
+
+// Deploy initiates distribution of a spec to the agents resolved by [Service.Plan].
+//
+// For each deployed agent the method either updates an existing rollout record or creates a new one,
+// setting status to pending with the current spec version. Excluded agents get a rollout
+// in the excluded state carrying the reason instead; skipped agents are left alone.
 //
-// For each agent in [model.Spec.Targets] the method either updates an existing rollout record or creates a new one,
-// setting status to pending with the current spec version.
+// Dependencies are checked first: a missing dependency returns [domain.ErrDependencyMissing],
+// a cycle returns [domain.ErrDependencyCycle].
 //
 // The sync runner will later pick up pending rollouts and push the spec payload to the agents.
-func (s *Service) Deploy(ctx context.Context, specID string) error {
+func (s *Service) Deploy(ctx context.Context, specID string) (*Plan, error) {
 	ts, err := s.store.GetSpec(ctx, specID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err = s.checkDependencies(ctx, ts); err != nil {
+		return nil, err
 	}
 
-	targets := ts.Targets()
+	plan, err := s.plan(ctx, ts)
+	if err != nil {
+		return nil, err
+	}
 	s.logger.Debug().
 		Str("spec_id", specID).
-		Int("targets", len(targets)).
+		Int("targets", len(plan.Entries)).
 		Int("version", ts.Version()).
 		Msg("deploy started")
 
-	var existing *model.Rollout
-	for _, agentID := range targets {
-		existing, err = s.store.GetRollout(ctx, model.RolloutID(specID, agentID))
-		if err == nil {
-			existing.MarkPending(ts.Version())
-			if err = s.store.UpsertRollout(ctx, existing); err != nil {
-				return err
+	var rollout *model.Rollout
+	for _, e := range plan.Entries {
+		if e.Action == PlanSkip {
+			s.logger.Trace().Str("spec_id", specID).Str("agent_id", e.AgentID).Str("reason", e.Reason).Msg("agent skipped")
+			continue
+		}
+
+		rollout, err = s.store.GetRollout(ctx, model.RolloutID(specID, e.AgentID))
+		switch {
+		case err == nil:
+			rollout.MarkPending(ts.Version())
+		case errors.Is(err, storage.ErrNotFound):
+			if rollout, err = model.NewRollout(specID, e.AgentID, ts.Version()); err != nil {
+				return nil, err
 			}
+		default:
+			return nil, err
+		}
+		if e.Action == PlanExclude {
+			rollout.MarkExcluded(e.Reason)
+		}
 
-			s.logger.Trace().Str("spec_id", specID).Str("agent_id", agentID).Msg("rollout updated")
-			continue
+		if err = s.store.UpsertRollout(ctx, rollout); err != nil {
+			return nil, err
 		}
+		s.logger.Trace().Str("spec_id", specID).Str("agent_id", e.AgentID).Str("action", string(e.Action)).Msg("rollout updated")
+	}
+	return plan, nil
+}
 
-		var rollout *model.Rollout
-		rollout, err = model.NewRollout(specID, agentID, ts.Version())
+// Undeploy stops distribution of a spec by removing all its rollout records.
+//
+// The spec itself is kept and can be deployed again. Tasks already running on
+// agents are left in place; the sync runner simply stops pushing the spec.
+// Returns [domain.ErrDependencyInUse] while other specs depend on it.
+func (s *Service) Undeploy(ctx context.Context, specID string) error {
+	if specID == "" {
+		return storage.ErrInvalidArgument
+	}
+	if _, err := s.store.GetSpec(ctx, specID); err != nil {
+		return err
+	}
+	if err := s.checkDependents(ctx, specID); err != nil {
+		return err
+	}
+	if err := s.store.DeleteRolloutsBySpec(ctx, specID); err != nil {
+		return err
+	}
+
+	s.logger.Debug().Str("spec_id", specID).Msg("spec undeployed")
+	return nil
+}
+
+// checkDependents rejects removing the spec id while other stored specs depend on it.
+//
+// Returns [domain.ErrDependencyInUse] naming the dependent specs.
+func (s *Service) checkDependents(ctx context.Context, id string) error {
+	var (
+		names  []string
+		cursor string
+	)
+	for {
+		res, err := s.store.ListSpecs(ctx, nil, storage.ListOptions{Limit: storage.MaxListLimit, Cursor: cursor})
 		if err != nil {
 			return err
 		}
-		if err = s.store.UpsertRollout(ctx, rollout); err != nil {
-			return err
+		for _, ts := range res.Items {
+			if ts != nil && slices.Contains(ts.DependsOn(), id) {
+				names = append(names, ts.Name())
+			}
 		}
+		if res.NextCursor == "" {
+			break
+		}
+		cursor = res.NextCursor
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	slices.Sort(names)
+	return fmt.Errorf("%w: %s", domain.ErrDependencyInUse, strings.Join(names, ", "))
+}
+
+// checkDependencies checks the dependencies of ts against the stored specs.
+func (s *Service) checkDependencies(ctx context.Context, ts *model.Spec) error {
+	return CheckDependencies(ctx, ts, s.store.GetSpec)
+}
+
+// CheckDependencies walks the dependency graph of ts depth-first and rejects
+// missing specs and cycles. get looks up a spec by ID and returns [storage.ErrNotFound]
+// for unknown ones; ts itself is never looked up, so its stored version is ignored.
+//
+// Returns [domain.ErrDependencyMissing] or [domain.ErrDependencyCycle].
+func CheckDependencies(ctx context.Context, ts *model.Spec, get func(context.Context, string) (*model.Spec, error)) error {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make(map[string]int)
 
-		s.logger.Trace().Str("spec_id", specID).Str("agent_id", agentID).Msg("rollout created")
+	var visit func(id string, deps []string) error
+	visit = func(id string, deps []string) error {
+		state[id] = visiting
+		for _, dep := range deps {
+			switch state[dep] {
+			case visiting:
+				return fmt.Errorf("%w: %s -> %s", domain.ErrDependencyCycle, id, dep)
+			case visited:
+				continue
+			}
+
+			d, err := get(ctx, dep)
+			if err != nil {
+				if errors.Is(err, storage.ErrNotFound) {
+					return fmt.Errorf("%w: %s", domain.ErrDependencyMissing, dep)
+				}
+				return err
+			}
+			if err = visit(dep, d.DependsOn()); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		return nil
 	}
-	return nil
+	return visit(ts.ID(), ts.DependsOn())
 }
@@ -0,0 +1,199 @@
+package spec
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/soltiHQ/control-plane/domain"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/storage"
+	"github.com/soltiHQ/control-plane/internal/storage/inmemory"
+)
+
+func newTestService(t *testing.T) (*Service, *inmemory.Store) {
+	t.Helper()
+
+	store := inmemory.New()
+	return New(store, zerolog.Nop()), store
+}
+
+func mkAgent(t *testing.T, store *inmemory.Store, id string, labels map[string]string) *model.Agent {
+	t.Helper()
+
+	a, err := model.NewAgent(id, id, "http://"+id+":8080")
+	if err != nil {
+		t.Fatalf("new agent: %v", err)
+	}
+	for k, v := range labels {
+		a.LabelAdd(k, v)
+	}
+	if err = store.UpsertAgent(context.Background(), a); err != nil {
+		t.Fatalf("upsert agent: %v", err)
+	}
+	return a
+}
+
+func mkSpec(t *testing.T, store *inmemory.Store, id string, setup func(ts *model.Spec)) *model.Spec {
+	t.Helper()
+
+	ts, err := model.NewSpec(id, id, id)
+	if err != nil {
+		t.Fatalf("new spec: %v", err)
+	}
+	if setup != nil {
+		setup(ts)
+	}
+	if err = store.UpsertSpec(context.Background(), ts); err != nil {
+		t.Fatalf("upsert spec: %v", err)
+	}
+	return ts
+}
+
+func getRollout(t *testing.T, store *inmemory.Store, specID, agentID string) *model.Rollout {
+	t.Helper()
+
+	ro, err := store.GetRollout(context.Background(), model.RolloutID(specID, agentID))
+	if err != nil {
+		t.Fatalf("get rollout %s/%s: %v", specID, agentID, err)
+	}
+	return ro
+}
+
+func TestCheckDependencies(t *testing.T) {
+	t.Parallel()
+
+	s, store := newTestService(t)
+	deps := func(ids ...string) func(*model.Spec) {
+		return func(ts *model.Spec) { ts.SetDependsOn(ids) }
+	}
+
+	mkSpec(t, store, "base", nil)
+	mkSpec(t, store, "mid", deps("base"))
+	mkSpec(t, store, "top", deps("mid", "base"))
+	mkSpec(t, store, "orphan", deps("gone"))
+	mkSpec(t, store, "deep-orphan", deps("orphan"))
+	mkSpec(t, store, "self", deps("self"))
+	mkSpec(t, store, "cycle-a", deps("cycle-b"))
+	mkSpec(t, store, "cycle-b", deps("cycle-c"))
+	mkSpec(t, store, "cycle-c", deps("cycle-a"))
+	mkSpec(t, store, "into-cycle", deps("base", "cycle-a"))
+
+	tests := []struct {
+		spec string
+		want error
+	}{
+		{spec: "base"},
+		{spec: "mid"},
+		{spec: "top"},
+		{spec: "orphan", want: domain.ErrDependencyMissing},
+		{spec: "deep-orphan", want: domain.ErrDependencyMissing},
+		{spec: "self", want: domain.ErrDependencyCycle},
+		{spec: "cycle-a", want: domain.ErrDependencyCycle},
+		{spec: "into-cycle", want: domain.ErrDependencyCycle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			ts, err := store.GetSpec(context.Background(), tt.spec)
+			if err != nil {
+				t.Fatalf("get spec: %v", err)
+			}
+			err = s.checkDependencies(context.Background(), ts)
+			switch {
+			case tt.want == nil && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Fatalf("expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDeploy_RejectsBrokenDependencies(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	mkAgent(t, store, "a1", nil)
+	mkSpec(t, store, "s1", func(ts *model.Spec) {
+		ts.SetTargets([]string{"a1"})
+		ts.SetDependsOn([]string{"gone"})
+	})
+
+	if _, err := s.Deploy(ctx, "s1"); !errors.Is(err, domain.ErrDependencyMissing) {
+		t.Fatalf("expected ErrDependencyMissing, got %v", err)
+	}
+	if _, err := store.GetRollout(ctx, model.RolloutID("s1", "a1")); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected no rollout after a rejected deploy, err=%v", err)
+	}
+}
+
+func TestDelete_RejectsDependedOnSpecs(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	mkSpec(t, store, "db", nil)
+	mkSpec(t, store, "worker", func(ts *model.Spec) { ts.SetDependsOn([]string{"db"}) })
+	mkSpec(t, store, "web", func(ts *model.Spec) { ts.SetDependsOn([]string{"db"}) })
+
+	for name, remove := range map[string]func(context.Context, string) error{"delete": s.Delete, "undeploy": s.Undeploy} {
+		err := remove(ctx, "db")
+		if !errors.Is(err, domain.ErrDependencyInUse) {
+			t.Fatalf("%s: expected ErrDependencyInUse, got %v", name, err)
+		}
+		if !strings.HasSuffix(err.Error(), ": web, worker") {
+			t.Fatalf("%s: error %q does not list the dependent specs", name, err)
+		}
+	}
+	if _, err := store.GetSpec(ctx, "db"); err != nil {
+		t.Fatalf("rejected delete removed the spec: %v", err)
+	}
+
+	// Once its dependents are gone, the spec can be removed.
+	for _, id := range []string{"web", "worker"} {
+		if err := s.Delete(ctx, id); err != nil {
+			t.Fatalf("delete %s: %v", id, err)
+		}
+	}
+	if err := s.Undeploy(ctx, "db"); err != nil {
+		t.Fatalf("undeploy: %v", err)
+	}
+	if err := s.Delete(ctx, "db"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+}
+
+func TestUpsert_RejectsDependencyCycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s, store := newTestService(t)
+	mkSpec(t, store, "a", func(ts *model.Spec) { ts.SetDependsOn([]string{"b"}) })
+	b := mkSpec(t, store, "b", nil)
+
+	edit := b.Clone()
+	edit.SetDependsOn([]string{"a"})
+	if err := s.Upsert(ctx, edit); !errors.Is(err, domain.ErrDependencyCycle) {
+		t.Fatalf("expected ErrDependencyCycle, got %v", err)
+	}
+	stored, err := store.GetSpec(ctx, "b")
+	if err != nil {
+		t.Fatalf("get spec: %v", err)
+	}
+	if len(stored.DependsOn()) != 0 || stored.Version() != b.Version() {
+		t.Fatalf("rejected edit was stored: depends_on=%v version=%d", stored.DependsOn(), stored.Version())
+	}
+
+	c, err := model.NewSpec("c", "c", "c")
+	if err != nil {
+		t.Fatalf("new spec: %v", err)
+	}
+	c.SetDependsOn([]string{"gone"})
+	if err = s.Create(ctx, c); !errors.Is(err, domain.ErrDependencyMissing) {
+		t.Fatalf("expected ErrDependencyMissing, got %v", err)
+	}
+}
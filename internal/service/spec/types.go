@@ -19,3 +19,68 @@ type Page struct {
 	Items      []*model.Spec
 	NextCursor string
 }
+
+// PlanAction is the deploy decision for one agent.
+type PlanAction string
+
+const (
+	// PlanDeploy creates or resets a pending rollout.
+	PlanDeploy PlanAction = "deploy"
+	// PlanExclude records an excluded rollout carrying the reason (explicit targets only).
+	PlanExclude PlanAction = "exclude"
+	// PlanSkip leaves a selector-matched agent without a rollout.
+	PlanSkip PlanAction = "skip"
+)
+
+// PlanEntry is the deploy decision for one resolved agent.
+//
+// Agent is nil for an explicit target that has not registered yet;
+// such targets are deployed and resolved by the sync runner later.
+type PlanEntry struct {
+	Agent    *model.Agent
+	AgentID  string
+	Selected bool // matched by the label selector rather than listed in targets
+	Action   PlanAction
+	Reason   string
+}
+
+// Plan lists the deploy decisions for a spec, explicit targets first.
+type Plan struct {
+	SpecID  string
+	Entries []PlanEntry
+}
+
+// Ack is a pull-mode agent's acknowledgement of a desired spec version.
+// Error is empty when the agent applied the version.
+type Ack struct {
+	SpecID  string
+	Version int
+	Error   string
+}
+
+// Desired is a spec rendered for a pull-mode agent.
+type Desired struct {
+	SpecID  string
+	Version int
+	Spec    map[string]any // agent CreateSpec JSON, as pushed by the sync runner
+}
+
+// PullResult is the outcome of a pull-mode sync.
+type PullResult struct {
+	// Specs is the agent's desired spec set, dependencies first.
+	Specs []Desired
+	// Failed lists the specs whose rollout failed during this sync.
+	Failed []*model.Spec
+	// Changed reports whether any rollout of the agent changed state.
+	Changed bool
+}
+
+// DrainResult is the outcome of draining an agent.
+type DrainResult struct {
+	// Undeployed lists the specs whose rollout on the agent was removed.
+	Undeployed []string
+	// Retargeted lists the label-selected specs that gained a rollout on another agent.
+	Retargeted []string
+	// Failed maps the specs that could not be retargeted to the reason.
+	Failed map[string]string
+}
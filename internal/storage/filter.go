@@ -32,3 +32,15 @@ type SpecFilter interface{}
 
 // RolloutFilter defines a backend-specific query object for rollouts.
 type RolloutFilter interface{}
+
+// SecretFilter defines a backend-specific query object for secrets.
+type SecretFilter interface{}
+
+// RunFilter defines a backend-specific query object for runs.
+type RunFilter interface{}
+
+// EnrollmentTokenFilter defines a backend-specific query object for enrollment tokens.
+type EnrollmentTokenFilter interface{}
+
+// AgentChangeFilter defines a backend-specific query object for agent changes.
+type AgentChangeFilter interface{}
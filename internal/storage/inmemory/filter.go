@@ -48,6 +48,12 @@ func (f *AgentFilter) ByArch(arch string) *AgentFilter {
 	return f
 }
 
+// ByApproval matches agents in the given approval state.
+func (f *AgentFilter) ByApproval(s kind.AgentApproval) *AgentFilter {
+	f.predicates = append(f.predicates, func(a *model.Agent) bool { return a.Approval() == s })
+	return f
+}
+
 // Query matches agents by id/name/endpoint (case-insensitive substring).
 func (f *AgentFilter) Query(q string) *AgentFilter {
 	q = strings.ToLower(strings.TrimSpace(q))
@@ -292,3 +298,146 @@ func (f *RolloutFilter) Matches(ss *model.Rollout) bool {
 	}
 	return true
 }
+
+// SecretFilter provides predicate-based filtering for in-memory secret queries.
+type SecretFilter struct {
+	predicates []func(*model.Secret) bool
+}
+
+// NewSecretFilter creates an empty secret filter that matches all secrets.
+func NewSecretFilter() *SecretFilter {
+	return &SecretFilter{predicates: make([]func(*model.Secret) bool, 0)}
+}
+
+// Query matches secrets by name/description (case-insensitive substring).
+func (f *SecretFilter) Query(q string) *SecretFilter {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if q == "" {
+		return f
+	}
+	f.predicates = append(f.predicates, func(s *model.Secret) bool {
+		if s == nil {
+			return false
+		}
+		return strings.Contains(strings.ToLower(s.Name()), q) ||
+			strings.Contains(strings.ToLower(s.Description()), q)
+	})
+	return f
+}
+
+// Matches reports whether the given secret satisfies all predicates.
+func (f *SecretFilter) Matches(s *model.Secret) bool {
+	for _, pred := range f.predicates {
+		if !pred(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// RunFilter provides predicate-based filtering for in-memory run queries.
+//
+// Filters are composed by chaining builder methods. All predicates are ANDed together.
+// RunFilter is mutable and not safe for concurrent use.
+type RunFilter struct {
+	predicates []func(*model.Run) bool
+}
+
+// NewRunFilter creates an empty run filter that matches all runs.
+func NewRunFilter() *RunFilter {
+	return &RunFilter{predicates: make([]func(*model.Run) bool, 0)}
+}
+
+// ByDone matches runs that are (or are not yet) finished on every target.
+func (f *RunFilter) ByDone(done bool) *RunFilter {
+	f.predicates = append(f.predicates, func(r *model.Run) bool { return r.Done() == done })
+	return f
+}
+
+// Query matches runs by id/name (case-insensitive substring).
+func (f *RunFilter) Query(q string) *RunFilter {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if q == "" {
+		return f
+	}
+	f.predicates = append(f.predicates, func(r *model.Run) bool {
+		if r == nil {
+			return false
+		}
+		return strings.Contains(strings.ToLower(r.ID()), q) ||
+			strings.Contains(strings.ToLower(r.Name()), q)
+	})
+	return f
+}
+
+// Matches reports whether the given run satisfies all predicates.
+func (f *RunFilter) Matches(r *model.Run) bool {
+	for _, pred := range f.predicates {
+		if !pred(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// EnrollmentTokenFilter provides predicate-based filtering for in-memory enrollment token queries.
+type EnrollmentTokenFilter struct {
+	predicates []func(*model.EnrollmentToken) bool
+}
+
+// NewEnrollmentTokenFilter creates an empty filter that matches all enrollment tokens.
+func NewEnrollmentTokenFilter() *EnrollmentTokenFilter {
+	return &EnrollmentTokenFilter{predicates: make([]func(*model.EnrollmentToken) bool, 0)}
+}
+
+// Query matches enrollment tokens by id/name (case-insensitive substring).
+func (f *EnrollmentTokenFilter) Query(q string) *EnrollmentTokenFilter {
+	q = strings.ToLower(strings.TrimSpace(q))
+	if q == "" {
+		return f
+	}
+	f.predicates = append(f.predicates, func(t *model.EnrollmentToken) bool {
+		if t == nil {
+			return false
+		}
+		return strings.Contains(strings.ToLower(t.ID()), q) ||
+			strings.Contains(strings.ToLower(t.Name()), q)
+	})
+	return f
+}
+
+// Matches reports whether the given enrollment token satisfies all predicates.
+func (f *EnrollmentTokenFilter) Matches(t *model.EnrollmentToken) bool {
+	for _, pred := range f.predicates {
+		if !pred(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// AgentChangeFilter provides predicate-based filtering for in-memory agent change queries.
+type AgentChangeFilter struct {
+	predicates []func(*model.AgentChange) bool
+}
+
+// NewAgentChangeFilter creates an empty filter that matches all agent changes.
+func NewAgentChangeFilter() *AgentChangeFilter {
+	return &AgentChangeFilter{predicates: make([]func(*model.AgentChange) bool, 0)}
+}
+
+// ByAgentID matches changes recorded for the given agent.
+func (f *AgentChangeFilter) ByAgentID(agentID string) *AgentChangeFilter {
+	f.predicates = append(f.predicates, func(c *model.AgentChange) bool { return c.AgentID() == agentID })
+	return f
+}
+
+// Matches reports whether the given agent change satisfies all predicates.
+func (f *AgentChangeFilter) Matches(c *model.AgentChange) bool {
+	for _, pred := range f.predicates {
+		if !pred(c) {
+			return false
+		}
+	}
+	return true
+}
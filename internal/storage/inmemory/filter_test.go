@@ -78,6 +78,23 @@ func TestAgentFilter_StaleAtBefore(t *testing.T) {
 	}
 }
 
+func TestAgentFilter_ByApproval(t *testing.T) {
+	t.Parallel()
+
+	a := mkAgent(t, "a1")
+	if !NewAgentFilter().ByApproval(kind.AgentApprovalApproved).Matches(a) {
+		t.Fatalf("new agent must be approved by default")
+	}
+
+	a.SetApproval(kind.AgentApprovalPending)
+	if !NewAgentFilter().ByApproval(kind.AgentApprovalPending).Matches(a) {
+		t.Fatalf("expected match for pending agent")
+	}
+	if NewAgentFilter().ByApproval(kind.AgentApprovalApproved).Matches(a) {
+		t.Fatalf("pending agent must not match approved")
+	}
+}
+
 func TestUserFilter_Matches_AllPredicatesANDed(t *testing.T) {
 	t.Parallel()
 
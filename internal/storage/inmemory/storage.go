@@ -3,6 +3,7 @@ package inmemory
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/soltiHQ/control-plane/domain/kind"
@@ -21,6 +22,10 @@ var (
 	_ storage.SessionStore   = (*Store)(nil)
 	_ storage.SpecStore  = (*Store)(nil)
 	_ storage.RolloutStore = (*Store)(nil)
+	_ storage.SecretStore  = (*Store)(nil)
+	_ storage.RunStore     = (*Store)(nil)
+	_ storage.EnrollmentTokenStore = (*Store)(nil)
+	_ storage.AgentChangeStore     = (*Store)(nil)
 )
 
 // Store provides an in-memory implementation of storage.Storage using GenericStore.
@@ -33,6 +38,10 @@ type Store struct {
 	sessions    *GenericStore[*model.Session]
 	specs   *GenericStore[*model.Spec]
 	rollouts *GenericStore[*model.Rollout]
+	secrets  *GenericStore[*model.Secret]
+	runs     *GenericStore[*model.Run]
+	enrollmentTokens *GenericStore[*model.EnrollmentToken]
+	agentChanges     *GenericStore[*model.AgentChange]
 }
 
 // New creates a new in-memory store with an empty state.
@@ -46,6 +55,10 @@ func New() *Store {
 		sessions:    NewGenericStore[*model.Session](),
 		specs:   NewGenericStore[*model.Spec](),
 		rollouts: NewGenericStore[*model.Rollout](),
+		secrets:  NewGenericStore[*model.Secret](),
+		runs:     NewGenericStore[*model.Run](),
+		enrollmentTokens: NewGenericStore[*model.EnrollmentToken](),
+		agentChanges:     NewGenericStore[*model.AgentChange](),
 	}
 }
 
@@ -564,3 +577,248 @@ func (s *Store) DeleteRolloutsBySpec(ctx context.Context, specID string) error {
 	}
 	return nil
 }
+
+// --- Secrets ---
+
+func (s *Store) CreateSecret(_ context.Context, sec *model.Secret) error {
+	if sec == nil || sec.ID() == "" || sec.Name() == "" || sec.CreatedAt().IsZero() {
+		return storage.ErrInvalidArgument
+	}
+
+	s.secrets.mu.Lock()
+	defer s.secrets.mu.Unlock()
+
+	if _, ok := s.secrets.data[sec.ID()]; ok {
+		return storage.ErrAlreadyExists
+	}
+	for _, cur := range s.secrets.data {
+		if cur.Name() == sec.Name() {
+			return storage.ErrAlreadyExists
+		}
+	}
+	s.secrets.data[sec.ID()] = sec.Clone()
+	return nil
+}
+
+func (s *Store) UpsertSecret(ctx context.Context, sec *model.Secret) error {
+	if sec == nil {
+		return storage.ErrInvalidArgument
+	}
+	return s.secrets.Upsert(ctx, sec)
+}
+
+func (s *Store) GetSecret(ctx context.Context, id string) (*model.Secret, error) {
+	return s.secrets.Get(ctx, id)
+}
+
+func (s *Store) GetSecretByName(ctx context.Context, name string) (*model.Secret, error) {
+	if name == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+
+	s.secrets.mu.RLock()
+	defer s.secrets.mu.RUnlock()
+
+	var (
+		found *model.Secret
+		i     int
+	)
+	for _, sec := range s.secrets.data {
+		if i%1000 == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+		i++
+
+		if sec.Name() != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("%w: non-unique secret name %q", storage.ErrInternal, name)
+		}
+		found = sec
+	}
+	if found == nil {
+		return nil, storage.ErrNotFound
+	}
+	return found.Clone(), nil
+}
+
+func (s *Store) ListSecrets(ctx context.Context, filter storage.SecretFilter, opts storage.ListOptions) (*storage.SecretListResult, error) {
+	var predicate func(*model.Secret) bool
+
+	if filter != nil {
+		f, ok := filter.(*SecretFilter)
+		if !ok {
+			return nil, storage.ErrInvalidArgument
+		}
+		predicate = f.Matches
+	}
+	return s.secrets.List(ctx, predicate, opts)
+}
+
+func (s *Store) DeleteSecret(ctx context.Context, id string) error {
+	return s.secrets.Delete(ctx, id)
+}
+
+// --- Runs ---
+
+func (s *Store) UpsertRun(ctx context.Context, r *model.Run) error {
+	if r == nil {
+		return storage.ErrInvalidArgument
+	}
+	return s.runs.Upsert(ctx, r)
+}
+
+func (s *Store) GetRun(ctx context.Context, id string) (*model.Run, error) {
+	return s.runs.Get(ctx, id)
+}
+
+func (s *Store) ListRuns(ctx context.Context, filter storage.RunFilter, opts storage.ListOptions) (*storage.RunListResult, error) {
+	var predicate func(*model.Run) bool
+
+	if filter != nil {
+		f, ok := filter.(*RunFilter)
+		if !ok {
+			return nil, storage.ErrInvalidArgument
+		}
+		predicate = f.Matches
+	}
+	return s.runs.List(ctx, predicate, opts)
+}
+
+func (s *Store) DeleteRun(ctx context.Context, id string) error {
+	return s.runs.Delete(ctx, id)
+}
+
+// --- Enrollment tokens ---
+
+func (s *Store) UpsertEnrollmentToken(ctx context.Context, t *model.EnrollmentToken) error {
+	if t == nil {
+		return storage.ErrInvalidArgument
+	}
+	return s.enrollmentTokens.Upsert(ctx, t)
+}
+
+func (s *Store) GetEnrollmentToken(ctx context.Context, id string) (*model.EnrollmentToken, error) {
+	return s.enrollmentTokens.Get(ctx, id)
+}
+
+func (s *Store) GetEnrollmentTokenByHash(ctx context.Context, hash string) (*model.EnrollmentToken, error) {
+	if hash == "" {
+		return nil, storage.ErrInvalidArgument
+	}
+
+	s.enrollmentTokens.mu.RLock()
+	defer s.enrollmentTokens.mu.RUnlock()
+
+	var i int
+	for _, t := range s.enrollmentTokens.data {
+		if i%1000 == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+		i++
+
+		if t.Hash() == hash {
+			return t.Clone(), nil
+		}
+	}
+	return nil, storage.ErrNotFound
+}
+
+func (s *Store) ListEnrollmentTokens(ctx context.Context, filter storage.EnrollmentTokenFilter, opts storage.ListOptions) (*storage.EnrollmentTokenListResult, error) {
+	var predicate func(*model.EnrollmentToken) bool
+
+	if filter != nil {
+		f, ok := filter.(*EnrollmentTokenFilter)
+		if !ok {
+			return nil, storage.ErrInvalidArgument
+		}
+		predicate = f.Matches
+	}
+	return s.enrollmentTokens.List(ctx, predicate, opts)
+}
+
+func (s *Store) DeleteEnrollmentToken(ctx context.Context, id string) error {
+	return s.enrollmentTokens.Delete(ctx, id)
+}
+
+// --- Agent changes ---
+
+func (s *Store) CreateAgentChange(ctx context.Context, c *model.AgentChange) error {
+	if c == nil {
+		return storage.ErrInvalidArgument
+	}
+	return s.agentChanges.Create(ctx, c)
+}
+
+func (s *Store) ListAgentChanges(ctx context.Context, filter storage.AgentChangeFilter, opts storage.ListOptions) (*storage.AgentChangeListResult, error) {
+	var predicate func(*model.AgentChange) bool
+
+	if filter != nil {
+		f, ok := filter.(*AgentChangeFilter)
+		if !ok {
+			return nil, storage.ErrInvalidArgument
+		}
+		predicate = f.Matches
+	}
+	return s.agentChanges.List(ctx, predicate, opts)
+}
+
+func (s *Store) DeleteAgentChanges(ctx context.Context, agentID string) error {
+	if agentID == "" {
+		return storage.ErrInvalidArgument
+	}
+
+	s.agentChanges.mu.RLock()
+	ids := make([]string, 0)
+	for id, c := range s.agentChanges.data {
+		if c.AgentID() == agentID {
+			ids = append(ids, id)
+		}
+	}
+	s.agentChanges.mu.RUnlock()
+
+	for _, id := range ids {
+		_ = s.agentChanges.Delete(ctx, id)
+	}
+	return nil
+}
+
+func (s *Store) TrimAgentChanges(ctx context.Context, agentID string, keep int) error {
+	if agentID == "" || keep < 0 {
+		return storage.ErrInvalidArgument
+	}
+
+	s.agentChanges.mu.RLock()
+	changes := make([]*model.AgentChange, 0)
+	for _, c := range s.agentChanges.data {
+		if c.AgentID() == agentID {
+			changes = append(changes, c)
+		}
+	}
+	s.agentChanges.mu.RUnlock()
+
+	if len(changes) <= keep {
+		return nil
+	}
+	// Same ordering as List: newest first.
+	sort.Slice(changes, func(i, j int) bool {
+		ti, tj := changes[i].CreatedAt(), changes[j].CreatedAt()
+		if ti.Equal(tj) {
+			return changes[i].ID() < changes[j].ID()
+		}
+		return ti.After(tj)
+	})
+	for _, c := range changes[keep:] {
+		_ = s.agentChanges.Delete(ctx, c.ID())
+	}
+	return nil
+}
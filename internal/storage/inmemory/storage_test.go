@@ -3,10 +3,14 @@ package inmemory
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
 	"github.com/soltiHQ/control-plane/internal/storage"
 )
 
@@ -399,3 +403,247 @@ func TestStore_Roles_GetRoles_MissingID_ReturnsNotFound(t *testing.T) {
 		t.Fatalf("expected ErrNotFound, err=%v", err)
 	}
 }
+
+func TestStore_Secrets_CRUD_AndGetByName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := New()
+	if err := s.UpsertSecret(ctx, nil); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+
+	sec := mkSecret(t, "s1", "db-password")
+	requireNoErr(t, s.UpsertSecret(ctx, sec))
+
+	got, err := s.GetSecretByName(ctx, "db-password")
+	requireNoErr(t, err)
+	requireNotNil(t, got)
+	if got.ID() != sec.ID() || got.Value() != sec.Value() {
+		t.Fatalf("unexpected secret")
+	}
+
+	if _, err = s.GetSecretByName(ctx, ""); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+	if _, err = s.GetSecretByName(ctx, "missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, err=%v", err)
+	}
+
+	requireNoErr(t, s.UpsertSecret(ctx, mkSecret(t, "s2", "db-password")))
+	if _, err = s.GetSecretByName(ctx, "db-password"); !errors.Is(err, storage.ErrInternal) {
+		t.Fatalf("expected ErrInternal, err=%v", err)
+	}
+
+	requireNoErr(t, s.DeleteSecret(ctx, sec.ID()))
+	if err = s.DeleteSecret(ctx, sec.ID()); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, err=%v", err)
+	}
+}
+
+func TestStore_CreateSecret_UniqueName(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := New()
+	if err := s.CreateSecret(ctx, nil); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+
+	requireNoErr(t, s.CreateSecret(ctx, mkSecret(t, "s1", "db-password")))
+	if err := s.CreateSecret(ctx, mkSecret(t, "s1", "other")); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists for duplicate id, err=%v", err)
+	}
+	if err := s.CreateSecret(ctx, mkSecret(t, "s2", "db-password")); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists for duplicate name, err=%v", err)
+	}
+
+	// Concurrent creates of one name: exactly one wins.
+	const n = 16
+	var (
+		wg  sync.WaitGroup
+		won atomic.Int32
+	)
+	for i := range n {
+		sec := mkSecret(t, fmt.Sprintf("r%d", i), "race")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if s.CreateSecret(ctx, sec) == nil {
+				won.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if won.Load() != 1 {
+		t.Fatalf("expected exactly one create to succeed, got=%d", won.Load())
+	}
+	if _, err := s.GetSecretByName(ctx, "race"); err != nil {
+		t.Fatalf("expected a single secret named race, err=%v", err)
+	}
+}
+
+func TestStore_EnrollmentTokens_CRUD_AndGetByHash(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := New()
+	if err := s.UpsertEnrollmentToken(ctx, nil); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+
+	et := mkEnrollmentToken(t, "e1", "edge")
+	requireNoErr(t, s.UpsertEnrollmentToken(ctx, et))
+	requireNoErr(t, s.UpsertEnrollmentToken(ctx, mkEnrollmentToken(t, "e2", "lab")))
+
+	got, err := s.GetEnrollmentTokenByHash(ctx, et.Hash())
+	requireNoErr(t, err)
+	requireNotNil(t, got)
+	if got.ID() != et.ID() {
+		t.Fatalf("unexpected token %q", got.ID())
+	}
+	if _, err = s.GetEnrollmentTokenByHash(ctx, ""); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+	if _, err = s.GetEnrollmentTokenByHash(ctx, "missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, err=%v", err)
+	}
+
+	res, err := s.ListEnrollmentTokens(ctx, NewEnrollmentTokenFilter().Query("ED"), storage.ListOptions{})
+	requireNoErr(t, err)
+	if len(res.Items) != 1 || res.Items[0].ID() != "e1" {
+		t.Fatalf("expected only e1, got %d items", len(res.Items))
+	}
+	if _, err = s.ListEnrollmentTokens(ctx, NewSecretFilter(), storage.ListOptions{}); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+
+	requireNoErr(t, s.DeleteEnrollmentToken(ctx, et.ID()))
+	if err = s.DeleteEnrollmentToken(ctx, et.ID()); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, err=%v", err)
+	}
+}
+
+func TestStore_Runs_CRUD_AndFilterByDone(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := New()
+	if err := s.UpsertRun(ctx, nil); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+
+	active := mkRun(t, "r1", "uptime")
+	active.SetTargets([]string{"a1", "a2"})
+	requireNoErr(t, s.UpsertRun(ctx, active))
+
+	done := mkRun(t, "r2", "df")
+	done.SetTargets([]string{"a1"})
+	done.SetResult(model.RunResult{AgentID: "a1", Status: kind.TaskStatusSucceeded, FinishedAt: fixedNow()})
+	requireNoErr(t, s.UpsertRun(ctx, done))
+
+	got, err := s.GetRun(ctx, "r1")
+	requireNoErr(t, err)
+	if len(got.Results()) != 2 || got.Done() {
+		t.Fatalf("unexpected run: results=%d done=%v", len(got.Results()), got.Done())
+	}
+
+	res, err := s.ListRuns(ctx, NewRunFilter().ByDone(false), storage.ListOptions{})
+	requireNoErr(t, err)
+	if len(res.Items) != 1 || res.Items[0].ID() != "r1" {
+		t.Fatalf("expected only r1 to be active, got %d items", len(res.Items))
+	}
+
+	if _, err = s.ListRuns(ctx, NewSpecFilter(), storage.ListOptions{}); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+
+	requireNoErr(t, s.DeleteRun(ctx, "r1"))
+	if _, err = s.GetRun(ctx, "r1"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, err=%v", err)
+	}
+}
+
+func TestStore_AgentChanges_AppendListDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := New()
+	if err := s.CreateAgentChange(ctx, nil); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+
+	requireNoErr(t, s.CreateAgentChange(ctx, mkAgentChange(t, "c1", "a1")))
+	requireNoErr(t, s.CreateAgentChange(ctx, mkAgentChange(t, "c2", "a1")))
+	requireNoErr(t, s.CreateAgentChange(ctx, mkAgentChange(t, "c3", "a2")))
+
+	if err := s.CreateAgentChange(ctx, mkAgentChange(t, "c1", "a1")); !errors.Is(err, storage.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, err=%v", err)
+	}
+
+	res, err := s.ListAgentChanges(ctx, NewAgentChangeFilter().ByAgentID("a1"), storage.ListOptions{})
+	requireNoErr(t, err)
+	if len(res.Items) != 2 {
+		t.Fatalf("expected 2 changes for a1, got %d", len(res.Items))
+	}
+
+	if _, err = s.ListAgentChanges(ctx, NewRunFilter(), storage.ListOptions{}); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+
+	if err = s.DeleteAgentChanges(ctx, ""); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected ErrInvalidArgument, err=%v", err)
+	}
+	requireNoErr(t, s.DeleteAgentChanges(ctx, "a1"))
+	requireNoErr(t, s.DeleteAgentChanges(ctx, "a1"))
+
+	res, err = s.ListAgentChanges(ctx, nil, storage.ListOptions{})
+	requireNoErr(t, err)
+	if len(res.Items) != 1 || res.Items[0].AgentID() != "a2" {
+		t.Fatalf("expected only the a2 change to remain, got %d items", len(res.Items))
+	}
+}
+
+func TestStore_AgentChanges_Trim(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := New()
+	for i := range 5 {
+		requireNoErr(t, s.CreateAgentChange(ctx, mkAgentChange(t, fmt.Sprintf("c%d", i), "a1")))
+	}
+	requireNoErr(t, s.CreateAgentChange(ctx, mkAgentChange(t, "other", "a2")))
+
+	before, err := s.ListAgentChanges(ctx, NewAgentChangeFilter().ByAgentID("a1"), storage.ListOptions{})
+	requireNoErr(t, err)
+
+	for _, tt := range []struct {
+		agentID string
+		keep    int
+	}{{agentID: "", keep: 1}, {agentID: "a1", keep: -1}} {
+		if err = s.TrimAgentChanges(ctx, tt.agentID, tt.keep); !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Fatalf("TrimAgentChanges(%q, %d): expected ErrInvalidArgument, err=%v", tt.agentID, tt.keep, err)
+		}
+	}
+	requireNoErr(t, s.TrimAgentChanges(ctx, "a1", 10))
+	requireNoErr(t, s.TrimAgentChanges(ctx, "a1", 2))
+
+	// The newest changes, in list order, survive; other agents are untouched.
+	after, err := s.ListAgentChanges(ctx, NewAgentChangeFilter().ByAgentID("a1"), storage.ListOptions{})
+	requireNoErr(t, err)
+	if len(after.Items) != 2 || after.Items[0].ID() != before.Items[0].ID() || after.Items[1].ID() != before.Items[1].ID() {
+		t.Fatalf("expected the 2 newest changes to remain, got %d items", len(after.Items))
+	}
+	other, err := s.ListAgentChanges(ctx, NewAgentChangeFilter().ByAgentID("a2"), storage.ListOptions{})
+	requireNoErr(t, err)
+	if len(other.Items) != 1 {
+		t.Fatalf("expected the a2 change to remain, got %d items", len(other.Items))
+	}
+
+	requireNoErr(t, s.TrimAgentChanges(ctx, "a1", 0))
+	after, err = s.ListAgentChanges(ctx, NewAgentChangeFilter().ByAgentID("a1"), storage.ListOptions{})
+	requireNoErr(t, err)
+	if len(after.Items) != 0 {
+		t.Fatalf("expected no changes left for a1, got %d items", len(after.Items))
+	}
+}
@@ -81,6 +81,38 @@ func mkSession(t *testing.T, id, userID, credentialID string, auth kind.Auth) *m
 	return s
 }
 
+func mkSecret(t *testing.T, id, name string) *model.Secret {
+	t.Helper()
+	s, err := model.NewSecret(id, name, "value-"+id)
+	requireNoErr(t, err)
+	requireNotNil(t, s)
+	return s
+}
+
+func mkEnrollmentToken(t *testing.T, id, name string) *model.EnrollmentToken {
+	t.Helper()
+	et, err := model.NewEnrollmentToken(id, name, "hash-"+id)
+	requireNoErr(t, err)
+	requireNotNil(t, et)
+	return et
+}
+
+func mkRun(t *testing.T, id, name string) *model.Run {
+	t.Helper()
+	r, err := model.NewRun(id, name)
+	requireNoErr(t, err)
+	requireNotNil(t, r)
+	return r
+}
+
+func mkAgentChange(t *testing.T, id, agentID string) *model.AgentChange {
+	t.Helper()
+	c, err := model.NewAgentChange(id, agentID, []model.FieldChange{{Field: "os", Old: "linux", New: "darwin"}})
+	requireNoErr(t, err)
+	requireNotNil(t, c)
+	return c
+}
+
 func userAddRole(t *testing.T, u *model.User, roleID string) {
 	t.Helper()
 	requireNoErr(t, u.RoleAdd(roleID))
@@ -1,7 +1,7 @@
 // Package storage defines persistence contracts for control-plane domain entities.
 //
 // It provides backend-agnostic interfaces describing how domain objects
-// (User, Role, Agent, Credential, Session, Verifier) are stored and retrieved.
+// (User, Role, Agent, Credential, Session, Verifier, Spec, Rollout, Secret, Run, EnrollmentToken, AgentChange) are stored and retrieved.
 //
 // Design goals
 //
@@ -64,6 +64,18 @@ type SpecListResult = ListResult[*model.Spec]
 // RolloutListResult contains a page of rollout results with pagination support.
 type RolloutListResult = ListResult[*model.Rollout]
 
+// SecretListResult contains a page of secret results with pagination support.
+type SecretListResult = ListResult[*model.Secret]
+
+// RunListResult contains a page of run results with pagination support.
+type RunListResult = ListResult[*model.Run]
+
+// EnrollmentTokenListResult contains a page of enrollment token results with pagination support.
+type EnrollmentTokenListResult = ListResult[*model.EnrollmentToken]
+
+// AgentChangeListResult contains a page of agent change results with pagination support.
+type AgentChangeListResult = ListResult[*model.AgentChange]
+
 // AgentStore defines persistence operations for agent entities.
 type AgentStore interface {
 	// UpsertAgent creates a new agent or replaces an existing one.
@@ -459,12 +471,209 @@ type RolloutStore interface {
 	DeleteRolloutsBySpec(ctx context.Context, specID string) error
 }
 
+// SecretStore defines persistence operations for secret entities.
+type SecretStore interface {
+	// CreateSecret inserts a new secret; the name check and the insert are one atomic operation.
+	//
+	// Returns:
+	//   - ErrAlreadyExists if a secret with the same ID or name exists.
+	//   - ErrInvalidArgument if the secret is nil or violates storage-level invariants.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	CreateSecret(ctx context.Context, s *model.Secret) error
+
+	// UpsertSecret creates a new secret or replaces an existing one.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if the secret is nil or violates storage-level invariants.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	UpsertSecret(ctx context.Context, s *model.Secret) error
+
+	// GetSecret retrieves a secret by its unique identifier.
+	//
+	// Returns:
+	//   - ErrNotFound if no secret with the given ID exists.
+	//   - ErrInvalidArgument if the ID is empty or malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	GetSecret(ctx context.Context, id string) (*model.Secret, error)
+
+	// GetSecretByName retrieves a secret by its unique name.
+	//
+	// Returns:
+	//   - ErrNotFound if no secret with the given name exists.
+	//   - ErrInvalidArgument if the name is empty.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	GetSecretByName(ctx context.Context, name string) (*model.Secret, error)
+
+	// ListSecrets retrieves secrets matching the provided filter with pagination support.
+	//
+	// Ordering and cursor contract are defined by ListOptions.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if the filter type is incompatible or the cursor is malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	ListSecrets(ctx context.Context, filter SecretFilter, opts ListOptions) (*SecretListResult, error)
+
+	// DeleteSecret removes a secret by its unique identifier.
+	//
+	// Returns:
+	//   - ErrNotFound if no secret with the given ID exists.
+	//   - ErrInvalidArgument if the ID is empty or malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	DeleteSecret(ctx context.Context, id string) error
+}
+
+// RunStore defines persistence operations for run entities.
+//
+// A run (model.Run) is an ad-hoc task execution on a set of agents together
+// with the per-agent results collected for it.
+type RunStore interface {
+	// UpsertRun creates a new run or replaces an existing one.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if the run is nil or violates storage-level invariants.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	UpsertRun(ctx context.Context, r *model.Run) error
+
+	// GetRun retrieves a run by its unique identifier.
+	//
+	// Returns:
+	//   - ErrNotFound if no run with the given ID exists.
+	//   - ErrInvalidArgument if the ID is empty or malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	GetRun(ctx context.Context, id string) (*model.Run, error)
+
+	// ListRuns retrieves runs matching the provided filter with pagination support.
+	//
+	// Ordering and cursor contract are defined by ListOptions.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if the filter type is incompatible or the cursor is malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	ListRuns(ctx context.Context, filter RunFilter, opts ListOptions) (*RunListResult, error)
+
+	// DeleteRun removes a run by its unique identifier.
+	//
+	// Returns:
+	//   - ErrNotFound if no run with the given ID exists.
+	//   - ErrInvalidArgument if the ID is empty or malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	DeleteRun(ctx context.Context, id string) error
+}
+
+// EnrollmentTokenStore defines persistence operations for enrollment token entities.
+type EnrollmentTokenStore interface {
+	// UpsertEnrollmentToken creates a new enrollment token or replaces an existing one.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if the token is nil or violates storage-level invariants.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	UpsertEnrollmentToken(ctx context.Context, t *model.EnrollmentToken) error
+
+	// GetEnrollmentToken retrieves an enrollment token by its unique identifier.
+	//
+	// Returns:
+	//   - ErrNotFound if no token with the given ID exists.
+	//   - ErrInvalidArgument if the ID is empty or malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	GetEnrollmentToken(ctx context.Context, id string) (*model.EnrollmentToken, error)
+
+	// GetEnrollmentTokenByHash retrieves an enrollment token by the hash of its value.
+	//
+	// Returns:
+	//   - ErrNotFound if no token with the given hash exists.
+	//   - ErrInvalidArgument if the hash is empty.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	GetEnrollmentTokenByHash(ctx context.Context, hash string) (*model.EnrollmentToken, error)
+
+	// ListEnrollmentTokens retrieves enrollment tokens matching the provided filter with pagination support.
+	//
+	// Ordering and cursor contract are defined by ListOptions.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if the filter type is incompatible or the cursor is malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	ListEnrollmentTokens(ctx context.Context, filter EnrollmentTokenFilter, opts ListOptions) (*EnrollmentTokenListResult, error)
+
+	// DeleteEnrollmentToken removes an enrollment token by its unique identifier.
+	//
+	// Returns:
+	//   - ErrNotFound if no token with the given ID exists.
+	//   - ErrInvalidArgument if the ID is empty or malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	DeleteEnrollmentToken(ctx context.Context, id string) error
+}
+
+// AgentChangeStore defines persistence operations for agent inventory changes.
+//
+// Changes (model.AgentChange) are an append-only history: they are created
+// once and only removed together with the agent they belong to, or trimmed
+// to the newest ones.
+type AgentChangeStore interface {
+	// CreateAgentChange stores a new agent change.
+	//
+	// Returns:
+	//   - ErrAlreadyExists if a change with the same ID exists.
+	//   - ErrInvalidArgument if the change is nil or violates storage-level invariants.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	CreateAgentChange(ctx context.Context, c *model.AgentChange) error
+
+	// ListAgentChanges retrieves agent changes matching the provided filter with pagination support.
+	//
+	// Ordering and cursor contract are defined by ListOptions.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if the filter type is incompatible or the cursor is malformed.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	ListAgentChanges(ctx context.Context, filter AgentChangeFilter, opts ListOptions) (*AgentChangeListResult, error)
+
+	// DeleteAgentChanges removes all changes recorded for a given agent.
+	//
+	// Idempotent: if no changes exist for the agent, the operation is a no-op.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if agentID is empty.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	DeleteAgentChanges(ctx context.Context, agentID string) error
+
+	// TrimAgentChanges removes all but the newest keep changes recorded for a given agent.
+	//
+	// Idempotent: if the agent has at most keep changes, the operation is a no-op.
+	//
+	// Returns:
+	//   - ErrInvalidArgument if agentID is empty or keep is negative.
+	//   - ErrUnavailable if the backend is temporarily unavailable.
+	//   - ErrInternal for unexpected storage failures.
+	TrimAgentChanges(ctx context.Context, agentID string, keep int) error
+}
+
 // Storage aggregates all storage capabilities for domain entities.
 type Storage interface {
+	EnrollmentTokenStore
+	AgentChangeStore
 	CredentialStore
 	VerifierStore
 	SessionStore
 	RolloutStore
+	SecretStore
+	RunStore
 	AgentStore
 	RoleStore
 	UserStore
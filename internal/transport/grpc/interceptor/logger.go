@@ -43,3 +43,37 @@ func UnaryLogger(logger zerolog.Logger) grpc.UnaryServerInterceptor {
 		return resp, err
 	}
 }
+
+// StreamLogger returns a stream server interceptor that logs every completed stream.
+func StreamLogger(logger zerolog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		var (
+			err   = handler(srv, ss)
+			st, _ = grpcstatus.FromError(err)
+
+			ctx  = ss.Context()
+			code = st.Code()
+			evt  = logger.Info()
+		)
+		if err != nil {
+			evt = logger.Error().Err(err)
+		}
+
+		evt.
+			Str("method", info.FullMethod).
+			Str("code", code.String()).
+			Str("duration", time.Since(start).String())
+
+		if rid, ok := transportctx.RequestID(ctx); ok {
+			evt = evt.Str("request_id", rid)
+		}
+		if reason := transportctx.TryError(ctx); reason != "" {
+			evt = evt.Str("error", reason)
+		}
+
+		evt.Msg("grpc stream")
+		return err
+	}
+}
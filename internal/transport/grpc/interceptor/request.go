@@ -20,6 +20,23 @@ func UnaryRequestID() grpc.UnaryServerInterceptor {
 	}
 }
 
+// StreamRequestID returns a stream server interceptor that ensures every stream has a unique ID.
+func StreamRequestID() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ensureRequestID(ss.Context())
+		ctx = transportctx.WithErrorSlot(ctx)
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// wrappedStream overrides the context of a server stream.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *wrappedStream) Context() context.Context { return s.ctx }
+
 func ensureRequestID(ctx context.Context) context.Context {
 	rid := extractRequestID(ctx)
 	if rid == "" {
@@ -12,7 +12,7 @@ func Agent(a *model.Agent) restv1.Agent {
 	if a == nil {
 		return restv1.Agent{}
 	}
-	return restv1.Agent{
+	dto := restv1.Agent{
 		ID:   a.ID(),
 		Name: a.Name(),
 
@@ -27,9 +27,39 @@ func Agent(a *model.Agent) restv1.Agent {
 		Endpoint:     a.Endpoint(),
 		EndpointType: string(a.EndpointType()),
 		APIVersion:   a.APIVersion().String(),
+		PullMode:     a.PullMode(),
 
 		Status:            a.Status().String(),
+		Approval:          a.Approval().String(),
+		Cordoned:          a.Cordoned(),
+		Reachability:      a.Reachability().String(),
+		ProbeError:        a.ProbeError(),
 		LastSeenAt:        a.LastSeenAt().Format(time.RFC3339),
 		HeartbeatInterval: int(a.HeartbeatInterval().Seconds()),
 	}
+	if c := a.Capabilities(); c.Reported() {
+		dto.Capabilities = AgentCapabilities(c)
+	}
+	if t := a.CertExpiresAt(); !t.IsZero() {
+		dto.CertExpiresAt = t.Format(time.RFC3339)
+	}
+	if t := a.ProbedAt(); !t.IsZero() {
+		dto.ProbedAt = t.Format(time.RFC3339)
+	}
+	return dto
+}
+
+// AgentCapabilities maps agent-reported capabilities to their REST DTO.
+func AgentCapabilities(c model.Capabilities) *restv1.AgentCapabilities {
+	kinds := make([]string, len(c.TaskKinds))
+	for i, k := range c.TaskKinds {
+		kinds[i] = string(k)
+	}
+	return &restv1.AgentCapabilities{
+		TaskKinds:     kinds,
+		Runtimes:      c.Runtimes,
+		CPUMillicores: c.Limits.CPUMillicores,
+		MemoryBytes:   c.Limits.MemoryBytes,
+		MaxTasks:      c.Limits.MaxTasks,
+	}
 }
@@ -0,0 +1,25 @@
+package apimapv1
+
+import (
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/domain/model"
+)
+
+// AgentChange maps a domain agent change to its REST DTO.
+func AgentChange(c *model.AgentChange) restv1.AgentChange {
+	if c == nil {
+		return restv1.AgentChange{}
+	}
+
+	fields := make([]restv1.AgentFieldChange, 0, len(c.Fields()))
+	for _, f := range c.Fields() {
+		fields = append(fields, restv1.AgentFieldChange{Field: f.Field, Old: f.Old, New: f.New})
+	}
+	return restv1.AgentChange{
+		ID:     c.ID(),
+		Time:   c.CreatedAt().Format(time.RFC3339),
+		Fields: fields,
+	}
+}
@@ -0,0 +1,28 @@
+package apimapv1
+
+import (
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/domain/model"
+	"github.com/soltiHQ/control-plane/internal/service/manifest"
+)
+
+// ManifestDocuments maps parsed manifest documents to the domain form applied by manifest.Service.
+//
+// A spec document is mapped like a create request, so fields left out keep the spec
+// defaults. Its ID is only a placeholder: Apply matches specs by name, and resolves
+// the spec names in depends_on to IDs.
+func ManifestDocuments(docs []restv1.ManifestDocument) []manifest.Document {
+	out := make([]manifest.Document, 0, len(docs))
+	for _, doc := range docs {
+		d := manifest.Document{Kind: doc.Kind, Agent: doc.Agent, Labels: doc.Labels}
+		if doc.Kind == manifest.KindSpec && doc.Spec != nil {
+			d.Name = doc.Spec.Name
+			d.Spec, d.Err = model.NewSpec(doc.Spec.Name, doc.Spec.Name, doc.Spec.Slot)
+			if d.Err == nil {
+				ApplySpecRequest(d.Spec, *doc.Spec, true)
+			}
+		}
+		out = append(out, d)
+	}
+	return out
+}
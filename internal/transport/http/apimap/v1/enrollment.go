@@ -0,0 +1,32 @@
+package apimapv1
+
+import (
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/domain/model"
+)
+
+// EnrollmentToken maps a domain EnrollmentToken to its REST DTO, leaving out the hash.
+func EnrollmentToken(t *model.EnrollmentToken) restv1.EnrollmentToken {
+	if t == nil {
+		return restv1.EnrollmentToken{}
+	}
+	dto := restv1.EnrollmentToken{
+		ID:        t.ID(),
+		Name:      t.Name(),
+		Labels:    t.LabelsAll(),
+		MaxUses:   t.MaxUses(),
+		Uses:      t.Uses(),
+		Usable:    !t.Expired(time.Now()) && !t.Exhausted(),
+		CreatedAt: t.CreatedAt().Format(time.RFC3339),
+		UpdatedAt: t.UpdatedAt().Format(time.RFC3339),
+	}
+	if !t.ExpiresAt().IsZero() {
+		dto.ExpiresAt = t.ExpiresAt().Format(time.RFC3339)
+	}
+	if !t.LastUsedAt().IsZero() {
+		dto.LastUsedAt = t.LastUsedAt().Format(time.RFC3339)
+	}
+	return dto
+}
@@ -0,0 +1,34 @@
+package apimapv1
+
+import (
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/internal/metrics"
+)
+
+// AgentMetricSample maps a metrics sample to its REST DTO.
+func AgentMetricSample(s metrics.Sample) restv1.AgentMetricSample {
+	return restv1.AgentMetricSample{
+		CPUPercent:       s.CPUPercent,
+		Load1:            s.Load1,
+		Load5:            s.Load5,
+		Load15:           s.Load15,
+		MemoryUsedBytes:  s.MemoryUsedBytes,
+		MemoryTotalBytes: s.MemoryTotalBytes,
+		DiskUsedBytes:    s.DiskUsedBytes,
+		DiskTotalBytes:   s.DiskTotalBytes,
+		TasksRunning:     s.TasksRunning,
+		TasksTotal:       s.TasksTotal,
+		Time:             s.Time.Format(time.RFC3339),
+	}
+}
+
+// AgentMetricSamples maps a series of metrics samples, keeping their order.
+func AgentMetricSamples(src []metrics.Sample) []restv1.AgentMetricSample {
+	out := make([]restv1.AgentMetricSample, 0, len(src))
+	for _, s := range src {
+		out = append(out, AgentMetricSample(s))
+	}
+	return out
+}
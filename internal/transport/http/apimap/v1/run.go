@@ -0,0 +1,74 @@
+package apimapv1
+
+import (
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/domain/kind"
+	"github.com/soltiHQ/control-plane/domain/model"
+)
+
+// Run maps a domain Run to its REST DTO, with per-agent results and a status summary.
+func Run(r *model.Run) restv1.Run {
+	if r == nil {
+		return restv1.Run{}
+	}
+	var (
+		results = r.Results()
+		summary = make(map[string]int)
+		items   = make([]restv1.RunResult, 0, len(results))
+		status  = "running"
+	)
+	if r.Done() {
+		status = "succeeded"
+	}
+	for _, res := range results {
+		summary[res.Status.String()]++
+		if r.Done() && res.Status != kind.TaskStatusSucceeded {
+			status = "failed"
+		}
+		items = append(items, RunResult(res))
+	}
+
+	dto := restv1.Run{
+		ID:        r.ID(),
+		Name:      r.Name(),
+		Slot:      r.Slot(),
+		Status:    status,
+		CreatedBy: r.CreatedBy(),
+
+		KindType:     string(r.KindType()),
+		KindConfig:   r.KindConfig(),
+		TimeoutMs:    r.TimeoutMs(),
+		TargetLabels: r.TargetLabels(),
+		RunnerLabels: r.RunnerLabels(),
+
+		Summary: summary,
+		Results: items,
+
+		CreatedAt: r.CreatedAt().Format(time.RFC3339),
+		UpdatedAt: r.UpdatedAt().Format(time.RFC3339),
+	}
+	if r.Done() {
+		dto.FinishedAt = r.FinishedAt().Format(time.RFC3339)
+	}
+	return dto
+}
+
+// RunResult maps a per-agent run result to its REST DTO.
+func RunResult(res model.RunResult) restv1.RunResult {
+	dto := restv1.RunResult{
+		AgentID: res.AgentID,
+		TaskID:  res.TaskID,
+		Attempt: res.Attempt,
+		Status:  res.Status.String(),
+		Error:   res.Error,
+	}
+	if res.Submitted() {
+		dto.SubmittedAt = res.SubmittedAt.Format(time.RFC3339)
+	}
+	if res.Done() {
+		dto.FinishedAt = res.FinishedAt.Format(time.RFC3339)
+	}
+	return dto
+}
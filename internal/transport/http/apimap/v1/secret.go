@@ -0,0 +1,24 @@
+package apimapv1
+
+import (
+	"strconv"
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/domain/model"
+)
+
+// Secret maps a domain Secret to its REST DTO, leaving out the value.
+func Secret(s *model.Secret) restv1.Secret {
+	if s == nil {
+		return restv1.Secret{}
+	}
+	return restv1.Secret{
+		ID:          s.ID(),
+		Name:        s.Name(),
+		Description: s.Description(),
+		Reference:   "{{ secret " + strconv.Quote(s.Name()) + " }}",
+		CreatedAt:   s.CreatedAt().Format(time.RFC3339),
+		UpdatedAt:   s.UpdatedAt().Format(time.RFC3339),
+	}
+}
@@ -4,20 +4,28 @@ import (
 	"time"
 
 	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/domain/kind"
 	"github.com/soltiHQ/control-plane/domain/model"
 )
 
 // Spec maps a domain Spec to its REST DTO.
+//
+// CreateSpec keeps template expressions as written, so secret references stay
+// references; Secrets lists the names they point to.
 func Spec(ts *model.Spec) restv1.Spec {
 	if ts == nil {
 		return restv1.Spec{}
 	}
+	secrets, _ := ts.SecretRefs()
 	return restv1.Spec{
 		ID:      ts.ID(),
 		Name:    ts.Name(),
 		Slot:    ts.Slot(),
 		Version: ts.Version(),
 
+		Priority:  ts.Priority(),
+		ManagedBy: ts.ManagedBy(),
+
 		KindType:   string(ts.KindType()),
 		KindConfig: ts.KindConfig(),
 
@@ -32,8 +40,11 @@ func Spec(ts *model.Spec) restv1.Spec {
 		Admission:   string(ts.Admission()),
 
 		Targets:      ts.Targets(),
+		DependsOn:    ts.DependsOn(),
+		Placement:    ts.Placement(),
 		TargetLabels: ts.TargetLabels(),
 		RunnerLabels: ts.RunnerLabels(),
+		Secrets:      secrets,
 
 		CreateSpec: ts.ToCreateSpec(),
 
@@ -41,3 +52,87 @@ func Spec(ts *model.Spec) restv1.Spec {
 		UpdatedAt: ts.UpdatedAt().Format(time.RFC3339),
 	}
 }
+
+// ApplySpecRequest applies a create/update request body to ts.
+//
+// Zero values keep the current value. On create, empty target, label and
+// dependency collections are ignored; on update, a non-nil collection replaces
+// the current one (an empty one clears it).
+func ApplySpecRequest(ts *model.Spec, in restv1.SpecCreateRequest, create bool) {
+	if in.Priority != nil {
+		ts.SetPriority(*in.Priority)
+	}
+
+	// Kind
+	if in.KindType != "" {
+		ts.SetKindType(kind.TaskKindType(in.KindType))
+	}
+	if in.KindConfig != nil {
+		ts.SetKindConfig(in.KindConfig)
+	}
+
+	// Lifecycle
+	if in.TimeoutMs > 0 {
+		ts.SetTimeoutMs(in.TimeoutMs)
+	}
+	if in.RestartType != "" {
+		ts.SetRestartType(kind.RestartType(in.RestartType))
+	}
+	if in.IntervalMs > 0 {
+		ts.SetIntervalMs(in.IntervalMs)
+	}
+	if in.Jitter != "" || in.BackoffFirstMs > 0 || in.BackoffMaxMs > 0 || in.BackoffFactor > 0 {
+		b := ts.Backoff()
+		if in.Jitter != "" {
+			b.Jitter = kind.JitterStrategy(in.Jitter)
+		}
+		if in.BackoffFirstMs > 0 {
+			b.FirstMs = in.BackoffFirstMs
+		}
+		if in.BackoffMaxMs > 0 {
+			b.MaxMs = in.BackoffMaxMs
+		}
+		if in.BackoffFactor > 0 {
+			b.Factor = in.BackoffFactor
+		}
+		ts.SetBackoff(b)
+	}
+	if in.Admission != "" {
+		ts.SetAdmission(kind.AdmissionStrategy(in.Admission))
+	}
+
+	// Targets
+	if create {
+		if len(in.Targets) > 0 {
+			ts.SetTargets(in.Targets)
+		}
+		if len(in.TargetLabels) > 0 {
+			ts.SetTargetLabels(in.TargetLabels)
+		}
+		if len(in.RunnerLabels) > 0 {
+			ts.SetRunnerLabels(in.RunnerLabels)
+		}
+		if len(in.DependsOn) > 0 {
+			ts.SetDependsOn(in.DependsOn)
+		}
+		if len(in.Placement) > 0 {
+			ts.SetPlacement(in.Placement)
+		}
+		return
+	}
+	if in.Targets != nil {
+		ts.SetTargets(in.Targets)
+	}
+	if in.TargetLabels != nil {
+		ts.SetTargetLabels(in.TargetLabels)
+	}
+	if in.RunnerLabels != nil {
+		ts.SetRunnerLabels(in.RunnerLabels)
+	}
+	if in.DependsOn != nil {
+		ts.SetDependsOn(in.DependsOn)
+	}
+	if in.Placement != nil {
+		ts.SetPlacement(in.Placement)
+	}
+}
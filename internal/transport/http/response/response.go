@@ -7,6 +7,8 @@ package response
 
 import (
 	"net/http"
+	"sort"
+	"strings"
 
 	"github.com/soltiHQ/control-plane/internal/transport/http/responder"
 	"github.com/soltiHQ/control-plane/internal/transport/httpctx"
@@ -18,9 +20,10 @@ import (
 )
 
 type errorBody struct {
-	Code      int    `json:"code"`
-	Message   string `json:"message,omitempty"`
-	RequestID string `json:"request_id,omitempty"`
+	Fields    map[string]string `json:"fields,omitempty"`
+	Code      int               `json:"code"`
+	Message   string            `json:"message,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
 }
 
 // OK renders a 200 response.
@@ -82,6 +85,40 @@ func BadRequestMsg(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMo
 	})
 }
 
+// Invalid renders a 400 response listing field-level validation errors.
+//
+// Field errors are data for the form that submitted them, so HTMX requests
+// receive the JSON body too; full-page requests get the error page.
+func Invalid(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, fields map[string]string) {
+	const msg = "validation failed"
+
+	transportctx.SetError(r.Context(), msg)
+	view := &responder.View{
+		Data: errorBody{
+			Fields:    fields,
+			Code:      http.StatusBadRequest,
+			Message:   msg,
+			RequestID: transportctx.TryRequestID(r.Context()),
+		},
+	}
+	if mode == httpctx.RenderPage {
+		keys := make([]string, 0, len(fields))
+		for k := range fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		lines := make([]string, 0, len(keys))
+		for _, k := range keys {
+			lines = append(lines, k+": "+fields[k])
+		}
+		view.Component = pageSystem.ErrorPage(http.StatusBadRequest, "Invalid request", strings.Join(lines, "; "))
+		httpctx.Responder(r.Context()).Respond(w, r, http.StatusBadRequest, view)
+		return
+	}
+	responder.NewJSON().Respond(w, r, http.StatusBadRequest, view)
+}
+
 // Conflict renders a 409 response.
 func Conflict(w http.ResponseWriter, r *http.Request, mode httpctx.RenderMode, msg string) {
 	transportctx.SetError(r.Context(), msg)
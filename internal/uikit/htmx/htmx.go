@@ -12,6 +12,7 @@ const (
 	AgentUpdate     = "agent_update"
 	SpecUpdate      = "spec_update"
 	UserUpdate      = "user_update"
+	RunUpdate       = "run_update"
 	DashboardUpdate = "dashboard_update"
 )
 
@@ -32,6 +33,8 @@ type Config struct {
 	AgentTasksRefresh   string `yaml:"agent_tasks_refresh"`
 	SpecsRefresh        string `yaml:"specs_refresh"`
 	SpecDetailRefresh   string `yaml:"spec_detail_refresh"`
+	RunsRefresh         string `yaml:"runs_refresh"`
+	RunDetailRefresh    string `yaml:"run_detail_refresh"`
 }
 
 var cfg = defaultConfig()
@@ -50,6 +53,9 @@ func defaultConfig() Config {
 
 		SpecsRefresh:      Every3m,
 		SpecDetailRefresh: Every1m,
+
+		RunsRefresh:      Every1m,
+		RunDetailRefresh: Every1m,
 	}
 }
 
@@ -82,6 +88,12 @@ func Configure(c Config) {
 	if c.SpecDetailRefresh != "" {
 		cfg.SpecDetailRefresh = c.SpecDetailRefresh
 	}
+	if c.RunsRefresh != "" {
+		cfg.RunsRefresh = c.RunsRefresh
+	}
+	if c.RunDetailRefresh != "" {
+		cfg.RunDetailRefresh = c.RunDetailRefresh
+	}
 }
 
 // GetDashboardRefresh returns the polling interval for the dashboard.
@@ -111,6 +123,12 @@ func GetSpecsRefresh() string { return cfg.SpecsRefresh }
 // GetSpecDetailRefresh returns the polling interval for spec detail identity.
 func GetSpecDetailRefresh() string { return cfg.SpecDetailRefresh }
 
+// GetRunsRefresh returns the polling interval for run lists.
+func GetRunsRefresh() string { return cfg.RunsRefresh }
+
+// GetRunDetailRefresh returns the polling interval for run detail.
+func GetRunDetailRefresh() string { return cfg.RunDetailRefresh }
+
 // Poll returns an hx-trigger value combining a polling interval with an SSE event.
 // Use on Results containers that handle periodic and event-driven refreshes.
 func Poll(interval, event string) string {
@@ -9,7 +9,10 @@ import "github.com/soltiHQ/control-plane/internal/auth/identity"
 //
 // Passed into the templ component so markup stays free of auth logic.
 type AgentDetail struct {
-	CanEditLabels bool
+	CanEditLabels   bool
+	CanControlTasks bool
+	CanApprove      bool
+	CanDrain        bool
 }
 
 // BuildAgentDetail derives UI action flags from the authenticated identity.
@@ -20,6 +23,9 @@ func BuildAgentDetail(id *identity.Identity) AgentDetail {
 
 	perms := permSet(id)
 	return AgentDetail{
-		CanEditLabels: hasAny(perms, agentsEdit),
+		CanEditLabels:   hasAny(perms, agentsEdit),
+		CanControlTasks: hasAny(perms, agentsTasks),
+		CanApprove:      hasAny(perms, agentsApprove),
+		CanDrain:        hasAny(perms, agentsDrain),
 	}
 }
@@ -14,6 +14,7 @@ type Nav struct {
 	ShowUsers  bool
 	ShowTasks  bool
 	ShowAgents bool
+	ShowRuns   bool
 	CanAddUser bool
 	CanAddSpec bool
 }
@@ -30,6 +31,7 @@ func BuildNav(id *identity.Identity) Nav {
 		ShowTasks:  hasAny(perms, specsGet),
 		CanAddSpec: hasAny(perms, specsAdd),
 		ShowAgents: hasAny(perms, agentsGet, agentsEdit),
+		ShowRuns:   hasAny(perms, runsGet),
 		ShowUsers:  hasAny(perms, usersGet, usersAdd, usersEdit, usersDelete),
 	}
 }
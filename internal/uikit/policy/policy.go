@@ -13,8 +13,12 @@ import (
 // Convenience aliases to keep policy builders readable.
 const (
 	// agents
-	agentsGet  = kind.AgentsGet
-	agentsEdit = kind.AgentsEdit
+	agentsGet   = kind.AgentsGet
+	agentsEdit  = kind.AgentsEdit
+	agentsTasks = kind.AgentsTasks
+
+	agentsApprove = kind.AgentsApprove
+	agentsDrain   = kind.AgentsDrain
 
 	// users
 	usersGet    = kind.UsersGet
@@ -27,6 +31,10 @@ const (
 	specsAdd    = kind.SpecsAdd
 	specsEdit   = kind.SpecsEdit
 	specsDeploy = kind.SpecsDeploy
+
+	// runs
+	runsGet    = kind.RunsGet
+	runsDelete = kind.RunsDelete
 )
 
 func permSet(id *identity.Identity) map[kind.Permission]struct{} {
@@ -0,0 +1,20 @@
+package policy
+
+import "github.com/soltiHQ/control-plane/internal/auth/identity"
+
+// RunDetail is a UI-oriented policy for the run detail page.
+type RunDetail struct {
+	CanDelete bool
+}
+
+// BuildRunDetail derives UI action flags from the authenticated identity.
+func BuildRunDetail(id *identity.Identity) RunDetail {
+	if id == nil {
+		return RunDetail{}
+	}
+
+	perms := permSet(id)
+	return RunDetail{
+		CanDelete: hasAny(perms, runsDelete),
+	}
+}
@@ -6,22 +6,23 @@ import "github.com/soltiHQ/control-plane/internal/auth/identity"
 //
 // It governs edit/deploy/delete actions shown on the detail view.
 // CanDelete reuses the specsEdit permission — there is no separate "delete" permission for task specs at the domain level.
+// Managed specs (owned by a declarative source such as GitOps) are never editable or deletable from the UI.
 type SpecDetail struct {
 	CanEdit   bool
 	CanDeploy bool
 	CanDelete bool
 }
 
-// BuildSpecDetail derives UI action flags from the authenticated identity.
-func BuildSpecDetail(id *identity.Identity) SpecDetail {
+// BuildSpecDetail derives UI action flags from the authenticated identity and whether the spec is managed.
+func BuildSpecDetail(id *identity.Identity, managed bool) SpecDetail {
 	if id == nil {
 		return SpecDetail{}
 	}
 
 	perms := permSet(id)
 	return SpecDetail{
-		CanEdit:   hasAny(perms, specsEdit),
+		CanEdit:   !managed && hasAny(perms, specsEdit),
 		CanDeploy: hasAny(perms, specsDeploy),
-		CanDelete: hasAny(perms, specsEdit),
+		CanDelete: !managed && hasAny(perms, specsEdit),
 	}
 }
@@ -25,13 +25,21 @@ const (
 	PageSpecNew  = "/specs/new"
 	PageSpecInfo = "/specs/info/"
 
+	PageRuns    = "/runs"
+	PageRunInfo = "/runs/info/"
+
 	ApiSession = "/api/v1/session/"
 
+	ApiAuthLogin   = "/api/v1/auth/login"
+	ApiAuthRefresh = "/api/v1/auth/refresh"
+	ApiAuthLogout  = "/api/v1/auth/logout"
+
 	ApiUsers = "/api/v1/users"
 	ApiUser  = "/api/v1/users/"
 
-	ApiAgents = "/api/v1/agents"
-	ApiAgent  = "/api/v1/agents/"
+	ApiAgents        = "/api/v1/agents"
+	ApiAgent         = "/api/v1/agents/"
+	ApiAgentsPending = ApiAgents + "?approval=pending"
 
 	ApiPermissions = "/api/v1/permissions"
 	ApiRoles       = "/api/v1/roles"
@@ -39,6 +47,17 @@ const (
 	ApiSpecs = "/api/v1/specs"
 	ApiSpec  = "/api/v1/specs/"
 
+	ApiSecrets = "/api/v1/secrets"
+	ApiSecret  = "/api/v1/secrets/"
+
+	ApiRuns = "/api/v1/runs"
+	ApiRun  = "/api/v1/runs/"
+
+	ApiEnrollmentTokens = "/api/v1/enrollment-tokens"
+	ApiEnrollmentToken  = "/api/v1/enrollment-tokens/"
+
+	ApiApply = "/api/v1/apply"
+
 	ApiDashboard       = "/api/v1/dashboard"
 	ApiDashboardIssues = "/api/v1/dashboard/issues"
 	ApiEventStream     = "/api/v1/events/stream"
@@ -53,15 +72,36 @@ var (
 	ApiUserPassword      = func(id string) string { return ApiUser + id + "/password" }
 	ApiUserRevokeSession = func(id string) string { return ApiSession + id + "/revoke" }
 
-	PageAgentInfoByID = func(id string) string { return PageAgentInfo + id }
-	ApiAgentByID      = func(id string) string { return ApiAgent + id }
-	ApiAgentLabels    = func(id string) string { return ApiAgent + id + "/labels" }
-	ApiAgentTasks     = func(id string) string { return ApiAgent + id + "/tasks" }
+	PageAgentInfoByID   = func(id string) string { return PageAgentInfo + id }
+	ApiAgentByID        = func(id string) string { return ApiAgent + id }
+	ApiAgentLabels      = func(id string) string { return ApiAgent + id + "/labels" }
+	ApiAgentApprove     = func(id string) string { return ApiAgent + id + "/approve" }
+	ApiAgentReject      = func(id string) string { return ApiAgent + id + "/reject" }
+	ApiAgentCordon      = func(id string) string { return ApiAgent + id + "/cordon" }
+	ApiAgentUncordon    = func(id string) string { return ApiAgent + id + "/uncordon" }
+	ApiAgentDrain       = func(id string) string { return ApiAgent + id + "/drain" }
+	ApiAgentTasks       = func(id string) string { return ApiAgent + id + "/tasks" }
+	ApiAgentMetrics     = func(id string) string { return ApiAgent + id + "/metrics" }
+	ApiAgentHistory     = func(id string) string { return ApiAgent + id + "/history" }
+	ApiAgentTask        = func(id string) string { return ApiAgent + id + "/tasks/" }
+	ApiAgentTaskCancel  = func(id, taskID string) string { return ApiAgentTask(id) + url.PathEscape(taskID) + "/cancel" }
+	ApiAgentTaskRestart = func(id, taskID string) string { return ApiAgentTask(id) + url.PathEscape(taskID) + "/restart" }
+	ApiAgentTaskLogs    = func(id, taskID string) string { return ApiAgentTask(id) + url.PathEscape(taskID) + "/logs" }
 
 	PageSpecInfoByID = func(id string) string { return PageSpecInfo + id }
 	ApiSpecByID      = func(id string) string { return ApiSpec + id }
 	ApiSpecDeploy    = func(id string) string { return ApiSpec + id + "/deploy" }
+	ApiSpecUndeploy  = func(id string) string { return ApiSpec + id + "/undeploy" }
 	ApiSpecSync      = func(id string) string { return ApiSpec + id + "/sync" }
+	ApiSpecPreview   = func(id string) string { return ApiSpec + id + "/preview" }
+	ApiSpecPlan      = func(id string) string { return ApiSpec + id + "/plan" }
+
+	ApiSecretByID = func(id string) string { return ApiSecret + id }
+
+	PageRunInfoByID = func(id string) string { return PageRunInfo + id }
+	ApiRunByID      = func(id string) string { return ApiRun + id }
+
+	ApiEnrollmentTokenByID = func(id string) string { return ApiEnrollmentToken + id }
 )
 
 // CursorURL appends optional cursor and query parameters to a base API path.
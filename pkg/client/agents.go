@@ -0,0 +1,262 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	proxyv1 "github.com/soltiHQ/control-plane/api/proxy/v1"
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// TaskListOptions filters the tasks reported by an agent.
+type TaskListOptions struct {
+	// Status keeps tasks in this state only.
+	Status string
+	// Query matches task IDs and slots.
+	Query string
+
+	Limit  int
+	Offset int
+}
+
+// LogOptions selects the task output streamed by [Client.TaskLogs].
+type LogOptions struct {
+	// Tail limits the backlog to the last N lines (0 = agent default).
+	Tail int
+	// Follow keeps the stream open for new lines.
+	Follow bool
+}
+
+// MetricsRange selects the samples returned by [Client.AgentMetrics].
+// From takes precedence over Since; with neither the server returns the last hour.
+type MetricsRange struct {
+	From time.Time
+	To   time.Time
+	// Since selects samples newer than now minus Since.
+	Since time.Duration
+}
+
+// ErrLogInterrupted is yielded by [Client.TaskLogs] when the agent stream broke off.
+var ErrLogInterrupted = errors.New("client: task log stream interrupted")
+
+// ListAgents returns one page of agents.
+func (c *Client) ListAgents(ctx context.Context, opts ListOptions) (*restv1.AgentListResponse, error) {
+	var out restv1.AgentListResponse
+	if err := c.do(ctx, http.MethodGet, opts.url(pathAgents), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Agents iterates over all agents, following cursors.
+func (c *Client) Agents(ctx context.Context, opts ListOptions) iter.Seq2[restv1.Agent, error] {
+	return paginate(ctx, opts, func(ctx context.Context, o ListOptions) ([]restv1.Agent, string, error) {
+		res, err := c.ListAgents(ctx, o)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, res.NextCursor, nil
+	})
+}
+
+// GetAgent returns an agent by ID.
+func (c *Client) GetAgent(ctx context.Context, id string) (*restv1.Agent, error) {
+	var out restv1.Agent
+	if err := c.do(ctx, http.MethodGet, pathAgentByID(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetAgentLabels replaces all labels of an agent.
+func (c *Client) SetAgentLabels(ctx context.Context, id string, labels map[string]string) error {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return c.do(ctx, http.MethodPut, pathAgentLabels(id), labels, nil)
+}
+
+// ApproveAgent admits an agent pending approval (or previously rejected) so it can receive work.
+func (c *Client) ApproveAgent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, pathAgentApprove(id), nil, nil)
+}
+
+// RejectAgent rejects an agent; its syncs are refused until it is approved again.
+func (c *Client) RejectAgent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, pathAgentReject(id), nil, nil)
+}
+
+// CordonAgent cordons an agent: it keeps its rollouts but gets no new deployments or runs.
+func (c *Client) CordonAgent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, pathAgentCordon(id), nil, nil)
+}
+
+// UncordonAgent lets a cordoned agent receive new work again.
+func (c *Client) UncordonAgent(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, pathAgentUncordon(id), nil, nil)
+}
+
+// DrainAgent cordons an agent and removes all of its rollouts. With retarget,
+// the label-selected specs it ran are rolled out to the other matching agents.
+func (c *Client) DrainAgent(ctx context.Context, id string, retarget bool) (*restv1.AgentDrainResponse, error) {
+	path := pathAgentDrain(id)
+	if retarget {
+		path += "?retarget=true"
+	}
+
+	var out restv1.AgentDrainResponse
+	if err := c.do(ctx, http.MethodPost, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AgentTasks returns the tasks running on an agent, fetched live through the control plane.
+func (c *Client) AgentTasks(ctx context.Context, id string, opts TaskListOptions) (*proxyv1.TaskListResponse, error) {
+	v := url.Values{}
+	if opts.Status != "" {
+		v.Set("status", opts.Status)
+	}
+	if opts.Query != "" {
+		v.Set("q", opts.Query)
+	}
+	if opts.Limit > 0 {
+		v.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		v.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	path := pathAgentTasks(id)
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	var out proxyv1.TaskListResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AgentMetrics returns the resource samples an agent reported in the range, oldest first.
+// The control plane keeps a bounded in-memory history, so old samples may be gone.
+func (c *Client) AgentMetrics(ctx context.Context, id string, r MetricsRange) (*restv1.AgentMetricsResponse, error) {
+	v := url.Values{}
+	if !r.From.IsZero() {
+		v.Set("from", r.From.Format(time.RFC3339))
+	}
+	if !r.To.IsZero() {
+		v.Set("to", r.To.Format(time.RFC3339))
+	}
+	if r.Since > 0 {
+		v.Set("since", r.Since.String())
+	}
+	path := pathAgentMetrics(id)
+	if len(v) > 0 {
+		path += "?" + v.Encode()
+	}
+
+	var out restv1.AgentMetricsResponse
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AgentHistory returns a page of the inventory changes recorded for an agent, newest first.
+// opts.Query is ignored.
+func (c *Client) AgentHistory(ctx context.Context, id string, opts ListOptions) (*restv1.AgentHistoryResponse, error) {
+	opts.Query = ""
+	var out restv1.AgentHistoryResponse
+	if err := c.do(ctx, http.MethodGet, opts.url(pathAgentHistory(id)), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CancelAgentTask cancels a pending or running task on an agent.
+func (c *Client) CancelAgentTask(ctx context.Context, id, taskID string) error {
+	return c.do(ctx, http.MethodPost, pathAgentTask(id, taskID, "cancel"), nil, nil)
+}
+
+// RestartAgentTask starts a new attempt of a task on an agent.
+func (c *Client) RestartAgentTask(ctx context.Context, id, taskID string) error {
+	return c.do(ctx, http.MethodPost, pathAgentTask(id, taskID, "restart"), nil, nil)
+}
+
+// TaskLogs streams the output of a task on an agent, relayed by the control plane.
+//
+// Without Follow the iteration ends after the backlog; with it, it ends when the
+// agent ends the stream or ctx is done. A broken agent stream yields
+// [ErrLogInterrupted]; other errors are yielded once and end the iteration.
+func (c *Client) TaskLogs(ctx context.Context, id, taskID string, opts LogOptions) iter.Seq2[proxyv1.LogLine, error] {
+	return func(yield func(proxyv1.LogLine, error) bool) {
+		v := url.Values{}
+		if opts.Tail > 0 {
+			v.Set("tail", strconv.Itoa(opts.Tail))
+		}
+		if opts.Follow {
+			v.Set("follow", "true")
+		}
+		path := pathAgentTask(id, taskID, "logs")
+		if len(v) > 0 {
+			path += "?" + v.Encode()
+		}
+
+		stream := &http.Client{Transport: c.http.Transport}
+		resp, err := c.authed(ctx, stream, http.MethodGet, path, "", nil)
+		if err == nil && resp.StatusCode/100 != 2 {
+			err = decode(resp, nil)
+			resp.Body.Close()
+		}
+		if err != nil {
+			yield(proxyv1.LogLine{}, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		var (
+			sc = bufio.NewScanner(resp.Body)
+			ev string
+		)
+		sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+		for sc.Scan() {
+			line := sc.Text()
+			if name, ok := strings.CutPrefix(line, "event:"); ok {
+				ev = strings.TrimSpace(name)
+				continue
+			}
+			data, ok := strings.CutPrefix(line, "data:")
+			if !ok {
+				continue
+			}
+
+			if ev == "end" {
+				var end restv1.TaskLogEnd
+				if json.Unmarshal([]byte(data), &end) == nil && end.Error != "" {
+					yield(proxyv1.LogLine{}, ErrLogInterrupted)
+				}
+				return
+			}
+			var ll proxyv1.LogLine
+			if err = json.Unmarshal([]byte(data), &ll); err != nil {
+				yield(proxyv1.LogLine{}, err)
+				return
+			}
+			if !yield(ll, nil) {
+				return
+			}
+		}
+		if err = sc.Err(); err != nil && ctx.Err() == nil {
+			yield(proxyv1.LogLine{}, err)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package client is a typed Go client for the control-plane REST v1 API:
+//   - One method per /api/v1 endpoint, using the api/rest/v1 DTOs
+//   - Bearer-token auth with automatic refresh through /api/v1/auth/refresh
+//   - Cursor iterators over list endpoints
+//   - Typed errors mapped from response status codes ([Error], [ErrNotFound], ...).
+//
+// Typical use:
+//
+//	c, err := client.New(client.Config{BaseURL: "https://podium.example"})
+//	if err != nil { ... }
+//	if err = c.Login(ctx, "admin", password); err != nil { ... }
+//	for ag, err := range c.Agents(ctx, client.ListOptions{}) {
+//		if err != nil { ... }
+//		fmt.Println(ag.ID, ag.Status)
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// Tokens is the token pair of an API session.
+type Tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	SessionID    string `json:"session_id"`
+}
+
+// Config configures a Client.
+type Config struct {
+	// HTTPClient sends requests; nil uses a client with a 30s timeout.
+	// Streaming calls ([Client.Notifications]) reuse its Transport without the timeout.
+	HTTPClient *http.Client
+
+	// OnTokens, if set, is called after Login and after every refresh with the
+	// new token pair, e.g. to persist it. It is called with an empty pair on Logout.
+	OnTokens func(Tokens)
+
+	// BaseURL is the control-plane address, e.g. "https://podium.example".
+	BaseURL string
+
+	// Tokens resumes an existing session (e.g. from a token cache).
+	Tokens Tokens
+}
+
+// Client calls the REST v1 API. It is safe for concurrent use.
+type Client struct {
+	http     *http.Client
+	onTokens func(Tokens)
+	base     string
+
+	mu     sync.Mutex
+	tokens Tokens
+}
+
+// New creates a Client.
+func New(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, ErrEmptyBaseURL
+	}
+	hc := cfg.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		base:     strings.TrimRight(cfg.BaseURL, "/"),
+		onTokens: cfg.OnTokens,
+		tokens:   cfg.Tokens,
+		http:     hc,
+	}, nil
+}
+
+// BaseURL returns the control-plane address the client talks to.
+func (c *Client) BaseURL() string { return c.base }
+
+// Tokens returns the current token pair.
+func (c *Client) Tokens() Tokens {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.tokens
+}
+
+// Login authenticates with a password and starts a session.
+func (c *Client) Login(ctx context.Context, subject, password string) error {
+	var out restv1.LoginResponse
+	if err := c.public(ctx, pathAuthLogin, restv1.LoginRequest{Subject: subject, Password: password}, &out); err != nil {
+		return err
+	}
+	c.setTokens(Tokens(out))
+	return nil
+}
+
+// Refresh exchanges the refresh token for a new token pair.
+// Authenticated calls do this automatically on 401.
+func (c *Client) Refresh(ctx context.Context) error {
+	return c.refresh(ctx, "")
+}
+
+// Logout revokes the current session and clears the tokens.
+// Tokens are cleared even if the call fails.
+func (c *Client) Logout(ctx context.Context) error {
+	t := c.Tokens()
+	err := c.do(ctx, http.MethodPost, pathAuthLogout, restv1.LogoutRequest{SessionID: t.SessionID}, nil)
+	c.setTokens(Tokens{})
+	return err
+}
+
+func (c *Client) setTokens(t Tokens) {
+	c.mu.Lock()
+	c.tokens = t
+	c.mu.Unlock()
+	if c.onTokens != nil {
+		c.onTokens(t)
+	}
+}
+
+// refresh renews the token pair unless it already changed since stale was used,
+// so concurrent 401s trigger a single refresh.
+func (c *Client) refresh(ctx context.Context, stale string) error {
+	c.mu.Lock()
+	t := c.tokens
+	c.mu.Unlock()
+	if stale != "" && t.AccessToken != stale {
+		return nil
+	}
+	if t.RefreshToken == "" {
+		return ErrNoTokens
+	}
+
+	var out restv1.LoginResponse
+	if err := c.public(ctx, pathAuthRefresh, restv1.RefreshRequest{
+		SessionID:    t.SessionID,
+		RefreshToken: t.RefreshToken,
+	}, &out); err != nil {
+		return err
+	}
+	c.setTokens(Tokens(out))
+	return nil
+}
+
+// do sends in as a JSON body (if non-nil) and decodes the JSON response into out (if non-nil).
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	return c.doRaw(ctx, method, path, "application/json", body, out)
+}
+
+// doRaw sends an authenticated request, refreshing the token once on 401.
+func (c *Client) doRaw(ctx context.Context, method, path, contentType string, body []byte, out any) error {
+	resp, err := c.authed(ctx, c.http, method, path, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decode(resp, out)
+}
+
+// authed sends a request with the bearer token and retries once after a refresh on 401.
+// The caller owns the response body.
+func (c *Client) authed(ctx context.Context, hc *http.Client, method, path, contentType string, body []byte) (*http.Response, error) {
+	token := c.Tokens().AccessToken
+	if token == "" {
+		return nil, ErrNoTokens
+	}
+	resp, err := c.send(ctx, hc, method, path, contentType, body, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	if err = c.refresh(ctx, token); err != nil {
+		return nil, err
+	}
+	return c.send(ctx, hc, method, path, contentType, body, c.Tokens().AccessToken)
+}
+
+// public sends an unauthenticated JSON POST.
+func (c *Client) public(ctx context.Context, path string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(ctx, c.http, http.MethodPost, path, "application/json", body, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return decode(resp, out)
+}
+
+func (c *Client) send(ctx context.Context, hc *http.Client, method, path, contentType string, body []byte, token string) (*http.Response, error) {
+	var rd io.Reader
+	if body != nil {
+		rd = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.base+path, rd)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return hc.Do(req)
+}
+
+// decode maps non-2xx responses to *Error and decodes JSON bodies into out.
+func decode(resp *http.Response, out any) error {
+	if resp.StatusCode/100 != 2 {
+		e := &Error{}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if json.Unmarshal(data, e) != nil {
+			e = &Error{Message: strings.TrimSpace(string(data))}
+		}
+		e.StatusCode = resp.StatusCode
+		return e
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,284 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	proxyv1 "github.com/soltiHQ/control-plane/api/proxy/v1"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+func newTestClient(t *testing.T, h http.Handler, tokens Tokens) (*Client, *[]Tokens) {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	var seen []Tokens
+	c, err := New(Config{
+		BaseURL:  srv.URL,
+		Tokens:   tokens,
+		OnTokens: func(tk Tokens) { seen = append(seen, tk) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, &seen
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestNew_EmptyBaseURL(t *testing.T) {
+	if _, err := New(Config{}); !errors.Is(err, ErrEmptyBaseURL) {
+		t.Fatalf("expected ErrEmptyBaseURL, got %v", err)
+	}
+}
+
+func TestClient_NoTokens(t *testing.T) {
+	c, _ := newTestClient(t, http.NotFoundHandler(), Tokens{})
+	if _, err := c.GetAgent(context.Background(), "a1"); !errors.Is(err, ErrNoTokens) {
+		t.Fatalf("expected ErrNoTokens, got %v", err)
+	}
+}
+
+func TestClient_RefreshesOn401(t *testing.T) {
+	refreshes := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/auth/refresh", func(w http.ResponseWriter, r *http.Request) {
+		var in restv1.RefreshRequest
+		_ = json.NewDecoder(r.Body).Decode(&in)
+		if in.RefreshToken != "r1" || in.SessionID != "s1" {
+			writeJSON(w, http.StatusUnauthorized, Error{StatusCode: http.StatusUnauthorized})
+			return
+		}
+		refreshes++
+		writeJSON(w, http.StatusOK, restv1.LoginResponse{AccessToken: "a2", RefreshToken: "r2", SessionID: "s1"})
+	})
+	mux.HandleFunc("/api/v1/agents/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer a2" {
+			writeJSON(w, http.StatusUnauthorized, Error{StatusCode: http.StatusUnauthorized})
+			return
+		}
+		writeJSON(w, http.StatusOK, restv1.Agent{ID: "a1"})
+	})
+
+	c, seen := newTestClient(t, mux, Tokens{AccessToken: "a1", RefreshToken: "r1", SessionID: "s1"})
+	ag, err := c.GetAgent(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("GetAgent: %v", err)
+	}
+	if ag.ID != "a1" {
+		t.Fatalf("expected agent a1, got %q", ag.ID)
+	}
+	if refreshes != 1 {
+		t.Fatalf("expected 1 refresh, got %d", refreshes)
+	}
+	if got := c.Tokens(); got.AccessToken != "a2" || got.RefreshToken != "r2" {
+		t.Fatalf("tokens not rotated: %+v", got)
+	}
+	if len(*seen) != 1 || (*seen)[0].AccessToken != "a2" {
+		t.Fatalf("OnTokens not called with new pair: %+v", *seen)
+	}
+}
+
+func TestClient_RefreshFailureIsUnauthorized(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusUnauthorized, Error{StatusCode: http.StatusUnauthorized, Message: "unauthorized"})
+	})
+	c, _ := newTestClient(t, h, Tokens{AccessToken: "a1", RefreshToken: "r1", SessionID: "s1"})
+
+	if _, err := c.GetSpec(context.Background(), "x"); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestError_StatusMapping(t *testing.T) {
+	tests := []struct {
+		code   int
+		fields map[string]string
+		want   error
+	}{
+		{http.StatusNotFound, nil, ErrNotFound},
+		{http.StatusConflict, nil, ErrConflict},
+		{http.StatusForbidden, nil, ErrForbidden},
+		{http.StatusTooManyRequests, nil, ErrRateLimited},
+		{http.StatusServiceUnavailable, nil, ErrUnavailable},
+		{http.StatusBadRequest, nil, ErrBadRequest},
+		{http.StatusBadRequest, map[string]string{"name": "is required"}, ErrValidation},
+	}
+	for _, tt := range tests {
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, tt.code, Error{StatusCode: tt.code, Fields: tt.fields})
+		})
+		c, _ := newTestClient(t, h, Tokens{AccessToken: "a1"})
+
+		err := c.CreateSpec(context.Background(), restv1.SpecCreateRequest{})
+		if !errors.Is(err, tt.want) {
+			t.Fatalf("status %d: expected %v, got %v", tt.code, tt.want, err)
+		}
+		var e *Error
+		if !errors.As(err, &e) || e.StatusCode != tt.code {
+			t.Fatalf("status %d: expected *Error, got %#v", tt.code, err)
+		}
+	}
+}
+
+func TestClient_AgentsFollowsCursor(t *testing.T) {
+	pages := map[string]restv1.AgentListResponse{
+		"":   {Items: []restv1.Agent{{ID: "a"}, {ID: "b"}}, NextCursor: "c1"},
+		"c1": {Items: []restv1.Agent{{ID: "c"}}},
+	}
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "web" {
+			t.Errorf("query not forwarded: %s", r.URL.RawQuery)
+		}
+		writeJSON(w, http.StatusOK, pages[r.URL.Query().Get("cursor")])
+	})
+	c, _ := newTestClient(t, h, Tokens{AccessToken: "a1"})
+
+	items, err := Collect(c.Agents(context.Background(), ListOptions{Query: "web"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 || items[0].ID != "a" || items[2].ID != "c" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+}
+
+func TestClient_AgentsStopsEarly(t *testing.T) {
+	calls := 0
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		writeJSON(w, http.StatusOK, restv1.AgentListResponse{Items: []restv1.Agent{{ID: "a"}, {ID: "b"}}, NextCursor: "next"})
+	})
+	c, _ := newTestClient(t, h, Tokens{AccessToken: "a1"})
+
+	for range c.Agents(context.Background(), ListOptions{}) {
+		break
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 page request, got %d", calls)
+	}
+}
+
+func TestClient_AgentMetricsRange(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/agents/a1/metrics" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("since"); got != "30m0s" {
+			t.Errorf("expected since=30m0s, got %q", got)
+		}
+		if r.URL.Query().Has("from") || r.URL.Query().Has("to") {
+			t.Errorf("unexpected bounds: %s", r.URL.RawQuery)
+		}
+		writeJSON(w, http.StatusOK, restv1.AgentMetricsResponse{Items: []restv1.AgentMetricSample{{CPUPercent: 12.5}}})
+	})
+	c, _ := newTestClient(t, h, Tokens{AccessToken: "a1"})
+
+	res, err := c.AgentMetrics(context.Background(), "a1", MetricsRange{Since: 30 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Items) != 1 || res.Items[0].CPUPercent != 12.5 {
+		t.Fatalf("unexpected items: %+v", res.Items)
+	}
+}
+
+func TestClient_DrainAgentRetarget(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/agents/a1/drain" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("retarget"); got != "true" {
+			t.Errorf("expected retarget=true, got %q", got)
+		}
+		writeJSON(w, http.StatusOK, restv1.AgentDrainResponse{Undeployed: []string{"s1", "s2"}, Retargeted: []string{"s1"}})
+	})
+	c, _ := newTestClient(t, h, Tokens{AccessToken: "a1"})
+
+	res, err := c.DrainAgent(context.Background(), "a1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Undeployed) != 2 || len(res.Retargeted) != 1 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestPaths_EscapeIDs(t *testing.T) {
+	const id = "a/1 ?#%"
+	tests := []struct {
+		got, want string
+	}{
+		{pathUserPassword(id), "/api/v1/users/a%2F1%20%3F%23%25/password"},
+		{pathAgentByID(id), "/api/v1/agents/a%2F1%20%3F%23%25"},
+		{pathSpecDeploy(id), "/api/v1/specs/a%2F1%20%3F%23%25/deploy"},
+		{pathSecretByID(id), "/api/v1/secrets/a%2F1%20%3F%23%25"},
+		{pathAgentTask(id, id, "restart"), "/api/v1/agents/a%2F1%20%3F%23%25/tasks/a%2F1%20%3F%23%25/restart"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("path = %q, want %q", tt.got, tt.want)
+		}
+	}
+
+	// The escaped ID reaches the server as a single path segment, before the query.
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/api/v1/agents/a%2F1%20%3F%23%25/drain"; got != want {
+			t.Errorf("path = %q, want %q", got, want)
+		}
+		if got := r.URL.Query().Get("retarget"); got != "true" {
+			t.Errorf("expected retarget=true, got %q", got)
+		}
+		writeJSON(w, http.StatusOK, restv1.AgentDrainResponse{})
+	})
+	c, _ := newTestClient(t, h, Tokens{AccessToken: "a1"})
+	if _, err := c.DrainAgent(context.Background(), id, true); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_TaskLogs(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/agents/a1/tasks/t1/logs" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("tail") != "10" || r.URL.Query().Get("follow") != "true" {
+			t.Errorf("options not forwarded: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"ts\":1,\"stream\":\"stdout\",\"line\":\"hello\"}\n\n")
+		fmt.Fprint(w, "data: {\"ts\":2,\"stream\":\"stderr\",\"line\":\"oops\"}\n\n")
+		fmt.Fprint(w, "event: end\ndata: {\"error\":\"agent log stream interrupted\"}\n\n")
+	})
+	c, _ := newTestClient(t, h, Tokens{AccessToken: "a1"})
+
+	var (
+		lines []proxyv1.LogLine
+		last  error
+	)
+	for ll, err := range c.TaskLogs(context.Background(), "a1", "t1", LogOptions{Tail: 10, Follow: true}) {
+		if err != nil {
+			last = err
+			continue
+		}
+		lines = append(lines, ll)
+	}
+	if len(lines) != 2 || lines[0].Line != "hello" || lines[1].Stream != "stderr" {
+		t.Fatalf("unexpected lines: %+v", lines)
+	}
+	if !errors.Is(last, ErrLogInterrupted) {
+		t.Fatalf("expected ErrLogInterrupted, got %v", last)
+	}
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// EventPayload describes who did what to whom.
+type EventPayload struct {
+	ID     string
+	By     string
+	Name   string
+	Detail string
+}
+
+// DisplayName returns Name if set, otherwise falls back to ID.
+func (p EventPayload) DisplayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.ID
+}
+
+// Event is one entry of the activity feed.
+type Event struct {
+	Time    time.Time
+	Kind    string
+	Payload EventPayload
+}
+
+// IssueGroup is a set of repeated issue events for one entity.
+type IssueGroup struct {
+	Time    time.Time
+	Count   int
+	Kind    string
+	Payload EventPayload
+}
+
+// Dashboard is the overview returned by /api/v1/dashboard.
+// Events and Issues are newest first.
+type Dashboard struct {
+	TotalAgents        int
+	ActiveAgents       int
+	InactiveAgents     int
+	DisconnectedAgents int
+	TotalSpecs         int
+	TotalUsers         int
+	TotalRollouts      int
+	SyncedRollouts     int
+	PendingRollouts    int
+	FailedRollouts     int
+	DriftRollouts      int
+	Events             []Event
+	Issues             []IssueGroup
+}
+
+// Dashboard returns fleet counters and the recent activity and issue feeds.
+func (c *Client) Dashboard(ctx context.Context) (*Dashboard, error) {
+	var out Dashboard
+	if err := c.do(ctx, http.MethodGet, pathDashboard, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CloseIssues dismisses the issues of one kind for one entity (the payload ID).
+func (c *Client) CloseIssues(ctx context.Context, kind, entity string) error {
+	q := url.Values{"kind": {kind}, "entity": {entity}}
+	return c.do(ctx, http.MethodDelete, pathDashboardIssues+"?"+q.Encode(), nil, nil)
+}
+
+// Notifications streams update notifications from /api/v1/events/stream
+// (e.g. "spec_update", "agent_update") until ctx is done or the stream ends.
+//
+// Notifications carry only the name of what changed; re-read the resource
+// (or [Client.Dashboard] for activity) to see the change. A connection error
+// is yielded once and ends the iteration.
+func (c *Client) Notifications(ctx context.Context) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		stream := &http.Client{Transport: c.http.Transport}
+		resp, err := c.authed(ctx, stream, http.MethodGet, pathEventStream, "", nil)
+		if err == nil && resp.StatusCode/100 != 2 {
+			err = decode(resp, nil)
+			resp.Body.Close()
+		}
+		if err != nil {
+			yield("", err)
+			return
+		}
+		defer resp.Body.Close()
+
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			data, ok := strings.CutPrefix(sc.Text(), "data:")
+			if !ok {
+				continue
+			}
+			if !yield(strings.TrimSpace(data), nil) {
+				return
+			}
+		}
+		if err = sc.Err(); err != nil && ctx.Err() == nil {
+			yield("", err)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// ListEnrollmentTokens returns one page of agent enrollment tokens. Token values are never returned.
+func (c *Client) ListEnrollmentTokens(ctx context.Context, opts ListOptions) (*restv1.EnrollmentTokenListResponse, error) {
+	var out restv1.EnrollmentTokenListResponse
+	if err := c.do(ctx, http.MethodGet, opts.url(pathEnrollmentTokens), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// EnrollmentTokens iterates over all enrollment tokens, following cursors.
+func (c *Client) EnrollmentTokens(ctx context.Context, opts ListOptions) iter.Seq2[restv1.EnrollmentToken, error] {
+	return paginate(ctx, opts, func(ctx context.Context, o ListOptions) ([]restv1.EnrollmentToken, string, error) {
+		res, err := c.ListEnrollmentTokens(ctx, o)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, res.NextCursor, nil
+	})
+}
+
+// GetEnrollmentToken returns an enrollment token's metadata by ID.
+func (c *Client) GetEnrollmentToken(ctx context.Context, id string) (*restv1.EnrollmentToken, error) {
+	var out restv1.EnrollmentToken
+	if err := c.do(ctx, http.MethodGet, pathEnrollmentTokenByID(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateEnrollmentToken creates an enrollment token.
+// The response carries the token value, which cannot be retrieved again.
+func (c *Client) CreateEnrollmentToken(ctx context.Context, in restv1.EnrollmentTokenCreateRequest) (*restv1.EnrollmentTokenCreateResponse, error) {
+	var out restv1.EnrollmentTokenCreateResponse
+	if err := c.do(ctx, http.MethodPost, pathEnrollmentTokens, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteEnrollmentToken deletes an enrollment token.
+// Agents already enrolled with it keep their credentials.
+func (c *Client) DeleteEnrollmentToken(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, pathEnrollmentTokenByID(id), nil, nil)
+}
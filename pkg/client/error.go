@@ -0,0 +1,90 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrBadRequest is returned for 400 responses.
+	ErrBadRequest = errors.New("client: bad request")
+	// ErrValidation is returned for 400 responses that carry field-level errors; see [Error.Fields].
+	ErrValidation = errors.New("client: validation failed")
+	// ErrUnauthorized is returned for 401 responses that a token refresh could not fix.
+	ErrUnauthorized = errors.New("client: unauthorized")
+	// ErrForbidden is returned for 403 responses.
+	ErrForbidden = errors.New("client: forbidden")
+	// ErrNotFound is returned for 404 responses.
+	ErrNotFound = errors.New("client: not found")
+	// ErrNotAllowed is returned for 405 responses.
+	ErrNotAllowed = errors.New("client: method not allowed")
+	// ErrConflict is returned for 409 responses (e.g. editing a managed spec).
+	ErrConflict = errors.New("client: conflict")
+	// ErrRateLimited is returned for 429 responses.
+	ErrRateLimited = errors.New("client: rate limited")
+	// ErrUnavailable is returned for 503 responses.
+	ErrUnavailable = errors.New("client: service unavailable")
+
+	// ErrNoTokens is returned when an authenticated call is made before Login
+	// and without [Config.Tokens].
+	ErrNoTokens = errors.New("client: not logged in")
+	// ErrEmptyBaseURL is returned by New when [Config.BaseURL] is empty.
+	ErrEmptyBaseURL = errors.New("client: empty base URL")
+)
+
+// statusErrors maps response status codes to sentinel errors.
+var statusErrors = map[int]error{
+	http.StatusBadRequest:         ErrBadRequest,
+	http.StatusUnauthorized:       ErrUnauthorized,
+	http.StatusForbidden:          ErrForbidden,
+	http.StatusNotFound:           ErrNotFound,
+	http.StatusMethodNotAllowed:   ErrNotAllowed,
+	http.StatusConflict:           ErrConflict,
+	http.StatusTooManyRequests:    ErrRateLimited,
+	http.StatusServiceUnavailable: ErrUnavailable,
+}
+
+// Error is a non-2xx response from the API.
+//
+// It matches the sentinel for its status code with errors.Is,
+// e.g. errors.Is(err, client.ErrNotFound).
+type Error struct {
+	// Fields holds per-field validation messages keyed by REST field name
+	// (e.g. "kind_config.command"), if any.
+	Fields map[string]string `json:"fields,omitempty"`
+
+	StatusCode int    `json:"code"`
+	Message    string `json:"message,omitempty"`
+	RequestID  string `json:"request_id,omitempty"`
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = strings.ToLower(http.StatusText(e.StatusCode))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "client: %s (HTTP %d)", msg, e.StatusCode)
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "; %s: %s", k, e.Fields[k])
+	}
+	return b.String()
+}
+
+// Is reports whether target is the sentinel for the response status.
+func (e *Error) Is(target error) bool {
+	if target == ErrValidation {
+		return e.StatusCode == http.StatusBadRequest && len(e.Fields) > 0
+	}
+	s, ok := statusErrors[e.StatusCode]
+	return ok && s == target
+}
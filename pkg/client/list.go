@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// ListOptions selects a page of a list endpoint.
+type ListOptions struct {
+	// Cursor is the next_cursor of the previous page; empty starts at the beginning.
+	Cursor string
+	// Query filters items by a free-text search ("q").
+	Query string
+	// Limit caps the page size; zero uses the server default.
+	Limit int
+}
+
+func (o ListOptions) url(base string) string {
+	v := url.Values{}
+	if o.Cursor != "" {
+		v.Set("cursor", o.Cursor)
+	}
+	if o.Query != "" {
+		v.Set("q", o.Query)
+	}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if len(v) == 0 {
+		return base
+	}
+	return base + "?" + v.Encode()
+}
+
+// paginate iterates over every item of a cursor-paged list, starting at opts.Cursor.
+// Iteration stops after the first error, which is yielded with a zero item.
+func paginate[T any](ctx context.Context, opts ListOptions, page func(context.Context, ListOptions) ([]T, string, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			items, next, err := page(ctx, opts)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, it := range items {
+				if !yield(it, nil) {
+					return
+				}
+			}
+			if next == "" || next == opts.Cursor {
+				return
+			}
+			opts.Cursor = next
+		}
+	}
+}
+
+// Collect drains an iterator such as [Client.Agents] into a slice, stopping at the first error.
+func Collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	out := []T{}
+	for it, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, nil
+}
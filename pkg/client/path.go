@@ -0,0 +1,70 @@
+package client
+
+import "net/url"
+
+// REST v1 paths. The client keeps its own copy instead of importing the server's
+// route table, so it stays usable outside this module.
+const (
+	pathAuthLogin   = "/api/v1/auth/login"
+	pathAuthRefresh = "/api/v1/auth/refresh"
+	pathAuthLogout  = "/api/v1/auth/logout"
+
+	pathSession = "/api/v1/session/"
+
+	pathUsers = "/api/v1/users"
+	pathUser  = "/api/v1/users/"
+
+	pathPermissions = "/api/v1/permissions"
+	pathRoles       = "/api/v1/roles"
+
+	pathAgents = "/api/v1/agents"
+	pathAgent  = "/api/v1/agents/"
+
+	pathSpecs = "/api/v1/specs"
+	pathSpec  = "/api/v1/specs/"
+	pathApply = "/api/v1/apply"
+
+	pathSecrets = "/api/v1/secrets"
+	pathSecret  = "/api/v1/secrets/"
+
+	pathRuns = "/api/v1/runs"
+	pathRun  = "/api/v1/runs/"
+
+	pathEnrollmentTokens = "/api/v1/enrollment-tokens"
+	pathEnrollmentToken  = "/api/v1/enrollment-tokens/"
+
+	pathDashboard       = "/api/v1/dashboard"
+	pathDashboardIssues = "/api/v1/dashboard/issues"
+	pathEventStream     = "/api/v1/events/stream"
+)
+
+func pathUserByID(id string) string            { return pathUser + url.PathEscape(id) }
+func pathUserEnable(id string) string          { return pathUser + url.PathEscape(id) + "/enable" }
+func pathUserDisable(id string) string         { return pathUser + url.PathEscape(id) + "/disable" }
+func pathUserSessions(id string) string        { return pathUser + url.PathEscape(id) + "/sessions" }
+func pathUserPassword(id string) string        { return pathUser + url.PathEscape(id) + "/password" }
+func pathSessionRevoke(id string) string       { return pathSession + url.PathEscape(id) + "/revoke" }
+func pathAgentByID(id string) string           { return pathAgent + url.PathEscape(id) }
+func pathAgentLabels(id string) string         { return pathAgent + url.PathEscape(id) + "/labels" }
+func pathAgentApprove(id string) string        { return pathAgent + url.PathEscape(id) + "/approve" }
+func pathAgentReject(id string) string         { return pathAgent + url.PathEscape(id) + "/reject" }
+func pathAgentCordon(id string) string         { return pathAgent + url.PathEscape(id) + "/cordon" }
+func pathAgentUncordon(id string) string       { return pathAgent + url.PathEscape(id) + "/uncordon" }
+func pathAgentDrain(id string) string          { return pathAgent + url.PathEscape(id) + "/drain" }
+func pathAgentTasks(id string) string          { return pathAgent + url.PathEscape(id) + "/tasks" }
+func pathAgentMetrics(id string) string        { return pathAgent + url.PathEscape(id) + "/metrics" }
+func pathAgentHistory(id string) string        { return pathAgent + url.PathEscape(id) + "/history" }
+func pathSpecByID(id string) string            { return pathSpec + url.PathEscape(id) }
+func pathSpecDeploy(id string) string          { return pathSpec + url.PathEscape(id) + "/deploy" }
+func pathSpecUndeploy(id string) string        { return pathSpec + url.PathEscape(id) + "/undeploy" }
+func pathSpecSync(id string) string            { return pathSpec + url.PathEscape(id) + "/sync" }
+func pathSpecPreview(id string) string         { return pathSpec + url.PathEscape(id) + "/preview" }
+func pathSpecPlan(id string) string            { return pathSpec + url.PathEscape(id) + "/plan" }
+func pathSecretByID(id string) string          { return pathSecret + url.PathEscape(id) }
+func pathRunByID(id string) string             { return pathRun + url.PathEscape(id) }
+func pathEnrollmentTokenByID(id string) string { return pathEnrollmentToken + url.PathEscape(id) }
+
+// pathAgentTask returns the path of an action on one of the agent's live tasks.
+func pathAgentTask(id, taskID, action string) string {
+	return pathAgent + url.PathEscape(id) + "/tasks/" + url.PathEscape(taskID) + "/" + action
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// ListRuns returns one page of ad-hoc runs.
+func (c *Client) ListRuns(ctx context.Context, opts ListOptions) (*restv1.RunListResponse, error) {
+	var out restv1.RunListResponse
+	if err := c.do(ctx, http.MethodGet, opts.url(pathRuns), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Runs iterates over all runs, following cursors.
+func (c *Client) Runs(ctx context.Context, opts ListOptions) iter.Seq2[restv1.Run, error] {
+	return paginate(ctx, opts, func(ctx context.Context, o ListOptions) ([]restv1.Run, string, error) {
+		res, err := c.ListRuns(ctx, o)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, res.NextCursor, nil
+	})
+}
+
+// GetRun returns a run with its per-agent results.
+func (c *Client) GetRun(ctx context.Context, id string) (*restv1.Run, error) {
+	var out restv1.Run
+	if err := c.do(ctx, http.MethodGet, pathRunByID(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateRun starts a run and returns it with its resolved targets.
+// An invalid task config returns [ErrValidation]; no matching agent returns [ErrBadRequest].
+func (c *Client) CreateRun(ctx context.Context, in restv1.RunCreateRequest) (*restv1.Run, error) {
+	var out restv1.Run
+	if err := c.do(ctx, http.MethodPost, pathRuns, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteRun deletes a run record; tasks already submitted keep running.
+func (c *Client) DeleteRun(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, pathRunByID(id), nil, nil)
+}
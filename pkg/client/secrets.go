@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// ListSecrets returns one page of secrets. Values are never returned.
+func (c *Client) ListSecrets(ctx context.Context, opts ListOptions) (*restv1.SecretListResponse, error) {
+	var out restv1.SecretListResponse
+	if err := c.do(ctx, http.MethodGet, opts.url(pathSecrets), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Secrets iterates over all secrets, following cursors.
+func (c *Client) Secrets(ctx context.Context, opts ListOptions) iter.Seq2[restv1.Secret, error] {
+	return paginate(ctx, opts, func(ctx context.Context, o ListOptions) ([]restv1.Secret, string, error) {
+		res, err := c.ListSecrets(ctx, o)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, res.NextCursor, nil
+	})
+}
+
+// GetSecret returns a secret's metadata by ID.
+func (c *Client) GetSecret(ctx context.Context, id string) (*restv1.Secret, error) {
+	var out restv1.Secret
+	if err := c.do(ctx, http.MethodGet, pathSecretByID(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSecret creates a secret and returns its metadata.
+// A name that is already taken returns [ErrConflict].
+func (c *Client) CreateSecret(ctx context.Context, in restv1.SecretUpsertRequest) (*restv1.Secret, error) {
+	var out restv1.Secret
+	if err := c.do(ctx, http.MethodPost, pathSecrets, in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateSecret updates a secret's description, and its value if non-empty.
+func (c *Client) UpdateSecret(ctx context.Context, id string, in restv1.SecretUpsertRequest) error {
+	return c.do(ctx, http.MethodPut, pathSecretByID(id), in, nil)
+}
+
+// DeleteSecret deletes a secret.
+func (c *Client) DeleteSecret(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, pathSecretByID(id), nil, nil)
+}
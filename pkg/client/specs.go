@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// ApplyOptions controls an Apply call.
+type ApplyOptions struct {
+	// Prune deletes unmanaged specs missing from the manifest.
+	Prune bool
+	// DryRun reports what would change without writing.
+	DryRun bool
+}
+
+// ListSpecs returns one page of specs.
+func (c *Client) ListSpecs(ctx context.Context, opts ListOptions) (*restv1.SpecListResponse, error) {
+	var out restv1.SpecListResponse
+	if err := c.do(ctx, http.MethodGet, opts.url(pathSpecs), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Specs iterates over all specs, following cursors.
+func (c *Client) Specs(ctx context.Context, opts ListOptions) iter.Seq2[restv1.Spec, error] {
+	return paginate(ctx, opts, func(ctx context.Context, o ListOptions) ([]restv1.Spec, string, error) {
+		res, err := c.ListSpecs(ctx, o)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, res.NextCursor, nil
+	})
+}
+
+// GetSpec returns a spec by ID.
+func (c *Client) GetSpec(ctx context.Context, id string) (*restv1.Spec, error) {
+	var out restv1.Spec
+	if err := c.do(ctx, http.MethodGet, pathSpecByID(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateSpec creates a spec. Invalid specs return [ErrValidation] with per-field messages.
+func (c *Client) CreateSpec(ctx context.Context, in restv1.SpecCreateRequest) error {
+	return c.do(ctx, http.MethodPost, pathSpecs, in, nil)
+}
+
+// UpdateSpec replaces a spec. Managed specs return [ErrConflict].
+func (c *Client) UpdateSpec(ctx context.Context, id string, in restv1.SpecCreateRequest) error {
+	return c.do(ctx, http.MethodPut, pathSpecByID(id), in, nil)
+}
+
+// DeleteSpec deletes a spec and its rollouts. Managed specs return [ErrConflict].
+func (c *Client) DeleteSpec(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, pathSpecByID(id), nil, nil)
+}
+
+// DeploySpec rolls a spec out to its target agents.
+func (c *Client) DeploySpec(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, pathSpecDeploy(id), nil, nil)
+}
+
+// SpecPlan returns the agents a deploy would target and the decision for each, without deploying.
+func (c *Client) SpecPlan(ctx context.Context, id string) (*restv1.SpecPlan, error) {
+	var out restv1.SpecPlan
+	if err := c.do(ctx, http.MethodGet, pathSpecPlan(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UndeploySpec stops rolling a spec out; tasks already on agents are left in place.
+func (c *Client) UndeploySpec(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, pathSpecUndeploy(id), nil, nil)
+}
+
+// SpecRollouts returns the per-agent delivery state of a spec.
+func (c *Client) SpecRollouts(ctx context.Context, id string) ([]restv1.RolloutEntry, error) {
+	var out []restv1.RolloutEntry
+	if err := c.do(ctx, http.MethodGet, pathSpecSync(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PreviewSpec renders the payload a spec would push to one agent.
+func (c *Client) PreviewSpec(ctx context.Context, id, agentID string) (*restv1.SpecPreview, error) {
+	var out restv1.SpecPreview
+	path := pathSpecPreview(id) + "?" + url.Values{"agent": {agentID}}.Encode()
+	if err := c.do(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Apply applies a multi-document YAML (or JSON) manifest.
+// Per-object failures are reported in the response, not as an error.
+func (c *Client) Apply(ctx context.Context, manifest []byte, opts ApplyOptions) (*restv1.ApplyResponse, error) {
+	v := url.Values{}
+	v.Set("prune", strconv.FormatBool(opts.Prune))
+	v.Set("dry_run", strconv.FormatBool(opts.DryRun))
+
+	var out restv1.ApplyResponse
+	if err := c.doRaw(ctx, http.MethodPost, pathApply+"?"+v.Encode(), "application/yaml", manifest, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// ListUsers returns one page of users.
+func (c *Client) ListUsers(ctx context.Context, opts ListOptions) (*restv1.UserListResponse, error) {
+	var out restv1.UserListResponse
+	if err := c.do(ctx, http.MethodGet, opts.url(pathUsers), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Users iterates over all users, following cursors.
+func (c *Client) Users(ctx context.Context, opts ListOptions) iter.Seq2[restv1.User, error] {
+	return paginate(ctx, opts, func(ctx context.Context, o ListOptions) ([]restv1.User, string, error) {
+		res, err := c.ListUsers(ctx, o)
+		if err != nil {
+			return nil, "", err
+		}
+		return res.Items, res.NextCursor, nil
+	})
+}
+
+// GetUser returns a user by ID.
+func (c *Client) GetUser(ctx context.Context, id string) (*restv1.User, error) {
+	var out restv1.User
+	if err := c.do(ctx, http.MethodGet, pathUserByID(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateUser creates a user. Subject is required; ID is assigned by the server.
+func (c *Client) CreateUser(ctx context.Context, u restv1.User) error {
+	return c.do(ctx, http.MethodPost, pathUsers, u, nil)
+}
+
+// UpdateUser updates a user's name, email and roles.
+func (c *Client) UpdateUser(ctx context.Context, id string, u restv1.User) error {
+	return c.do(ctx, http.MethodPut, pathUserByID(id), u, nil)
+}
+
+// DeleteUser deletes a user.
+func (c *Client) DeleteUser(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, pathUserByID(id), nil, nil)
+}
+
+// EnableUser re-enables a disabled user.
+func (c *Client) EnableUser(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, pathUserEnable(id), nil, nil)
+}
+
+// DisableUser disables a user, blocking new logins.
+func (c *Client) DisableUser(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, pathUserDisable(id), nil, nil)
+}
+
+// SetUserPassword sets a user's password.
+func (c *Client) SetUserPassword(ctx context.Context, id, password string) error {
+	return c.do(ctx, http.MethodPost, pathUserPassword(id), restv1.SetPasswordRequest{Password: password}, nil)
+}
+
+// UserSessions returns the sessions of a user.
+func (c *Client) UserSessions(ctx context.Context, id string) ([]restv1.Session, error) {
+	var out restv1.SessionResponse
+	if err := c.do(ctx, http.MethodGet, pathUserSessions(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+// RevokeSession revokes any user's session by ID.
+func (c *Client) RevokeSession(ctx context.Context, sessionID string) error {
+	return c.do(ctx, http.MethodPost, pathSessionRevoke(sessionID), nil, nil)
+}
+
+// Permissions returns every permission known to the server.
+func (c *Client) Permissions(ctx context.Context) ([]string, error) {
+	var out restv1.PermissionListResponse
+	if err := c.do(ctx, http.MethodGet, pathPermissions, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
+
+// Roles returns every role.
+func (c *Client) Roles(ctx context.Context) ([]restv1.Role, error) {
+	var out restv1.RoleListResponse
+	if err := c.do(ctx, http.MethodGet, pathRoles, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Items, nil
+}
@@ -0,0 +1,66 @@
+document.addEventListener("alpine:init", () => {
+    const maxLines = 5000;
+
+    Alpine.data("taskLogs", () => ({
+        url: "",
+        title: "",
+        lines: [],
+        follow: true,
+        state: "",
+        es: null,
+
+        open(detail) {
+            this.url = detail.url;
+            this.title = detail.title;
+            this.start();
+        },
+
+        start() {
+            this.stop();
+            this.lines = [];
+            this.state = "Connecting…";
+
+            const q = new URLSearchParams({ tail: "500" });
+            if (this.follow) q.set("follow", "true");
+
+            const es = new EventSource(this.url + "?" + q.toString());
+            this.es = es;
+
+            es.onopen = () => {
+                this.state = this.follow ? "Following…" : "Loading…";
+            };
+            es.onmessage = (e) => this.append(JSON.parse(e.data));
+            // The server always ends with "end"; without it EventSource would reconnect.
+            es.addEventListener("end", (e) => {
+                const end = JSON.parse(e.data || "{}");
+                this.state = end.error || "End of log";
+                this.stop();
+            });
+            es.onerror = () => {
+                if (this.es !== es) return;
+                this.state = "Log stream unavailable";
+                this.stop();
+            };
+        },
+
+        append(line) {
+            const out = this.$refs.out;
+            const atBottom = out.scrollTop + out.clientHeight >= out.scrollHeight - 8;
+
+            this.lines.push(line);
+            if (this.lines.length > maxLines) {
+                this.lines.splice(0, this.lines.length - maxLines);
+            }
+            if (atBottom) {
+                this.$nextTick(() => (out.scrollTop = out.scrollHeight));
+            }
+        },
+
+        stop() {
+            if (this.es) {
+                this.es.close();
+                this.es = null;
+            }
+        },
+    }));
+});
@@ -4,6 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/internal/uikit/routepath"
 )
 
 // kvRow is a single key-value pair for the labels editor.
@@ -53,3 +56,55 @@ func kvSubmitExpr(action string, triggerEvent string) string {
 		strings.ReplaceAll(triggerEvent, "'", "\\'"),
 	)
 }
+
+// logsOpenExpr builds the Alpine expression that opens the task log viewer.
+//
+//	$dispatch('modal:open:task-logs', {"url":"/api/v1/agents/a1/tasks/t1/logs","title":"worker · t1"})
+func logsOpenExpr(agentID, taskID, slot string) string {
+	b, _ := json.Marshal(map[string]string{
+		"url":   routepath.ApiAgentTaskLogs(agentID, taskID),
+		"title": slot + " · " + taskID,
+	})
+	return "$dispatch('modal:open:" + logsModal + "', " + string(b) + ")"
+}
+
+// formatBytes renders a byte count with a binary unit.
+//
+//	4294967296 → "4.0 GiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// changeValue returns an inventory change value, or "—" when the field was absent.
+func changeValue(v string) string {
+	if v == "" {
+		return "—"
+	}
+	return v
+}
+
+// displayName returns the agent name, falling back to its ID.
+func displayName(a restv1.Agent) string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.ID
+}
+
+// reachabilityLabel describes the last probe result, with its error when unreachable.
+func reachabilityLabel(a restv1.Agent) string {
+	label := a.Reachability + " (probed " + a.ProbedAt + ")"
+	if a.ProbeError != "" {
+		label += ": " + a.ProbeError
+	}
+	return label
+}
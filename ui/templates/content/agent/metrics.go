@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+)
+
+// Sparkline viewBox size; the SVG scales it to the card width.
+const (
+	sparkW = 120
+	sparkH = 24
+)
+
+// metricSeries is one sparkline row: a label, the latest value formatted, and the values.
+type metricSeries struct {
+	Label  string
+	Latest string
+	Values []float64
+	// Max is the top of the scale; 0 scales to the largest value.
+	Max float64
+}
+
+// metricSeriesOf builds the sparkline rows for samples (oldest first),
+// leaving out metrics the agent never reported.
+func metricSeriesOf(items []restv1.AgentMetricSample) []metricSeries {
+	if len(items) == 0 {
+		return nil
+	}
+	var (
+		last = items[len(items)-1]
+		out  []metricSeries
+	)
+	add := func(label, latest string, max float64, value func(restv1.AgentMetricSample) float64) {
+		values := make([]float64, len(items))
+		var seen bool
+		for i, s := range items {
+			values[i] = value(s)
+			seen = seen || values[i] != 0
+		}
+		if seen {
+			out = append(out, metricSeries{Label: label, Latest: latest, Values: values, Max: max})
+		}
+	}
+
+	add("CPU", fmt.Sprintf("%.0f%%", last.CPUPercent), 100, func(s restv1.AgentMetricSample) float64 { return s.CPUPercent })
+	add("Memory", usage(last.MemoryUsedBytes, last.MemoryTotalBytes), 100, func(s restv1.AgentMetricSample) float64 {
+		return percent(s.MemoryUsedBytes, s.MemoryTotalBytes)
+	})
+	add("Disk", usage(last.DiskUsedBytes, last.DiskTotalBytes), 100, func(s restv1.AgentMetricSample) float64 {
+		return percent(s.DiskUsedBytes, s.DiskTotalBytes)
+	})
+	add("Load", fmt.Sprintf("%.2f %.2f %.2f", last.Load1, last.Load5, last.Load15), 0, func(s restv1.AgentMetricSample) float64 { return s.Load1 })
+	add("Tasks", fmt.Sprintf("%d / %d running", last.TasksRunning, last.TasksTotal), 0, func(s restv1.AgentMetricSample) float64 {
+		return float64(s.TasksRunning)
+	})
+	return out
+}
+
+// sparkline returns the SVG polyline points of values scaled into the sparkW×sparkH box,
+// with top as the scale maximum (0 scales to the largest value).
+// A single value is drawn as a flat line.
+func sparkline(values []float64, top float64) string {
+	if top <= 0 {
+		for _, v := range values {
+			top = max(top, v)
+		}
+	}
+	if top <= 0 {
+		top = 1
+	}
+	y := func(v float64) string { return coord(sparkH - min(v, top)/top*sparkH) }
+
+	if len(values) == 1 {
+		return fmt.Sprintf("0,%s %d,%s", y(values[0]), sparkW, y(values[0]))
+	}
+	var (
+		b    strings.Builder
+		step = float64(sparkW) / float64(max(len(values)-1, 1))
+	)
+	for i, v := range values {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s,%s", coord(float64(i)*step), y(v))
+	}
+	return b.String()
+}
+
+func coord(v float64) string { return strconv.FormatFloat(v, 'f', 1, 64) }
+
+func percent(used, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(used) / float64(total) * 100
+}
+
+// usage formats used of total bytes with the percentage, or used alone without a total.
+func usage(used, total int64) string {
+	if total <= 0 {
+		return formatBytes(used)
+	}
+	return fmt.Sprintf("%s / %s (%.0f%%)", formatBytes(used), formatBytes(total), percent(used, total))
+}
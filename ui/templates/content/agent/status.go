@@ -54,3 +54,23 @@ func agentStatusLabel(s string) string {
 	}
 	return strings.ToUpper(s[:1]) + s[1:]
 }
+
+// approvalBadgeVariant returns the badge variant for an approval state string (REST API).
+func approvalBadgeVariant(s string) visual.Variant {
+	switch s {
+	case "pending":
+		return visual.VariantSecondary
+	case "rejected":
+		return visual.VariantDanger
+	default:
+		return visual.VariantSuccess
+	}
+}
+
+// approvalLabel returns a display label for an approval state string.
+func approvalLabel(s string) string {
+	if s == "pending" {
+		return "Pending approval"
+	}
+	return agentStatusLabel(s)
+}
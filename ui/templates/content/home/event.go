@@ -61,10 +61,10 @@ func issueDescription(g IssueGroup) string {
 // issueBorderColor returns the left border accent class for an issue event.
 func issueBorderColor(kind string) string {
 	switch kind {
-	case event.AgentDisconnected, event.AgentDeleted, event.RateLimited,
-		event.SyncFailed:
+	case event.AgentDisconnected, event.AgentDeleted, event.AgentRejected, event.AgentUnreachable, event.RateLimited,
+		event.SyncFailed, event.GitOpsFailed, event.RunFailed:
 		return "border-l-danger"
-	case event.AgentInactive:
+	case event.AgentInactive, event.AgentPendingApproval:
 		return "border-l-warning"
 	default:
 		return "border-l-border"
@@ -74,17 +74,21 @@ func issueBorderColor(kind string) string {
 // eventLabelColor returns the text color class for an event label.
 func eventLabelColor(kind string) string {
 	switch kind {
-	case event.AgentConnected, event.SessionCreated:
+	case event.AgentConnected, event.AgentEnrolled, event.AgentApproved, event.SessionCreated, event.GitOpsSynced, event.RunFinished:
 		return "text-success"
-	case event.AgentDisconnected, event.AgentDeleted,
-		event.UserDeleted, event.RateLimited, event.SyncFailed:
+	case event.AgentDisconnected, event.AgentDeleted, event.AgentRejected, event.AgentApprovalRejected,
+		event.AgentUnreachable, event.UserDeleted, event.RateLimited, event.SyncFailed,
+		event.SecretDeleted, event.EnrollmentTokenDeleted, event.GitOpsFailed, event.RunFailed, event.RunDeleted,
+		event.SpecDeleted:
 		return "text-danger"
-	case event.AgentInactive:
+	case event.AgentInactive, event.AgentPendingApproval, event.AgentCordoned, event.AgentDrained:
 		return "text-warning"
-	case event.SpecCreated, event.UserCreated:
+	case event.SpecCreated, event.UserCreated, event.SecretCreated, event.EnrollmentTokenCreated, event.RunCreated:
 		return "text-primary"
-	case event.SpecUpdated, event.SpecDeployed,
-		event.UserUpdated, event.UserPasswordChanged, event.UserStatusChanged:
+	case event.SpecUpdated, event.SpecDeployed, event.SpecUndeployed, event.SecretUpdated,
+		event.UserUpdated, event.UserPasswordChanged, event.UserStatusChanged,
+		event.TaskCanceled, event.TaskRestarted, event.AgentInventoryChanged, event.AgentUncordoned,
+		event.AgentReachable:
 		return "text-secondary"
 	default:
 		return "text-muted"
@@ -102,18 +106,52 @@ func eventLabel(kind string) string {
 		return "disconnected"
 	case event.AgentDeleted:
 		return "deleted"
+	case event.AgentEnrolled:
+		return "enrolled"
+	case event.AgentRejected:
+		return "rejected"
+	case event.AgentPendingApproval:
+		return "awaiting approval"
+	case event.AgentApproved:
+		return "approved"
+	case event.AgentApprovalRejected:
+		return "approval rejected"
+	case event.AgentInventoryChanged:
+		return "inventory changed"
+	case event.AgentCordoned:
+		return "cordoned"
+	case event.AgentUncordoned:
+		return "uncordoned"
+	case event.AgentDrained:
+		return "drained"
+	case event.AgentUnreachable:
+		return "unreachable"
+	case event.AgentReachable:
+		return "reachable again"
+	case event.TaskCanceled:
+		return "task canceled"
+	case event.TaskRestarted:
+		return "task restarted"
 	case event.SpecCreated:
 		return "created"
 	case event.SpecUpdated:
 		return "updated"
 	case event.SpecDeployed:
 		return "deployed"
-	case event.UserCreated:
+	case event.SpecUndeployed:
+		return "undeployed"
+	case event.UserCreated, event.SecretCreated, event.EnrollmentTokenCreated:
 		return "created"
-	case event.UserUpdated:
+	case event.UserUpdated, event.SecretUpdated:
 		return "updated"
-	case event.UserDeleted:
+	case event.SpecDeleted, event.UserDeleted, event.SecretDeleted, event.EnrollmentTokenDeleted, event.RunDeleted:
 		return "deleted"
+	case event.RunCreated:
+		return "started"
+	case event.RunFinished:
+		return "finished"
+	case event.RunFailed:
+		return "failed"
 	case event.UserPasswordChanged:
 		return "password changed"
 	case event.UserStatusChanged:
@@ -124,6 +162,10 @@ func eventLabel(kind string) string {
 		return "rate limited"
 	case event.SyncFailed:
 		return "sync failed"
+	case event.GitOpsSynced:
+		return "synced"
+	case event.GitOpsFailed:
+		return "sync failed"
 	case event.IssueClosed:
 		return "closed"
 	default:
@@ -135,15 +177,28 @@ func eventLabel(kind string) string {
 func eventEntity(kind string) string {
 	switch kind {
 	case event.AgentConnected, event.AgentInactive,
-		event.AgentDisconnected, event.AgentDeleted:
+		event.AgentDisconnected, event.AgentDeleted,
+		event.AgentEnrolled, event.AgentRejected,
+		event.AgentPendingApproval, event.AgentApproved, event.AgentApprovalRejected,
+		event.AgentInventoryChanged, event.AgentCordoned, event.AgentUncordoned, event.AgentDrained,
+		event.AgentUnreachable, event.AgentReachable,
+		event.TaskCanceled, event.TaskRestarted:
 		return "agent"
 	case event.SpecCreated, event.SpecUpdated, event.SpecDeployed,
-		event.SyncFailed:
+		event.SpecUndeployed, event.SpecDeleted, event.SyncFailed:
 		return "spec"
 	case event.UserCreated, event.UserUpdated, event.UserDeleted,
 		event.UserPasswordChanged, event.UserStatusChanged,
 		event.SessionCreated, event.RateLimited:
 		return "user"
+	case event.SecretCreated, event.SecretUpdated, event.SecretDeleted:
+		return "secret"
+	case event.EnrollmentTokenCreated, event.EnrollmentTokenDeleted:
+		return "enrollment token"
+	case event.RunCreated, event.RunFinished, event.RunFailed, event.RunDeleted:
+		return "run"
+	case event.GitOpsSynced, event.GitOpsFailed:
+		return "gitops"
 	case event.IssueClosed:
 		return "issue"
 	default:
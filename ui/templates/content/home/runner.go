@@ -0,0 +1,14 @@
+package home
+
+import (
+	"github.com/soltiHQ/control-plane/internal/server"
+	"github.com/soltiHQ/control-plane/internal/uikit/timeformat"
+)
+
+// runnerRanAt describes when a runner last ticked.
+func runnerRanAt(st server.Status) string {
+	if st.RanAt.IsZero() {
+		return "not run yet"
+	}
+	return "ran " + timeformat.Relative(st.RanAt)
+}
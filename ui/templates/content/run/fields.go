@@ -0,0 +1,64 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	restv1 "github.com/soltiHQ/control-plane/api/rest/v1"
+	"github.com/soltiHQ/control-plane/internal/uikit/timeformat"
+)
+
+// summary renders the per-status result counts of a run, most frequent first.
+//
+//	{"succeeded":3,"failed":1} → "3 succeeded, 1 failed"
+func summary(x restv1.Run) string {
+	if len(x.Summary) == 0 {
+		return "no targets"
+	}
+	keys := make([]string, 0, len(x.Summary))
+	for k := range x.Summary {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if x.Summary[keys[i]] != x.Summary[keys[j]] {
+			return x.Summary[keys[i]] > x.Summary[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%d %s", x.Summary[k], k))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// labelSelector renders a label selector as sorted "key=value" pairs.
+func labelSelector(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for k, v := range labels {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+// timestamp renders an RFC 3339 timestamp relative to now.
+func timestamp(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return timeformat.Relative(t)
+}
+
+func prettyJSON(m map[string]any) string {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
@@ -25,7 +25,7 @@ var presets = []backoffPreset{
 func builderXData(agentsEndpoint string) string {
 	presetsJSON, _ := json.Marshal(presets)
 	return fmt.Sprintf(`{
-  name: '', slot: '', kind_type: 'subprocess',
+  name: '', slot: '', priority: 0, depends_on: '', kind_type: 'subprocess',
   timeout_ms: 30000, restart_type: 'never', interval_ms: 0,
   admission: 'dropIfRunning',
   backoff_preset: 'standard',
@@ -36,12 +36,13 @@ func builderXData(agentsEndpoint string) string {
 
   target_mode: 'agents',
   agents: [], agents_opts: [], agents_open: false,
-  label_rows: [],
+  label_rows: [], placement: '',
 
   runner_label_rows: [],
 
   presets: %s,
   submitting: false,
+  errors: {}, error: '',
   agents_endpoint: '%s',
 
   get kindConfig() {
@@ -77,6 +78,7 @@ func builderXData(agentsEndpoint string) string {
   get createSpec() {
     const spec = {
       name: this.name, slot: this.slot,
+      priority: Number(this.priority),
       kind_type: this.kind_type, kind_config: this.kindConfig,
       timeout_ms: Number(this.timeout_ms),
       restart_type: this.restart_type,
@@ -94,11 +96,29 @@ func builderXData(agentsEndpoint string) string {
     }
     const tl = this.targetLabels;
     if (Object.keys(tl).length) spec.target_labels = tl;
+    const pl = this.placement.split(/[;\n]/).map(x => x.trim()).filter(Boolean);
+    if (pl.length) spec.placement = pl;
+    const deps = this.depends_on.split(/[\s,]+/).filter(Boolean);
+    if (deps.length) spec.depends_on = deps;
     const rl = this.runnerLabels;
     if (Object.keys(rl).length) spec.runner_labels = rl;
     return spec;
   },
 
+  get kindErrors() {
+    return Object.entries(this.errors)
+      .filter(([k]) => k.startsWith('kind_config.'))
+      .map(([k, v]) => k.slice('kind_config.'.length) + ': ' + v)
+      .join('; ');
+  },
+
+  get placementErrors() {
+    return Object.entries(this.errors)
+      .filter(([k]) => k.startsWith('placement.'))
+      .map(([, v]) => v)
+      .join('; ');
+  },
+
   get previewJSON() {
     return JSON.stringify(this.createSpec, null, 2);
   },
@@ -127,18 +147,37 @@ func builderInitExpr() string {
 func builderSubmitExpr(action string) string {
 	return fmt.Sprintf(
 		`submitting = true;
+errors = {}; error = '';
 fetch('%s', {
   method: 'POST',
   headers: { 'Content-Type': 'application/json', 'HX-Request': 'true' },
   body: JSON.stringify(createSpec)
-}).then(r => {
+}).then(async r => {
   if (r.ok) {
     const redirect = r.headers.get('HX-Redirect');
     if (redirect) { window.location.href = redirect; return; }
     show = false;
     htmx.trigger(document.body, 'spec_update');
+  } else {
+    try { const d = await r.json(); errors = d.fields || {}; error = d.fields ? '' : (d.message || 'Request failed'); }
+    catch { error = 'Request failed'; }
   }
-}).catch(() => {}).finally(() => submitting = false)`,
+}).catch(() => { error = 'Network error'; }).finally(() => submitting = false)`,
 		strings.ReplaceAll(action, "'", "\\'"),
 	)
 }
+
+// builderErrorKeys maps builder inputs to the REST field names used in validation errors.
+var builderErrorKeys = map[string]string{
+	"cmd":  "kind_config.command",
+	"args": "kind_config.args",
+	"cwd":  "kind_config.cwd",
+}
+
+// errorKey returns the validation error key for a builder input.
+func errorKey(id string) string {
+	if k, ok := builderErrorKeys[id]; ok {
+		return k
+	}
+	return id
+}
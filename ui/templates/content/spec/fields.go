@@ -1 +1,18 @@
 package spec
+
+import (
+	"sort"
+	"strings"
+)
+
+// labelSelector renders a label selector as sorted "key=value" pairs.
+//
+//	{"env":"prod","tier":"web"} → "env=prod, tier=web"
+func labelSelector(labels map[string]string) string {
+	parts := make([]string, 0, len(labels))
+	for k, v := range labels {
+		parts = append(parts, k+"="+v)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}